@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/spf13/cobra"
+)
+
+var (
+	backfillFrom     string
+	backfillTo       string
+	backfillInterval string
+)
+
+var backfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Generate digests for every window in a past date range",
+	Long: `Builds historical digests for a past range, one per interval. Sources are
+fetched once for the whole range, then each window is analyzed separately
+(reusing the analysis cache) and written as its own dated digest.
+
+Windows start on --from, so pass a Monday for Monday-Sunday weekly digests.
+Windows that already have a digest for the same SIG set are skipped, which
+makes an interrupted backfill safe to rerun with the same arguments. With
+--offline, only data already in the store is used.
+
+Exit codes:
+  0 - Success
+  1 - Partial failure (some windows could not be generated)
+  2 - Fatal error
+  3 - Configuration error`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+
+		windows, err := backfillWindows()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+
+		p, err := pipeline.New(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error: failed to create pipeline: %v\n", err)
+			exit(2)
+		}
+		defer p.Close()
+
+		stopNotifier := startNotifier(p)
+		if _, err := p.BeginRangeRun("backfill", windows[0].Start, windows[len(windows)-1].End); err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+			exit(2)
+		}
+
+		stopProgress := startProgress(p)
+		result, err := p.Backfill(cmd.Context(), windows)
+		runErr := err
+		if err == nil && result.Failed > 0 {
+			runErr = &pipeline.PartialError{Errors: result.Errors}
+		}
+		p.EndRun(runErr)
+		stopProgress()
+		stopNotifier()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+			exit(2)
+		}
+
+		fmt.Fprintf(os.Stdout, "Backfill complete: %d digest(s) generated, %d already present, %d failed, in: %s\n",
+			result.Generated, result.Skipped, result.Failed, cfg.OutputDir)
+		if result.Failed > 0 {
+			exit(1)
+		}
+		return nil
+	},
+}
+
+// backfillWindows resolves the --from/--to/--interval flags in the configured
+// time zone.
+func backfillWindows() ([]pipeline.Window, error) {
+	loc, err := cfg.Location()
+	if err != nil {
+		return nil, err
+	}
+	if backfillFrom == "" {
+		return nil, fmt.Errorf("--from is required")
+	}
+	from, err := config.ParseDate(backfillFrom, loc)
+	if err != nil {
+		return nil, err
+	}
+	to := time.Now().In(loc)
+	if backfillTo != "" {
+		if to, err = config.ParseDate(backfillTo, loc); err != nil {
+			return nil, err
+		}
+	}
+	return pipeline.BackfillWindows(from, to, backfillInterval)
+}
+
+func init() {
+	backfillCmd.Flags().StringVar(&backfillFrom, "from", "", "First day of the range (YYYY-MM-DD)")
+	backfillCmd.Flags().StringVar(&backfillTo, "to", "", "Last day of the range (YYYY-MM-DD, default: today)")
+	backfillCmd.Flags().StringVar(&backfillInterval, "interval", "1w", "Length of each digest window (e.g., 1w, 14d, 1m)")
+	rootCmd.AddCommand(backfillCmd)
+}
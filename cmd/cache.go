@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/registry"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and prune the LLM analysis cache",
+	Long: `Inspect and prune the analysis cache, which holds every LLM result keyed by
+SIG, stage, date window, content and prompt. Cached results are reused by
+later runs over the same data, but are never removed on their own.
+
+Entries can also be pruned automatically at the start of every report run
+with the cache block of the config file:
+
+  cache:
+    retention_days: 90
+    prune_stale_prompts: true`,
+}
+
+// Filters shared by cache stats and cache clear, next to the global
+// --sigs, --since and --until.
+var (
+	cacheStages []string
+	cacheModels []string
+)
+
+var cacheStatsBy []string
+
+// cacheStatsKeys are the columns cache stats can group by.
+var cacheStatsKeys = []string{"sig", "stage", "model"}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show the size of the cache by SIG, stage and model",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, k := range cacheStatsBy {
+			if !slices.Contains(cacheStatsKeys, k) {
+				fmt.Fprintf(os.Stderr, "Error: invalid --by %q (want %s)\n", k, strings.Join(cacheStatsKeys, ", "))
+				exit(3)
+			}
+		}
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		stats, err := db.AnalysisCacheStats(cacheFilter(db))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading analysis cache: %v\n", err)
+			exit(2)
+		}
+		if len(stats) == 0 {
+			fmt.Fprintln(os.Stdout, "The analysis cache is empty.")
+			return nil
+		}
+
+		groups, total := groupCacheStats(stats, cacheStatsBy)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		var header []string
+		for _, k := range cacheStatsBy {
+			header = append(header, strings.ToUpper(k))
+		}
+		fmt.Fprintln(w, strings.Join(append(header, "ENTRIES", "SIZE", "TOKENS", "OLDEST", "NEWEST"), "\t"))
+		for _, g := range groups {
+			fmt.Fprintln(w, formatCacheStat(g, cacheStatKey(g, cacheStatsBy)))
+		}
+		totalKey := make([]string, len(cacheStatsBy))
+		if len(totalKey) > 0 {
+			totalKey[0] = "TOTAL"
+			for i := 1; i < len(totalKey); i++ {
+				totalKey[i] = "-"
+			}
+		}
+		fmt.Fprintln(w, formatCacheStat(total, totalKey))
+		return w.Flush()
+	},
+}
+
+// groupCacheStats sums stats over the columns not in by, keeping the order
+// of stats, and returns the groups and their total.
+func groupCacheStats(stats []*store.AnalysisCacheStat, by []string) ([]*store.AnalysisCacheStat, *store.AnalysisCacheStat) {
+	total := &store.AnalysisCacheStat{}
+	index := make(map[string]*store.AnalysisCacheStat)
+	var groups []*store.AnalysisCacheStat
+	for _, st := range stats {
+		key := strings.Join(cacheStatKey(st, by), "\x00")
+		g, ok := index[key]
+		if !ok {
+			g = &store.AnalysisCacheStat{SIGID: st.SIGID, SourceType: st.SourceType, Model: st.Model}
+			index[key] = g
+			groups = append(groups, g)
+		}
+		addCacheStat(g, st)
+		addCacheStat(total, st)
+	}
+	return groups, total
+}
+
+// addCacheStat adds st to sum.
+func addCacheStat(sum, st *store.AnalysisCacheStat) {
+	sum.Entries += st.Entries
+	sum.Bytes += st.Bytes
+	sum.TokensUsed += st.TokensUsed
+	if sum.Oldest.IsZero() || st.Oldest.Before(sum.Oldest) {
+		sum.Oldest = st.Oldest
+	}
+	if st.Newest.After(sum.Newest) {
+		sum.Newest = st.Newest
+	}
+}
+
+// cacheStatKey returns the values of st in the columns by.
+func cacheStatKey(st *store.AnalysisCacheStat, by []string) []string {
+	key := make([]string, len(by))
+	for i, k := range by {
+		switch k {
+		case "sig":
+			key[i] = st.SIGID
+		case "stage":
+			key[i] = st.SourceType
+		case "model":
+			key[i] = st.Model
+		}
+	}
+	return key
+}
+
+// formatCacheStat renders a cache stats row after the key columns.
+func formatCacheStat(st *store.AnalysisCacheStat, key []string) string {
+	return strings.Join(append(key,
+		fmt.Sprint(st.Entries),
+		formatBytes(st.Bytes),
+		fmt.Sprint(st.TokensUsed),
+		st.Oldest.Local().Format("2006-01-02"),
+		st.Newest.Local().Format("2006-01-02"),
+	), "\t")
+}
+
+// formatBytes renders n bytes in the largest unit that keeps it >= 1.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+var cacheClearAll bool
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete cache entries by SIG, stage, model and date",
+	Long: `Delete the analysis cache entries matching all of the given filters, so the
+next run makes those LLM calls again. --sigs selects SIGs the way report
+does, by name, ID or ID prefix, and --since and --until entries whose date
+window overlaps the range in the configured time zone. Without filters,
+--all is required.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !cacheClearAll && len(cfg.SIGs) == 0 && len(cacheStages) == 0 && len(cacheModels) == 0 &&
+			cfg.Since == "" && cfg.Until == "" {
+			fmt.Fprintln(os.Stderr, "Error: give at least one filter, or --all to clear the whole cache")
+			exit(3)
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		filter := cacheFilter(db)
+		n, err := db.DeleteAnalysisCache(filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error clearing analysis cache: %v\n", err)
+			exit(2)
+		}
+		fmt.Fprintf(os.Stdout, "Deleted %d cache entries.\n", n)
+		return nil
+	},
+}
+
+var (
+	cachePruneOlderThan   int
+	cachePruneStalePrompt bool
+)
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete old cache entries and those of earlier prompt versions",
+	Long: `Delete the analysis cache entries cached more than --older-than days ago
+and, with --stale-prompts, those made with a version of the system prompts
+other than the current one (see 'otel-sig-scraper prompts show'), which are
+never served again. Entries cached before prompt versions were recorded
+are only pruned by age.
+
+Without flags, the retention of the cache block of the config file is
+applied.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		days, stale := cfg.Cache.RetentionDays, cfg.Cache.PruneStalePrompts
+		if cmd.Flags().Changed("older-than") || cmd.Flags().Changed("stale-prompts") {
+			days, stale = cachePruneOlderThan, cachePruneStalePrompt
+		}
+		if days < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --older-than must be >= 0, got %d\n", days)
+			exit(3)
+		}
+		if days == 0 && !stale {
+			fmt.Fprintln(os.Stderr, "Error: nothing to prune; give --older-than or --stale-prompts, or configure cache retention")
+			exit(3)
+		}
+		var prompts *analysis.Prompts
+		if stale {
+			prompts = loadPrompts()
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		n, err := analysis.PruneCache(db, time.Duration(days)*24*time.Hour, prompts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error pruning analysis cache: %v\n", err)
+			exit(2)
+		}
+		fmt.Fprintf(os.Stdout, "Pruned %d cache entries.\n", n)
+		return nil
+	},
+}
+
+// cacheFilter builds the analysis cache filter of the --sigs, --stage,
+// --model, --since and --until flags, resolving --sigs against db.
+func cacheFilter(db *store.Store) store.AnalysisCacheFilter {
+	since, until := filterDates()
+	return store.AnalysisCacheFilter{
+		SIGIDs: filterSIGIDs(db),
+		Stages: cacheStages,
+		Models: cacheModels,
+		Since:  since,
+		Until:  until,
+	}
+}
+
+// filterSIGIDs resolves the global --sigs to the IDs of the SIGs stored in
+// db, matching names and prefixes as report does. It returns nil without
+// --sigs, and exits with a config error when no stored SIG matches.
+func filterSIGIDs(db *store.Store) []string {
+	if len(cfg.SIGs) == 0 {
+		return nil
+	}
+	sigs, err := db.ListSIGs(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading SIGs: %v\n", err)
+		exit(2)
+	}
+	var ids []string
+	for _, sig := range sigs {
+		if registry.MatchSIGFilter(sig.ID, cfg.SIGs) {
+			ids = append(ids, sig.ID)
+		}
+	}
+	if len(ids) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no stored SIG matches --sigs %s\n", strings.Join(cfg.SIGs, ","))
+		exit(3)
+	}
+	return ids
+}
+
+// filterDates parses the global --since and --until as whole days in the
+// configured time zone: since from the start of its day, until to the end
+// of its day. Unset flags are the zero time; invalid ones exit with a
+// config error.
+func filterDates() (since, until time.Time) {
+	loc, err := cfg.Location()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		exit(3)
+	}
+	parse := func(name, value string) time.Time {
+		t, err := config.ParseDate(value, loc)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: --%s: %v\n", name, err)
+			exit(3)
+		}
+		return t
+	}
+	if cfg.Since != "" {
+		since = parse("since", cfg.Since)
+	}
+	if cfg.Until != "" {
+		until = config.EndOfDay(parse("until", cfg.Until))
+	}
+	return since, until
+}
+
+func init() {
+	for _, c := range []*cobra.Command{cacheStatsCmd, cacheClearCmd} {
+		c.Flags().StringSliceVar(&cacheStages, "stage", nil, "Only entries of these stages (notes, video, slack, synthesis, relevance, relevance:<profile>)")
+		c.Flags().StringSliceVar(&cacheModels, "model", nil, "Only entries produced by these models")
+	}
+	cacheStatsCmd.Flags().StringSliceVar(&cacheStatsBy, "by", cacheStatsKeys, "Columns to group by (sig, stage, model)")
+	cacheClearCmd.Flags().BoolVar(&cacheClearAll, "all", false, "Clear every entry when no filter is given")
+	cachePruneCmd.Flags().IntVar(&cachePruneOlderThan, "older-than", 0, "Delete entries cached more than this many days ago")
+	cachePruneCmd.Flags().BoolVar(&cachePruneStalePrompt, "stale-prompts", false, "Delete entries made with earlier versions of the prompts")
+
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+
+	rootCmd.AddCommand(cacheCmd)
+}
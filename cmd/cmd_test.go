@@ -7,13 +7,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
 	"github.com/spf13/cobra"
 )
 
 func TestRootCommand_SubcommandsRegistered(t *testing.T) {
-	expected := []string{"report", "fetch", "list-sigs", "slack-login", "slack-status", "context"}
+	expected := []string{"report", "fetch", "backfill", "runs", "serve", "daemon", "list-sigs", "slack-login", "slack-status", "context", "publish", "site", "prompts", "eval", "cache", "data"}
 	for _, name := range expected {
 		found := false
 		for _, sub := range rootCmd.Commands() {
@@ -110,10 +111,10 @@ func TestContextSetCommand_Flags(t *testing.T) {
 
 func TestRootCommand_PersistentFlags(t *testing.T) {
 	expectedFlags := []string{
-		"lookback", "sigs", "topics", "output-dir", "format",
+		"lookback", "since", "until", "week", "timezone", "sigs", "topics", "output-dir", "format", "template",
 		"llm-provider", "llm-model", "anthropic-api-key", "openai-api-key",
 		"slack-creds", "context-file", "db-path", "workers",
-		"skip-videos", "skip-slack", "skip-notes", "offline", "verbose", "config",
+		"skip-videos", "skip-slack", "skip-notes", "offline", "verbose", "log-file", "log-format", "log-level", "progress", "config", "otlp-endpoint",
 	}
 
 	for _, name := range expectedFlags {
@@ -214,6 +215,12 @@ func TestCommandUseStrings(t *testing.T) {
 		{rootCmd, "otel-sig-scraper"},
 		{reportCmd, "report"},
 		{fetchCmd, "fetch"},
+		{backfillCmd, "backfill"},
+		{runsCmd, "runs"},
+		{runsListCmd, "list"},
+		{runsShowCmd, "show <run-id>"},
+		{serveCmd, "serve"},
+		{daemonCmd, "daemon"},
 		{listSigsCmd, "list-sigs"},
 		{slackLoginCmd, "slack-login"},
 		{slackStatusCmd, "slack-status"},
@@ -221,6 +228,15 @@ func TestCommandUseStrings(t *testing.T) {
 		{contextShowCmd, "show"},
 		{contextSetCmd, "set"},
 		{contextClearCmd, "clear"},
+		{publishCmd, "publish"},
+		{publishSlackCmd, "slack"},
+		{publishEmailCmd, "email"},
+		{siteCmd, "site"},
+		{siteBuildCmd, "build"},
+		{promptsCmd, "prompts"},
+		{promptsShowCmd, "show [name]"},
+		{promptsDiffCmd, "diff [name]"},
+		{evalCmd, "eval"},
 	}
 
 	for _, tt := range tests {
@@ -390,6 +406,126 @@ func TestListSigsCommand_HasRefreshFlag(t *testing.T) {
 	}
 }
 
+func TestBackfillCommand_Flags(t *testing.T) {
+	for _, tt := range []struct{ name, def string }{
+		{"from", ""},
+		{"to", ""},
+		{"interval", "1w"},
+	} {
+		flag := backfillCmd.Flags().Lookup(tt.name)
+		if flag == nil {
+			t.Errorf("backfill should have --%s flag", tt.name)
+			continue
+		}
+		if flag.DefValue != tt.def {
+			t.Errorf("--%s default = %q, want %q", tt.name, flag.DefValue, tt.def)
+		}
+	}
+}
+
+func TestResumeFlag(t *testing.T) {
+	for _, c := range []*cobra.Command{reportCmd, fetchCmd} {
+		flag := c.Flags().Lookup("resume")
+		if flag == nil {
+			t.Errorf("%s should have --resume flag", c.Name())
+			continue
+		}
+		if flag.DefValue != "0" {
+			t.Errorf("%s --resume default = %q, want %q", c.Name(), flag.DefValue, "0")
+		}
+	}
+}
+
+func TestReportCommand_CostFlags(t *testing.T) {
+	for _, tt := range []struct{ name, def string }{
+		{"dry-run", "false"},
+		{"max-cost", "0"},
+		{"max-tokens", "0"},
+		{"batch", "false"},
+	} {
+		flag := reportCmd.Flags().Lookup(tt.name)
+		if flag == nil {
+			t.Errorf("report should have --%s flag", tt.name)
+			continue
+		}
+		if flag.DefValue != tt.def {
+			t.Errorf("--%s default = %q, want %q", tt.name, flag.DefValue, tt.def)
+		}
+	}
+}
+
+func TestCacheCommand_Flags(t *testing.T) {
+	for _, tt := range []struct {
+		cmd  *cobra.Command
+		name string
+		def  string
+	}{
+		{cacheStatsCmd, "by", "[sig,stage,model]"},
+		{cacheStatsCmd, "stage", "[]"},
+		{cacheClearCmd, "stage", "[]"},
+		{cacheClearCmd, "model", "[]"},
+		{cacheClearCmd, "all", "false"},
+		{cachePruneCmd, "older-than", "0"},
+		{cachePruneCmd, "stale-prompts", "false"},
+	} {
+		flag := tt.cmd.Flags().Lookup(tt.name)
+		if flag == nil {
+			t.Errorf("cache %s should have --%s flag", tt.cmd.Name(), tt.name)
+			continue
+		}
+		if flag.DefValue != tt.def {
+			t.Errorf("cache %s --%s default = %q, want %q", tt.cmd.Name(), tt.name, flag.DefValue, tt.def)
+		}
+	}
+}
+
+func TestDataCommand_Flags(t *testing.T) {
+	for _, name := range []string{"export", "import", "prune"} {
+		found := false
+		for _, sub := range dataCmd.Commands() {
+			if sub.Name() == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("subcommand %q not found on dataCmd", name)
+		}
+	}
+	for _, tt := range []struct{ name, def string }{
+		{"older-than", "0"},
+		{"source", "[notes,video,slack]"},
+	} {
+		flag := dataPruneCmd.Flags().Lookup(tt.name)
+		if flag == nil {
+			t.Errorf("data prune should have --%s flag", tt.name)
+			continue
+		}
+		if flag.DefValue != tt.def {
+			t.Errorf("--%s default = %q, want %q", tt.name, flag.DefValue, tt.def)
+		}
+	}
+}
+
+func TestGroupCacheStats(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	stats := []*store.AnalysisCacheStat{
+		{SIGID: "collector", SourceType: "notes", Model: "haiku", Entries: 2, Bytes: 100, TokensUsed: 10, Oldest: day, Newest: day},
+		{SIGID: "collector", SourceType: "synthesis", Model: "sonnet", Entries: 1, Bytes: 50, TokensUsed: 5, Oldest: day.AddDate(0, 0, 1), Newest: day.AddDate(0, 0, 1)},
+		{SIGID: "java", SourceType: "notes", Model: "haiku", Entries: 3, Bytes: 30, TokensUsed: 3, Oldest: day.AddDate(0, 0, -1), Newest: day},
+	}
+	groups, total := groupCacheStats(stats, []string{"model"})
+	if len(groups) != 2 || groups[0].Model != "haiku" || groups[0].Entries != 5 || groups[0].Bytes != 130 {
+		t.Errorf("groups by model = %+v, want haiku with 5 entries of 130 bytes first", groups)
+	}
+	if total.Entries != 6 || total.TokensUsed != 18 || !total.Oldest.Equal(day.AddDate(0, 0, -1)) || !total.Newest.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("total = %+v", total)
+	}
+	if got := formatBytes(1536); got != "1.5 KiB" {
+		t.Errorf("formatBytes(1536) = %q, want 1.5 KiB", got)
+	}
+}
+
 func TestRootCommand_SilenceSettings(t *testing.T) {
 	if !rootCmd.SilenceUsage {
 		t.Error("rootCmd.SilenceUsage should be true")
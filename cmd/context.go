@@ -31,7 +31,7 @@ var contextShowCmd = &cobra.Command{
 		content, err := analysis.LoadCustomContext(contextFile)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error reading context: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		if content == "" {
@@ -66,11 +66,11 @@ Examples:
 
 		if contextSetFile == "" && contextSetText == "" {
 			fmt.Fprintln(os.Stderr, "Error: either --file or --text must be specified")
-			os.Exit(3)
+			exit(3)
 		}
 		if contextSetFile != "" && contextSetText != "" {
 			fmt.Fprintln(os.Stderr, "Error: --file and --text are mutually exclusive")
-			os.Exit(3)
+			exit(3)
 		}
 
 		var content string
@@ -78,7 +78,7 @@ Examples:
 			data, err := os.ReadFile(contextSetFile)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error reading file %q: %v\n", contextSetFile, err)
-				os.Exit(1)
+				exit(1)
 			}
 			content = string(data)
 		} else {
@@ -87,7 +87,7 @@ Examples:
 
 		if err := analysis.SaveCustomContext(contextFile, content); err != nil {
 			fmt.Fprintf(os.Stderr, "Error saving context: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Fprintf(os.Stdout, "Custom context saved to: %s\n", contextFile)
@@ -105,7 +105,7 @@ var contextClearCmd = &cobra.Command{
 
 		if err := analysis.ClearCustomContext(contextFile); err != nil {
 			fmt.Fprintf(os.Stderr, "Error clearing context: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Fprintf(os.Stdout, "Custom context cleared (removed: %s)\n", contextFile)
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/browser"
+	"github.com/gordyrad/otel-sig-tracker/internal/notify"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/scheduler"
+	"github.com/spf13/cobra"
+)
+
+var daemonJobsFile string
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run fetch and report jobs on a schedule",
+	Long: `Runs recurring fetch and report jobs from a YAML job file (see
+jobs.example.yaml) in a long-lived process:
+
+  timezone: America/New_York
+  registry_refresh: 24h
+  jobs:
+    - name: fetch
+      command: fetch
+      schedule: every 6h
+    - name: weekly-digest
+      command: report
+      schedule: mon 08:00
+      window: last-week
+
+Schedules are "every <duration>", "daily HH:MM", "weekdays HH:MM" or a list
+of days such as "mon,thu HH:MM". Jobs run one at a time and share one
+headless browser. Every run takes a lock in the database, so a job is skipped
+while a CLI or API run is using it. On SIGINT or SIGTERM a running job is
+given shutdown_timeout (default 5m) to finish its in-flight LLM calls before
+it is interrupted; interrupted runs can be resumed with --resume.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := scheduler.LoadSpec(daemonJobsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+		if spec.Timezone == "" {
+			spec.Timezone = cfg.Timezone
+		}
+		now := time.Now()
+		for _, job := range spec.Jobs {
+			if _, err := spec.Config(job, cfg, now); err != nil {
+				fmt.Fprintf(os.Stderr, "Configuration error: job %q: %v\n", job.Name, err)
+				exit(3)
+			}
+		}
+
+		notifier := newNotifier()
+		defer closeNotifier(notifier)
+
+		pool := browser.NewPool(true)
+		defer pool.Cleanup()
+
+		sched, err := scheduler.New(spec, func(ctx context.Context, job scheduler.JobSpec) error {
+			return runDaemonJob(ctx, spec, job, pool, notifier)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+
+		fmt.Fprintf(os.Stdout, "Running %d job(s) from %s\n", len(spec.Jobs), daemonJobsFile)
+		sched.Run(cmd.Context())
+		return nil
+	},
+}
+
+// runDaemonJob runs one scheduled job through the pipeline. notifier, if
+// not nil, receives the job's events.
+func runDaemonJob(ctx context.Context, spec *scheduler.Spec, job scheduler.JobSpec, pool *browser.Pool, notifier *notify.Notifier) error {
+	jobCfg, err := spec.Config(job, cfg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	p, err := pipeline.New(jobCfg)
+	if err != nil {
+		return fmt.Errorf("creating pipeline: %w", err)
+	}
+	defer p.Close()
+	p.SetLogger(slog.Default().With("job", job.Name))
+	if notifier != nil {
+		defer p.Subscribe(notifier)()
+	}
+
+	if !jobCfg.Offline && !jobCfg.SkipVideos {
+		// Start the shared browser lazily so Slack- or notes-only jobs
+		// never launch Chrome.
+		if err := pool.Start(); err != nil {
+			slog.Warn("daemon: starting shared browser", "err", err)
+		}
+	}
+	p.SetBrowserPool(pool)
+	p.SetRegistryMaxAge(spec.RegistryRefresh)
+
+	run, err := p.BeginRun(job.Command)
+	if errors.Is(err, pipeline.ErrRunInProgress) {
+		slog.Info("daemon: skipping job", "job", job.Name, "reason", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	slog.Info("daemon: job started run", "job", job.Name, "run", run.ID)
+
+	switch {
+	case job.Command == "fetch":
+		err = p.FetchOnly(ctx)
+	case jobCfg.Offline:
+		err = p.AnalyzeOnly(ctx)
+	default:
+		err = p.Run(ctx)
+	}
+	p.EndRun(err)
+	return err
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&daemonJobsFile, "jobs", "jobs.yaml", "Path to the YAML job file")
+	rootCmd.AddCommand(daemonCmd)
+}
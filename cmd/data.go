@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/archive"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var dataCmd = &cobra.Command{
+	Use:   "data",
+	Short: "Export, import and prune fetched source data",
+	Long: `Manage the meeting notes, video transcripts and Slack messages fetched into
+the database.
+
+An archive is a zip file with one JSONL file per table (sigs,
+meeting_notes, video_transcripts, slack_messages) and a manifest.json
+recording the schema version, so a teammate can analyze a pre-fetched
+corpus with --offline instead of fetching it again. --sigs, --since and
+--until select what is exported or imported: SIGs by name, ID or ID prefix
+as for report, and whole days in the configured time zone.`,
+}
+
+var dataExportCmd = &cobra.Command{
+	Use:   "export <file.zip>",
+	Short: "Write fetched data to a portable archive",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := dataFilter()
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		f, err := os.Create(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating archive: %v\n", err)
+			exit(2)
+		}
+		m, err := archive.Export(db, f, filter)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			os.Remove(args[0])
+			fmt.Fprintf(os.Stderr, "Error exporting data: %v\n", err)
+			exit(2)
+		}
+
+		fmt.Fprintf(os.Stdout, "Exported to %s (schema version %d):\n", args[0], m.SchemaVersion)
+		return printTableRows(m.Rows)
+	},
+}
+
+var dataImportCmd = &cobra.Command{
+	Use:   "import <file.zip>",
+	Short: "Load fetched data from an archive",
+	Long: `Upsert the rows of an archive into the database. Rows already present are
+updated, so importing is safe to repeat. Archives exported at a newer
+schema version than this database are rejected.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := dataFilter()
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening archive: %v\n", err)
+			exit(2)
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening archive: %v\n", err)
+			exit(2)
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		m, imported, err := archive.Import(db, f, info.Size(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error importing data: %v\n", err)
+			exit(2)
+		}
+
+		fmt.Fprintf(os.Stdout, "Imported from %s (exported %s, schema version %d):\n",
+			args[0], m.CreatedAt.Local().Format("2006-01-02 15:04"), m.SchemaVersion)
+		return printTableRows(imported)
+	},
+}
+
+// printTableRows prints the row count of each archive table.
+func printTableRows(rows map[string]int) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tROWS")
+	for _, table := range archive.Tables {
+		fmt.Fprintf(w, "%s\t%d\n", table, rows[table])
+	}
+	return w.Flush()
+}
+
+// dataFilter builds the archive filter of the --sigs, --since and --until
+// flags, exiting on an invalid date. SIGs are matched by the archive, as an
+// imported archive may hold SIGs this database has not stored yet.
+func dataFilter() archive.Filter {
+	since, until := filterDates()
+	return archive.Filter{
+		SIGIDs: cfg.SIGs,
+		Since:  since,
+		Until:  until,
+	}
+}
+
+// dataSources are the source types data prune applies to.
+var dataSources = []string{"notes", "video", "slack"}
+
+var (
+	dataPruneOlderThan int
+	dataPruneSources   []string
+)
+
+var dataPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Delete fetched data older than a retention period",
+	Long: `Delete the meeting notes, video transcripts and Slack messages dated more
+than --older-than days ago, or, without it, older than the per-source
+retention of the config file:
+
+  retention:
+    notes_days: 730
+    video_days: 365
+    slack_days: 180
+
+--sigs limits pruning to some SIGs, matched as for report. SIG entries are
+kept, as other tables refer to them, and cached analysis results are left
+to 'cache prune'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, source := range dataPruneSources {
+			if !slices.Contains(dataSources, source) {
+				fmt.Fprintf(os.Stderr, "Error: invalid --source %q (want %s)\n", source, strings.Join(dataSources, ", "))
+				exit(3)
+			}
+		}
+		if dataPruneOlderThan < 0 {
+			fmt.Fprintf(os.Stderr, "Error: --older-than must be >= 0, got %d\n", dataPruneOlderThan)
+			exit(3)
+		}
+		retention := make(map[string]int)
+		for _, source := range dataPruneSources {
+			days := cfg.Retention.Days(source)
+			if cmd.Flags().Changed("older-than") {
+				days = dataPruneOlderThan
+			}
+			if days > 0 {
+				retention[source] = days
+			}
+		}
+		if len(retention) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: nothing to prune; give --older-than or configure retention")
+			exit(3)
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+		sigIDs := filterSIGIDs(db)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SOURCE\tOLDER THAN\tDELETED")
+		for _, source := range dataPruneSources {
+			days, ok := retention[source]
+			if !ok {
+				continue
+			}
+			before := time.Now().AddDate(0, 0, -days)
+			n, err := db.DeleteSourceData(source, sigIDs, before)
+			if err != nil {
+				w.Flush()
+				fmt.Fprintf(os.Stderr, "Error pruning %s: %v\n", source, err)
+				exit(2)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\n", source, before.Format("2006-01-02"), n)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	dataPruneCmd.Flags().IntVar(&dataPruneOlderThan, "older-than", 0, "Delete data dated more than this many days ago, overriding the configured retention")
+	dataPruneCmd.Flags().StringSliceVar(&dataPruneSources, "source", dataSources, "Sources to prune (notes, video, slack)")
+
+	dataCmd.AddCommand(dataExportCmd)
+	dataCmd.AddCommand(dataImportCmd)
+	dataCmd.AddCommand(dataPruneCmd)
+
+	rootCmd.AddCommand(dataCmd)
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/eval"
+	"github.com/spf13/cobra"
+)
+
+var (
+	evalFixtures          string
+	evalCompareProvider   string
+	evalCompareModel      string
+	evalComparePromptsDir string
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval",
+	Short: "Score report quality over a fixture corpus",
+	Long: `Runs the summarize, synthesize and relevance stages over every fixture case
+in --fixtures and scores the relevance reports against the cases' expected
+items:
+
+  Topic recall       expected topics reported at any level
+  HIGH recall        expected HIGH topics reported at HIGH
+  Level agreement    reported expected topics at the expected level
+  Format compliance  section headers, item form, no prose or banned sections
+
+The configuration under test is the usual --llm-provider, --llm-model and
+--prompts-dir. Set any of the --compare-* flags to evaluate a second
+configuration and show both side by side. The "mock" provider replays each
+case's recorded response without calling a model, which checks the harness
+and the fixtures offline.
+
+Each case is a YAML file; see testdata/eval for examples.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cases, err := eval.LoadCases(evalFixtures)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading eval cases: %v\n", err)
+			exit(3)
+		}
+
+		baseline := evalConfig("A", cfg.LLM.Provider, cfg.LLM.Model, cfg.PromptsDir)
+		configs := []eval.Config{baseline}
+		f := cmd.Flags()
+		if f.Changed("compare-provider") || f.Changed("compare-model") || f.Changed("compare-prompts-dir") {
+			provider, model, promptsDir := baseline.Provider, baseline.Model, baseline.PromptsDir
+			if f.Changed("compare-provider") {
+				provider = evalCompareProvider
+			}
+			if f.Changed("compare-model") {
+				model = evalCompareModel
+			}
+			if f.Changed("compare-prompts-dir") {
+				promptsDir = evalComparePromptsDir
+			}
+			configs = append(configs, evalConfig("B", provider, model, promptsDir))
+		}
+
+		var results []*eval.Result
+		for _, c := range configs {
+			fmt.Fprintf(os.Stderr, "Evaluating %s: %s over %d cases...\n", c.Label, c, len(cases))
+			res, err := eval.Run(cmd.Context(), c, cases)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error evaluating %s: %v\n", c.Label, err)
+				exit(2)
+			}
+			results = append(results, res)
+		}
+
+		if err := printEvalResults(os.Stdout, cases, results); err != nil {
+			return err
+		}
+		for _, res := range results {
+			if res.Errors() > 0 {
+				exit(1)
+			}
+		}
+		return nil
+	},
+}
+
+// evalConfig builds an eval configuration, exiting with a config error if
+// the provider has no API key.
+func evalConfig(label, provider, model, promptsDir string) eval.Config {
+	c := eval.Config{Label: label, Provider: provider, Model: model, PromptsDir: promptsDir}
+	switch provider {
+	case "anthropic":
+		c.APIKey = cfg.LLM.AnthropicKey
+	case "openai":
+		c.APIKey = cfg.LLM.OpenAIKey
+	case "mock":
+		return c
+	default:
+		fmt.Fprintf(os.Stderr, "Error: llm provider must be 'anthropic', 'openai' or 'mock', got %q\n", provider)
+		exit(3)
+	}
+	if c.APIKey == "" {
+		fmt.Fprintf(os.Stderr, "Error: %s API key is required (or use --llm-provider mock)\n", provider)
+		exit(3)
+	}
+	return c
+}
+
+// printEvalResults writes the summary metrics, a per-case table and what
+// each configuration missed.
+func printEvalResults(out io.Writer, cases []*eval.Case, results []*eval.Result) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "METRIC")
+	for _, res := range results {
+		fmt.Fprintf(w, "\t%s: %s", res.Config.Label, res.Config)
+	}
+	fmt.Fprintln(w)
+	for _, m := range []struct {
+		name  string
+		ratio func(*eval.Result) eval.Ratio
+	}{
+		{"Topic recall", (*eval.Result).Recall},
+		{"HIGH recall", (*eval.Result).HighRecall},
+		{"Level agreement", (*eval.Result).LevelAgreement},
+		{"Format compliance", (*eval.Result).FormatCompliance},
+	} {
+		fmt.Fprint(w, m.name)
+		for _, res := range results {
+			r := m.ratio(res)
+			fmt.Fprintf(w, "\t%d/%d (%.0f%%)", r.N, r.Of, r.Percent())
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprint(w, "Failed cases")
+	for _, res := range results {
+		fmt.Fprintf(w, "\t%d", res.Errors())
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, "Tokens")
+	for _, res := range results {
+		fmt.Fprintf(w, "\t%d", res.Tokens())
+	}
+	fmt.Fprintln(w)
+
+	fmt.Fprint(w, "\nCASE")
+	for _, res := range results {
+		fmt.Fprintf(w, "\t%s RECALL\t%s HIGH\t%s LEVEL\t%s FORMAT", res.Config.Label, res.Config.Label, res.Config.Label, res.Config.Label)
+	}
+	fmt.Fprintln(w)
+	for i, c := range cases {
+		fmt.Fprint(w, c.Name)
+		for _, res := range results {
+			cr := res.Cases[i]
+			if cr.Err != nil {
+				fmt.Fprint(w, "\terror\t-\t-\t-")
+				continue
+			}
+			fmt.Fprintf(w, "\t%d/%d\t%d/%d\t%d/%d\t%d/%d", cr.Found, cr.Expected, cr.HighFound, cr.HighExpected,
+				cr.LevelAgreed, cr.Found, cr.FormatPassed, cr.FormatChecks)
+		}
+		fmt.Fprintln(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, res := range results {
+		var notes []string
+		for _, cr := range res.Cases {
+			if cr.Err != nil {
+				notes = append(notes, fmt.Sprintf("%s: error: %v", cr.Case.Name, cr.Err))
+				continue
+			}
+			for _, topic := range cr.Missed {
+				notes = append(notes, fmt.Sprintf("%s: missed %q", cr.Case.Name, topic))
+			}
+			for _, v := range cr.Violations {
+				notes = append(notes, fmt.Sprintf("%s: %s", cr.Case.Name, v))
+			}
+		}
+		if len(notes) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n%s (%s):\n", res.Config.Label, res.Config)
+		for _, n := range notes {
+			fmt.Fprintf(out, "  %s\n", n)
+		}
+	}
+	return nil
+}
+
+func init() {
+	f := evalCmd.Flags()
+	f.StringVar(&evalFixtures, "fixtures", "testdata/eval", "Directory of eval case files (*.yaml)")
+	f.StringVar(&evalCompareProvider, "compare-provider", "", "LLM provider of the configuration to compare against: anthropic, openai, mock")
+	f.StringVar(&evalCompareModel, "compare-model", "", "LLM model of the configuration to compare against")
+	f.StringVar(&evalComparePromptsDir, "compare-prompts-dir", "", "Prompt overrides of the configuration to compare against")
+	rootCmd.AddCommand(evalCmd)
+}
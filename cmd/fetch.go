@@ -16,29 +16,48 @@ configured SIGs and time window, storing everything in the local SQLite database
 Does not run LLM analysis or generate reports.
 
 This is useful for populating the cache before running analysis, or for archiving
-raw data from OTel SIG sources.`,
+raw data from OTel SIG sources.
+
+Each run is recorded in the run ledger; --resume <run-id> continues an
+interrupted fetch without refetching SIGs that already completed.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Create the pipeline.
 		p, err := pipeline.New(cfg)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Fatal error: failed to create pipeline: %v\n", err)
-			os.Exit(2)
+			exit(2)
 		}
 		defer p.Close()
+		stopNotifier := startNotifier(p)
+
+		if resumeRunID != 0 {
+			_, err = p.ResumeRun(resumeRunID, "fetch")
+		} else {
+			_, err = p.BeginRun("fetch")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+			exit(2)
+		}
 
 		ctx := cmd.Context()
+		stopProgress := startProgress(p)
 
-		if err := p.FetchOnly(ctx); err != nil {
+		err = p.FetchOnly(ctx)
+		p.EndRun(err)
+		stopProgress()
+		stopNotifier()
+		if err != nil {
 			if pErr, ok := err.(*pipeline.PartialError); ok {
 				fmt.Fprintf(os.Stderr, "Warning: partial failure — %d source(s) failed:\n", len(pErr.Errors))
 				for _, e := range pErr.Errors {
 					fmt.Fprintf(os.Stderr, "  - %v\n", e)
 				}
 				fmt.Fprintf(os.Stdout, "\nFetch completed with partial data stored in: %s\n", cfg.DBPath)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
-			os.Exit(2)
+			exit(2)
 		}
 
 		fmt.Fprintf(os.Stdout, "Fetch completed successfully. Data stored in: %s\n", cfg.DBPath)
@@ -47,5 +66,6 @@ raw data from OTel SIG sources.`,
 }
 
 func init() {
+	fetchCmd.Flags().Int64Var(&resumeRunID, "resume", 0, "Resume an interrupted fetch run by ID")
 	rootCmd.AddCommand(fetchCmd)
 }
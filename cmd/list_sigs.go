@@ -26,7 +26,7 @@ from the OTel community GitHub repository.`,
 		db, err := store.New(cfg.DBPath)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
-			os.Exit(2)
+			exit(2)
 		}
 		defer db.Close()
 
@@ -54,7 +54,7 @@ from the OTel community GitHub repository.`,
 					fmt.Fprintf(os.Stderr, "Warning: could not refresh from GitHub: %v (using cached data)\n", fetchErr)
 				} else {
 					fmt.Fprintf(os.Stderr, "Error: could not fetch SIG registry: %v\n", fetchErr)
-					os.Exit(2)
+					exit(2)
 				}
 			} else {
 				// Store the fresh data.
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/notify"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+)
+
+// notifyCloseTimeout bounds how long a command waits at exit for pending
+// notifications to be delivered.
+const notifyCloseTimeout = 30 * time.Second
+
+// newNotifier creates a notifier for the configured hooks, or returns nil if
+// there are none.
+func newNotifier() *notify.Notifier {
+	if len(cfg.Notify) == 0 {
+		return nil
+	}
+	n, err := notify.New(cfg.Notify)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		exit(3)
+	}
+	return n
+}
+
+// closeNotifier delivers pending notifications and stops n.
+func closeNotifier(n *notify.Notifier) {
+	if n == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notifyCloseTimeout)
+	defer cancel()
+	if err := n.Close(ctx); err != nil {
+		slog.Warn("notifications not delivered", "err", err)
+	}
+}
+
+// startNotifier subscribes the configured hooks to p's events and returns a
+// function that delivers pending notifications and stops them.
+func startNotifier(p *pipeline.Pipeline) (stop func()) {
+	n := newNotifier()
+	if n == nil {
+		return func() {}
+	}
+	unsubscribe := p.Subscribe(n)
+	return func() {
+		unsubscribe()
+		closeNotifier(n)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/progress"
+)
+
+// startProgress shows p's progress on stderr as selected by --progress and
+// returns a function that stops the display, leaving its final state.
+func startProgress(p *pipeline.Pipeline) (stop func()) {
+	tty := progress.IsTerminal(os.Stderr)
+	switch cfg.Progress {
+	case "", "auto":
+	case "tty":
+		tty = true
+	case "plain":
+		tty = false
+	case "off":
+		return func() {}
+	default:
+		fmt.Fprintf(os.Stderr, "Configuration error: progress must be 'auto', 'tty', 'plain' or 'off', got %q\n", cfg.Progress)
+		exit(3)
+	}
+
+	r := progress.NewRenderer(os.Stderr, tty)
+	if tty && cfg.LogFile == "" {
+		// Route logs through the display so records scroll above the status
+		// block instead of through it.
+		level, _ := logging.ParseLevel(logLevel())
+		if logger, err := logging.New(r, cfg.LogFormat, level); err == nil {
+			slog.SetDefault(logger)
+			p.SetLogger(logger)
+		}
+	}
+
+	unsubscribe := p.Subscribe(r)
+	r.Start()
+	return func() {
+		unsubscribe()
+		r.Stop()
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/spf13/cobra"
+)
+
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "Inspect the LLM system prompts",
+	Long: `Inspect the system prompts used for summarizing, synthesizing and scoring.
+
+The prompts are built in and can be overridden by placing <name>.tmpl files
+in the directory given by --prompts-dir (prompts-dir in the config file).
+Each prompt is a text/template whose first line declares its version:
+
+  {{/* version: 2 */ -}}
+
+The rendered prompt is part of the analysis cache key, so an edited prompt
+is never served results produced by the old wording.`,
+}
+
+var promptsShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "List the prompts in effect, or print one",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prompts := loadPrompts()
+
+		if len(args) == 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tVERSION\tHASH\tSOURCE")
+			for _, p := range prompts.List() {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", p.Name, p.Version, p.Hash(), p.Source)
+			}
+			return w.Flush()
+		}
+
+		p, ok := prompts.Get(args[0])
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown prompt %q (see 'otel-sig-scraper prompts show')\n", args[0])
+			exit(3)
+		}
+		// The details go to stderr so the output can be saved as an override.
+		fmt.Fprintf(os.Stderr, "%s, version %d, hash %s, from %s\n", p.Name, p.Version, p.Hash(), p.Source)
+		fmt.Fprint(os.Stdout, p.Text)
+		return nil
+	},
+}
+
+var promptsDiffCmd = &cobra.Command{
+	Use:   "diff [name]",
+	Short: "Show how overridden prompts differ from the built-in ones",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		prompts := loadPrompts()
+
+		list := prompts.List()
+		if len(args) == 1 {
+			p, ok := prompts.Get(args[0])
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: unknown prompt %q (see 'otel-sig-scraper prompts show')\n", args[0])
+				exit(3)
+			}
+			list = []*analysis.Prompt{p}
+		}
+
+		changed := 0
+		for _, p := range list {
+			embedded, err := analysis.EmbeddedPrompt(p.Name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				exit(2)
+			}
+			if diff := analysis.DiffPrompts(embedded, p); diff != "" {
+				fmt.Fprint(os.Stdout, diff)
+				changed++
+			} else if p.Source != "embedded" {
+				fmt.Fprintf(os.Stdout, "%s is identical to the built-in prompt.\n", p.Source)
+			}
+		}
+		if changed == 0 && len(args) == 0 {
+			fmt.Fprintln(os.Stdout, "No prompts differ from the built-in ones.")
+		}
+		return nil
+	},
+}
+
+// loadPrompts loads the built-in prompts with the configured overrides,
+// exiting with a config error if the overrides are invalid.
+func loadPrompts() *analysis.Prompts {
+	prompts, err := analysis.LoadPrompts(cfg.PromptsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading prompts: %v\n", err)
+		exit(3)
+	}
+	return prompts
+}
+
+func init() {
+	promptsCmd.AddCommand(promptsShowCmd)
+	promptsCmd.AddCommand(promptsDiffCmd)
+	rootCmd.AddCommand(promptsCmd)
+}
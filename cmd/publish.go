@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/email"
+	"github.com/gordyrad/otel-sig-tracker/internal/publish"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Publish stored digests to external destinations",
+}
+
+var (
+	publishReportID   int64
+	publishToken      string
+	publishChannel    string
+	publishWebhookURL string
+	publishForce      bool
+)
+
+var publishSlackCmd = &cobra.Command{
+	Use:   "slack",
+	Short: "Post a digest to a Slack channel as Block Kit messages",
+	Long: `Posts a stored digest to Slack. The parent message holds the Top Takeaways
+and quiet SIGs; the per-SIG details follow as replies in its thread. Long
+digests are split across messages to stay within Slack's limits.
+
+Publishing with a bot token (chat:write scope) and --channel threads the
+replies. An incoming webhook cannot thread, so the details are posted to the
+channel after the parent message instead.
+
+The most recent digest is published unless --report selects one by ID.
+
+Environment variables:
+  SLACK_BOT_TOKEN    Bot token, if --token is not set
+  SLACK_CHANNEL      Channel, if --channel is not set
+  SLACK_WEBHOOK_URL  Incoming webhook URL, if --webhook-url is not set`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := publish.NewSlackPublisher(
+			flagOrEnv(publishToken, "SLACK_BOT_TOKEN"),
+			flagOrEnv(publishChannel, "SLACK_CHANNEL"),
+			flagOrEnv(publishWebhookURL, "SLACK_WEBHOOK_URL"),
+		)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		rec, err := storedDigest(db, publishReportID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(2)
+		}
+		digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: report %d: %v\n", rec.ID, err)
+			exit(2)
+		}
+
+		parent, replies := report.RenderSlackDigest(digest)
+		if _, err := p.Publish(cmd.Context(), parent, replies); err != nil {
+			fmt.Fprintf(os.Stderr, "Error publishing to Slack: %v\n", err)
+			exit(2)
+		}
+		fmt.Fprintf(os.Stdout, "Published digest %d (%s to %s) to Slack in %d message(s).\n",
+			rec.ID, digest.DateRangeStart, digest.DateRangeEnd, 1+len(replies))
+		return nil
+	},
+}
+
+var publishEmailCmd = &cobra.Command{
+	Use:   "email",
+	Short: "Email a digest to the configured subscribers",
+	Long: `Emails a stored digest through the SMTP server configured under "email" in
+config.yaml (see config.example.yaml). Each subscriber receives a multipart
+plain text and HTML message restricted to their SIGs; subscribers without
+any of their SIGs in the digest are skipped.
+
+Sends are recorded in the database, so running the command again only mails
+subscribers who have not received the report, or another digest of the same
+SIGs and week from an earlier run, yet. Use --force to send it to everyone
+again.
+
+The most recent digest is sent unless --report selects one by ID.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		m, err := email.New(cfg.Email, db)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+
+		rec, err := storedDigest(db, publishReportID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			exit(2)
+		}
+		digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: report %d: %v\n", rec.ID, err)
+			exit(2)
+		}
+
+		res, sendErr := m.SendDigest(cmd.Context(), rec, digest, publishForce)
+		fmt.Fprintf(os.Stdout, "Digest %d: sent to %d, already sent to %d, no subscribed SIGs for %d.\n",
+			rec.ID, len(res.Sent), len(res.AlreadySent), len(res.NoSIGs))
+		if sendErr != nil {
+			fmt.Fprintf(os.Stderr, "Error sending email: %v\n", sendErr)
+			if len(res.Sent) > 0 {
+				exit(1)
+			}
+			exit(2)
+		}
+		return nil
+	},
+}
+
+// storedDigest returns the digest report with id, or the latest one when id
+// is zero.
+func storedDigest(db *store.Store, id int64) (*store.Report, error) {
+	if id != 0 {
+		rec, err := db.GetReport(id)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("report %d not found", id)
+		} else if err != nil {
+			return nil, err
+		}
+		if rec.ReportType != "digest" {
+			return nil, fmt.Errorf("report %d is a %s report, not a digest", id, rec.ReportType)
+		}
+		if rec.Payload == "" {
+			return nil, fmt.Errorf("report %d has no stored content", id)
+		}
+		return rec, nil
+	}
+
+	recs, err := db.ListReports("digest", 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) == 0 {
+		return nil, fmt.Errorf("no digest reports stored; run \"report\" first")
+	}
+	if recs[0].Payload == "" {
+		return nil, fmt.Errorf("report %d has no stored content", recs[0].ID)
+	}
+	return recs[0], nil
+}
+
+// flagOrEnv returns the flag value, or the environment variable key when the
+// flag is empty.
+func flagOrEnv(v, key string) string {
+	if v != "" {
+		return v
+	}
+	return os.Getenv(key)
+}
+
+func init() {
+	f := publishSlackCmd.Flags()
+	f.Int64Var(&publishReportID, "report", 0, "Stored digest report ID to publish (default: latest)")
+	f.StringVar(&publishToken, "token", "", "Slack bot token (xoxb-...)")
+	f.StringVar(&publishChannel, "channel", "", "Channel to post to with the bot token (e.g., #otel-digest)")
+	f.StringVar(&publishWebhookURL, "webhook-url", "", "Slack incoming webhook URL")
+	publishCmd.AddCommand(publishSlackCmd)
+
+	ef := publishEmailCmd.Flags()
+	ef.Int64Var(&publishReportID, "report", 0, "Stored digest report ID to send (default: latest)")
+	ef.BoolVar(&publishForce, "force", false, "Send to subscribers who already received the report")
+	publishCmd.AddCommand(publishEmailCmd)
+	rootCmd.AddCommand(publishCmd)
+}
@@ -1,13 +1,26 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
 	"github.com/spf13/cobra"
 )
 
+var (
+	sinceLastReport bool
+	resumeRunID     int64
+	reportDryRun    bool
+	reportMaxCost   float64
+	reportMaxTokens int
+	reportBatch     bool
+)
+
 var reportCmd = &cobra.Command{
 	Use:   "report",
 	Short: "Generate intelligence reports for OTel SIGs",
@@ -15,27 +28,77 @@ var reportCmd = &cobra.Command{
 Markdown/JSON reports. Uses the pipeline to fetch meeting notes, video transcripts,
 and Slack discussions, then produces Datadog-focused intelligence reports.
 
+With --since-last-report, the window starts at the end of the previous digest
+for the same SIG set, and each item is marked NEW, UPDATED or ONGOING relative
+to that digest. Ongoing items are collapsed into a short list per SIG.
+
+Each run is recorded in the run ledger (see "runs list"). If a run is
+interrupted, --resume <run-id> continues it with its original settings and
+window, skipping SIGs whose fetch or analysis already completed.
+
+--max-cost and --max-tokens cap the projected LLM usage of the analysis. Over
+budget, video summaries are skipped; if the run would still exceed it, it is
+aborted before any LLM call. --dry-run prints the projection per analysis
+stage, from the data already in the store, without fetching or calling the
+LLM. Costs are approximate: each stage is priced at its model's list prices,
+or at a flat per-token rate for models the tool does not know.
+
+--batch sends the per-source summaries on Anthropic as one Message Batch and
+waits for it, which can take a while but costs about half as much; it suits
+scheduled weekly runs. Summaries the batch fails on are made individually.
+
 Exit codes:
   0 - Success
   1 - Partial failure (some sources failed, report generated from available data)
   2 - Fatal error (no data could be fetched, no report generated)
   3 - Configuration error`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg.SinceLastReport = sinceLastReport
+		cfg.MaxCost = reportMaxCost
+		cfg.MaxTokens = reportMaxTokens
+		if reportBatch {
+			cfg.LLM.Batch = true
+		}
+
 		// Validate configuration.
 		if err := cfg.Validate(); err != nil {
 			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
-			os.Exit(3)
+			exit(3)
 		}
 
 		// Create the pipeline.
 		p, err := pipeline.New(cfg)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Fatal error: failed to create pipeline: %v\n", err)
-			os.Exit(2)
+			exit(2)
 		}
 		defer p.Close()
 
+		if reportDryRun {
+			est, err := p.Estimate(cmd.Context())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+				exit(2)
+			}
+			printEstimate(est)
+			return nil
+		}
+
+		stopNotifier := startNotifier(p)
+
+		var run *store.Run
+		if resumeRunID != 0 {
+			run, err = p.ResumeRun(resumeRunID, "report")
+		} else {
+			run, err = p.BeginRun("report")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+			exit(2)
+		}
+
 		ctx := cmd.Context()
+		stopProgress := startProgress(p)
 
 		// If offline mode, run analysis only on cached data.
 		// Otherwise, run the full pipeline (fetch + analyze + report).
@@ -45,6 +108,9 @@ Exit codes:
 		} else {
 			runErr = p.Run(ctx)
 		}
+		p.EndRun(runErr)
+		stopProgress()
+		stopNotifier()
 
 		if runErr != nil {
 			// Determine if this is a partial or fatal failure.
@@ -54,10 +120,15 @@ Exit codes:
 					fmt.Fprintf(os.Stderr, "  - %v\n", e)
 				}
 				fmt.Fprintf(os.Stdout, "\nReports generated with available data in: %s\n", cfg.OutputDir)
-				os.Exit(1)
+				exit(1)
 			}
 			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", runErr)
-			os.Exit(2)
+			if errors.Is(runErr, pipeline.ErrBudgetExceeded) {
+				fmt.Fprintln(os.Stderr, "See the projection with: otel-sig-scraper report --dry-run")
+				exit(2)
+			}
+			fmt.Fprintf(os.Stderr, "Resume with: otel-sig-scraper report --resume %d\n", run.ID)
+			exit(2)
 		}
 
 		fmt.Fprintf(os.Stdout, "Reports generated successfully in: %s\n", cfg.OutputDir)
@@ -66,5 +137,33 @@ Exit codes:
 }
 
 func init() {
+	reportCmd.Flags().Int64Var(&resumeRunID, "resume", 0, "Resume an interrupted run by ID")
+	reportCmd.Flags().BoolVar(&sinceLastReport, "since-last-report", false, "Only report changes since the previous digest for the same SIGs")
+	reportCmd.Flags().BoolVar(&reportDryRun, "dry-run", false, "Print the projected LLM calls, tokens and cost per stage without running")
+	reportCmd.Flags().Float64Var(&reportMaxCost, "max-cost", 0, "Budget in US dollars for the run's LLM calls (0 = no limit)")
+	reportCmd.Flags().IntVar(&reportMaxTokens, "max-tokens", 0, "Budget in tokens for the run's LLM calls (0 = no limit)")
+	reportCmd.Flags().BoolVar(&reportBatch, "batch", false, "Summarize sources through the Anthropic Message Batches API (slower, about half the cost)")
 	rootCmd.AddCommand(reportCmd)
 }
+
+// printEstimate writes the projected LLM usage of a report run as a table.
+func printEstimate(est *pipeline.Estimate) {
+	fmt.Fprintf(os.Stdout, "Projected LLM usage for %s to %s:\n\n",
+		est.Start.Format("2006-01-02"), est.End.Format("2006-01-02"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STAGE\tMODEL\tCALLS\tCACHE HITS\tINPUT TOKENS\tOUTPUT TOKENS\tCOST")
+	for _, s := range est.Stages {
+		fmt.Fprintf(w, "%s\t%s/%s\t%d\t%d\t%d\t%d\t$%.2f\n", s.Stage, s.Provider, s.Model,
+			s.Calls, s.CacheHits, s.InputTokens, s.OutputTokens, s.EstimatedCostUSD)
+	}
+	t := est.Total()
+	fmt.Fprintf(w, "total\t\t%d\t%d\t%d\t%d\t$%.2f\n", t.Calls, t.CacheHits, t.InputTokens, t.OutputTokens, t.EstimatedCostUSD)
+	w.Flush()
+
+	switch {
+	case est.OverBudget:
+		fmt.Fprintf(os.Stdout, "\nOver budget even without %s summaries: the run would be aborted.\n", strings.Join(est.Skipped, ", "))
+	case len(est.Skipped) > 0:
+		fmt.Fprintf(os.Stdout, "\nOver budget: %s summaries would be skipped.\n", strings.Join(est.Skipped, ", "))
+	}
+}
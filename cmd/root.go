@@ -1,10 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -19,6 +26,54 @@ and Slack discussions, then uses an LLM to produce Markdown intelligence reports
 focused on topics relevant to Datadog.`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		_, closeLog, err := logging.Setup(logging.Options{File: cfg.LogFile, Format: cfg.LogFormat, Level: logLevel()})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+		closeLogFile = closeLog
+
+		shutdown, err := telemetry.Setup(cmd.Context(), cfg.OTLPEndpoint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+			exit(3)
+		}
+		shutdownTelemetry = shutdown
+		return nil
+	},
+}
+
+// shutdownTelemetry flushes and stops the telemetry providers installed for
+// the running command.
+var shutdownTelemetry = func(context.Context) error { return nil }
+
+// closeLogFile closes the --log-file, if any.
+var closeLogFile = func() error { return nil }
+
+// flushTelemetry exports any buffered spans and metrics, giving up after a
+// few seconds so an unreachable collector never blocks exit.
+func flushTelemetry() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTelemetry(ctx); err != nil {
+		slog.Warn("flushing telemetry", "err", err)
+	}
+}
+
+// logLevel returns the configured log level, honoring --verbose.
+func logLevel() string {
+	if cfg.LogLevel == "" && cfg.Verbose {
+		return "debug"
+	}
+	return cfg.LogLevel
+}
+
+// exit flushes telemetry and exits with code, so failed runs are exported too.
+func exit(code int) {
+	flushTelemetry()
+	_ = closeLogFile()
+	os.Exit(code)
 }
 
 func init() {
@@ -26,10 +81,16 @@ func init() {
 
 	pf := rootCmd.PersistentFlags()
 	pf.String("lookback", "7d", "How far back to look (e.g., 7d, 2w, 1m)")
+	pf.String("since", "", "Start of an absolute window, inclusive (YYYY-MM-DD)")
+	pf.String("until", "", "End of an absolute window, inclusive (YYYY-MM-DD)")
+	pf.String("week", "", "ISO week to report on, Monday to Sunday (e.g., 2026-W41)")
+	pf.String("timezone", "", "IANA time zone for day boundaries (default: local)")
 	pf.StringSlice("sigs", nil, "Comma-separated SIG names to process")
 	pf.StringSlice("topics", nil, "Comma-separated topic filters")
 	pf.String("output-dir", "./reports", "Output directory for reports")
-	pf.String("format", "markdown", "Output format: markdown, json")
+	pf.String("format", "markdown", "Output format: markdown, json, html")
+	pf.String("template", "", "text/template file for the Markdown digest layout (default: built-in)")
+	pf.String("prompts-dir", "", "Directory of <name>.tmpl files overriding the built-in LLM prompts")
 	pf.String("llm-provider", "anthropic", "LLM provider: anthropic, openai")
 	pf.String("llm-model", "claude-sonnet-4-20250514", "LLM model to use")
 	pf.String("anthropic-api-key", "", "Anthropic API key")
@@ -42,15 +103,20 @@ func init() {
 	pf.Bool("skip-slack", false, "Skip Slack fetching")
 	pf.Bool("skip-notes", false, "Skip Google Docs meeting notes")
 	pf.Bool("offline", false, "Use only cached data")
-	pf.Bool("verbose", false, "Verbose logging")
+	pf.Bool("verbose", false, "Verbose logging (same as --log-level debug)")
+	pf.String("log-file", "", "Write logs to this file instead of stderr")
+	pf.String("log-format", "text", "Log format: text, json")
+	pf.String("log-level", "", "Log level: debug, info, warn, error (default: info)")
+	pf.String("progress", "auto", "Progress display for long runs: auto, tty, plain, off")
 	pf.String("config", "", "Path to YAML config file")
+	pf.String("otlp-endpoint", "", "OTLP/HTTP endpoint for the tool's own traces and metrics (e.g., http://localhost:4318)")
 
 	// Bind flags to viper
 	flags := []string{
-		"lookback", "sigs", "topics", "output-dir", "format",
+		"lookback", "since", "until", "week", "timezone", "sigs", "topics", "output-dir", "format", "template", "prompts-dir",
 		"llm-provider", "llm-model", "anthropic-api-key", "openai-api-key",
 		"slack-creds", "context-file", "db-path", "workers",
-		"skip-videos", "skip-slack", "skip-notes", "offline", "verbose", "config",
+		"skip-videos", "skip-slack", "skip-notes", "offline", "verbose", "log-file", "log-format", "log-level", "progress", "config", "otlp-endpoint",
 	}
 	for _, f := range flags {
 		_ = viper.BindPFlag(f, pf.Lookup(f))
@@ -76,6 +142,7 @@ func initConfig() {
 	_ = viper.BindEnv("anthropic-api-key", "ANTHROPIC_API_KEY")
 	_ = viper.BindEnv("openai-api-key", "OPENAI_API_KEY")
 	_ = viper.BindEnv("lookback", "OTEL_LOOKBACK")
+	_ = viper.BindEnv("timezone", "OTEL_TIMEZONE")
 	_ = viper.BindEnv("output-dir", "OTEL_OUTPUT_DIR")
 	_ = viper.BindEnv("format", "OTEL_FORMAT")
 	_ = viper.BindEnv("llm-provider", "OTEL_LLM_PROVIDER")
@@ -83,6 +150,7 @@ func initConfig() {
 	_ = viper.BindEnv("db-path", "OTEL_DB_PATH")
 	_ = viper.BindEnv("workers", "OTEL_WORKERS")
 	_ = viper.BindEnv("verbose", "OTEL_VERBOSE")
+	_ = viper.BindEnv("log-level", "OTEL_LOG_LEVEL")
 	_ = viper.BindEnv("slack-creds", "OTEL_SLACK_CREDS")
 	_ = viper.BindEnv("context-file", "OTEL_CONTEXT_FILE")
 
@@ -93,6 +161,15 @@ func initConfig() {
 		if d, err := config.ParseLookback(v); err == nil {
 			cfg.Lookback = d
 		}
+		if months, ok := config.ParseLookbackMonths(v); ok {
+			cfg.LookbackMonths = months
+		}
+	}
+	cfg.Since = viper.GetString("since")
+	cfg.Until = viper.GetString("until")
+	cfg.Week = viper.GetString("week")
+	if v := viper.GetString("timezone"); v != "" {
+		cfg.Timezone = v
 	}
 	if v := viper.GetStringSlice("sigs"); len(v) > 0 {
 		cfg.SIGs = v
@@ -106,6 +183,8 @@ func initConfig() {
 	if v := viper.GetString("format"); v != "" {
 		cfg.Format = v
 	}
+	cfg.Template = viper.GetString("template")
+	cfg.PromptsDir = viper.GetString("prompts-dir")
 	if v := viper.GetString("llm-provider"); v != "" {
 		cfg.LLM.Provider = v
 	}
@@ -135,11 +214,39 @@ func initConfig() {
 	cfg.SkipNotes = viper.GetBool("skip-notes")
 	cfg.Offline = viper.GetBool("offline")
 	cfg.Verbose = viper.GetBool("verbose")
+	cfg.OTLPEndpoint = viper.GetString("otlp-endpoint")
+	cfg.LogFile = viper.GetString("log-file")
+	cfg.LogFormat = viper.GetString("log-format")
+	cfg.LogLevel = viper.GetString("log-level")
+	cfg.Progress = viper.GetString("progress")
+	_ = viper.UnmarshalKey("notify", &cfg.Notify)
+	_ = viper.UnmarshalKey("email", &cfg.Email)
+	_ = viper.UnmarshalKey("feeds", &cfg.Feeds)
+	_ = viper.UnmarshalKey("profiles", &cfg.Profiles)
+	_ = viper.UnmarshalKey("cache", &cfg.Cache)
+	_ = viper.UnmarshalKey("retention", &cfg.Retention)
+	_ = viper.UnmarshalKey("llm.stages", &cfg.LLM.Stages)
+	_ = viper.UnmarshalKey("llm.fallbacks", &cfg.LLM.Fallbacks)
+	cfg.LLM.Batch = viper.GetBool("llm.batch")
+	if cfg.Email.SMTP.Password == "" {
+		cfg.Email.SMTP.Password = os.Getenv("SMTP_PASSWORD")
+	}
 }
 
-// Execute runs the root command.
+// Execute runs the root command. The first interrupt cancels the command's
+// context so runs can stop cleanly; a second one exits immediately.
 func Execute() error {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	flushTelemetry()
+	_ = closeLogFile()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
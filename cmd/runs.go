@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Inspect past fetch and report runs",
+	Long: `Inspect the run ledger. Every fetch and report run records its settings,
+date window, status, and the per-SIG status of each stage, so interrupted
+runs can be diagnosed and continued with --resume.`,
+}
+
+var runsListLimit int
+
+var runsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recent runs",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		runs, err := db.ListRuns(runsListLimit)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing runs: %v\n", err)
+			exit(2)
+		}
+		if len(runs) == 0 {
+			fmt.Fprintln(os.Stdout, "No runs recorded.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCOMMAND\tSTATUS\tWINDOW\tSIGS\tSTARTED")
+		for _, r := range runs {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", r.ID, r.Command, r.Status,
+				formatRunWindow(r), r.SIGSet, r.StartedAt.Local().Format("2006-01-02 15:04"))
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var runsShowCmd = &cobra.Command{
+	Use:   "show <run-id>",
+	Short: "Show a run's settings and per-SIG progress",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid run ID %q\n", args[0])
+			exit(3)
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		run, err := db.GetRun(id)
+		if err == sql.ErrNoRows {
+			fmt.Fprintf(os.Stderr, "Error: run %d not found\n", id)
+			exit(1)
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading run: %v\n", err)
+			exit(2)
+		}
+		steps, err := db.GetRunSteps(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading run steps: %v\n", err)
+			exit(2)
+		}
+
+		fmt.Fprintf(os.Stdout, "Run %d (%s)\n", run.ID, run.Command)
+		fmt.Fprintf(os.Stdout, "  Status:   %s\n", run.Status)
+		if run.Error != "" {
+			fmt.Fprintf(os.Stdout, "  Error:    %s\n", run.Error)
+		}
+		fmt.Fprintf(os.Stdout, "  Window:   %s\n", formatRunWindow(run))
+		fmt.Fprintf(os.Stdout, "  SIGs:     %s\n", run.SIGSet)
+		fmt.Fprintf(os.Stdout, "  Started:  %s\n", run.StartedAt.Local().Format(time.RFC3339))
+		if !run.FinishedAt.IsZero() {
+			fmt.Fprintf(os.Stdout, "  Finished: %s\n", run.FinishedAt.Local().Format(time.RFC3339))
+		}
+		fmt.Fprintf(os.Stdout, "  Config:   %s\n", run.Config)
+
+		if len(steps) == 0 {
+			fmt.Fprintln(os.Stdout, "\nNo SIG steps recorded.")
+			return nil
+		}
+
+		fmt.Fprintln(os.Stdout)
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SIG\tSTAGE\tSTATUS\tERROR")
+		for _, st := range steps {
+			errMsg := st.Error
+			if errMsg == "" {
+				errMsg = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.SIGID, st.Stage, st.Status, errMsg)
+		}
+		w.Flush()
+
+		if run.Status != store.RunCompleted {
+			fmt.Fprintf(os.Stdout, "\nResume with: otel-sig-scraper %s --resume %d\n", run.Command, run.ID)
+		}
+		return nil
+	},
+}
+
+// formatRunWindow renders a run's date window as "start to end", in the time
+// zone the run was configured with.
+func formatRunWindow(r *store.Run) string {
+	loc := time.Local
+	var rc struct {
+		Timezone string `json:"timezone"`
+	}
+	if json.Unmarshal([]byte(r.Config), &rc) == nil && rc.Timezone != "" {
+		if l, err := time.LoadLocation(rc.Timezone); err == nil {
+			loc = l
+		}
+	}
+	return fmt.Sprintf("%s to %s",
+		r.DateRangeStart.In(loc).Format("2006-01-02"), r.DateRangeEnd.In(loc).Format("2006-01-02"))
+}
+
+func init() {
+	runsListCmd.Flags().IntVar(&runsListLimit, "limit", 20, "Maximum number of runs to list (0 for all)")
+
+	runsCmd.AddCommand(runsListCmd)
+	runsCmd.AddCommand(runsShowCmd)
+
+	rootCmd.AddCommand(runsCmd)
+}
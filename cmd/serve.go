@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/server"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr  string
+	serveToken string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a JSON HTTP API for the web UI",
+	Long: `Starts an HTTP server exposing the local database and pipeline as a JSON API:
+
+  GET  /api/sigs                      List SIGs
+  GET  /api/sigs/{id}                 Get a SIG
+  GET  /api/sigs/{id}/notes           Meeting notes (?since=&until=&week=&lookback=)
+  GET  /api/sigs/{id}/transcripts     Video transcripts (same window parameters)
+  GET  /api/sigs/{id}/messages        Slack messages (same window parameters)
+  POST /api/jobs                      Start a fetch or report run asynchronously
+  GET  /api/jobs, /api/jobs/{id}      Job status and live progress
+  GET  /api/runs, /api/runs/{id}      Run ledger with per-SIG steps
+  GET  /api/reports, /api/reports/{id} Stored reports (?type=digest), with payload
+  GET  /api/search?q=&sig=            Search notes, transcripts and messages
+
+All /api requests require "Authorization: Bearer <token>", with the token
+given by --token or OTEL_API_TOKEN. Jobs run one at a time.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token := serveToken
+		if token == "" {
+			token = os.Getenv("OTEL_API_TOKEN")
+		}
+		if token == "" {
+			fmt.Fprintln(os.Stderr, "Configuration error: an API token is required (--token or OTEL_API_TOKEN)")
+			exit(3)
+		}
+
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		ctx := cmd.Context()
+		srv := server.New(cfg, db, token)
+		notifier := newNotifier()
+		defer closeNotifier(notifier)
+		if notifier != nil {
+			srv.AddObserver(notifier)
+		}
+		httpServer := &http.Server{
+			Addr:              serveAddr,
+			Handler:           srv.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			srv.RunJobs(ctx)
+		}()
+
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if err := httpServer.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("server shutdown", "err", err)
+			}
+		}()
+
+		fmt.Fprintf(os.Stdout, "Serving API on http://%s\n", serveAddr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+			exit(2)
+		}
+
+		// Wait for the job worker to record any interrupted run.
+		wg.Wait()
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "Bearer token required by API clients (or OTEL_API_TOKEN)")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/site"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var siteCmd = &cobra.Command{
+	Use:   "site",
+	Short: "Build a static website from the report archive",
+}
+
+var (
+	siteOutDir string
+	siteFull   bool
+)
+
+var siteBuildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build the static site from all stored digests",
+	Long: `Builds a static website from every digest stored in the database:
+
+  index.html          Digests grouped by ISO week, newest first
+  digests/            One page per digest, as in --format html
+  sigs/<id>.html      Each SIG's items across all digests
+  tags/<topic>.html   Every item about a topic, across SIGs and weeks
+  search.html         Client-side search over all items (search-index.js)
+
+The output directory can be served by any static file server or opened
+from disk. Builds are incremental: a manifest in the output directory
+records what each page was built from, so only pages for new or changed
+reports, and the SIG and topic pages they touch, are written again. Use
+--full to rebuild everything.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := store.New(cfg.DBPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening database: %v\n", err)
+			exit(2)
+		}
+		defer db.Close()
+
+		res, err := site.New(db, siteOutDir).Build(siteFull)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building site: %v\n", err)
+			exit(2)
+		}
+		fmt.Fprintf(os.Stdout, "Site built in %s: %d digest, %d SIG and %d topic pages written, %d digests unchanged, %d pages removed.\n",
+			siteOutDir, res.DigestPages, res.SIGPages, res.TagPages, res.Unchanged, res.Removed)
+		return nil
+	},
+}
+
+func init() {
+	f := siteBuildCmd.Flags()
+	f.StringVar(&siteOutDir, "out", "./site", "Output directory for the site")
+	f.BoolVar(&siteFull, "full", false, "Rebuild every page, not just those for new reports")
+	siteCmd.AddCommand(siteBuildCmd)
+	rootCmd.AddCommand(siteCmd)
+}
@@ -31,18 +31,18 @@ expires.`,
 
 		if err := sources.SlackLogin(ctx, credsFile); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Slack login failed: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Load the newly saved credentials to display details.
 		creds, err := sources.LoadSlackCredentials(credsFile)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: could not load saved credentials: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		if creds == nil {
 			fmt.Fprintf(os.Stderr, "Error: credentials file not found after login: %s\n", credsFile)
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Fprintln(os.Stdout, "Slack login successful!")
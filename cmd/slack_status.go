@@ -23,12 +23,12 @@ Displays the authenticated user, team name, and token validity status.`,
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Not authenticated: %v\n", err)
 			fmt.Fprintf(os.Stderr, "\nRun 'otel-sig-scraper slack-login' to authenticate.\n")
-			os.Exit(1)
+			exit(1)
 		}
 		if creds == nil {
 			fmt.Fprintf(os.Stderr, "Not authenticated: no credentials found at %s\n", credsFile)
 			fmt.Fprintf(os.Stderr, "\nRun 'otel-sig-scraper slack-login' to authenticate.\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		// Validate credentials with Slack API.
@@ -36,7 +36,7 @@ Displays the authenticated user, team name, and token validity status.`,
 			fmt.Fprintln(os.Stdout, "Slack credentials found but invalid or expired.")
 			fmt.Fprintf(os.Stderr, "  Error: %v\n", err)
 			fmt.Fprintf(os.Stderr, "\nRun 'otel-sig-scraper slack-login' to re-authenticate.\n")
-			os.Exit(1)
+			exit(1)
 		}
 
 		fmt.Fprintln(os.Stdout, "Slack authentication status: valid")
@@ -2,14 +2,29 @@ package analysis
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
+	anthropic "github.com/liushuangls/go-anthropic/v2"
+	openai "github.com/sashabaranov/go-openai"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // mockLLMClient implements LLMClient for testing.
@@ -17,10 +32,12 @@ type mockLLMClient struct {
 	response  string
 	err       error
 	callCount atomic.Int64
+	lastReq   atomic.Pointer[CompletionRequest]
 }
 
 func (m *mockLLMClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
 	m.callCount.Add(1)
+	m.lastReq.Store(req)
 	if m.err != nil {
 		return nil, m.err
 	}
@@ -608,6 +625,261 @@ func TestRelevanceScorer_WithCustomContext(t *testing.T) {
 	}
 }
 
+func TestRelevanceScorer_Profile(t *testing.T) {
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: mockRelevanceResponse}
+	profile := &RelevanceProfile{
+		Name:     "logs",
+		Audience: "the Datadog Logs team",
+		Criteria: []string{"Changes to the log data model or log bridge API"},
+		Keywords: []string{"log bridge", "event API"},
+		Rubric:   Rubric{High: "Breaks log ingest", Low: "Docs only"},
+	}
+	scorer := NewRelevanceScorer(mock, s, "custom context is not used by profiles")
+	scorer.SetProfile(profile)
+
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	synthesis := &SynthesizedReport{SIGID: "logs", SIGName: "Logs", Synthesis: "The Logs SIG stabilized the event API."}
+
+	result, err := scorer.Score(context.Background(), "logs", "Logs", synthesis, start, end)
+	if err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+	if len(result.HighItems) != 2 {
+		t.Errorf("HighItems count = %d, want 2", len(result.HighItems))
+	}
+
+	req := mock.lastReq.Load()
+	for _, want := range []string{
+		"intelligence brief for the Datadog Logs team.",
+		"- Changes to the log data model or log bridge API\n",
+		"- event API\n",
+		"- HIGH: Breaks log ingest\n",
+		"- LOW: Docs only\n",
+		"#### HIGH Relevance",
+	} {
+		if !strings.Contains(req.SystemPrompt, want) {
+			t.Errorf("system prompt missing %q:\n%s", want, req.SystemPrompt)
+		}
+	}
+	for _, unwanted := range []string{"Datadog engineering leaders", "MEDIUM:", "custom context"} {
+		if strings.Contains(req.SystemPrompt, unwanted) {
+			t.Errorf("system prompt should not contain %q", unwanted)
+		}
+	}
+	if !strings.Contains(req.UserPrompt, "Produce a relevance report for the Datadog Logs team for the Logs SIG") {
+		t.Errorf("user prompt = %q", req.UserPrompt)
+	}
+
+	// The built-in scorer does not reuse the profile's cached scores.
+	if _, err := NewRelevanceScorer(mock, s, "").Score(context.Background(), "logs", "Logs", synthesis, start, end); err != nil {
+		t.Fatalf("default Score failed: %v", err)
+	}
+	if mock.callCount.Load() != 2 {
+		t.Errorf("expected a separate LLM call for the default profile, got %d calls", mock.callCount.Load())
+	}
+
+	// Neither does the profile once its rubric changes.
+	profile.Rubric.High = "Breaks log ingest or parsing"
+	if _, err := scorer.Score(context.Background(), "logs", "Logs", synthesis, start, end); err != nil {
+		t.Fatalf("Score after rubric change failed: %v", err)
+	}
+	if mock.callCount.Load() != 3 {
+		t.Errorf("expected a new LLM call after the rubric changed, got %d calls", mock.callCount.Load())
+	}
+	if _, err := scorer.Score(context.Background(), "logs", "Logs", synthesis, start, end); err != nil {
+		t.Fatalf("cached Score failed: %v", err)
+	}
+	if mock.callCount.Load() != 3 {
+		t.Errorf("expected the unchanged profile to hit the cache, got %d calls", mock.callCount.Load())
+	}
+}
+
+func TestRelevanceScorer_ScoreWithPrior_Tags(t *testing.T) {
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: `#### HIGH Relevance
+- [NEW] **Profiling Signal** — New profiling data model proposal.
+- [UPDATED] **OTLP/HTTP Partial Success** — OTEP moved to final review.
+
+#### MEDIUM Relevance
+- [ONGOING] **Pipeline Fan-out/Fan-in** — Architectural change for fan-out patterns.
+
+#### LOW Relevance
+None this period.`}
+	scorer := NewRelevanceScorer(mock, s, "")
+
+	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+	synthesis := &SynthesizedReport{SIGID: "collector", SIGName: "Collector", Synthesis: "Synthesis text."}
+	prior := []PriorItem{
+		{Level: "high", Topic: "OTLP/HTTP Partial Success", Text: "**OTLP/HTTP Partial Success** — OTEP draft opened."},
+		{Level: "medium", Topic: "Pipeline Fan-out/Fan-in", Text: "**Pipeline Fan-out/Fan-in** — Architectural change for fan-out patterns."},
+	}
+
+	result, err := scorer.ScoreWithPrior(context.Background(), "collector", "Collector", synthesis, start, end, prior)
+	if err != nil {
+		t.Fatalf("ScoreWithPrior failed: %v", err)
+	}
+
+	if len(result.HighItems) != 2 {
+		t.Fatalf("HighItems count = %d, want 2", len(result.HighItems))
+	}
+	if result.HighItems[0] != "**Profiling Signal** — New profiling data model proposal." {
+		t.Errorf("status tag should be stripped, got %q", result.HighItems[0])
+	}
+	if got := result.ItemStatuses[result.HighItems[0]]; got != ItemNew {
+		t.Errorf("first item status = %q, want NEW", got)
+	}
+	if got := result.ItemStatuses[result.HighItems[1]]; got != ItemUpdated {
+		t.Errorf("second item status = %q, want UPDATED", got)
+	}
+	if len(result.MediumItems) != 0 {
+		t.Errorf("ONGOING item should be removed from MediumItems, got %v", result.MediumItems)
+	}
+	if len(result.OngoingItems) != 1 || ItemTopic(result.OngoingItems[0]) != "Pipeline Fan-out/Fan-in" {
+		t.Errorf("OngoingItems = %v", result.OngoingItems)
+	}
+
+	// A different prior set must not be served from the plain Score cache entry.
+	if _, err := scorer.Score(context.Background(), "collector", "Collector", synthesis, start, end); err != nil {
+		t.Fatalf("Score failed: %v", err)
+	}
+	if mock.callCount.Load() != 2 {
+		t.Errorf("expected 2 LLM calls for different prior sets, got %d", mock.callCount.Load())
+	}
+}
+
+func TestRelevanceScorer_ScoreWithPrior_UntaggedFallback(t *testing.T) {
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: mockRelevanceResponse}
+	scorer := NewRelevanceScorer(mock, s, "")
+
+	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC)
+	synthesis := &SynthesizedReport{SIGID: "collector", SIGName: "Collector", Synthesis: "Synthesis text."}
+	prior := []PriorItem{
+		// Same topic, same text: ongoing.
+		{Level: "low", Topic: "Docs Updates", Text: "**Docs Updates** — Documentation updates for contributing guide."},
+		// Same topic, different text: updated.
+		{Level: "high", Topic: "OTLP/HTTP Partial Success", Text: "**OTLP/HTTP Partial Success** — Initial proposal."},
+	}
+
+	result, err := scorer.ScoreWithPrior(context.Background(), "collector", "Collector", synthesis, start, end, prior)
+	if err != nil {
+		t.Fatalf("ScoreWithPrior failed: %v", err)
+	}
+
+	if got := result.ItemStatuses[result.HighItems[0]]; got != ItemUpdated {
+		t.Errorf("OTLP item status = %q, want UPDATED", got)
+	}
+	if got := result.ItemStatuses[result.HighItems[1]]; got != ItemNew {
+		t.Errorf("semconv item status = %q, want NEW", got)
+	}
+	if len(result.LowItems) != 1 {
+		t.Errorf("LowItems count = %d, want 1", len(result.LowItems))
+	}
+	if len(result.OngoingItems) != 1 {
+		t.Errorf("OngoingItems count = %d, want 1", len(result.OngoingItems))
+	}
+}
+
+func TestEstimate_FollowsCache(t *testing.T) {
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: mockRelevanceResponse}
+	summarizer := NewSummarizer(mock, s)
+	synthesizer := NewSynthesizer(mock, s)
+	scorer := NewRelevanceScorer(mock, s, "")
+
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	notes := []*store.MeetingNote{{
+		SIGID:       "collector",
+		DocID:       "doc123",
+		MeetingDate: time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC),
+		RawText:     "Meeting notes for the estimate test.",
+	}}
+
+	// Before any analysis, every stage would call the LLM.
+	est, err := summarizer.EstimateMeetingNotes("collector", "Collector", notes, start, end)
+	if err != nil {
+		t.Fatalf("EstimateMeetingNotes: %v", err)
+	}
+	if est.Cached || est.SourceType != "notes" {
+		t.Errorf("estimate = %+v, want an uncached notes call", est)
+	}
+	if est.InputTokens < EstimateTokens(notes[0].RawText) {
+		t.Errorf("InputTokens = %d, want at least the notes' %d", est.InputTokens, EstimateTokens(notes[0].RawText))
+	}
+	if mock.callCount.Load() != 0 {
+		t.Fatalf("estimating made %d LLM calls, want none", mock.callCount.Load())
+	}
+
+	// After analysis, the estimates find the cached results.
+	summary, err := summarizer.SummarizeMeetingNotes(context.Background(), "collector", "Collector", notes, start, end)
+	if err != nil {
+		t.Fatalf("SummarizeMeetingNotes: %v", err)
+	}
+	synthesis, err := synthesizer.Synthesize(context.Background(), "collector", "Collector", []*SourceSummary{summary}, start, end)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if _, err := scorer.Score(context.Background(), "collector", "Collector", synthesis, start, end); err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+
+	est, err = summarizer.EstimateMeetingNotes("collector", "Collector", notes, start, end)
+	if err != nil {
+		t.Fatalf("EstimateMeetingNotes: %v", err)
+	}
+	if !est.Cached || est.Result != summary.Summary {
+		t.Errorf("notes estimate = %+v, want the cached summary", est)
+	}
+	est, err = synthesizer.Estimate("collector", "Collector", []*SourceSummary{{SourceType: "notes", Summary: est.Result}}, start, end)
+	if err != nil {
+		t.Fatalf("Synthesizer.Estimate: %v", err)
+	}
+	if !est.Cached || est.Result != synthesis.Synthesis {
+		t.Errorf("synthesis estimate = %+v, want the cached synthesis", est)
+	}
+	est, err = scorer.Estimate("collector", "Collector", &SynthesizedReport{Synthesis: est.Result}, start, end, nil)
+	if err != nil {
+		t.Fatalf("RelevanceScorer.Estimate: %v", err)
+	}
+	if !est.Cached || est.SourceType != "relevance" {
+		t.Errorf("relevance estimate = %+v, want a cached relevance call", est)
+	}
+
+	// Prior items change the relevance request, so it is not cached.
+	est, err = scorer.Estimate("collector", "Collector", synthesis, start, end, []PriorItem{{Level: "high", Topic: "earlier item", Text: "Earlier item"}})
+	if err != nil {
+		t.Fatalf("RelevanceScorer.Estimate: %v", err)
+	}
+	if est.Cached {
+		t.Error("relevance estimate with prior items should not be cached")
+	}
+	if mock.callCount.Load() != 3 {
+		t.Errorf("LLM calls = %d, want only the 3 of the analysis", mock.callCount.Load())
+	}
+}
+
+func TestItemTopic(t *testing.T) {
+	tests := []struct {
+		item string
+		want string
+	}{
+		{"**OTLP/HTTP Partial Success** — New support.", "OTLP/HTTP Partial Success"},
+		{"Sampling — tail sampling changes", "Sampling"},
+		{"Entities: resource lifecycle", "Entities"},
+		{"Plain item", "Plain item"},
+	}
+	for _, tt := range tests {
+		if got := ItemTopic(tt.item); got != tt.want {
+			t.Errorf("ItemTopic(%q) = %q, want %q", tt.item, got, tt.want)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // parseRelevanceItems tests
 // ---------------------------------------------------------------------------
@@ -723,11 +995,20 @@ func TestParseRelevanceItems_NoSections(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// buildRelevanceSystemPrompt tests
+// Relevance system prompt tests
 // ---------------------------------------------------------------------------
 
-func TestBuildRelevanceSystemPrompt_NoCustomContext(t *testing.T) {
-	prompt := buildRelevanceSystemPrompt("")
+func renderRelevancePrompt(t *testing.T, customContext string) string {
+	t.Helper()
+	prompt, err := DefaultPrompts().render(PromptRelevance, &promptData{CustomContext: customContext})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	return prompt
+}
+
+func TestRelevanceSystemPrompt_NoCustomContext(t *testing.T) {
+	prompt := renderRelevancePrompt(t, "")
 
 	// Should contain concise brief language.
 	if !containsStr(prompt, "intelligence brief") {
@@ -745,8 +1026,8 @@ func TestBuildRelevanceSystemPrompt_NoCustomContext(t *testing.T) {
 	}
 }
 
-func TestBuildRelevanceSystemPrompt_WithCustomContext(t *testing.T) {
-	prompt := buildRelevanceSystemPrompt("Focus on profiling signal.")
+func TestRelevanceSystemPrompt_WithCustomContext(t *testing.T) {
+	prompt := renderRelevancePrompt(t, "Focus on profiling signal.")
 
 	if !containsStr(prompt, "Additional Context from User") {
 		t.Error("prompt should contain custom context section")
@@ -756,6 +1037,184 @@ func TestBuildRelevanceSystemPrompt_WithCustomContext(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// Prompt loading tests
+// ---------------------------------------------------------------------------
+
+func TestDefaultPrompts(t *testing.T) {
+	ps := DefaultPrompts()
+	for _, name := range []string{PromptNotes, PromptVideo, PromptSlack, PromptSynthesis, PromptRelevance, PromptRelevanceProfile, PromptRelevanceFormat} {
+		p, ok := ps.Get(name)
+		if !ok {
+			t.Errorf("prompt %s not embedded", name)
+			continue
+		}
+		if p.Version < 1 || p.Source != "embedded" || len(p.Hash()) != 12 {
+			t.Errorf("prompt %s = version %d, source %q, hash %q", name, p.Version, p.Source, p.Hash())
+		}
+	}
+	if len(ps.List()) != 7 {
+		t.Errorf("List() has %d prompts, want 7", len(ps.List()))
+	}
+}
+
+func TestLoadPrompts_Override(t *testing.T) {
+	dir := t.TempDir()
+	override := "{{/* version: 2 */ -}}\nSummarize the {{.SIGName}} notes from {{.Start}}.\n"
+	if err := os.WriteFile(filepath.Join(dir, "notes.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Other files are ignored.
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ps, err := LoadPrompts(dir)
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	p, _ := ps.Get(PromptNotes)
+	if p.Version != 2 || p.Source != filepath.Join(dir, "notes.tmpl") {
+		t.Errorf("notes prompt = version %d from %s", p.Version, p.Source)
+	}
+	if p, _ := ps.Get(PromptSlack); p.Source != "embedded" {
+		t.Errorf("slack prompt source = %s, want embedded", p.Source)
+	}
+
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: "Summary."}
+	summarizer := NewSummarizer(mock, s)
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	notes := []*store.MeetingNote{{SIGID: "collector", DocID: "doc123", MeetingDate: start, RawText: "Notes."}}
+
+	if _, err := summarizer.SummarizeMeetingNotes(context.Background(), "collector", "Collector", notes, start, end); err != nil {
+		t.Fatalf("SummarizeMeetingNotes: %v", err)
+	}
+
+	// The edited prompt is used, and is not served the old prompt's result.
+	summarizer.SetPrompts(ps)
+	if _, err := summarizer.SummarizeMeetingNotes(context.Background(), "collector", "Collector", notes, start, end); err != nil {
+		t.Fatalf("SummarizeMeetingNotes with override: %v", err)
+	}
+	if mock.callCount.Load() != 2 {
+		t.Errorf("LLM call count = %d, want 2: a changed prompt must miss the cache", mock.callCount.Load())
+	}
+	if got := mock.lastReq.Load().SystemPrompt; got != "Summarize the Collector notes from 2026-02-11." {
+		t.Errorf("system prompt = %q", got)
+	}
+}
+
+func TestPruneCache_StalePrompts(t *testing.T) {
+	dir := t.TempDir()
+	override := "{{/* version: 2 */ -}}\nSummarize the {{.SIGName}} notes.\n"
+	if err := os.WriteFile(filepath.Join(dir, "notes.tmpl"), []byte(override), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	edited, err := LoadPrompts(dir)
+	if err != nil {
+		t.Fatalf("LoadPrompts: %v", err)
+	}
+	if v := edited.Version(PromptRelevance); !strings.HasPrefix(v, "relevance@1:") || !strings.Contains(v, "+relevance-format@") {
+		t.Errorf("relevance version = %q, want it to cover the included format", v)
+	}
+
+	s := newTestStore(t)
+	summarizer := NewSummarizer(&mockLLMClient{response: "Summary."}, s)
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	notes := []*store.MeetingNote{{SIGID: "collector", DocID: "doc123", MeetingDate: start, RawText: "Notes."}}
+	messages := []*store.SlackMessage{{SIGID: "collector", ChannelID: "C1", MessageTS: "1", UserName: "a", Text: "Hi.", MessageDate: start}}
+	if _, err := summarizer.SummarizeMeetingNotes(context.Background(), "collector", "Collector", notes, start, end); err != nil {
+		t.Fatalf("SummarizeMeetingNotes: %v", err)
+	}
+	if _, err := summarizer.SummarizeSlackMessages(context.Background(), "collector", "Collector", messages, start, end); err != nil {
+		t.Fatalf("SummarizeSlackMessages: %v", err)
+	}
+
+	// Nothing is stale under the prompts the entries were made with.
+	if n, err := PruneCache(s, 0, DefaultPrompts()); err != nil || n != 0 {
+		t.Errorf("PruneCache with the same prompts = %d, %v; want nothing pruned", n, err)
+	}
+	// Editing the notes prompt makes only its summary stale.
+	n, err := PruneCache(s, 0, edited)
+	if err != nil {
+		t.Fatalf("PruneCache: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("pruned %d entries, want the notes summary only", n)
+	}
+	stats, err := s.AnalysisCacheStats(store.AnalysisCacheFilter{})
+	if err != nil {
+		t.Fatalf("AnalysisCacheStats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].SourceType != "slack" {
+		t.Errorf("left %+v, want the slack summary", stats)
+	}
+}
+
+func TestLoadPrompts_Errors(t *testing.T) {
+	tests := []struct {
+		name, file, text, wantErr string
+	}{
+		{"unknown prompt", "note.tmpl", "{{/* version: 1 */}}x", `unknown prompt "note"`},
+		{"missing version", "notes.tmpl", "Summarize.", "must declare"},
+		{"bad template", "notes.tmpl", "{{/* version: 1 */}}{{.SIGName", "parsing prompt notes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.text), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadPrompts(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadPrompts error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadPrompts(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("LoadPrompts should fail for a missing directory")
+	}
+}
+
+func TestDiffPrompts(t *testing.T) {
+	lines := []string{"{{/* version: 1 */ -}}", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+	from := &Prompt{Name: "notes", Version: 1, Source: "embedded", Text: strings.Join(lines, "\n") + "\n"}
+	if got := DiffPrompts(from, from); got != "" {
+		t.Errorf("diff of identical prompts = %q, want empty", got)
+	}
+
+	edited := append([]string{"{{/* version: 2 */ -}}"}, lines[1:]...)
+	edited[2] = "B"
+	edited = append(edited[:12], "L", "m")
+	to := &Prompt{Name: "notes", Version: 2, Source: "prompts/notes.tmpl", Text: strings.Join(edited, "\n") + "\n"}
+
+	want := `--- embedded (version 1)
++++ prompts/notes.tmpl (version 2)
+@@ -1,6 +1,6 @@
+-{{/* version: 1 */ -}}
++{{/* version: 2 */ -}}
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,4 +10,5 @@
+ i
+ j
+ k
+-l
++L
++m
+`
+	if got := DiffPrompts(from, to); got != want {
+		t.Errorf("DiffPrompts =\n%s\nwant\n%s", got, want)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Context management tests
 // ---------------------------------------------------------------------------
@@ -875,29 +1334,570 @@ func TestBuildCacheKey_Deterministic(t *testing.T) {
 	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
 
-	k1 := buildCacheKey("collector", "notes", start, end, "hash1")
-	k2 := buildCacheKey("collector", "notes", start, end, "hash1")
+	k1 := buildCacheKey("collector", "notes", start, end, "hash1", "prompt1", "")
+	k2 := buildCacheKey("collector", "notes", start, end, "hash1", "prompt1", "")
 	if k1 != k2 {
 		t.Errorf("buildCacheKey is not deterministic: %q != %q", k1, k2)
 	}
 
 	// Different content hash should produce different cache key.
-	k3 := buildCacheKey("collector", "notes", start, end, "hash2")
+	k3 := buildCacheKey("collector", "notes", start, end, "hash2", "prompt1", "")
 	if k1 == k3 {
 		t.Error("buildCacheKey should produce different keys for different content hashes")
 	}
 
 	// Different source type should produce different cache key.
-	k4 := buildCacheKey("collector", "video", start, end, "hash1")
+	k4 := buildCacheKey("collector", "video", start, end, "hash1", "prompt1", "")
 	if k1 == k4 {
 		t.Error("buildCacheKey should produce different keys for different source types")
 	}
+
+	// Different prompt hash should produce different cache key.
+	k5 := buildCacheKey("collector", "notes", start, end, "hash1", "prompt2", "")
+	if k1 == k5 {
+		t.Error("buildCacheKey should produce different keys for different prompt hashes")
+	}
+
+	// Different model should produce different cache key.
+	k6 := buildCacheKey("collector", "notes", start, end, "hash1", "prompt1", "anthropic/claude-haiku")
+	k7 := buildCacheKey("collector", "notes", start, end, "hash1", "prompt1", "anthropic/claude-sonnet")
+	if k1 == k6 || k6 == k7 {
+		t.Error("buildCacheKey should produce different keys for different models")
+	}
+}
+
+// describedClient is a mockLLMClient configured with a provider and model.
+type describedClient struct {
+	*mockLLMClient
+	provider, model string
+}
+
+func (c describedClient) DescribeModel() (string, string) {
+	return c.provider, c.model
+}
+
+func TestSynthesize_ModelChangeMissesCache(t *testing.T) {
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: "Synthesis."}
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	summaries := []*SourceSummary{{SourceType: "notes", Summary: "OTLP partial success merged."}}
+
+	synthesize := func(model string) {
+		t.Helper()
+		llm := describedClient{mockLLMClient: mock, provider: "anthropic", model: model}
+		if _, err := NewSynthesizer(llm, s).Synthesize(context.Background(), "collector", "Collector", summaries, start, end); err != nil {
+			t.Fatalf("Synthesize with %s: %v", model, err)
+		}
+	}
+
+	synthesize("claude-haiku")
+	synthesize("claude-haiku")
+	if n := mock.callCount.Load(); n != 1 {
+		t.Fatalf("LLM calls = %d, want 1: the second run should be served from cache", n)
+	}
+	synthesize("claude-sonnet")
+	if n := mock.callCount.Load(); n != 2 {
+		t.Errorf("LLM calls = %d, want 2: a new model should miss the cache", n)
+	}
 }
 
 // ---------------------------------------------------------------------------
 // helpers
 // ---------------------------------------------------------------------------
 
+// ---------------------------------------------------------------------------
+// Mock client tests
+// ---------------------------------------------------------------------------
+
+func TestMockClient(t *testing.T) {
+	s := newTestStore(t)
+	mock := NewMockClient(mockRelevanceResponse)
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	synthesis, err := NewSynthesizer(mock, s).Synthesize(context.Background(), "collector", "Collector",
+		[]*SourceSummary{{SourceType: "notes", Summary: "OTLP partial success merged."}}, start, end)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if !strings.Contains(synthesis.Synthesis, "OTLP partial success merged.") || synthesis.Model != MockModel || synthesis.TokensUsed == 0 {
+		t.Errorf("synthesis should echo the sources, got %+v", synthesis)
+	}
+
+	report, err := NewRelevanceScorer(mock, s, "").Score(context.Background(), "collector", "Collector", synthesis, start, end)
+	if err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+	if report.Report != mockRelevanceResponse || len(report.HighItems) != 2 {
+		t.Errorf("relevance report = %+v, want the canned response", report)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Fallback tests
+// ---------------------------------------------------------------------------
+
+func TestFallbackClient(t *testing.T) {
+	s := newTestStore(t)
+	overloaded := fmt.Errorf("anthropic API error: %w",
+		fmt.Errorf("error, status code: 529, message: %w", &anthropic.APIError{Type: anthropic.ErrTypeOverloaded, Message: "Overloaded"}))
+	primary := &mockLLMClient{err: overloaded}
+	secondary := &mockLLMClient{response: "Synthesis from the fallback."}
+	llm := NewFallbackClient(
+		FallbackTarget{Provider: "anthropic", Model: "claude-sonnet-4-20250514", Client: primary},
+		FallbackTarget{Provider: "openai", Model: "gpt-4o", Client: secondary},
+	)
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	synthesis, err := NewSynthesizer(llm, s).Synthesize(context.Background(), "collector", "Collector",
+		[]*SourceSummary{{SourceType: "notes", Summary: "OTLP partial success merged."}}, start, end)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+	if primary.callCount.Load() != 1 || secondary.callCount.Load() != 1 {
+		t.Errorf("calls = %d primary, %d fallback, want 1 each", primary.callCount.Load(), secondary.callCount.Load())
+	}
+	if synthesis.Synthesis != "Synthesis from the fallback." || synthesis.Model != "mock-model" {
+		t.Errorf("synthesis = %+v, want the fallback's answer", synthesis)
+	}
+
+	// The fallback's answer is cached under its own model, not the stage's,
+	// so once the primary recovers the next run asks it again.
+	req := secondary.lastReq.Load()
+	key := buildCacheKey("collector", "synthesis", start, end, hashContent(req.UserPrompt), hashContent(req.SystemPrompt), "")
+	if _, err := s.GetAnalysisCache(key); err != sql.ErrNoRows {
+		t.Errorf("GetAnalysisCache error = %v, want the fallback answer kept off the stage's key", err)
+	}
+	fallbackKey := buildCacheKey("collector", "synthesis", start, end, hashContent(req.UserPrompt), hashContent(req.SystemPrompt), "openai/mock-model")
+	cached, err := s.GetAnalysisCache(fallbackKey)
+	if err != nil {
+		t.Fatalf("GetAnalysisCache of the fallback's key: %v", err)
+	}
+	if cached.Provider != "openai" || cached.Model != "mock-model" || cached.Result != "Synthesis from the fallback." {
+		t.Errorf("cached fallback answer = %s/%s %q, want the openai answer", cached.Provider, cached.Model, cached.Result)
+	}
+	primary.err = nil
+	primary.response = "Synthesis from the primary."
+	synthesis, err = NewSynthesizer(llm, s).Synthesize(context.Background(), "collector", "Collector",
+		[]*SourceSummary{{SourceType: "notes", Summary: "OTLP partial success merged."}}, start, end)
+	if err != nil {
+		t.Fatalf("Synthesize after recovery: %v", err)
+	}
+	if synthesis.Synthesis != "Synthesis from the primary." || primary.callCount.Load() != 2 {
+		t.Errorf("synthesis after recovery = %+v, want the primary's answer", synthesis)
+	}
+	cached, err = s.GetAnalysisCache(key)
+	if err != nil {
+		t.Fatalf("GetAnalysisCache: %v", err)
+	}
+	if cached.Provider != "anthropic" || cached.Result != "Synthesis from the primary." {
+		t.Errorf("cached = %s %q, want the primary's answer", cached.Provider, cached.Result)
+	}
+	primary.err = overloaded
+
+	resp, err := llm.Complete(context.Background(), &CompletionRequest{UserPrompt: "hi"})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if !resp.Fallback || resp.Provider != "openai" {
+		t.Errorf("response = %+v, want a fallback answer from openai", resp)
+	}
+
+	// A request the provider rejects is not retried elsewhere.
+	primary.err = fmt.Errorf("anthropic API error: %w", &anthropic.APIError{Type: anthropic.ErrTypeAuthentication})
+	if _, err := llm.Complete(context.Background(), &CompletionRequest{}); err == nil {
+		t.Error("expected the primary's authentication error")
+	}
+	if secondary.callCount.Load() != 2 {
+		t.Errorf("fallback calls = %d, want 2: authentication errors should not fall back", secondary.callCount.Load())
+	}
+
+	// When every provider is unavailable, all errors are reported.
+	primary.err = overloaded
+	secondary.err = &openai.APIError{HTTPStatusCode: 503, Message: "unavailable"}
+	_, err = llm.Complete(context.Background(), &CompletionRequest{})
+	if err == nil || !strings.Contains(err.Error(), "all LLM providers failed") ||
+		!strings.Contains(err.Error(), "anthropic/claude-sonnet-4-20250514") || !strings.Contains(err.Error(), "openai/gpt-4o") {
+		t.Errorf("error = %v, want both providers' failures", err)
+	}
+}
+
+func TestIsUnavailable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"anthropic overloaded", &anthropic.APIError{Type: anthropic.ErrTypeOverloaded}, true},
+		{"anthropic rate limit", fmt.Errorf("wrapped: %w", &anthropic.APIError{Type: anthropic.ErrTypeRateLimit}), true},
+		{"anthropic invalid request", &anthropic.APIError{Type: anthropic.ErrTypeInvalidRequest}, false},
+		{"anthropic bad gateway", &anthropic.RequestError{StatusCode: 502}, true},
+		{"openai rate limit", &openai.APIError{HTTPStatusCode: 429}, true},
+		{"openai unauthorized", &openai.APIError{HTTPStatusCode: 401}, false},
+		{"openai server error", &openai.RequestError{HTTPStatusCode: 500}, true},
+		{"network", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, true},
+		{"timeout", context.DeadlineExceeded, true},
+		{"cancelled", context.Canceled, false},
+		{"other", errors.New("openai API returned no choices"), false},
+	}
+	for _, tt := range tests {
+		if got := IsUnavailable(tt.err); got != tt.want {
+			t.Errorf("%s: IsUnavailable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Telemetry tests
+// ---------------------------------------------------------------------------
+
+func TestTracedClient_SpansAndCacheHits(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	shutdown := telemetry.Install(sdktrace.WithSyncer(exporter), sdkmetric.NewManualReader())
+	defer shutdown(context.Background())
+
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: "Traced summary."}
+	summarizer := NewSummarizer(NewTracedClient(mock, "anthropic", "mock-model"), s)
+
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	notes := []*store.MeetingNote{
+		{SIGID: "collector", DocID: "doc123", MeetingDate: start.AddDate(0, 0, 1), RawText: "Notes."},
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := summarizer.SummarizeMeetingNotes(context.Background(), "collector", "Collector", notes, start, end); err != nil {
+			t.Fatalf("call %d failed: %v", i+1, err)
+		}
+	}
+
+	var llmSpans int
+	var cacheHits []bool
+	for _, span := range exporter.GetSpans() {
+		switch span.Name {
+		case "llm.complete":
+			llmSpans++
+			if !hasAttr(span.Attributes, telemetry.AttrTokens.Int(100)) {
+				t.Errorf("llm.complete attributes = %v, want llm.tokens=100", span.Attributes)
+			}
+		case "analysis.summarize":
+			if !hasAttr(span.Attributes, telemetry.AttrSIGID.String("collector")) || !hasAttr(span.Attributes, telemetry.AttrSourceType.String("notes")) {
+				t.Errorf("analysis.summarize attributes = %v", span.Attributes)
+			}
+			for _, kv := range span.Attributes {
+				if kv.Key == telemetry.AttrCacheHit {
+					cacheHits = append(cacheHits, kv.Value.AsBool())
+				}
+			}
+		}
+	}
+	if llmSpans != 1 {
+		t.Errorf("llm.complete spans = %d, want 1 (second call is cached)", llmSpans)
+	}
+	if len(cacheHits) != 2 || cacheHits[0] || !cacheHits[1] {
+		t.Errorf("cache.hit per call = %v, want [false true]", cacheHits)
+	}
+}
+
+// fakeAnthropicAPI is a local stand-in for the Messages and Message Batches
+// endpoints of the Anthropic API.
+type fakeAnthropicAPI struct {
+	mu sync.Mutex
+	// messages and batch hold the request bodies received.
+	messages []map[string]any
+	batch    []map[string]any
+	polls    int
+	canceled bool
+	// failLast makes the last request of a batch error.
+	failLast bool
+}
+
+func (f *fakeAnthropicAPI) serve(t *testing.T) *httptest.Server {
+	t.Helper()
+	message := func(text string) map[string]any {
+		return map[string]any{
+			"id": "msg_1", "type": "message", "role": "assistant", "model": "claude-test",
+			"content":     []map[string]any{{"type": "text", "text": text}},
+			"stop_reason": "end_turn",
+			"usage":       map[string]any{"input_tokens": 10, "output_tokens": 5, "cache_read_input_tokens": 100},
+		}
+	}
+	batch := func(status string) map[string]any {
+		return map[string]any{
+			"id": "msgbatch_1", "type": "message_batch", "processing_status": status,
+			"request_counts": map[string]any{"processing": 0, "succeeded": 1},
+			"created_at":     "2026-10-18T00:00:00Z", "expires_at": "2026-10-19T00:00:00Z",
+		}
+	}
+	writeJSON := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/messages", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.mu.Lock()
+		f.messages = append(f.messages, body)
+		f.mu.Unlock()
+		writeJSON(w, message("answer"))
+	})
+	mux.HandleFunc("POST /v1/messages/batches", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []map[string]any `json:"requests"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.mu.Lock()
+		f.batch = body.Requests
+		f.mu.Unlock()
+		writeJSON(w, batch("in_progress"))
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.polls++
+		status := "in_progress"
+		if f.polls >= 2 {
+			status = "ended"
+		}
+		f.mu.Unlock()
+		writeJSON(w, batch(status))
+	})
+	mux.HandleFunc("GET /v1/messages/batches/msgbatch_1/results", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		// Results come back in no particular order.
+		enc := json.NewEncoder(w)
+		for i := len(f.batch) - 1; i >= 0; i-- {
+			id := f.batch[i]["custom_id"]
+			if f.failLast && i == len(f.batch)-1 {
+				_ = enc.Encode(map[string]any{"custom_id": id, "result": map[string]any{"type": "errored"}})
+				continue
+			}
+			_ = enc.Encode(map[string]any{"custom_id": id, "result": map[string]any{
+				"type": "succeeded", "message": message(fmt.Sprintf("batched answer %v", id)),
+			}})
+		}
+	})
+	mux.HandleFunc("POST /v1/messages/batches/msgbatch_1/cancel", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.canceled = true
+		f.mu.Unlock()
+		writeJSON(w, batch("canceling"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestAnthropicClient_CacheSystemPrompt(t *testing.T) {
+	api := &fakeAnthropicAPI{}
+	srv := api.serve(t)
+	client := NewAnthropicClient("test-key", "claude-test", anthropic.WithBaseURL(srv.URL+"/v1"))
+
+	for _, cache := range []bool{true, false} {
+		resp, err := client.Complete(context.Background(), &CompletionRequest{
+			SystemPrompt:      "Shared instructions.",
+			UserPrompt:        "SIG synthesis.",
+			CacheSystemPrompt: cache,
+		})
+		if err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+		if resp.Content != "answer" || resp.Provider != "anthropic" {
+			t.Errorf("response = %+v", resp)
+		}
+		// Cache reads count as used tokens.
+		if resp.TokensUsed != 115 {
+			t.Errorf("TokensUsed = %d, want 115", resp.TokensUsed)
+		}
+	}
+
+	if len(api.messages) != 2 {
+		t.Fatalf("got %d requests, want 2", len(api.messages))
+	}
+	for i, wantCache := range []bool{true, false} {
+		system, _ := api.messages[i]["system"].([]any)
+		if len(system) != 1 {
+			t.Fatalf("request %d system = %v, want one part", i, api.messages[i]["system"])
+		}
+		part := system[0].(map[string]any)
+		cc, hasCache := part["cache_control"].(map[string]any)
+		if hasCache != wantCache || (wantCache && cc["type"] != "ephemeral") {
+			t.Errorf("request %d system part = %v, want cache control %v", i, part, wantCache)
+		}
+	}
+}
+
+func TestRelevanceScorer_CachesSystemPrompt(t *testing.T) {
+	mock := &mockLLMClient{response: mockRelevanceResponse}
+	scorer := NewRelevanceScorer(mock, newTestStore(t), "")
+	synthesis := &SynthesizedReport{SIGID: "collector", SIGName: "Collector", Synthesis: "Synthesis."}
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+
+	if _, err := scorer.Score(context.Background(), "collector", "Collector", synthesis, start, end); err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+	if req := mock.lastReq.Load(); req == nil || !req.CacheSystemPrompt {
+		t.Error("relevance requests should mark their shared system prompt for caching")
+	}
+}
+
+func TestAnthropicBatchClient(t *testing.T) {
+	api := &fakeAnthropicAPI{failLast: true}
+	srv := api.serve(t)
+	client := NewAnthropicBatchClient("test-key", anthropic.WithBaseURL(srv.URL+"/v1"))
+	client.SetPollInterval(time.Millisecond)
+
+	results, err := client.CompleteBatch(context.Background(), []BatchRequest{
+		{Model: "claude-haiku", Request: &CompletionRequest{SystemPrompt: "Summarize.", UserPrompt: "notes", MaxTokens: 1024}},
+		{Model: "claude-sonnet", Request: &CompletionRequest{UserPrompt: "video"}},
+		{Model: "claude-haiku", Request: &CompletionRequest{UserPrompt: "slack"}},
+	})
+	if err != nil {
+		t.Fatalf("CompleteBatch: %v", err)
+	}
+	if api.polls != 2 {
+		t.Errorf("polled %d times, want until the batch ended", api.polls)
+	}
+	if len(api.batch) != 3 {
+		t.Fatalf("batch had %d requests, want 3", len(api.batch))
+	}
+	params := api.batch[1]["params"].(map[string]any)
+	if params["model"] != "claude-sonnet" || params["max_tokens"] != float64(4096) {
+		t.Errorf("second request params = %v, want its own model and the default max tokens", params)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, want := range []string{"batched answer 0", "batched answer 1"} {
+		r := results[i]
+		if r.Err != nil || r.Response.Content != want || !r.Response.Batch || r.Response.Provider != "anthropic" {
+			t.Errorf("result %d = %+v (err %v), want %q from the batch", i, r.Response, r.Err, want)
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("the errored request should have an error")
+	}
+}
+
+func TestAnthropicBatchClient_CancelsOnContextDone(t *testing.T) {
+	api := &fakeAnthropicAPI{}
+	srv := api.serve(t)
+	client := NewAnthropicBatchClient("test-key", anthropic.WithBaseURL(srv.URL+"/v1"))
+	client.SetPollInterval(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.CompleteBatch(ctx, []BatchRequest{{Model: "claude-haiku", Request: &CompletionRequest{UserPrompt: "notes"}}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CompleteBatch error = %v, want the context's", err)
+	}
+	if !api.canceled {
+		t.Error("the batch should be cancelled when the context is done")
+	}
+}
+
+// fakeBatchClient answers every request of a batch, except those whose
+// user prompt contains fail.
+type fakeBatchClient struct {
+	fail string
+	reqs []BatchRequest
+}
+
+func (f *fakeBatchClient) CompleteBatch(ctx context.Context, reqs []BatchRequest) ([]BatchResult, error) {
+	f.reqs = reqs
+	results := make([]BatchResult, len(reqs))
+	for i, r := range reqs {
+		if f.fail != "" && strings.Contains(r.Request.UserPrompt, f.fail) {
+			results[i].Err = errors.New("errored")
+			continue
+		}
+		results[i].Response = &CompletionResponse{Content: "Batched summary.", Provider: "anthropic", Model: r.Model, TokensUsed: 50, Batch: true}
+	}
+	return results, nil
+}
+
+func TestSummaryBatch(t *testing.T) {
+	s := newTestStore(t)
+	mock := &mockLLMClient{response: "Individual summary."}
+	summarizer := NewSummarizer(mock, s)
+	start := time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC)
+	notes := []*store.MeetingNote{{SIGID: "collector", DocID: "doc", MeetingDate: day, RawText: "Collector notes."}}
+	transcripts := []*store.VideoTranscript{{SIGID: "collector", RecordingDate: day, Transcript: "Collector recording."}}
+	messages := []*store.SlackMessage{{SIGID: "collector", ChannelID: "C1", MessageTS: "1", UserName: "alice", Text: "Slack thread.", MessageDate: day}}
+
+	batch := summarizer.NewBatch(map[string]BatchModel{
+		"notes": {Model: "claude-haiku", MaxTokens: 1024},
+		"video": {Model: "claude-haiku"},
+	})
+	for _, add := range []func() error{
+		func() error { return batch.AddMeetingNotes("collector", "Collector", notes, start, end) },
+		func() error { return batch.AddVideoTranscripts("collector", "Collector", transcripts, start, end) },
+		func() error { return batch.AddSlackMessages("collector", "Collector", messages, start, end) },
+	} {
+		if err := add(); err != nil {
+			t.Fatalf("adding to batch: %v", err)
+		}
+	}
+	// Slack has no batch model, so it is left to the individual call.
+	if batch.Len() != 2 {
+		t.Fatalf("batch has %d calls, want 2", batch.Len())
+	}
+
+	client := &fakeBatchClient{fail: "recording"}
+	results, err := batch.Run(context.Background(), client)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if client.reqs[0].Request.MaxTokens != 1024 {
+		t.Errorf("notes request max tokens = %d, want the batch model's 1024", client.reqs[0].Request.MaxTokens)
+	}
+	if len(results) != 2 || results[0].SourceType != "notes" || results[0].Err != nil || results[1].SourceType != "video" || results[1].Err == nil {
+		t.Fatalf("results = %+v, want notes answered and video failed", results)
+	}
+
+	// The batched summary is cached; the failed one is made individually.
+	summary, err := summarizer.SummarizeMeetingNotes(context.Background(), "collector", "Collector", notes, start, end)
+	if err != nil {
+		t.Fatalf("SummarizeMeetingNotes: %v", err)
+	}
+	if summary.Summary != "Batched summary." {
+		t.Errorf("notes summary = %q, want the batched one", summary.Summary)
+	}
+	summary, err = summarizer.SummarizeVideoTranscripts(context.Background(), "collector", "Collector", transcripts, start, end)
+	if err != nil {
+		t.Fatalf("SummarizeVideoTranscripts: %v", err)
+	}
+	if summary.Summary != "Individual summary." || mock.callCount.Load() != 1 {
+		t.Errorf("video summary = %q after %d calls, want one individual call", summary.Summary, mock.callCount.Load())
+	}
+
+	// Cached summaries are not batched again.
+	batch = summarizer.NewBatch(map[string]BatchModel{"notes": {Model: "claude-haiku"}})
+	if err := batch.AddMeetingNotes("collector", "Collector", notes, start, end); err != nil {
+		t.Fatalf("AddMeetingNotes: %v", err)
+	}
+	if batch.Len() != 0 {
+		t.Errorf("batch has %d calls for a cached summary, want 0", batch.Len())
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
+
 func containsStr(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > 0 && containsSubstring(s, substr))
 }
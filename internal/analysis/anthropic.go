@@ -13,9 +13,10 @@ type AnthropicClient struct {
 	model  string
 }
 
-// NewAnthropicClient creates a new Anthropic Claude client.
-func NewAnthropicClient(apiKey, model string) *AnthropicClient {
-	client := anthropic.NewClient(apiKey)
+// NewAnthropicClient creates a new Anthropic Claude client. opts configure
+// the underlying API client, e.g. its base URL.
+func NewAnthropicClient(apiKey, model string, opts ...anthropic.ClientOption) *AnthropicClient {
+	client := anthropic.NewClient(apiKey, opts...)
 	return &AnthropicClient{
 		client: client,
 		model:  model,
@@ -24,6 +25,16 @@ func NewAnthropicClient(apiKey, model string) *AnthropicClient {
 
 // Complete sends a completion request to the Anthropic Claude API.
 func (c *AnthropicClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	resp, err := c.client.CreateMessages(ctx, anthropicMessagesRequest(c.model, req))
+	if err != nil {
+		return nil, fmt.Errorf("anthropic API error: %w", err)
+	}
+	return anthropicCompletionResponse(&resp), nil
+}
+
+// anthropicMessagesRequest converts req into a Messages API request for
+// model.
+func anthropicMessagesRequest(model string, req *CompletionRequest) anthropic.MessagesRequest {
 	maxTokens := req.MaxTokens
 	if maxTokens <= 0 {
 		maxTokens = 4096
@@ -40,30 +51,30 @@ func (c *AnthropicClient) Complete(ctx context.Context, req *CompletionRequest)
 	}
 
 	apiReq := anthropic.MessagesRequest{
-		Model:       anthropic.Model(c.model),
+		Model:       anthropic.Model(model),
 		MaxTokens:   maxTokens,
 		Temperature: &temperatureF32,
 		Messages:    messages,
 	}
 
 	if req.SystemPrompt != "" {
-		apiReq.MultiSystem = []anthropic.MessageSystemPart{
-			anthropic.NewSystemMessagePart(req.SystemPrompt),
+		part := anthropic.NewSystemMessagePart(req.SystemPrompt)
+		if req.CacheSystemPrompt {
+			part.CacheControl = &anthropic.MessageCacheControl{Type: anthropic.CacheControlTypeEphemeral}
 		}
+		apiReq.MultiSystem = []anthropic.MessageSystemPart{part}
 	}
+	return apiReq
+}
 
-	resp, err := c.client.CreateMessages(ctx, apiReq)
-	if err != nil {
-		return nil, fmt.Errorf("anthropic API error: %w", err)
-	}
-
-	content := resp.GetFirstContentText()
-
-	tokensUsed := resp.Usage.InputTokens + resp.Usage.OutputTokens
-
+// anthropicCompletionResponse converts a Messages API response. Tokens
+// written to and read from the prompt cache count as used.
+func anthropicCompletionResponse(resp *anthropic.MessagesResponse) *CompletionResponse {
+	u := resp.Usage
 	return &CompletionResponse{
-		Content:    content,
+		Content:    resp.GetFirstContentText(),
+		Provider:   "anthropic",
 		Model:      string(resp.Model),
-		TokensUsed: tokensUsed,
-	}, nil
+		TokensUsed: u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens + u.OutputTokens,
+	}
 }
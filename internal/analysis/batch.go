@@ -0,0 +1,278 @@
+package analysis
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	anthropic "github.com/liushuangls/go-anthropic/v2"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// defaultBatchPollInterval is how often AnthropicBatchClient checks whether
+// a submitted batch has ended.
+const defaultBatchPollInterval = 30 * time.Second
+
+// BatchRequest is one request of a batch and the model to answer it with.
+type BatchRequest struct {
+	Model   string
+	Request *CompletionRequest
+}
+
+// BatchResult is the outcome of one BatchRequest: Response, or Err if the
+// request failed.
+type BatchResult struct {
+	Response *CompletionResponse
+	Err      error
+}
+
+// BatchCompleter answers many requests together, more cheaply but more
+// slowly than one Complete call each.
+type BatchCompleter interface {
+	CompleteBatch(ctx context.Context, reqs []BatchRequest) ([]BatchResult, error)
+}
+
+// AnthropicBatchClient implements BatchCompleter using Anthropic's Message
+// Batches API, which answers within 24 hours at about half the price of
+// individual calls.
+type AnthropicBatchClient struct {
+	client       *anthropic.Client
+	pollInterval time.Duration
+	logger       *slog.Logger
+}
+
+// NewAnthropicBatchClient creates a new Message Batches client. opts
+// configure the underlying API client, e.g. its base URL.
+func NewAnthropicBatchClient(apiKey string, opts ...anthropic.ClientOption) *AnthropicBatchClient {
+	return &AnthropicBatchClient{
+		client:       anthropic.NewClient(apiKey, opts...),
+		pollInterval: defaultBatchPollInterval,
+		logger:       slog.Default(),
+	}
+}
+
+// SetPollInterval replaces how often the client checks on a batch.
+func (c *AnthropicBatchClient) SetPollInterval(d time.Duration) {
+	c.pollInterval = d
+}
+
+// SetLogger replaces the logger that records batch progress.
+func (c *AnthropicBatchClient) SetLogger(l *slog.Logger) {
+	c.logger = l
+}
+
+// CompleteBatch submits reqs as one message batch and polls until it has
+// ended. The results are in the order of reqs. An error is returned only if
+// the batch could not be submitted or followed; if ctx is cancelled first,
+// the batch is cancelled too.
+func (c *AnthropicBatchClient) CompleteBatch(ctx context.Context, reqs []BatchRequest) ([]BatchResult, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	var batchReq anthropic.BatchRequest
+	for i, r := range reqs {
+		batchReq.Requests = append(batchReq.Requests, anthropic.InnerRequests{
+			CustomId: strconv.Itoa(i),
+			Params:   anthropicMessagesRequest(r.Model, r.Request),
+		})
+	}
+	batch, err := c.client.CreateBatch(ctx, batchReq)
+	if err != nil {
+		return nil, fmt.Errorf("creating message batch: %w", err)
+	}
+	id := batch.Id
+	c.logger.Info("submitted message batch", "batch_id", id, "requests", len(reqs))
+
+	for batch.ProcessingStatus != anthropic.ProcessingStatusEnded {
+		select {
+		case <-ctx.Done():
+			c.cancel(ctx, id)
+			return nil, ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+		batch, err = c.client.RetrieveBatch(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.cancel(ctx, id)
+			}
+			return nil, fmt.Errorf("retrieving message batch %s: %w", id, err)
+		}
+		c.logger.Debug("polled message batch", "batch_id", id, "status", batch.ProcessingStatus,
+			"processing", batch.RequestCounts.Processing, "succeeded", batch.RequestCounts.Succeeded)
+	}
+
+	res, err := c.client.RetrieveBatchResults(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving results of message batch %s: %w", id, err)
+	}
+	results := make([]BatchResult, len(reqs))
+	for i := range results {
+		results[i].Err = fmt.Errorf("message batch %s has no result for request %d", id, i)
+	}
+	for _, r := range res.Responses {
+		i, err := strconv.Atoi(r.CustomId)
+		if err != nil || i < 0 || i >= len(reqs) {
+			continue
+		}
+		if r.Result.Type != anthropic.ResultTypeSucceeded {
+			results[i] = BatchResult{Err: fmt.Errorf("message batch %s request %d %s", id, i, r.Result.Type)}
+			continue
+		}
+		resp := anthropicCompletionResponse(&r.Result.Result)
+		resp.Batch = true
+		results[i] = BatchResult{Response: resp}
+	}
+	c.logger.Info("message batch ended", "batch_id", id,
+		"succeeded", batch.RequestCounts.Succeeded, "errored", batch.RequestCounts.Errored,
+		"expired", batch.RequestCounts.Expired, "canceled", batch.RequestCounts.Canceled)
+	return results, nil
+}
+
+// cancel asks the API to stop processing batch id, which ctx no longer
+// waits for.
+func (c *AnthropicBatchClient) cancel(ctx context.Context, id anthropic.BatchId) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if _, err := c.client.CancelBatch(ctx, id); err != nil {
+		c.logger.Warn("failed to cancel message batch", "batch_id", id, "err", err)
+	}
+}
+
+// SummaryBatch collects the per-source summary calls of many SIGs so they
+// can be sent as one batch. Running it stores each summary in the analysis
+// cache, where the Summarize methods then find it.
+type SummaryBatch struct {
+	s      *Summarizer
+	models map[string]BatchModel
+	calls  []*batchCall
+}
+
+// BatchModel is the model and request settings a SummaryBatch summarizes a
+// source type with. Zero settings leave the client defaults.
+type BatchModel struct {
+	Model       string
+	MaxTokens   int
+	Temperature float64
+}
+
+// batchCall is one summary call of a SummaryBatch.
+type batchCall struct {
+	sigID      string
+	sourceType string
+	model      string
+	call       *preparedCall
+}
+
+// SummaryBatchResult is the outcome of one call of a SummaryBatch.
+type SummaryBatchResult struct {
+	SIGID      string
+	SourceType string
+	Response   *CompletionResponse
+	Err        error
+}
+
+// NewBatch starts a SummaryBatch. models holds the model to summarize each
+// source type with; sources without one are left out of the batch.
+func (s *Summarizer) NewBatch(models map[string]BatchModel) *SummaryBatch {
+	return &SummaryBatch{s: s, models: models}
+}
+
+// AddMeetingNotes adds the call SummarizeMeetingNotes would make, unless
+// its result is already cached.
+func (b *SummaryBatch) AddMeetingNotes(sigID, sigName string, notes []*store.MeetingNote, start, end time.Time) error {
+	call, err := b.s.notesCall(sigID, sigName, notes, start, end)
+	if err != nil {
+		return err
+	}
+	return b.add(sigID, "notes", call)
+}
+
+// AddVideoTranscripts adds the call SummarizeVideoTranscripts would make,
+// unless its result is already cached.
+func (b *SummaryBatch) AddVideoTranscripts(sigID, sigName string, transcripts []*store.VideoTranscript, start, end time.Time) error {
+	call, err := b.s.videoCall(sigID, sigName, transcripts, start, end)
+	if err != nil {
+		return err
+	}
+	return b.add(sigID, "video", call)
+}
+
+// AddSlackMessages adds the call SummarizeSlackMessages would make, unless
+// its result is already cached.
+func (b *SummaryBatch) AddSlackMessages(sigID, sigName string, messages []*store.SlackMessage, start, end time.Time) error {
+	call, err := b.s.slackCall(sigID, sigName, messages, start, end)
+	if err != nil {
+		return err
+	}
+	return b.add(sigID, "slack", call)
+}
+
+// add queues call, unless its source type is not batched or its result is
+// already cached.
+func (b *SummaryBatch) add(sigID, sourceType string, call *preparedCall) error {
+	model, ok := b.models[sourceType]
+	if !ok {
+		return nil
+	}
+	if call.req.MaxTokens == 0 {
+		call.req.MaxTokens = model.MaxTokens
+	}
+	if call.req.Temperature == 0 {
+		call.req.Temperature = model.Temperature
+	}
+	cached, err := b.s.store.GetAnalysisCache(call.cacheKey)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("checking analysis cache: %w", err)
+	}
+	if cached != nil {
+		return nil
+	}
+	b.calls = append(b.calls, &batchCall{
+		sigID:      sigID,
+		sourceType: sourceType,
+		model:      model.Model,
+		call:       call,
+	})
+	return nil
+}
+
+// Len returns the number of calls in the batch.
+func (b *SummaryBatch) Len() int {
+	return len(b.calls)
+}
+
+// Run sends the batch through client and caches every summary it returns.
+// The results are in the order the calls were added.
+func (b *SummaryBatch) Run(ctx context.Context, client BatchCompleter) ([]SummaryBatchResult, error) {
+	if len(b.calls) == 0 {
+		return nil, nil
+	}
+	reqs := make([]BatchRequest, len(b.calls))
+	for i, bc := range b.calls {
+		reqs[i] = BatchRequest{Model: bc.model, Request: bc.call.req}
+	}
+	results, err := client.CompleteBatch(ctx, reqs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]SummaryBatchResult, len(b.calls))
+	for i, bc := range b.calls {
+		out[i] = SummaryBatchResult{SIGID: bc.sigID, SourceType: bc.sourceType}
+		if i >= len(results) {
+			out[i].Err = fmt.Errorf("batch returned no result")
+			continue
+		}
+		out[i].Response, out[i].Err = results[i].Response, results[i].Err
+		if out[i].Err != nil {
+			continue
+		}
+		putCache(b.s.logger.With(logging.KeySIG, bc.sigID, logging.KeySource, bc.sourceType), b.s.store, bc.call, out[i].Response)
+	}
+	return out, nil
+}
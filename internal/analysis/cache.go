@@ -0,0 +1,31 @@
+package analysis
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// PruneCache deletes the analysis cache entries cached more than maxAge
+// ago, when maxAge is positive, and, when prompts is not nil, those made
+// with prompts other than the current ones of prompts, which are never
+// served again. It returns the number of entries deleted.
+func PruneCache(st *store.Store, maxAge time.Duration, prompts *Prompts) (int64, error) {
+	var deleted int64
+	if maxAge > 0 {
+		n, err := st.DeleteAnalysisCache(store.AnalysisCacheFilter{CreatedBefore: time.Now().Add(-maxAge)})
+		if err != nil {
+			return deleted, fmt.Errorf("pruning old analysis cache entries: %w", err)
+		}
+		deleted += n
+	}
+	if prompts != nil {
+		n, err := st.DeleteAnalysisCache(store.AnalysisCacheFilter{CurrentPromptVersions: prompts.Versions()})
+		if err != nil {
+			return deleted, fmt.Errorf("pruning stale analysis cache entries: %w", err)
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// CallEstimate describes the LLM call an analysis stage would make, worked
+// out without making it.
+type CallEstimate struct {
+	SourceType string
+	// Cached is set when the analysis cache already holds the result, in
+	// which case no call is made and Result is the cached text.
+	Cached bool
+	Result string
+	// InputTokens approximates the size of the request.
+	InputTokens int
+}
+
+// EstimateTokens approximates the number of tokens in text at four
+// characters per token.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// preparedCall is an LLM request together with the cache key of its
+// result.
+type preparedCall struct {
+	req           *CompletionRequest
+	sigID         string
+	sourceType    string
+	start, end    time.Time
+	contentHash   string
+	promptHash    string
+	promptVersion string
+	cacheKey      string
+}
+
+// prepareCall builds the request to llm for systemPrompt, rendered from the
+// templates identified by promptVersion, and userPrompt. The cache key
+// covers keyContent, the part of the user prompt that varies between runs,
+// the system prompt and the model llm is configured with.
+func prepareCall(llm LLMClient, sigID, sourceType string, start, end time.Time, promptVersion, systemPrompt, userPrompt, keyContent string) *preparedCall {
+	call := &preparedCall{
+		req:           &CompletionRequest{SystemPrompt: systemPrompt, UserPrompt: userPrompt},
+		sigID:         sigID,
+		sourceType:    sourceType,
+		start:         start,
+		end:           end,
+		contentHash:   hashContent(keyContent),
+		promptHash:    hashContent(systemPrompt),
+		promptVersion: promptVersion,
+	}
+	call.cacheKey = call.keyFor(clientModel(llm))
+	return call
+}
+
+// keyFor returns the cache key of call's result as answered by model, a
+// "provider/model".
+func (c *preparedCall) keyFor(model string) string {
+	return buildCacheKey(c.sigID, c.sourceType, c.start, c.end, c.contentHash, c.promptHash, model)
+}
+
+// estimateCall looks up call's result in the analysis cache and estimates
+// its size.
+func estimateCall(st *store.Store, sourceType string, call *preparedCall) (*CallEstimate, error) {
+	est := &CallEstimate{
+		SourceType:  sourceType,
+		InputTokens: EstimateTokens(call.req.SystemPrompt) + EstimateTokens(call.req.UserPrompt),
+	}
+	cached, err := st.GetAnalysisCache(call.cacheKey)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("checking analysis cache: %w", err)
+	}
+	if cached != nil {
+		est.Cached = true
+		est.Result = cached.Result
+	}
+	return est, nil
+}
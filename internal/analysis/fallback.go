@@ -0,0 +1,112 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+
+	anthropic "github.com/liushuangls/go-anthropic/v2"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// FallbackTarget is one provider and model in a FallbackClient's chain.
+type FallbackTarget struct {
+	Provider string
+	Model    string
+	Client   LLMClient
+}
+
+// FallbackClient implements LLMClient over an ordered chain of providers.
+// Each request goes to the first target; when a target fails with an error
+// that another provider could avoid (rate limits, overload, server errors,
+// network failures), the next one is tried.
+type FallbackClient struct {
+	targets []FallbackTarget
+	logger  *slog.Logger
+}
+
+// NewFallbackClient creates a FallbackClient trying targets in order. The
+// first target is the primary.
+func NewFallbackClient(targets ...FallbackTarget) *FallbackClient {
+	return &FallbackClient{targets: targets, logger: slog.Default()}
+}
+
+// SetLogger replaces the logger that records fallbacks.
+func (c *FallbackClient) SetLogger(l *slog.Logger) {
+	c.logger = l
+}
+
+// Complete sends req to each target in turn until one answers. The response
+// names the provider and model that answered, and is marked as a fallback
+// if that was not the primary.
+func (c *FallbackClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	var errs []error
+	for i, t := range c.targets {
+		resp, err := t.Client.Complete(ctx, req)
+		if err == nil {
+			if resp.Provider == "" {
+				resp.Provider = t.Provider
+			}
+			if resp.Model == "" {
+				resp.Model = t.Model
+			}
+			resp.Fallback = i > 0
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s/%s: %w", t.Provider, t.Model, err))
+		if ctx.Err() != nil || !IsUnavailable(err) {
+			break
+		}
+		if i+1 < len(c.targets) {
+			next := c.targets[i+1]
+			c.logger.Warn("LLM provider unavailable, falling back",
+				"provider", t.Provider, "model", t.Model,
+				"fallback_provider", next.Provider, "fallback_model", next.Model, "err", err)
+		}
+	}
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, fmt.Errorf("all LLM providers failed: %w", errors.Join(errs...))
+}
+
+// IsUnavailable reports whether err means the provider could not serve the
+// request right now — rate limited, overloaded, failing or unreachable — as
+// opposed to rejecting the request itself, as it does for a bad API key or
+// an invalid request.
+func IsUnavailable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var anthropicAPIErr *anthropic.APIError
+	if errors.As(err, &anthropicAPIErr) {
+		return anthropicAPIErr.IsRateLimitErr() || anthropicAPIErr.IsApiErr() || anthropicAPIErr.IsOverloadedErr()
+	}
+	var anthropicReqErr *anthropic.RequestError
+	if errors.As(err, &anthropicReqErr) {
+		return unavailableStatus(anthropicReqErr.StatusCode)
+	}
+	var openaiAPIErr *openai.APIError
+	if errors.As(err, &openaiAPIErr) {
+		return unavailableStatus(openaiAPIErr.HTTPStatusCode)
+	}
+	var openaiReqErr *openai.RequestError
+	if errors.As(err, &openaiReqErr) {
+		return unavailableStatus(openaiReqErr.HTTPStatusCode)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// unavailableStatus reports whether an HTTP status means the provider is
+// temporarily unable to answer.
+func unavailableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
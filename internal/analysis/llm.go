@@ -8,19 +8,48 @@ type LLMClient interface {
 	Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error)
 }
 
+// ModelDescriber is implemented by LLM clients that know the provider and
+// model they are configured to call. The analysis stages key their cache on
+// it, so changing a stage's model does not serve another model's results.
+type ModelDescriber interface {
+	DescribeModel() (provider, model string)
+}
+
+// clientModel returns "provider/model" of llm, or "" if llm does not
+// describe its model.
+func clientModel(llm LLMClient) string {
+	if d, ok := llm.(ModelDescriber); ok {
+		provider, model := d.DescribeModel()
+		return provider + "/" + model
+	}
+	return ""
+}
+
 // CompletionRequest represents a request to the LLM.
 type CompletionRequest struct {
 	SystemPrompt string
 	UserPrompt   string
 	MaxTokens    int
 	Temperature  float64
+	// CacheSystemPrompt marks the system prompt as a prefix shared by many
+	// requests, which providers that support prompt caching keep cached so
+	// later requests pay less for it.
+	CacheSystemPrompt bool
 }
 
 // CompletionResponse represents a response from the LLM.
 type CompletionResponse struct {
-	Content    string
+	Content string
+	// Provider and Model identify the LLM that answered.
+	Provider   string
 	Model      string
 	TokensUsed int
+	// Fallback is set when a FallbackClient's primary was unavailable and
+	// a later provider in its chain answered.
+	Fallback bool
+	// Batch is set when the request was answered through a batch API,
+	// which bills it at a discount.
+	Batch bool
 }
 
 // SourceSummary holds a per-source summary for a SIG.
@@ -42,6 +71,22 @@ type SynthesizedReport struct {
 	TokensUsed int
 }
 
+// ItemStatus classifies a relevance item relative to the previous digest.
+type ItemStatus string
+
+const (
+	ItemNew     ItemStatus = "NEW"
+	ItemUpdated ItemStatus = "UPDATED"
+	ItemOngoing ItemStatus = "ONGOING"
+)
+
+// PriorItem is a relevance item reported in the previous digest for a SIG.
+type PriorItem struct {
+	Level string // "high", "medium", "low"
+	Topic string
+	Text  string
+}
+
 // RelevanceReport holds the Datadog relevance-scored report.
 type RelevanceReport struct {
 	SIGID          string
@@ -52,6 +97,13 @@ type RelevanceReport struct {
 	LowItems       []string
 	Model          string
 	TokensUsed     int
+
+	// ItemStatuses maps item text to its status relative to the previous
+	// digest. Only populated when scoring against prior items.
+	ItemStatuses map[string]ItemStatus
+	// OngoingItems holds items unchanged since the previous digest. They are
+	// removed from the High/Medium/Low lists.
+	OngoingItems []string
 }
 
 // SIGReport is the final combined report for a single SIG.
@@ -67,6 +119,11 @@ type SIGReport struct {
 	NotesLink       string
 	RecordingLink   string
 	SlackChannel    string
+
+	// ProfileReports holds the SIG's relevance reports for the configured
+	// relevance profiles, by profile name. RelevanceReport is the built-in
+	// Datadog profile's.
+	ProfileReports map[string]*RelevanceReport `json:",omitempty"`
 }
 
 // RunStats tracks resource usage for the entire pipeline run.
@@ -79,6 +136,35 @@ type RunStats struct {
 	SIGsWithData      int
 	DurationSeconds   float64
 	EstimatedCostUSD  float64
+
+	// Stages breaks down this run's LLM calls by analysis stage, in stage
+	// order. Results answered from the analysis cache are not counted.
+	Stages []StageUsage
+	// Fallbacks lists the calls answered by a fallback provider because
+	// the stage's own was unavailable. Empty when none were.
+	Fallbacks []FallbackUsage
+	// BudgetSkipped lists the sources left unsummarized because the run's
+	// projected LLM usage exceeded the configured budget.
+	BudgetSkipped []string
+}
+
+// StageUsage is the LLM usage of one analysis stage during a run.
+type StageUsage struct {
+	Stage            string
+	Provider         string
+	Model            string
+	Calls            int
+	TokensUsed       int
+	EstimatedCostUSD float64
+}
+
+// FallbackUsage counts the calls of a stage answered by one fallback
+// provider and model during a run.
+type FallbackUsage struct {
+	Stage    string
+	Provider string
+	Model    string
+	Calls    int
 }
 
 // DigestReport is the weekly digest across all SIGs.
@@ -88,4 +174,8 @@ type DigestReport struct {
 	SIGReports     []*SIGReport
 	CrossSIGThemes string
 	Stats          *RunStats
+
+	// PreviousDigestEnd is the end date of the digest this one was diffed
+	// against, or empty if it is a full report.
+	PreviousDigestEnd string
 }
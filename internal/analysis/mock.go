@@ -0,0 +1,41 @@
+package analysis
+
+import (
+	"context"
+	"strings"
+)
+
+// MockModel is the provider and model name reported by MockClient.
+const MockModel = "mock"
+
+// MockClient is an offline LLMClient for evaluation runs. It answers
+// summary and synthesis requests with the user prompt, so source text flows
+// through unchanged, and relevance requests with a fixed response.
+type MockClient struct {
+	relevance string
+}
+
+// NewMockClient creates a MockClient that answers relevance requests with
+// relevance.
+func NewMockClient(relevance string) *MockClient {
+	return &MockClient{relevance: relevance}
+}
+
+// Complete implements LLMClient. Token usage is estimated at four
+// characters per token.
+func (m *MockClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	content := req.UserPrompt
+	// Relevance prompts carry the response format parseRelevanceItems needs.
+	if strings.Contains(req.SystemPrompt, "#### HIGH Relevance") {
+		content = m.relevance
+	}
+	return &CompletionResponse{
+		Content:    content,
+		Provider:   MockModel,
+		Model:      MockModel,
+		TokensUsed: (len(req.SystemPrompt) + len(req.UserPrompt) + len(content)) / 4,
+	}, nil
+}
@@ -69,6 +69,7 @@ func (c *OpenAIClient) Complete(ctx context.Context, req *CompletionRequest) (*C
 
 	return &CompletionResponse{
 		Content:    resp.Choices[0].Message.Content,
+		Provider:   "openai",
 		Model:      resp.Model,
 		TokensUsed: tokensUsed,
 	}, nil
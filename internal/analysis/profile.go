@@ -0,0 +1,25 @@
+package analysis
+
+// RelevanceProfile is a named scoring lens over the shared SIG syntheses:
+// who the brief is for, what makes a topic relevant to them, and how to
+// grade it. The built-in Datadog lens is used when no profile is set.
+// Profiles are rendered with the relevance-profile prompt.
+type RelevanceProfile struct {
+	// Name identifies the profile in cache keys and stored reports.
+	Name string
+	// Audience is who the brief is for, e.g. "the Datadog Logs team".
+	Audience string
+	// Criteria list what makes a topic relevant to the audience.
+	Criteria []string
+	// Keywords are topics to watch for.
+	Keywords []string
+	// Rubric describes each relevance level. Empty levels are left out.
+	Rubric Rubric
+}
+
+// Rubric describes what earns a topic each relevance level.
+type Rubric struct {
+	High   string
+	Medium string
+	Low    string
+}
@@ -0,0 +1,305 @@
+package analysis
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+//go:embed prompts
+var promptFS embed.FS
+
+// Names of the system prompts. PromptRelevanceFormat is the response format
+// shared by both relevance prompts, which parseRelevanceItems relies on.
+const (
+	PromptNotes            = "notes"
+	PromptVideo            = "video"
+	PromptSlack            = "slack"
+	PromptSynthesis        = "synthesis"
+	PromptRelevance        = "relevance"
+	PromptRelevanceProfile = "relevance-profile"
+	PromptRelevanceFormat  = "relevance-format"
+)
+
+// promptIncludes lists the prompts each prompt includes with {{template}}.
+var promptIncludes = map[string][]string{
+	PromptRelevance:        {PromptRelevanceFormat},
+	PromptRelevanceProfile: {PromptRelevanceFormat},
+}
+
+// promptExt is the file extension of prompt templates, embedded and
+// overrides alike.
+const promptExt = ".tmpl"
+
+// versionPattern matches the version comment every prompt starts with.
+var versionPattern = regexp.MustCompile(`^\{\{-?\s*/\*\s*version:\s*(\d+)\s*\*/\s*-?\}\}`)
+
+// Prompt is one system prompt template.
+type Prompt struct {
+	Name string
+	// Version is declared in the template's first line, {{/* version: N */}},
+	// and should be bumped whenever the wording changes.
+	Version int
+	// Source is "embedded" or the path of the override file.
+	Source string
+	// Text is the template source.
+	Text string
+}
+
+// Hash returns a short hash of the template source, which tells edits
+// apart when the version was not bumped.
+func (p *Prompt) Hash() string {
+	return hashContent(p.Text)[:12]
+}
+
+// Prompts is a set of system prompt templates: the embedded defaults, with
+// any overrides loaded from a prompt directory.
+type Prompts struct {
+	prompts map[string]*Prompt
+	tmpl    *template.Template
+}
+
+var defaultPrompts = mustLoadEmbeddedPrompts()
+
+// DefaultPrompts returns the embedded prompts.
+func DefaultPrompts() *Prompts {
+	return defaultPrompts
+}
+
+func mustLoadEmbeddedPrompts() *Prompts {
+	ps, err := LoadPrompts("")
+	if err != nil {
+		panic(err)
+	}
+	return ps
+}
+
+// EmbeddedPrompt returns the embedded default of the named prompt.
+func EmbeddedPrompt(name string) (*Prompt, error) {
+	data, err := promptFS.ReadFile("prompts/" + name + promptExt)
+	if err != nil {
+		return nil, fmt.Errorf("unknown prompt %q", name)
+	}
+	return newPrompt(name, "embedded", string(data))
+}
+
+// LoadPrompts loads the embedded prompts and replaces each one that has a
+// <name>.tmpl file in dir. An empty dir uses only the embedded prompts.
+// Files that do not name a known prompt are an error, so a misspelled
+// override is not silently ignored.
+func LoadPrompts(dir string) (*Prompts, error) {
+	entries, err := promptFS.ReadDir("prompts")
+	if err != nil {
+		return nil, err
+	}
+	ps := &Prompts{prompts: make(map[string]*Prompt)}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), promptExt)
+		p, err := EmbeddedPrompt(name)
+		if err != nil {
+			return nil, err
+		}
+		ps.prompts[name] = p
+	}
+
+	if dir != "" {
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("reading prompt directory: %w", err)
+		}
+		for _, f := range files {
+			if f.IsDir() || filepath.Ext(f.Name()) != promptExt {
+				continue
+			}
+			name := strings.TrimSuffix(f.Name(), promptExt)
+			if _, ok := ps.prompts[name]; !ok {
+				return nil, fmt.Errorf("prompt override %s: unknown prompt %q", f.Name(), name)
+			}
+			path := filepath.Join(dir, f.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("reading prompt override: %w", err)
+			}
+			if ps.prompts[name], err = newPrompt(name, path, string(data)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	ps.tmpl = template.New("prompts").Option("missingkey=error")
+	for _, p := range ps.prompts {
+		if _, err := ps.tmpl.New(p.Name).Parse(p.Text); err != nil {
+			return nil, fmt.Errorf("parsing prompt %s (%s): %w", p.Name, p.Source, err)
+		}
+	}
+	return ps, nil
+}
+
+func newPrompt(name, source, text string) (*Prompt, error) {
+	m := versionPattern.FindStringSubmatch(text)
+	if m == nil {
+		return nil, fmt.Errorf("prompt %s (%s): first line must declare {{/* version: N */}}", name, source)
+	}
+	version, _ := strconv.Atoi(m[1])
+	return &Prompt{Name: name, Version: version, Source: source, Text: text}, nil
+}
+
+// Get returns the named prompt.
+func (ps *Prompts) Get(name string) (*Prompt, bool) {
+	p, ok := ps.prompts[name]
+	return p, ok
+}
+
+// List returns all prompts sorted by name.
+func (ps *Prompts) List() []*Prompt {
+	list := make([]*Prompt, 0, len(ps.prompts))
+	for _, p := range ps.prompts {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Version identifies the templates the named prompt renders from, as the
+// name, version and hash of each, e.g. "notes@1:3f2a9c0d41b7". It is
+// recorded with cached results so those of earlier prompts can be pruned.
+func (ps *Prompts) Version(name string) string {
+	var parts []string
+	for _, n := range append([]string{name}, promptIncludes[name]...) {
+		if p, ok := ps.prompts[n]; ok {
+			parts = append(parts, fmt.Sprintf("%s@%d:%s", p.Name, p.Version, p.Hash()))
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// Versions returns the Version of every prompt, which together identify
+// the results the current prompts can be served.
+func (ps *Prompts) Versions() []string {
+	versions := make([]string, 0, len(ps.prompts))
+	for _, p := range ps.List() {
+		versions = append(versions, ps.Version(p.Name))
+	}
+	return versions
+}
+
+// promptData is what prompt templates are executed with. Each prompt uses
+// the fields relevant to its stage.
+type promptData struct {
+	SIGName       string
+	Channel       string
+	Start         string // YYYY-MM-DD
+	End           string // YYYY-MM-DD
+	CustomContext string
+	Profile       *RelevanceProfile
+}
+
+// render executes the named prompt. Trailing whitespace is trimmed so
+// templates can end with a newline.
+func (ps *Prompts) render(name string, data *promptData) (string, error) {
+	var buf bytes.Buffer
+	if err := ps.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("rendering %s prompt: %w", name, err)
+	}
+	return strings.TrimRight(buf.String(), " \t\n"), nil
+}
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// DiffPrompts returns a unified diff from one version of a prompt to
+// another, or an empty string if their text is the same.
+func DiffPrompts(from, to *Prompt) string {
+	if from.Text == to.Text {
+		return ""
+	}
+	a := strings.Split(strings.TrimSuffix(from.Text, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(to.Text, "\n"), "\n")
+
+	// Longest common subsequence table; prompts are short enough for the
+	// quadratic version.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte // ' ', '-' or '+'
+		text string
+		ai   int // line index in a before this line
+		bi   int // line index in b before this line
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i, j})
+			i, j = i+1, j+1
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s (version %d)\n+++ %s (version %d)\n", from.Source, from.Version, to.Source, to.Version)
+	for k := 0; k < len(lines); {
+		if lines[k].op == ' ' {
+			k++
+			continue
+		}
+		// Extend the hunk while changes are within 2*diffContext lines.
+		start := max(k-diffContext, 0)
+		end := k
+		for end < len(lines) {
+			next := end
+			for next < len(lines) && lines[next].op == ' ' {
+				next++
+			}
+			if next == len(lines) || next-end > 2*diffContext {
+				break
+			}
+			for next < len(lines) && lines[next].op != ' ' {
+				next++
+			}
+			end = next
+		}
+		end = min(end+diffContext, len(lines))
+
+		var aLen, bLen int
+		for _, l := range lines[start:end] {
+			if l.op != '+' {
+				aLen++
+			}
+			if l.op != '-' {
+				bLen++
+			}
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", lines[start].ai+1, aLen, lines[start].bi+1, bLen)
+		for _, l := range lines[start:end] {
+			fmt.Fprintf(&sb, "%c%s\n", l.op, l.text)
+		}
+		k = end
+	}
+	return sb.String()
+}
@@ -4,45 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
 )
 
-const datadogRelevanceKeywords = `## High Relevance Keywords
-These topics have direct impact on Datadog's OpenTelemetry integration:
-- OTLP, OTLP/HTTP, OTLP/gRPC
-- trace context, W3C trace context, baggage
-- sampling, tail sampling, head sampling
-- Datadog exporter, vendor exporters
-- semantic conventions (all: HTTP, DB, messaging, etc.)
-- resource detection, resource attributes
-- metrics SDK, delta vs cumulative temporality
-- log bridge, log SDK
-- collector pipeline, processor, receiver, exporter
-- profiling signal, profile data model
-- OpAMP, agent management
-- context propagation
-- instrumentation libraries
-- configuration file format
-- entities, resource lifecycle
-
-## Medium Relevance Keywords
-These topics are relevant but less directly impactful:
-- SDK lifecycle, provider, tracer, meter, logger
-- batch processing, export retry
-- gRPC instrumentation, HTTP instrumentation
-- Kubernetes operator, auto-instrumentation
-- eBPF instrumentation
-- Prometheus compatibility, remote write
-`
-
-// RelevanceScorer scores synthesized reports for Datadog relevance.
+// RelevanceScorer scores synthesized reports for Datadog relevance, or
+// against a relevance profile set with SetProfile.
 type RelevanceScorer struct {
 	llm           LLMClient
 	store         *store.Store
 	customContext string
+	profile       *RelevanceProfile
+	prompts       *Prompts
+	logger        *slog.Logger
 }
 
 // NewRelevanceScorer creates a new RelevanceScorer.
@@ -52,20 +29,48 @@ func NewRelevanceScorer(llm LLMClient, s *store.Store, customContext string) *Re
 		llm:           llm,
 		store:         s,
 		customContext: customContext,
+		prompts:       DefaultPrompts(),
+		logger:        slog.Default(),
 	}
 }
 
-// Score produces a Datadog relevance report from a synthesized SIG report.
+// SetLogger replaces the scorer's logger.
+func (r *RelevanceScorer) SetLogger(l *slog.Logger) {
+	r.logger = l
+}
+
+// SetPrompts replaces the embedded system prompts.
+func (r *RelevanceScorer) SetPrompts(ps *Prompts) {
+	r.prompts = ps
+}
+
+// SetProfile makes the scorer grade topics for p instead of Datadog. The
+// custom context is not used with a profile; profiles carry their own
+// criteria.
+func (r *RelevanceScorer) SetProfile(p *RelevanceProfile) {
+	r.profile = p
+}
+
+// Score produces a relevance report from a synthesized SIG report.
 func (r *RelevanceScorer) Score(ctx context.Context, sigID, sigName string, synthesis *SynthesizedReport, start, end time.Time) (*RelevanceReport, error) {
-	if synthesis == nil {
-		return nil, fmt.Errorf("no synthesis to score for SIG %s", sigID)
-	}
+	return r.ScoreWithPrior(ctx, sigID, sigName, synthesis, start, end, nil)
+}
 
-	contentHash := hashContent(synthesis.Synthesis)
-	cacheKey := buildCacheKey(sigID, "relevance", start, end, contentHash)
+// ScoreWithPrior produces a relevance report and classifies each item
+// as NEW, UPDATED or ONGOING relative to the items reported in the previous
+// digest. ONGOING items are moved out of the level lists into OngoingItems.
+// With no prior items it behaves exactly like Score.
+func (r *RelevanceScorer) ScoreWithPrior(ctx context.Context, sigID, sigName string, synthesis *SynthesizedReport, start, end time.Time, prior []PriorItem) (*RelevanceReport, error) {
+	ctx, span, log := startStage(ctx, r.logger, "relevance", sigID, "all")
+	defer span.End()
+
+	call, err := r.prepare(sigID, sigName, synthesis, start, end, prior)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check cache.
-	cached, err := r.store.GetAnalysisCache(cacheKey)
+	cached, err := lookupCache(ctx, log, r.store, call.cacheKey)
 	if err == nil && cached != nil {
 		report := &RelevanceReport{
 			SIGID:      sigID,
@@ -75,50 +80,25 @@ func (r *RelevanceScorer) Score(ctx context.Context, sigID, sigName string, synt
 			TokensUsed: cached.TokensUsed,
 		}
 		report.HighItems, report.MediumItems, report.LowItems = parseRelevanceItems(cached.Result)
+		if len(prior) > 0 {
+			applyItemStatuses(report, prior)
+		}
 		return report, nil
 	}
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("checking analysis cache: %w", err)
 	}
 
-	systemPrompt := buildRelevanceSystemPrompt(r.customContext)
-	promptHash := hashContent(systemPrompt)
-
-	userPrompt := fmt.Sprintf(
-		"Produce a Datadog relevance report for the %s SIG based on the following synthesis "+
-			"covering %s to %s:\n\n%s",
-		sigName,
-		start.Format("2006-01-02"),
-		end.Format("2006-01-02"),
-		synthesis.Synthesis,
-	)
-
-	resp, err := r.llm.Complete(ctx, &CompletionRequest{
-		SystemPrompt: systemPrompt,
-		UserPrompt:   userPrompt,
-	})
+	resp, err := r.llm.Complete(ctx, call.req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion for relevance scoring: %w", err)
 	}
 
-	// Cache the result.
-	if cacheErr := r.store.PutAnalysisCache(&store.AnalysisCache{
-		CacheKey:       cacheKey,
-		SIGID:          sigID,
-		SourceType:     "relevance",
-		DateRangeStart: start,
-		DateRangeEnd:   end,
-		PromptHash:     promptHash,
-		Result:         resp.Content,
-		Model:          resp.Model,
-		TokensUsed:     resp.TokensUsed,
-	}); cacheErr != nil {
-		_ = cacheErr
-	}
+	putCache(log, r.store, call, resp)
 
 	highItems, mediumItems, lowItems := parseRelevanceItems(resp.Content)
 
-	return &RelevanceReport{
+	report := &RelevanceReport{
 		SIGID:       sigID,
 		SIGName:     sigName,
 		Report:      resp.Content,
@@ -127,52 +107,178 @@ func (r *RelevanceScorer) Score(ctx context.Context, sigID, sigName string, synt
 		LowItems:    lowItems,
 		Model:       resp.Model,
 		TokensUsed:  resp.TokensUsed,
-	}, nil
+	}
+	if len(prior) > 0 {
+		applyItemStatuses(report, prior)
+	}
+	return report, nil
 }
 
-// buildRelevanceSystemPrompt constructs the full system prompt for relevance scoring.
-func buildRelevanceSystemPrompt(customContext string) string {
-	var sb strings.Builder
+// Estimate estimates ScoreWithPrior without calling the LLM.
+func (r *RelevanceScorer) Estimate(sigID, sigName string, synthesis *SynthesizedReport, start, end time.Time, prior []PriorItem) (*CallEstimate, error) {
+	call, err := r.prepare(sigID, sigName, synthesis, start, end, prior)
+	if err != nil {
+		return nil, err
+	}
+	return estimateCall(r.store, r.sourceType(), call)
+}
+
+// sourceType is the analysis cache source type of the scorer's reports.
+func (r *RelevanceScorer) sourceType() string {
+	if r.profile != nil {
+		return "relevance:" + r.profile.Name
+	}
+	return "relevance"
+}
+
+// prepare builds the relevance request for a synthesis. The cache key
+// covers the prior items, since they change the report.
+func (r *RelevanceScorer) prepare(sigID, sigName string, synthesis *SynthesizedReport, start, end time.Time, prior []PriorItem) (*preparedCall, error) {
+	if synthesis == nil {
+		return nil, fmt.Errorf("no synthesis to score for SIG %s", sigID)
+	}
+
+	priorSection := buildPriorItemsSection(prior)
+
+	promptName := PromptRelevance
+	data := &promptData{CustomContext: r.customContext}
+	subject := "a Datadog relevance report"
+	if r.profile != nil {
+		promptName = PromptRelevanceProfile
+		data = &promptData{Profile: r.profile}
+		subject = "a relevance report for " + r.profile.Audience
+	}
+	systemPrompt, err := r.prompts.render(promptName, data)
+	if err != nil {
+		return nil, err
+	}
 
-	sb.WriteString("You are producing a concise intelligence brief for Datadog engineering leaders.\n")
-	sb.WriteString("Score each topic's relevance to Datadog (HIGH/MEDIUM/LOW) based on:\n")
-	sb.WriteString("- Direct impact on Datadog's OTLP ingest pipeline\n")
-	sb.WriteString("- Changes to trace/metric/log formats or semantic conventions\n")
-	sb.WriteString("- New instrumentation that Datadog should support\n")
-	sb.WriteString("- Collector changes affecting Datadog exporter\n")
-	sb.WriteString("- Competitive landscape (features overlapping with Datadog products)\n")
-	sb.WriteString("- SDK changes affecting Datadog's tracing libraries\n")
-	sb.WriteString("- Changes to sampling, context propagation, or resource detection\n")
-	sb.WriteString("- OpAMP or agent management developments\n")
-	sb.WriteString("- Profiling signal developments\n\n")
-
-	sb.WriteString("Use the following keyword reference for relevance classification:\n\n")
-	sb.WriteString(datadogRelevanceKeywords)
-
-	sb.WriteString("\n\nFormat your response with clear markdown sections:\n")
-	sb.WriteString("#### HIGH Relevance\n")
-	sb.WriteString("Each bullet: `- **Topic Name** — one-sentence what + why. Action clause if needed.`\n")
-	sb.WriteString("If no items, write: `None this period.`\n\n")
-	sb.WriteString("#### MEDIUM Relevance\n")
-	sb.WriteString("Each bullet: `- **Topic Name** — one-sentence what + why.`\n")
-	sb.WriteString("If no items, write: `None this period.`\n\n")
-	sb.WriteString("#### LOW Relevance\n")
-	sb.WriteString("Each bullet: `- **Topic Name** — one-sentence what + why.`\n")
-	sb.WriteString("If no items, write: `None this period.`\n\n")
-
-	sb.WriteString("Do NOT include any of the following in your response: ")
-	sb.WriteString("\"Overall Assessment\", \"Analysis Summary\", \"Note\", \"Recommendation\", ")
-	sb.WriteString("\"Executive Summary\", or prose paragraphs outside the bullet lists. ")
-	sb.WriteString("Only output the three sections above with their bullet items.\n")
-
-	if customContext != "" {
-		sb.WriteString("\n\n## Additional Context from User\n")
-		sb.WriteString(customContext)
+	userPrompt := fmt.Sprintf(
+		"Produce %s for the %s SIG based on the following synthesis "+
+			"covering %s to %s:\n\n%s",
+		subject,
+		sigName,
+		start.Format("2006-01-02"),
+		end.Format("2006-01-02"),
+		synthesis.Synthesis,
+	)
+	userPrompt += priorSection
+
+	call := prepareCall(r.llm, sigID, r.sourceType(), start, end, r.prompts.Version(promptName), systemPrompt, userPrompt, synthesis.Synthesis+priorSection)
+	// Every SIG is scored with the same system prompt.
+	call.req.CacheSystemPrompt = true
+	return call, nil
+}
+
+// buildPriorItemsSection renders the previous digest's items as an addition
+// to the relevance user prompt. Returns an empty string when there are none.
+func buildPriorItemsSection(prior []PriorItem) string {
+	if len(prior) == 0 {
+		return ""
 	}
 
+	var sb strings.Builder
+	sb.WriteString("\n\n## Previously Reported Items\n")
+	sb.WriteString("The previous digest already reported the following items for this SIG:\n")
+	for _, p := range prior {
+		fmt.Fprintf(&sb, "- (%s) %s\n", strings.ToUpper(p.Level), p.Text)
+	}
+	sb.WriteString("\nPrefix every bullet in your response with exactly one status tag:\n")
+	sb.WriteString("`[NEW]` for topics not in the list above, `[UPDATED]` for listed topics with\n")
+	sb.WriteString("material new developments, and `[ONGOING]` for listed topics with nothing new.\n")
+	sb.WriteString("Reuse the previous topic name when a topic is UPDATED or ONGOING.\n")
 	return sb.String()
 }
 
+// applyItemStatuses classifies every item in the report against the prior
+// items, strips status tags from the item text, and moves ONGOING items
+// into OngoingItems.
+func applyItemStatuses(report *RelevanceReport, prior []PriorItem) {
+	report.ItemStatuses = make(map[string]ItemStatus)
+	report.OngoingItems = nil
+
+	classify := func(items []string) []string {
+		var kept []string
+		for _, item := range items {
+			status, text := splitStatusTag(item)
+			if status == "" {
+				status = classifyItem(text, prior)
+			}
+			if status == ItemOngoing {
+				report.OngoingItems = append(report.OngoingItems, text)
+				continue
+			}
+			report.ItemStatuses[text] = status
+			kept = append(kept, text)
+		}
+		return kept
+	}
+
+	report.HighItems = classify(report.HighItems)
+	report.MediumItems = classify(report.MediumItems)
+	report.LowItems = classify(report.LowItems)
+}
+
+// splitStatusTag removes a leading [NEW], [UPDATED] or [ONGOING] tag from an
+// item. Returns an empty status if the item carries no recognized tag.
+func splitStatusTag(item string) (ItemStatus, string) {
+	trimmed := strings.TrimSpace(item)
+	for _, status := range []ItemStatus{ItemNew, ItemUpdated, ItemOngoing} {
+		for _, tag := range []string{"[" + string(status) + "]", "`[" + string(status) + "]`", "**[" + string(status) + "]**"} {
+			if len(trimmed) >= len(tag) && strings.EqualFold(trimmed[:len(tag)], tag) {
+				return status, strings.TrimSpace(trimmed[len(tag):])
+			}
+		}
+	}
+	return "", trimmed
+}
+
+// classifyItem compares an untagged item against the prior items by topic.
+// A matching topic with identical text is ONGOING, a matching topic with
+// different text is UPDATED, and anything else is NEW.
+func classifyItem(item string, prior []PriorItem) ItemStatus {
+	topic := normalizeTopic(ItemTopic(item))
+	for _, p := range prior {
+		if normalizeTopic(p.Topic) != topic {
+			continue
+		}
+		if normalizeTopic(p.Text) == normalizeTopic(item) {
+			return ItemOngoing
+		}
+		return ItemUpdated
+	}
+	return ItemNew
+}
+
+// ItemTopic extracts the topic name from a relevance item such as
+// "**OTLP/HTTP Partial Success** — New partial success ...". It falls back to
+// the text before the first em-dash or colon, or the whole item.
+func ItemTopic(item string) string {
+	trimmed := strings.TrimSpace(item)
+	if strings.HasPrefix(trimmed, "**") {
+		if idx := strings.Index(trimmed[2:], "**"); idx > 0 {
+			return strings.TrimSpace(trimmed[2 : 2+idx])
+		}
+	}
+	for _, sep := range []string{" — ", ": "} {
+		if idx := strings.Index(trimmed, sep); idx > 0 {
+			return strings.TrimSpace(trimmed[:idx])
+		}
+	}
+	return trimmed
+}
+
+// SameTopic reports whether two item topics name the same topic, ignoring
+// case and spacing.
+func SameTopic(a, b string) bool {
+	return normalizeTopic(a) == normalizeTopic(b)
+}
+
+// normalizeTopic lowercases and collapses whitespace for topic comparison.
+func normalizeTopic(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
 // parseRelevanceItems extracts HIGH, MEDIUM, and LOW items from the LLM output.
 // It looks for markdown headers like "#### HIGH Relevance", "#### MEDIUM Relevance", "#### LOW Relevance"
 // and collects bullet points under each section.
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -13,37 +14,63 @@ import (
 
 // Summarizer produces per-source summaries for SIG content using an LLM.
 type Summarizer struct {
-	llm   LLMClient
-	store *store.Store
+	llm LLMClient
+	// sourceClients overrides llm for single source types.
+	sourceClients map[string]LLMClient
+	store         *store.Store
+	prompts       *Prompts
+	logger        *slog.Logger
 }
 
 // NewSummarizer creates a new Summarizer.
 func NewSummarizer(llm LLMClient, s *store.Store) *Summarizer {
 	return &Summarizer{
-		llm:   llm,
-		store: s,
+		llm:     llm,
+		store:   s,
+		prompts: DefaultPrompts(),
+		logger:  slog.Default(),
 	}
 }
 
-// SummarizeMeetingNotes produces a summary of meeting notes for a SIG within a date range.
-func (s *Summarizer) SummarizeMeetingNotes(ctx context.Context, sigID, sigName string, notes []*store.MeetingNote, start, end time.Time) (*SourceSummary, error) {
-	if len(notes) == 0 {
-		return nil, fmt.Errorf("no meeting notes to summarize for SIG %s", sigID)
+// SetLogger replaces the summarizer's logger.
+func (s *Summarizer) SetLogger(l *slog.Logger) {
+	s.logger = l
+}
+
+// SetPrompts replaces the embedded system prompts.
+func (s *Summarizer) SetPrompts(ps *Prompts) {
+	s.prompts = ps
+}
+
+// SetSourceClient makes the summarizer use llm for sources of sourceType
+// ("notes", "video" or "slack") instead of the client it was created with.
+func (s *Summarizer) SetSourceClient(sourceType string, llm LLMClient) {
+	if s.sourceClients == nil {
+		s.sourceClients = make(map[string]LLMClient)
 	}
+	s.sourceClients[sourceType] = llm
+}
 
-	// Build the content from all notes in the range.
-	var contentParts []string
-	for _, note := range notes {
-		contentParts = append(contentParts, fmt.Sprintf("--- Meeting Date: %s ---\n%s",
-			note.MeetingDate.Format("2006-01-02"), note.RawText))
+// client returns the LLM client that summarizes sources of sourceType.
+func (s *Summarizer) client(sourceType string) LLMClient {
+	if llm, ok := s.sourceClients[sourceType]; ok {
+		return llm
 	}
-	content := strings.Join(contentParts, "\n\n")
+	return s.llm
+}
 
-	contentHash := hashContent(content)
-	cacheKey := buildCacheKey(sigID, "notes", start, end, contentHash)
+// SummarizeMeetingNotes produces a summary of meeting notes for a SIG within a date range.
+func (s *Summarizer) SummarizeMeetingNotes(ctx context.Context, sigID, sigName string, notes []*store.MeetingNote, start, end time.Time) (*SourceSummary, error) {
+	ctx, span, log := startStage(ctx, s.logger, "summarize", sigID, "notes")
+	defer span.End()
+
+	call, err := s.notesCall(sigID, sigName, notes, start, end)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check cache.
-	cached, err := s.store.GetAnalysisCache(cacheKey)
+	cached, err := lookupCache(ctx, log, s.store, call.cacheKey)
 	if err == nil && cached != nil {
 		return &SourceSummary{
 			SIGID:      sigID,
@@ -58,43 +85,12 @@ func (s *Summarizer) SummarizeMeetingNotes(ctx context.Context, sigID, sigName s
 		return nil, fmt.Errorf("checking analysis cache: %w", err)
 	}
 
-	systemPrompt := fmt.Sprintf(
-		"You are analyzing OpenTelemetry SIG meeting notes for the %s SIG.\n"+
-			"Summarize the key discussions, decisions, and action items from the following\n"+
-			"meeting notes dated between %s and %s.\n"+
-			"Focus on: technical decisions, new features, breaking changes, deprecations,\n"+
-			"integration changes, protocol/format changes, and anything affecting\n"+
-			"telemetry pipelines or clients.",
-		sigName,
-		start.Format("2006-01-02"),
-		end.Format("2006-01-02"),
-	)
-
-	promptHash := hashContent(systemPrompt)
-
-	resp, err := s.llm.Complete(ctx, &CompletionRequest{
-		SystemPrompt: systemPrompt,
-		UserPrompt:   content,
-	})
+	resp, err := s.client("notes").Complete(ctx, call.req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion for meeting notes: %w", err)
 	}
 
-	// Cache the result.
-	if cacheErr := s.store.PutAnalysisCache(&store.AnalysisCache{
-		CacheKey:       cacheKey,
-		SIGID:          sigID,
-		SourceType:     "notes",
-		DateRangeStart: start,
-		DateRangeEnd:   end,
-		PromptHash:     promptHash,
-		Result:         resp.Content,
-		Model:          resp.Model,
-		TokensUsed:     resp.TokensUsed,
-	}); cacheErr != nil {
-		// Log but do not fail on cache write errors.
-		_ = cacheErr
-	}
+	putCache(log, s.store, call, resp)
 
 	return &SourceSummary{
 		SIGID:      sigID,
@@ -108,23 +104,16 @@ func (s *Summarizer) SummarizeMeetingNotes(ctx context.Context, sigID, sigName s
 
 // SummarizeVideoTranscripts produces a summary of video transcripts for a SIG within a date range.
 func (s *Summarizer) SummarizeVideoTranscripts(ctx context.Context, sigID, sigName string, transcripts []*store.VideoTranscript, start, end time.Time) (*SourceSummary, error) {
-	if len(transcripts) == 0 {
-		return nil, fmt.Errorf("no video transcripts to summarize for SIG %s", sigID)
-	}
+	ctx, span, log := startStage(ctx, s.logger, "summarize", sigID, "video")
+	defer span.End()
 
-	// Build the content from all transcripts in the range.
-	var contentParts []string
-	for _, t := range transcripts {
-		contentParts = append(contentParts, fmt.Sprintf("--- Recording Date: %s (Duration: %d min) ---\n%s",
-			t.RecordingDate.Format("2006-01-02"), t.DurationMinutes, t.Transcript))
+	call, err := s.videoCall(sigID, sigName, transcripts, start, end)
+	if err != nil {
+		return nil, err
 	}
-	content := strings.Join(contentParts, "\n\n")
-
-	contentHash := hashContent(content)
-	cacheKey := buildCacheKey(sigID, "video", start, end, contentHash)
 
 	// Check cache.
-	cached, err := s.store.GetAnalysisCache(cacheKey)
+	cached, err := lookupCache(ctx, log, s.store, call.cacheKey)
 	if err == nil && cached != nil {
 		return &SourceSummary{
 			SIGID:      sigID,
@@ -139,39 +128,12 @@ func (s *Summarizer) SummarizeVideoTranscripts(ctx context.Context, sigID, sigNa
 		return nil, fmt.Errorf("checking analysis cache: %w", err)
 	}
 
-	// Build a combined system prompt covering all transcripts in the range.
-	systemPrompt := fmt.Sprintf(
-		"You are analyzing transcripts of the %s SIG meetings.\n"+
-			"Summarize the key technical discussions, noting any decisions made,\n"+
-			"controversies, and planned work. Identify speakers and their positions\n"+
-			"where possible.",
-		sigName,
-	)
-
-	promptHash := hashContent(systemPrompt)
-
-	resp, err := s.llm.Complete(ctx, &CompletionRequest{
-		SystemPrompt: systemPrompt,
-		UserPrompt:   content,
-	})
+	resp, err := s.client("video").Complete(ctx, call.req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion for video transcripts: %w", err)
 	}
 
-	// Cache the result.
-	if cacheErr := s.store.PutAnalysisCache(&store.AnalysisCache{
-		CacheKey:       cacheKey,
-		SIGID:          sigID,
-		SourceType:     "video",
-		DateRangeStart: start,
-		DateRangeEnd:   end,
-		PromptHash:     promptHash,
-		Result:         resp.Content,
-		Model:          resp.Model,
-		TokensUsed:     resp.TokensUsed,
-	}); cacheErr != nil {
-		_ = cacheErr
-	}
+	putCache(log, s.store, call, resp)
 
 	return &SourceSummary{
 		SIGID:      sigID,
@@ -185,30 +147,16 @@ func (s *Summarizer) SummarizeVideoTranscripts(ctx context.Context, sigID, sigNa
 
 // SummarizeSlackMessages produces a summary of Slack messages for a SIG within a date range.
 func (s *Summarizer) SummarizeSlackMessages(ctx context.Context, sigID, sigName string, messages []*store.SlackMessage, start, end time.Time) (*SourceSummary, error) {
-	if len(messages) == 0 {
-		return nil, fmt.Errorf("no slack messages to summarize for SIG %s", sigID)
-	}
-
-	// Determine the channel name from the first message (all messages belong to the same SIG).
-	channelName := sigName
+	ctx, span, log := startStage(ctx, s.logger, "summarize", sigID, "slack")
+	defer span.End()
 
-	// Build the content from all messages in the range.
-	var contentParts []string
-	for _, m := range messages {
-		entry := fmt.Sprintf("[%s] %s: %s",
-			m.MessageDate.Format("2006-01-02 15:04"), m.UserName, m.Text)
-		if m.ThreadTS != "" && m.ThreadTS != m.MessageTS {
-			entry = "  (thread reply) " + entry
-		}
-		contentParts = append(contentParts, entry)
+	call, err := s.slackCall(sigID, sigName, messages, start, end)
+	if err != nil {
+		return nil, err
 	}
-	content := strings.Join(contentParts, "\n")
-
-	contentHash := hashContent(content)
-	cacheKey := buildCacheKey(sigID, "slack", start, end, contentHash)
 
 	// Check cache.
-	cached, err := s.store.GetAnalysisCache(cacheKey)
+	cached, err := lookupCache(ctx, log, s.store, call.cacheKey)
 	if err == nil && cached != nil {
 		return &SourceSummary{
 			SIGID:      sigID,
@@ -223,41 +171,12 @@ func (s *Summarizer) SummarizeSlackMessages(ctx context.Context, sigID, sigName
 		return nil, fmt.Errorf("checking analysis cache: %w", err)
 	}
 
-	systemPrompt := fmt.Sprintf(
-		"You are analyzing Slack discussions from the #%s channel\n"+
-			"(%s SIG) between %s and %s.\n"+
-			"Identify the most significant technical discussions, questions,\n"+
-			"and announcements. Group by topic.",
-		channelName,
-		sigName,
-		start.Format("2006-01-02"),
-		end.Format("2006-01-02"),
-	)
-
-	promptHash := hashContent(systemPrompt)
-
-	resp, err := s.llm.Complete(ctx, &CompletionRequest{
-		SystemPrompt: systemPrompt,
-		UserPrompt:   content,
-	})
+	resp, err := s.client("slack").Complete(ctx, call.req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion for slack messages: %w", err)
 	}
 
-	// Cache the result.
-	if cacheErr := s.store.PutAnalysisCache(&store.AnalysisCache{
-		CacheKey:       cacheKey,
-		SIGID:          sigID,
-		SourceType:     "slack",
-		DateRangeStart: start,
-		DateRangeEnd:   end,
-		PromptHash:     promptHash,
-		Result:         resp.Content,
-		Model:          resp.Model,
-		TokensUsed:     resp.TokensUsed,
-	}); cacheErr != nil {
-		_ = cacheErr
-	}
+	putCache(log, s.store, call, resp)
 
 	return &SourceSummary{
 		SIGID:      sigID,
@@ -269,6 +188,143 @@ func (s *Summarizer) SummarizeSlackMessages(ctx context.Context, sigID, sigName
 	}, nil
 }
 
+// EstimateMeetingNotes estimates SummarizeMeetingNotes without calling the
+// LLM.
+func (s *Summarizer) EstimateMeetingNotes(sigID, sigName string, notes []*store.MeetingNote, start, end time.Time) (*CallEstimate, error) {
+	call, err := s.notesCall(sigID, sigName, notes, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return estimateCall(s.store, "notes", call)
+}
+
+// notesCall prepares the request that summarizes meeting notes.
+func (s *Summarizer) notesCall(sigID, sigName string, notes []*store.MeetingNote, start, end time.Time) (*preparedCall, error) {
+	if len(notes) == 0 {
+		return nil, fmt.Errorf("no meeting notes to summarize for SIG %s", sigID)
+	}
+
+	// Build the content from all notes in the range.
+	var contentParts []string
+	for _, note := range notes {
+		contentParts = append(contentParts, fmt.Sprintf("--- Meeting Date: %s ---\n%s",
+			note.MeetingDate.Format("2006-01-02"), note.RawText))
+	}
+	content := strings.Join(contentParts, "\n\n")
+
+	systemPrompt, err := s.prompts.render(PromptNotes, &promptData{
+		SIGName: sigName,
+		Start:   start.Format("2006-01-02"),
+		End:     end.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prepareCall(s.client("notes"), sigID, "notes", start, end, s.prompts.Version(PromptNotes), systemPrompt, content, content), nil
+}
+
+// EstimateVideoTranscripts estimates SummarizeVideoTranscripts without
+// calling the LLM.
+func (s *Summarizer) EstimateVideoTranscripts(sigID, sigName string, transcripts []*store.VideoTranscript, start, end time.Time) (*CallEstimate, error) {
+	call, err := s.videoCall(sigID, sigName, transcripts, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return estimateCall(s.store, "video", call)
+}
+
+// videoCall prepares the request that summarizes video transcripts.
+func (s *Summarizer) videoCall(sigID, sigName string, transcripts []*store.VideoTranscript, start, end time.Time) (*preparedCall, error) {
+	if len(transcripts) == 0 {
+		return nil, fmt.Errorf("no video transcripts to summarize for SIG %s", sigID)
+	}
+
+	// Build the content from all transcripts in the range.
+	var contentParts []string
+	for _, t := range transcripts {
+		contentParts = append(contentParts, fmt.Sprintf("--- Recording Date: %s (Duration: %d min) ---\n%s",
+			t.RecordingDate.Format("2006-01-02"), t.DurationMinutes, t.Transcript))
+	}
+	content := strings.Join(contentParts, "\n\n")
+
+	systemPrompt, err := s.prompts.render(PromptVideo, &promptData{SIGName: sigName})
+	if err != nil {
+		return nil, err
+	}
+	return prepareCall(s.client("video"), sigID, "video", start, end, s.prompts.Version(PromptVideo), systemPrompt, content, content), nil
+}
+
+// EstimateSlackMessages estimates SummarizeSlackMessages without calling
+// the LLM.
+func (s *Summarizer) EstimateSlackMessages(sigID, sigName string, messages []*store.SlackMessage, start, end time.Time) (*CallEstimate, error) {
+	call, err := s.slackCall(sigID, sigName, messages, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return estimateCall(s.store, "slack", call)
+}
+
+// slackCall prepares the request that summarizes Slack messages.
+func (s *Summarizer) slackCall(sigID, sigName string, messages []*store.SlackMessage, start, end time.Time) (*preparedCall, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no slack messages to summarize for SIG %s", sigID)
+	}
+
+	// Determine the channel name from the first message (all messages belong to the same SIG).
+	channelName := sigName
+
+	// Build the content from all messages in the range.
+	var contentParts []string
+	for _, m := range messages {
+		entry := fmt.Sprintf("[%s] %s: %s",
+			m.MessageDate.Format("2006-01-02 15:04"), m.UserName, m.Text)
+		if m.ThreadTS != "" && m.ThreadTS != m.MessageTS {
+			entry = "  (thread reply) " + entry
+		}
+		contentParts = append(contentParts, entry)
+	}
+	content := strings.Join(contentParts, "\n")
+
+	systemPrompt, err := s.prompts.render(PromptSlack, &promptData{
+		SIGName: sigName,
+		Channel: channelName,
+		Start:   start.Format("2006-01-02"),
+		End:     end.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return prepareCall(s.client("slack"), sigID, "slack", start, end, s.prompts.Version(PromptSlack), systemPrompt, content, content), nil
+}
+
+// putCache stores resp, the answer to call, in the analysis cache. An
+// answer from a fallback provider is stored under the key of the model
+// that gave it rather than the stage's own, so it records who answered
+// without being served in place of the stage's model once that is
+// available again. Write errors are logged, not returned.
+func putCache(log *slog.Logger, st *store.Store, call *preparedCall, resp *CompletionResponse) {
+	key := call.cacheKey
+	if resp.Fallback {
+		key = call.keyFor(resp.Provider + "/" + resp.Model)
+		log.Info("caching fallback answer under its own model", "provider", resp.Provider, "model", resp.Model)
+	}
+	if err := st.PutAnalysisCache(&store.AnalysisCache{
+		CacheKey:       key,
+		SIGID:          call.sigID,
+		SourceType:     call.sourceType,
+		DateRangeStart: call.start,
+		DateRangeEnd:   call.end,
+		PromptHash:     call.promptHash,
+		PromptVersion:  call.promptVersion,
+		Result:         resp.Content,
+		Provider:       resp.Provider,
+		Model:          resp.Model,
+		TokensUsed:     resp.TokensUsed,
+	}); err != nil {
+		log.Warn("failed to write analysis cache", "err", err)
+	}
+}
+
 // hashContent returns the hex-encoded SHA-256 hash of the given string.
 func hashContent(content string) string {
 	h := sha256.Sum256([]byte(content))
@@ -276,13 +332,21 @@ func hashContent(content string) string {
 }
 
 // buildCacheKey constructs a deterministic cache key from the given components.
-func buildCacheKey(sigID, sourceType string, start, end time.Time, contentHash string) string {
-	raw := fmt.Sprintf("%s|%s|%s|%s|%s",
+// promptHash is the hash of the rendered system prompt, so editing a prompt
+// does not serve results produced by the old wording, and model the
+// configured "provider/model" of the stage, if known, so switching models
+// does not serve the previous model's results.
+func buildCacheKey(sigID, sourceType string, start, end time.Time, contentHash, promptHash, model string) string {
+	raw := fmt.Sprintf("%s|%s|%s|%s|%s|%s",
 		sigID,
 		sourceType,
 		start.Format("2006-01-02"),
 		end.Format("2006-01-02"),
 		contentHash,
+		promptHash,
 	)
+	if model != "" {
+		raw += "|" + model
+	}
 	return hashContent(raw)
 }
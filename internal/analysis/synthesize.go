@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -12,36 +13,44 @@ import (
 
 // Synthesizer merges per-source summaries into a unified cross-source report.
 type Synthesizer struct {
-	llm   LLMClient
-	store *store.Store
+	llm     LLMClient
+	store   *store.Store
+	prompts *Prompts
+	logger  *slog.Logger
 }
 
 // NewSynthesizer creates a new Synthesizer.
 func NewSynthesizer(llm LLMClient, s *store.Store) *Synthesizer {
 	return &Synthesizer{
-		llm:   llm,
-		store: s,
+		llm:     llm,
+		store:   s,
+		prompts: DefaultPrompts(),
+		logger:  slog.Default(),
 	}
 }
 
+// SetLogger replaces the synthesizer's logger.
+func (s *Synthesizer) SetLogger(l *slog.Logger) {
+	s.logger = l
+}
+
+// SetPrompts replaces the embedded system prompts.
+func (s *Synthesizer) SetPrompts(ps *Prompts) {
+	s.prompts = ps
+}
+
 // Synthesize produces a unified report from multiple per-source summaries for a SIG.
 func (s *Synthesizer) Synthesize(ctx context.Context, sigID, sigName string, summaries []*SourceSummary, start, end time.Time) (*SynthesizedReport, error) {
-	if len(summaries) == 0 {
-		return nil, fmt.Errorf("no summaries to synthesize for SIG %s", sigID)
-	}
+	ctx, span, log := startStage(ctx, s.logger, "synthesize", sigID, "all")
+	defer span.End()
 
-	// Build the user prompt from all source summaries.
-	var parts []string
-	for _, summary := range summaries {
-		parts = append(parts, fmt.Sprintf("=== Source: %s ===\n%s", summary.SourceType, summary.Summary))
+	call, err := s.prepare(sigID, sigName, summaries, start, end)
+	if err != nil {
+		return nil, err
 	}
-	content := strings.Join(parts, "\n\n")
-
-	contentHash := hashContent(content)
-	cacheKey := buildCacheKey(sigID, "synthesis", start, end, contentHash)
 
 	// Check cache.
-	cached, err := s.store.GetAnalysisCache(cacheKey)
+	cached, err := lookupCache(ctx, log, s.store, call.cacheKey)
 	if err == nil && cached != nil {
 		return &SynthesizedReport{
 			SIGID:      sigID,
@@ -55,38 +64,12 @@ func (s *Synthesizer) Synthesize(ctx context.Context, sigID, sigName string, sum
 		return nil, fmt.Errorf("checking analysis cache: %w", err)
 	}
 
-	systemPrompt := fmt.Sprintf(
-		"Given the following summaries from meeting notes, video recordings,\n"+
-			"and Slack discussions for the %s SIG, produce a unified report.\n"+
-			"Deduplicate topics discussed across sources. Flag items where different\n"+
-			"sources provide complementary information.",
-		sigName,
-	)
-
-	promptHash := hashContent(systemPrompt)
-
-	resp, err := s.llm.Complete(ctx, &CompletionRequest{
-		SystemPrompt: systemPrompt,
-		UserPrompt:   content,
-	})
+	resp, err := s.llm.Complete(ctx, call.req)
 	if err != nil {
 		return nil, fmt.Errorf("LLM completion for synthesis: %w", err)
 	}
 
-	// Cache the result.
-	if cacheErr := s.store.PutAnalysisCache(&store.AnalysisCache{
-		CacheKey:       cacheKey,
-		SIGID:          sigID,
-		SourceType:     "synthesis",
-		DateRangeStart: start,
-		DateRangeEnd:   end,
-		PromptHash:     promptHash,
-		Result:         resp.Content,
-		Model:          resp.Model,
-		TokensUsed:     resp.TokensUsed,
-	}); cacheErr != nil {
-		_ = cacheErr
-	}
+	putCache(log, s.store, call, resp)
 
 	return &SynthesizedReport{
 		SIGID:      sigID,
@@ -96,3 +79,32 @@ func (s *Synthesizer) Synthesize(ctx context.Context, sigID, sigName string, sum
 		TokensUsed: resp.TokensUsed,
 	}, nil
 }
+
+// Estimate estimates Synthesize without calling the LLM.
+func (s *Synthesizer) Estimate(sigID, sigName string, summaries []*SourceSummary, start, end time.Time) (*CallEstimate, error) {
+	call, err := s.prepare(sigID, sigName, summaries, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return estimateCall(s.store, "synthesis", call)
+}
+
+// prepare builds the synthesis request from the per-source summaries.
+func (s *Synthesizer) prepare(sigID, sigName string, summaries []*SourceSummary, start, end time.Time) (*preparedCall, error) {
+	if len(summaries) == 0 {
+		return nil, fmt.Errorf("no summaries to synthesize for SIG %s", sigID)
+	}
+
+	// Build the user prompt from all source summaries.
+	var parts []string
+	for _, summary := range summaries {
+		parts = append(parts, fmt.Sprintf("=== Source: %s ===\n%s", summary.SourceType, summary.Summary))
+	}
+	content := strings.Join(parts, "\n\n")
+
+	systemPrompt, err := s.prompts.render(PromptSynthesis, &promptData{SIGName: sigName})
+	if err != nil {
+		return nil, err
+	}
+	return prepareCall(s.llm, sigID, "synthesis", start, end, s.prompts.Version(PromptSynthesis), systemPrompt, content, content), nil
+}
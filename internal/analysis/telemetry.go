@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedClient wraps an LLMClient with a span and metrics per Complete call.
+type TracedClient struct {
+	next     LLMClient
+	provider string
+	model    string
+}
+
+// NewTracedClient wraps next, labelling its telemetry with provider and model.
+func NewTracedClient(next LLMClient, provider, model string) *TracedClient {
+	return &TracedClient{next: next, provider: provider, model: model}
+}
+
+// DescribeModel returns the provider and model the client is labelled with.
+func (c *TracedClient) DescribeModel() (provider, model string) {
+	return c.provider, c.model
+}
+
+// Complete calls the wrapped client inside an "llm.complete" span.
+func (c *TracedClient) Complete(ctx context.Context, req *CompletionRequest) (*CompletionResponse, error) {
+	ctx, span := telemetry.Start(ctx, "llm.complete",
+		telemetry.AttrProvider.String(c.provider),
+		telemetry.AttrModel.String(c.model),
+	)
+	start := time.Now()
+	resp, err := c.next.Complete(ctx, req)
+
+	tokens := 0
+	if resp != nil {
+		tokens = resp.TokensUsed
+		span.SetAttributes(telemetry.AttrTokens.Int(tokens))
+	}
+	telemetry.RecordLLM(ctx, c.provider, c.model, tokens, time.Since(start), err)
+	telemetry.End(span, err)
+	return resp, err
+}
+
+// startStage starts the span for one analysis stage of a SIG and returns a
+// logger carrying the same SIG, source and stage.
+func startStage(ctx context.Context, logger *slog.Logger, stage, sigID, sourceType string) (context.Context, trace.Span, *slog.Logger) {
+	ctx, span := telemetry.Start(ctx, "analysis."+stage,
+		telemetry.AttrSIGID.String(sigID),
+		telemetry.AttrSourceType.String(sourceType),
+	)
+	log := logger.With(logging.KeySIG, sigID, logging.KeySource, sourceType, logging.KeyStage, stage)
+	return ctx, span, log
+}
+
+// lookupCache reads an analysis cache entry and records on the current span
+// and in the log whether it was a hit.
+func lookupCache(ctx context.Context, log *slog.Logger, st *store.Store, key string) (*store.AnalysisCache, error) {
+	cached, err := st.GetAnalysisCache(key)
+	hit := err == nil && cached != nil
+	trace.SpanFromContext(ctx).SetAttributes(telemetry.AttrCacheHit.Bool(hit))
+	log.Debug("analysis cache lookup", "hit", hit)
+	return cached, err
+}
@@ -0,0 +1,442 @@
+// Package archive moves fetched source data between databases as a
+// portable zip archive: one JSONL file per table, with a manifest recording
+// the schema version the data was exported at and the filter it was
+// exported with. Importing upserts every row, so importing the same archive
+// twice, or an archive overlapping data already fetched, is harmless.
+package archive
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/registry"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// FormatVersion is the version of the archive layout written by Export.
+// Import rejects archives of a later version.
+const FormatVersion = 1
+
+const manifestName = "manifest.json"
+
+// Names of the tables in an archive, in the order they are imported: SIGs
+// first, as the other tables reference them.
+const (
+	TableSIGs             = "sigs"
+	TableMeetingNotes     = "meeting_notes"
+	TableVideoTranscripts = "video_transcripts"
+	TableSlackMessages    = "slack_messages"
+)
+
+// Tables lists the tables in import order.
+var Tables = []string{TableSIGs, TableMeetingNotes, TableVideoTranscripts, TableSlackMessages}
+
+// Manifest describes an archive.
+type Manifest struct {
+	FormatVersion int `json:"format_version"`
+	// SchemaVersion is the database schema version of the exporting store.
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	// SIGs, Since and Until are the filter the archive was exported with;
+	// empty means all SIGs and no date bound.
+	SIGs  []string `json:"sigs,omitempty"`
+	Since string   `json:"since,omitempty"`
+	Until string   `json:"until,omitempty"`
+	// Rows counts the rows of each table.
+	Rows map[string]int `json:"rows"`
+}
+
+// Filter selects the data to export or import. Empty fields match all data.
+type Filter struct {
+	// SIGIDs are SIG names or IDs, matched as registry.MatchSIGFilter does.
+	SIGIDs []string
+	// Since and Until bound the meeting, recording and message dates, as
+	// whole days in their location: both are inclusive.
+	Since time.Time
+	Until time.Time
+}
+
+// window returns the start and end times covering f's dates.
+func (f Filter) window() (time.Time, time.Time) {
+	end := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if !f.Until.IsZero() {
+		end = config.EndOfDay(f.Until)
+	}
+	return f.Since, end
+}
+
+// location returns the time zone of f's dates, in which meeting dates are
+// days.
+func (f Filter) location() *time.Location {
+	switch {
+	case !f.Since.IsZero():
+		return f.Since.Location()
+	case !f.Until.IsZero():
+		return f.Until.Location()
+	}
+	return time.UTC
+}
+
+// matchesSIG reports whether the SIG sigID is selected by f.
+func (f Filter) matchesSIG(sigID string) bool {
+	return registry.MatchSIGFilter(sigID, f.SIGIDs)
+}
+
+// matches reports whether the row of sigID dated t is selected by f.
+func (f Filter) matches(sigID string, t time.Time) bool {
+	if !f.matchesSIG(sigID) {
+		return false
+	}
+	start, end := f.window()
+	return !t.Before(start) && !t.After(end)
+}
+
+// The rows of each table as written to the archive. Field names follow the
+// database columns; fetched_at is not kept, as importing is fetching.
+type (
+	sigRow struct {
+		ID               string `json:"id"`
+		Name             string `json:"name"`
+		Category         string `json:"category"`
+		MeetingTime      string `json:"meeting_time,omitempty"`
+		NotesDocID       string `json:"notes_doc_id,omitempty"`
+		SlackChannelID   string `json:"slack_channel_id,omitempty"`
+		SlackChannelName string `json:"slack_channel_name,omitempty"`
+	}
+	meetingNoteRow struct {
+		SIGID       string `json:"sig_id"`
+		DocID       string `json:"doc_id"`
+		MeetingDate string `json:"meeting_date"` // YYYY-MM-DD
+		RawText     string `json:"raw_text"`
+		ContentHash string `json:"content_hash"`
+	}
+	videoTranscriptRow struct {
+		SIGID            string    `json:"sig_id"`
+		ZoomURL          string    `json:"zoom_url"`
+		RecordingDate    time.Time `json:"recording_date"`
+		DurationMinutes  int       `json:"duration_minutes,omitempty"`
+		Transcript       string    `json:"transcript"`
+		TranscriptSource string    `json:"transcript_source,omitempty"`
+		ContentHash      string    `json:"content_hash,omitempty"`
+	}
+	slackMessageRow struct {
+		SIGID       string    `json:"sig_id"`
+		ChannelID   string    `json:"channel_id"`
+		MessageTS   string    `json:"message_ts"`
+		ThreadTS    string    `json:"thread_ts,omitempty"`
+		UserID      string    `json:"user_id,omitempty"`
+		UserName    string    `json:"user_name,omitempty"`
+		Text        string    `json:"text"`
+		MessageDate time.Time `json:"message_date"`
+	}
+)
+
+// Export writes the SIGs, meeting notes, video transcripts and Slack
+// messages of st selected by f to w as an archive, and returns its
+// manifest. Every exported row's SIG is included.
+func Export(st *store.Store, w io.Writer, f Filter) (*Manifest, error) {
+	schema, err := st.SchemaVersion()
+	if err != nil {
+		return nil, fmt.Errorf("reading schema version: %w", err)
+	}
+	m := &Manifest{
+		FormatVersion: FormatVersion,
+		SchemaVersion: schema,
+		CreatedAt:     time.Now().UTC(),
+		SIGs:          f.SIGIDs,
+		Rows:          make(map[string]int),
+	}
+	if !f.Since.IsZero() {
+		m.Since = f.Since.Format("2006-01-02")
+	}
+	if !f.Until.IsZero() {
+		m.Until = f.Until.Format("2006-01-02")
+	}
+
+	all, err := st.ListSIGs(nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing SIGs: %w", err)
+	}
+	var sigs []*store.SIG
+	for _, sig := range all {
+		if f.matchesSIG(sig.ID) {
+			sigs = append(sigs, sig)
+		}
+	}
+	start, end := f.window()
+
+	// Tables are written one after the other, SIG by SIG, so only one
+	// SIG's rows of one table are held at a time.
+	zw := zip.NewWriter(w)
+	for _, table := range Tables {
+		fw, err := create(zw, table+".jsonl", m.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(fw)
+		for _, sig := range sigs {
+			rows, err := exportRows(st, table, sig, start, end)
+			if err != nil {
+				return nil, fmt.Errorf("exporting %s of %s: %w", table, sig.ID, err)
+			}
+			for _, row := range rows {
+				if err := enc.Encode(row); err != nil {
+					return nil, fmt.Errorf("writing %s: %w", table, err)
+				}
+			}
+			m.Rows[table] += len(rows)
+		}
+	}
+	mw, err := create(zw, manifestName, m.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("writing manifest: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// create adds the compressed file name, modified at t, to zw.
+func create(zw *zip.Writer, name string, t time.Time) (io.Writer, error) {
+	return zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: t})
+}
+
+// exportRows returns the archive rows of table for sig between start and
+// end.
+func exportRows(st *store.Store, table string, sig *store.SIG, start, end time.Time) ([]any, error) {
+	var rows []any
+	switch table {
+	case TableSIGs:
+		rows = append(rows, sigRow{
+			ID:               sig.ID,
+			Name:             sig.Name,
+			Category:         sig.Category,
+			MeetingTime:      sig.MeetingTime,
+			NotesDocID:       sig.NotesDocID,
+			SlackChannelID:   sig.SlackChannelID,
+			SlackChannelName: sig.SlackChannelName,
+		})
+	case TableMeetingNotes:
+		notes, err := st.GetMeetingNotes(sig.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			rows = append(rows, meetingNoteRow{
+				SIGID:       n.SIGID,
+				DocID:       n.DocID,
+				MeetingDate: n.MeetingDate.Format("2006-01-02"),
+				RawText:     n.RawText,
+				ContentHash: n.ContentHash,
+			})
+		}
+	case TableVideoTranscripts:
+		transcripts, err := st.GetVideoTranscripts(sig.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transcripts {
+			rows = append(rows, videoTranscriptRow{
+				SIGID:            t.SIGID,
+				ZoomURL:          t.ZoomURL,
+				RecordingDate:    t.RecordingDate.UTC(),
+				DurationMinutes:  t.DurationMinutes,
+				Transcript:       t.Transcript,
+				TranscriptSource: t.TranscriptSource,
+				ContentHash:      t.ContentHash,
+			})
+		}
+	case TableSlackMessages:
+		messages, err := st.GetSlackMessages(sig.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range messages {
+			rows = append(rows, slackMessageRow{
+				SIGID:       msg.SIGID,
+				ChannelID:   msg.ChannelID,
+				MessageTS:   msg.MessageTS,
+				ThreadTS:    msg.ThreadTS,
+				UserID:      msg.UserID,
+				UserName:    msg.UserName,
+				Text:        msg.Text,
+				MessageDate: msg.MessageDate.UTC(),
+			})
+		}
+	}
+	return rows, nil
+}
+
+// ReadManifest returns the manifest of the archive in r, of size bytes.
+func ReadManifest(r io.ReaderAt, size int64) (*Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+	return readManifest(zr)
+}
+
+func readManifest(zr *zip.Reader) (*Manifest, error) {
+	f, err := zr.Open(manifestName)
+	if err != nil {
+		return nil, fmt.Errorf("archive has no %s", manifestName)
+	}
+	defer f.Close()
+	m := &Manifest{}
+	if err := json.NewDecoder(f).Decode(m); err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Import upserts the rows of the archive in r, of size bytes, selected by f
+// into st, and returns the archive's manifest and the rows imported per
+// table. Archives of a later format or schema version than st are
+// rejected, and so are rows of SIGs neither in the archive nor in st. An
+// import that fails part way leaves the rows before the failure, and can
+// simply be run again.
+func Import(st *store.Store, r io.ReaderAt, size int64, f Filter) (*Manifest, map[string]int, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading archive: %w", err)
+	}
+	m, err := readManifest(zr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if m.FormatVersion > FormatVersion {
+		return m, nil, fmt.Errorf("archive format version %d is newer than this version supports (%d)", m.FormatVersion, FormatVersion)
+	}
+	schema, err := st.SchemaVersion()
+	if err != nil {
+		return m, nil, fmt.Errorf("reading schema version: %w", err)
+	}
+	if m.SchemaVersion > schema {
+		return m, nil, fmt.Errorf("archive was exported at schema version %d, newer than this database's %d; upgrade before importing", m.SchemaVersion, schema)
+	}
+
+	imported := make(map[string]int)
+	for _, table := range Tables {
+		n, err := importTable(st, zr, table, f)
+		imported[table] = n
+		if err != nil {
+			return m, imported, fmt.Errorf("importing %s: %w", table, err)
+		}
+	}
+	return m, imported, nil
+}
+
+// importTable upserts the rows of one table selected by f. A table missing
+// from the archive imports nothing.
+func importTable(st *store.Store, zr *zip.Reader, table string, f Filter) (int, error) {
+	file, err := zr.Open(table + ".jsonl")
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	dec := json.NewDecoder(file)
+
+	switch table {
+	case TableSIGs:
+		return importRows(dec, func(row *sigRow) (bool, error) {
+			if !f.matchesSIG(row.ID) {
+				return false, nil
+			}
+			return true, st.UpsertSIG(&store.SIG{
+				ID:               row.ID,
+				Name:             row.Name,
+				Category:         row.Category,
+				MeetingTime:      row.MeetingTime,
+				NotesDocID:       row.NotesDocID,
+				SlackChannelID:   row.SlackChannelID,
+				SlackChannelName: row.SlackChannelName,
+			})
+		})
+	case TableMeetingNotes:
+		return importRows(dec, func(row *meetingNoteRow) (bool, error) {
+			date, err := time.ParseInLocation("2006-01-02", row.MeetingDate, f.location())
+			if err != nil {
+				return false, fmt.Errorf("invalid meeting_date: %w", err)
+			}
+			if !f.matches(row.SIGID, date) {
+				return false, nil
+			}
+			return true, st.UpsertMeetingNote(&store.MeetingNote{
+				SIGID:       row.SIGID,
+				DocID:       row.DocID,
+				MeetingDate: date,
+				RawText:     row.RawText,
+				ContentHash: row.ContentHash,
+			})
+		})
+	case TableVideoTranscripts:
+		return importRows(dec, func(row *videoTranscriptRow) (bool, error) {
+			if !f.matches(row.SIGID, row.RecordingDate) {
+				return false, nil
+			}
+			return true, st.UpsertVideoTranscript(&store.VideoTranscript{
+				SIGID:            row.SIGID,
+				ZoomURL:          row.ZoomURL,
+				RecordingDate:    row.RecordingDate,
+				DurationMinutes:  row.DurationMinutes,
+				Transcript:       row.Transcript,
+				TranscriptSource: row.TranscriptSource,
+				ContentHash:      row.ContentHash,
+			})
+		})
+	case TableSlackMessages:
+		return importRows(dec, func(row *slackMessageRow) (bool, error) {
+			if !f.matches(row.SIGID, row.MessageDate) {
+				return false, nil
+			}
+			return true, st.UpsertSlackMessage(&store.SlackMessage{
+				SIGID:       row.SIGID,
+				ChannelID:   row.ChannelID,
+				MessageTS:   row.MessageTS,
+				ThreadTS:    row.ThreadTS,
+				UserID:      row.UserID,
+				UserName:    row.UserName,
+				Text:        row.Text,
+				MessageDate: row.MessageDate,
+			})
+		})
+	}
+	return 0, fmt.Errorf("unknown table %q", table)
+}
+
+// importRows decodes rows from dec until EOF and passes each to upsert,
+// which reports whether it imported the row. It returns the number
+// imported.
+func importRows[T any](dec *json.Decoder, upsert func(*T) (bool, error)) (int, error) {
+	n := 0
+	for line := 1; ; line++ {
+		var row T
+		if err := dec.Decode(&row); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, fmt.Errorf("line %d: %w", line, err)
+		}
+		ok, err := upsert(&row)
+		if err != nil {
+			return n, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			n++
+		}
+	}
+}
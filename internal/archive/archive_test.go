@@ -0,0 +1,207 @@
+package archive
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// seed fills st with two SIGs, each with a note, a transcript and a Slack
+// message in January and in March 2026.
+func seed(t *testing.T, st *store.Store) {
+	t.Helper()
+	for _, sig := range []*store.SIG{
+		{ID: "collector", Name: "Collector", Category: "implementation", SlackChannelID: "C1"},
+		{ID: "java", Name: "Java", Category: "implementation", SlackChannelID: "C2"},
+	} {
+		if err := st.UpsertSIG(sig); err != nil {
+			t.Fatalf("UpsertSIG: %v", err)
+		}
+		for _, day := range []time.Time{
+			time.Date(2026, 1, 14, 17, 0, 0, 0, time.UTC),
+			time.Date(2026, 3, 11, 17, 0, 0, 0, time.UTC),
+		} {
+			if err := st.UpsertMeetingNote(&store.MeetingNote{SIGID: sig.ID, DocID: "doc", MeetingDate: day, RawText: "Notes " + sig.ID, ContentHash: "h"}); err != nil {
+				t.Fatalf("UpsertMeetingNote: %v", err)
+			}
+			if err := st.UpsertVideoTranscript(&store.VideoTranscript{SIGID: sig.ID, ZoomURL: "https://zoom.us/rec/" + sig.ID + day.Format("0102"), RecordingDate: day, DurationMinutes: 60, Transcript: "Transcript"}); err != nil {
+				t.Fatalf("UpsertVideoTranscript: %v", err)
+			}
+			if err := st.UpsertSlackMessage(&store.SlackMessage{SIGID: sig.ID, ChannelID: sig.SlackChannelID, MessageTS: day.Format("0102.1504"), UserName: "pablo", Text: "Hi", MessageDate: day}); err != nil {
+				t.Fatalf("UpsertSlackMessage: %v", err)
+			}
+		}
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := newTestStore(t)
+	seed(t, src)
+
+	var buf bytes.Buffer
+	m, err := Export(src, &buf, Filter{
+		SIGIDs: []string{"collector"},
+		Since:  time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Until:  time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	want := map[string]int{TableSIGs: 1, TableMeetingNotes: 1, TableVideoTranscripts: 1, TableSlackMessages: 1}
+	for table, n := range want {
+		if m.Rows[table] != n {
+			t.Errorf("exported %d %s, want %d", m.Rows[table], table, n)
+		}
+	}
+	if m.Since != "2026-03-01" || m.Until != "2026-03-11" || m.SchemaVersion == 0 {
+		t.Errorf("manifest = %+v", m)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+	read, err := ReadManifest(r, r.Size())
+	if err != nil {
+		t.Fatalf("ReadManifest: %v", err)
+	}
+	if read.SchemaVersion != m.SchemaVersion || read.Rows[TableMeetingNotes] != 1 {
+		t.Errorf("read manifest = %+v, want %+v", read, m)
+	}
+
+	dst := newTestStore(t)
+	_, imported, err := Import(dst, r, r.Size(), Filter{})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	for table, n := range want {
+		if imported[table] != n {
+			t.Errorf("imported %d %s, want %d", imported[table], table, n)
+		}
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	notes, err := dst.GetMeetingNotes("collector", start, end)
+	if err != nil || len(notes) != 1 || notes[0].RawText != "Notes collector" {
+		t.Errorf("imported notes = %v, %v; want the March note", notes, err)
+	}
+	messages, err := dst.GetSlackMessages("collector", start, end)
+	if err != nil || len(messages) != 1 || !messages[0].MessageDate.Equal(time.Date(2026, 3, 11, 17, 0, 0, 0, time.UTC)) {
+		t.Errorf("imported messages = %v, %v; want the March message", messages, err)
+	}
+	if sigs, _ := dst.ListSIGs(nil); len(sigs) != 1 {
+		t.Errorf("imported %d SIGs, want 1", len(sigs))
+	}
+
+	// Importing again upserts the same rows.
+	if _, _, err := Import(dst, r, r.Size(), Filter{}); err != nil {
+		t.Fatalf("second Import: %v", err)
+	}
+	if notes, _ := dst.GetMeetingNotes("collector", start, end); len(notes) != 1 {
+		t.Errorf("second import left %d notes, want 1", len(notes))
+	}
+}
+
+func TestImport_Filter(t *testing.T) {
+	src := newTestStore(t)
+	seed(t, src)
+	var buf bytes.Buffer
+	if _, err := Export(src, &buf, Filter{}); err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+
+	dst := newTestStore(t)
+	r := bytes.NewReader(buf.Bytes())
+	_, imported, err := Import(dst, r, r.Size(), Filter{SIGIDs: []string{"java"}, Until: time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Import: %v", err)
+	}
+	want := map[string]int{TableSIGs: 1, TableMeetingNotes: 1, TableVideoTranscripts: 1, TableSlackMessages: 1}
+	for table, n := range want {
+		if imported[table] != n {
+			t.Errorf("imported %d %s, want %d", imported[table], table, n)
+		}
+	}
+}
+
+func TestExport_FilterNamesAndZone(t *testing.T) {
+	src := newTestStore(t)
+	seed(t, src)
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+
+	// The March 11 17:00 UTC recording and message are on March 12 in
+	// Tokyo; the meeting note is dated March 11.
+	var buf bytes.Buffer
+	day := time.Date(2026, 3, 12, 0, 0, 0, 0, tokyo)
+	m, err := Export(src, &buf, Filter{SIGIDs: []string{"Java"}, Since: day, Until: day})
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	want := map[string]int{TableSIGs: 1, TableMeetingNotes: 0, TableVideoTranscripts: 1, TableSlackMessages: 1}
+	for table, n := range want {
+		if m.Rows[table] != n {
+			t.Errorf("exported %d %s, want %d", m.Rows[table], table, n)
+		}
+	}
+}
+
+func TestImport_Rejects(t *testing.T) {
+	st := newTestStore(t)
+	schema, err := st.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		files   map[string]any
+		wantErr string
+	}{
+		{"no manifest", map[string]any{}, "no manifest.json"},
+		{"newer format", map[string]any{manifestName: Manifest{FormatVersion: FormatVersion + 1}}, "format version"},
+		{"newer schema", map[string]any{manifestName: Manifest{FormatVersion: FormatVersion, SchemaVersion: schema + 1}}, "schema version"},
+		{"unknown SIG", map[string]any{
+			manifestName:                 Manifest{FormatVersion: FormatVersion, SchemaVersion: schema},
+			TableMeetingNotes + ".jsonl": meetingNoteRow{SIGID: "missing", DocID: "doc", MeetingDate: "2026-03-11", RawText: "x"},
+		}, "meeting_notes: line 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			zw := zip.NewWriter(&buf)
+			for name, v := range tt.files {
+				w, err := zw.Create(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := json.NewEncoder(w).Encode(v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := zw.Close(); err != nil {
+				t.Fatal(err)
+			}
+			r := bytes.NewReader(buf.Bytes())
+			_, _, err := Import(st, r, r.Size(), Filter{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Import error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package browser
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/chromedp/chromedp"
@@ -19,6 +20,12 @@ const (
 type Pool struct {
 	headless bool
 	timeout  time.Duration
+
+	// browserCtx is the shared browser started by Start, or nil if every
+	// context gets its own browser.
+	mu            sync.Mutex
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
 }
 
 // NewPool creates a new browser pool.
@@ -35,9 +42,68 @@ func (p *Pool) SetTimeout(d time.Duration) {
 	p.timeout = d
 }
 
+// Start launches a shared browser. Later NewContext calls open tabs in it
+// instead of starting a browser each, which saves a cold start per page in
+// long-running processes. Cleanup stops it.
+func (p *Pool) Start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.browserCtx != nil {
+		return nil
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), p.allocatorOptions()...)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return err
+	}
+
+	p.browserCtx = browserCtx
+	p.browserCancel = func() {
+		browserCancel()
+		allocCancel()
+	}
+	return nil
+}
+
 // NewContext creates a new browser context. The caller must call the returned
 // cancel function when done to release resources.
 func (p *Pool) NewContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	p.mu.Lock()
+	shared := p.browserCtx
+	p.mu.Unlock()
+
+	if shared != nil {
+		// Open a tab in the shared browser, tied to ctx's cancellation.
+		tabCtx, tabCancel := chromedp.NewContext(shared)
+		timeoutCtx, timeoutCancel := context.WithTimeout(tabCtx, p.timeout)
+		stop := context.AfterFunc(ctx, tabCancel)
+		return timeoutCtx, func() {
+			stop()
+			timeoutCancel()
+			tabCancel()
+		}
+	}
+
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, p.allocatorOptions()...)
+	taskCtx, taskCancel := chromedp.NewContext(allocCtx)
+
+	// Apply timeout.
+	timeoutCtx, timeoutCancel := context.WithTimeout(taskCtx, p.timeout)
+
+	cancel := func() {
+		timeoutCancel()
+		taskCancel()
+		allocCancel()
+	}
+
+	return timeoutCtx, cancel
+}
+
+// allocatorOptions returns the Chrome flags used for every browser.
+func (p *Pool) allocatorOptions() []chromedp.ExecAllocatorOption {
 	var opts []chromedp.ExecAllocatorOption
 	opts = append(opts, chromedp.DefaultExecAllocatorOptions[:]...)
 
@@ -59,25 +125,17 @@ func (p *Pool) NewContext(ctx context.Context) (context.Context, context.CancelF
 		chromedp.Flag("disable-translate", true),
 		chromedp.WindowSize(1280, 900),
 	)
-
-	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
-	taskCtx, taskCancel := chromedp.NewContext(allocCtx)
-
-	// Apply timeout.
-	timeoutCtx, timeoutCancel := context.WithTimeout(taskCtx, p.timeout)
-
-	cancel := func() {
-		timeoutCancel()
-		taskCancel()
-		allocCancel()
-	}
-
-	return timeoutCtx, cancel
+	return opts
 }
 
-// Cleanup releases any shared resources held by the pool.
-// Currently a no-op since contexts are created per-use, but reserved for
-// future connection pooling.
+// Cleanup releases any shared resources held by the pool, stopping the
+// browser launched by Start.
 func (p *Pool) Cleanup() {
-	// No shared resources to clean up currently.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.browserCancel != nil {
+		p.browserCancel()
+		p.browserCtx = nil
+		p.browserCancel = nil
+	}
 }
@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +20,7 @@ type Config struct {
 	SIGs        []string
 	Topics      []string
 	OutputDir   string
-	Format      string // "markdown" or "json"
+	Format      string // "markdown", "json" or "html"
 	DBPath      string
 	Workers     int
 	Verbose     bool
@@ -27,6 +31,78 @@ type Config struct {
 	ConfigFile  string
 	ContextFile string
 
+	// Template is a text/template file that replaces the built-in Markdown
+	// digest layout. Empty uses the default.
+	Template string
+
+	// PromptsDir holds <name>.tmpl files that replace the embedded LLM
+	// system prompts of the same name. Empty uses the embedded prompts.
+	PromptsDir string
+
+	// Since, Until and Week select an absolute window of whole calendar days
+	// in Timezone instead of a lookback ending now. Since and Until are
+	// YYYY-MM-DD; Week is an ISO week like "2026-W41".
+	Since string
+	Until string
+	Week  string
+	// Timezone is the IANA time zone used for day boundaries. Empty means
+	// the local time zone.
+	Timezone string
+	// LookbackMonths, when > 0, makes the lookback N calendar months rather
+	// than the approximate Lookback duration.
+	LookbackMonths int
+
+	// SinceLastReport starts the window at the end of the previous digest
+	// for the same SIG set and marks items NEW/UPDATED/ONGOING against it.
+	SinceLastReport bool
+
+	// MaxCost and MaxTokens cap the projected LLM usage of an analysis run,
+	// in US dollars and tokens. Over budget, video summaries are skipped,
+	// and the run is aborted if that is not enough. Zero means no limit.
+	MaxCost   float64
+	MaxTokens int
+
+	// OTLPEndpoint is the OTLP/HTTP base URL the tool exports its own traces
+	// and metrics to. Empty falls back to OTEL_EXPORTER_OTLP_ENDPOINT, and
+	// disables telemetry if that is unset too.
+	OTLPEndpoint string
+
+	// LogFile, LogFormat and LogLevel configure the structured logger. An
+	// empty LogLevel means info, or debug with Verbose.
+	LogFile   string
+	LogFormat string // "text" or "json"
+	LogLevel  string
+
+	// Progress selects the live progress display of long runs: "auto" (the
+	// default) redraws in place on a terminal and prints periodic status
+	// lines otherwise; "tty", "plain" and "off" force one behavior.
+	Progress string
+
+	// Notify lists the hooks notified about run events, read from the
+	// "notify" key of the config file.
+	Notify []NotifyHook
+
+	// Email configures digest delivery by email, read from the "email" key
+	// of the config file.
+	Email EmailConfig
+
+	// Feeds configures the Atom feeds the report command maintains in
+	// OutputDir, read from the "feeds" key of the config file.
+	Feeds FeedConfig
+
+	// Profiles are extra relevance lenses each SIG is scored against, read
+	// from the "profiles" key of the config file. Each writes its own digest
+	// next to the built-in Datadog one.
+	Profiles []ProfileConfig
+
+	// Cache configures the automatic pruning of the analysis cache, read
+	// from the "cache" key of the config file.
+	Cache CacheConfig
+
+	// Retention is how long "data prune" keeps fetched source data, read
+	// from the "retention" key of the config file.
+	Retention RetentionConfig
+
 	LLM   LLMConfig
 	Slack SlackConfig
 }
@@ -37,6 +113,65 @@ type LLMConfig struct {
 	Model         string
 	AnthropicKey  string
 	OpenAIKey     string
+
+	// Stages overrides the provider, model and request settings of single
+	// analysis stages, keyed by a name in LLMStages. Unset fields fall back
+	// to the top-level provider and model and the client defaults.
+	Stages map[string]StageLLMConfig
+
+	// Fallbacks are tried in order, for every stage, when the stage's
+	// provider is rate limited, overloaded or unreachable.
+	Fallbacks []FallbackLLMConfig
+
+	// Batch sends a run's per-source summaries to Anthropic as one Message
+	// Batch, which takes longer but costs about half as much. Summary
+	// stages on other providers are unaffected.
+	Batch bool
+}
+
+// FallbackLLMConfig is one provider and model of the fallback chain.
+type FallbackLLMConfig struct {
+	Provider string `mapstructure:"provider"`
+	Model    string `mapstructure:"model"`
+}
+
+// LLMStages are the analysis stages whose LLM settings can be configured
+// separately: one per summarized source, then synthesis and relevance.
+var LLMStages = []string{"notes", "video", "slack", "synthesis", "relevance"}
+
+// StageLLMConfig is the LLM configuration of one analysis stage.
+type StageLLMConfig struct {
+	Provider string `mapstructure:"provider"`
+	Model    string `mapstructure:"model"`
+	// MaxTokens caps the response length. Zero means the client default.
+	MaxTokens int `mapstructure:"max_tokens"`
+	// Temperature is the sampling temperature. Zero means the client
+	// default.
+	Temperature float64 `mapstructure:"temperature"`
+}
+
+// Stage returns the effective configuration of an analysis stage, with the
+// provider and model filled in from the top level when not overridden.
+func (c LLMConfig) Stage(name string) StageLLMConfig {
+	sc := c.Stages[name]
+	if sc.Provider == "" {
+		sc.Provider = c.Provider
+	}
+	if sc.Model == "" && sc.Provider == c.Provider {
+		sc.Model = c.Model
+	}
+	return sc
+}
+
+// APIKey returns the API key configured for provider.
+func (c LLMConfig) APIKey(provider string) string {
+	switch provider {
+	case "anthropic":
+		return c.AnthropicKey
+	case "openai":
+		return c.OpenAIKey
+	}
+	return ""
 }
 
 // SlackConfig holds Slack credential paths.
@@ -44,6 +179,138 @@ type SlackConfig struct {
 	CredentialsFile string
 }
 
+// NotifyHook sends notifications about selected run events to a webhook,
+// a Slack incoming webhook or a local command.
+type NotifyHook struct {
+	Name string `mapstructure:"name"`
+	// Type is "webhook" (JSON POST), "slack" (incoming webhook) or "command".
+	Type string `mapstructure:"type"`
+	// URL is the endpoint of webhook and slack hooks.
+	URL string `mapstructure:"url"`
+	// Command is the program and arguments of a command hook. It receives
+	// the notification as JSON on stdin.
+	Command []string `mapstructure:"command"`
+	// Events selects the notifications sent: "high_item", "run_failed",
+	// "run_completed" and "slack_auth_failed". Empty means all.
+	Events []string `mapstructure:"events"`
+	// SIGs restricts SIG-specific notifications (high_item) to these SIGs,
+	// matched by name, ID or ID prefix as --sigs is. Empty means all.
+	SIGs []string `mapstructure:"sigs"`
+	// Retries is the number of times a failed delivery is retried. Zero
+	// means the default of 3; use -1 to disable retries.
+	Retries int `mapstructure:"retries"`
+	// Timeout bounds each delivery attempt. Zero means 10s.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// EmailConfig holds the SMTP server digests are sent through and who
+// receives which SIGs.
+type EmailConfig struct {
+	SMTP SMTPConfig `mapstructure:"smtp"`
+	// From is the sender address, e.g. "OTel SIG Tracker <otel@example.com>".
+	From string `mapstructure:"from"`
+	// Subscriptions lists the recipients and the SIGs each one receives.
+	Subscriptions []EmailSubscription `mapstructure:"subscriptions"`
+}
+
+// SMTPConfig holds SMTP server settings.
+type SMTPConfig struct {
+	Host string `mapstructure:"host"`
+	// Port defaults to 587, or 465 with TLS "tls".
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	// Password falls back to the SMTP_PASSWORD environment variable.
+	Password string `mapstructure:"password"`
+	// TLS is "starttls" (the default, required), "tls" for implicit TLS or
+	// "none" for unencrypted local relays.
+	TLS string `mapstructure:"tls"`
+}
+
+// EmailSubscription is one recipient of the digest.
+type EmailSubscription struct {
+	Email string `mapstructure:"email"`
+	// SIGs restricts the recipient's digest to these SIGs, matched by name,
+	// ID or ID prefix as --sigs is. Empty means all.
+	SIGs []string `mapstructure:"sigs"`
+}
+
+// FeedConfig controls the Atom feeds written alongside reports. Feeds are
+// generated from every stored digest after each report run.
+type FeedConfig struct {
+	// Disabled turns feed generation off.
+	Disabled bool `mapstructure:"disabled"`
+	// PerSIG adds a feed of all relevance items for each SIG.
+	PerSIG bool `mapstructure:"per_sig"`
+	// BaseURL is where OutputDir is served; entries link to report files
+	// under it. Empty leaves entries without links.
+	BaseURL string `mapstructure:"base_url"`
+	// MaxEntries caps the entries per feed. Zero means 50.
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// CacheConfig controls how long analysis results are kept. Pruning runs at
+// the start of every report run; the zero value keeps everything.
+type CacheConfig struct {
+	// RetentionDays drops entries cached more than this many days ago.
+	// Zero keeps entries regardless of age.
+	RetentionDays int `mapstructure:"retention_days"`
+	// PruneStalePrompts drops entries produced with an earlier version of
+	// the system prompts, which can no longer be served.
+	PruneStalePrompts bool `mapstructure:"prune_stale_prompts"`
+}
+
+// RetentionConfig is how many days of fetched meeting notes, video
+// transcripts and Slack messages are kept, counted from the date of the
+// meeting, recording or message. Zero keeps a source's data forever.
+type RetentionConfig struct {
+	NotesDays int `mapstructure:"notes_days"`
+	VideoDays int `mapstructure:"video_days"`
+	SlackDays int `mapstructure:"slack_days"`
+}
+
+// Days returns the retention of sourceType: "notes", "video" or "slack".
+func (r RetentionConfig) Days(sourceType string) int {
+	switch sourceType {
+	case "notes":
+		return r.NotesDays
+	case "video":
+		return r.VideoDays
+	case "slack":
+		return r.SlackDays
+	}
+	return 0
+}
+
+// ProfileConfig is a named relevance profile: the audience a digest is
+// written for, what makes a topic relevant to them and how to grade it.
+type ProfileConfig struct {
+	// Name is lowercase letters, digits and hyphens, e.g. "logs".
+	Name string `mapstructure:"name"`
+	// Audience is who the brief is for, e.g. "the Datadog Logs team".
+	Audience string       `mapstructure:"audience"`
+	Criteria []string     `mapstructure:"criteria"`
+	Keywords []string     `mapstructure:"keywords"`
+	Rubric   RubricConfig `mapstructure:"rubric"`
+	// Output is the directory the profile's digests are written to. Empty
+	// means a subdirectory of OutputDir named after the profile.
+	Output string `mapstructure:"output"`
+}
+
+// RubricConfig describes what earns a topic each relevance level.
+type RubricConfig struct {
+	High   string `mapstructure:"high"`
+	Medium string `mapstructure:"medium"`
+	Low    string `mapstructure:"low"`
+}
+
+// OutputDir returns the directory the profile's digests are written to.
+func (p ProfileConfig) OutputDir(base string) string {
+	if p.Output != "" {
+		return p.Output
+	}
+	return filepath.Join(base, p.Name)
+}
+
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
@@ -91,6 +358,7 @@ func Load() (*Config, error) {
 		"OTEL_DB_PATH":      "db-path",
 		"OTEL_WORKERS":      "workers",
 		"OTEL_VERBOSE":      "verbose",
+		"OTEL_TIMEZONE":     "timezone",
 	}
 	for env, key := range envMappings {
 		_ = viper.BindEnv(key, env)
@@ -153,28 +421,253 @@ func ParseLookback(s string) (time.Duration, error) {
 	return 0, fmt.Errorf("invalid lookback format: %q (use Nd, Nw, Nm, or Go duration like 1h)", s)
 }
 
+// ParseLookbackMonths reports whether a lookback string is expressed in
+// months ("1m", "3m") and returns the number of calendar months.
+func ParseLookbackMonths(s string) (int, bool) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if len(s) < 2 || s[len(s)-1] != 'm' {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// ParseDate parses a YYYY-MM-DD date as midnight in loc.
+func ParseDate(s string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(s), loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", s)
+	}
+	return t, nil
+}
+
+// ParseISOWeek parses an ISO 8601 week such as "2026-W41" and returns
+// midnight on the Monday that starts it, in loc.
+func ParseISOWeek(s string, loc *time.Location) (time.Time, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	var year, week int
+	if _, err := fmt.Sscanf(strings.Replace(s, "-W", "W", 1), "%4dW%d", &year, &week); err != nil {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q (use YYYY-Www, e.g. 2026-W41)", s)
+	}
+	if week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: week must be 1-53", s)
+	}
+
+	// January 4th is always in week 1; step back to its Monday.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	monday := jan4.AddDate(0, 0, -offset+(week-1)*7)
+
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: %d has no week %d", s, year, week)
+	}
+	return monday, nil
+}
+
+// StartOfDay returns midnight of t's day in t's location.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the last instant of t's day in t's location.
+func EndOfDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// Location returns the time zone used for day boundaries.
+func (c *Config) Location() (*time.Location, error) {
+	if c.Timezone == "" || strings.EqualFold(c.Timezone, "local") {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Timezone, err)
+	}
+	return loc, nil
+}
+
+// Window resolves the reporting window for a run started at now. Week and
+// Since/Until select whole calendar days in the configured time zone. Without
+// them the window is the lookback ending at now, starting at the beginning of
+// its first day. An Until without a Since applies the lookback to Until.
+func (c *Config) Window(now time.Time) (start, end time.Time, err error) {
+	loc, err := c.Location()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if c.Week != "" {
+		if c.Since != "" || c.Until != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--week cannot be combined with --since or --until")
+		}
+		monday, err := ParseISOWeek(c.Week, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return monday, EndOfDay(monday.AddDate(0, 0, 6)), nil
+	}
+
+	end = now.In(loc)
+	if c.Until != "" {
+		d, err := ParseDate(c.Until, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		end = EndOfDay(d)
+	}
+
+	if c.Since != "" {
+		d, err := ParseDate(c.Since, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = d
+	} else if c.LookbackMonths > 0 {
+		start = StartOfDay(end.AddDate(0, -c.LookbackMonths, 0))
+	} else {
+		start = StartOfDay(end.Add(-c.Lookback))
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("window start %s is after end %s",
+			start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return start, end, nil
+}
+
 // Validate checks config for errors.
 func (c *Config) Validate() error {
 	if c.Workers < 1 {
 		return fmt.Errorf("workers must be >= 1, got %d", c.Workers)
 	}
-	if c.Format != "markdown" && c.Format != "json" {
-		return fmt.Errorf("format must be 'markdown' or 'json', got %q", c.Format)
+	if c.Format != "markdown" && c.Format != "json" && c.Format != "html" {
+		return fmt.Errorf("format must be 'markdown', 'json' or 'html', got %q", c.Format)
+	}
+	if c.Template != "" {
+		if _, err := os.Stat(c.Template); err != nil {
+			return fmt.Errorf("report template: %w", err)
+		}
+	}
+	if c.PromptsDir != "" {
+		if _, err := os.Stat(c.PromptsDir); err != nil {
+			return fmt.Errorf("prompts directory: %w", err)
+		}
+	}
+	if err := validateProfiles(c.Profiles); err != nil {
+		return err
+	}
+	if c.MaxCost < 0 {
+		return fmt.Errorf("max cost must be >= 0, got %g", c.MaxCost)
+	}
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("max tokens must be >= 0, got %d", c.MaxTokens)
+	}
+	if c.Feeds.MaxEntries < 0 {
+		return fmt.Errorf("feeds max_entries must be >= 0, got %d", c.Feeds.MaxEntries)
+	}
+	if c.Cache.RetentionDays < 0 {
+		return fmt.Errorf("cache retention_days must be >= 0, got %d", c.Cache.RetentionDays)
+	}
+	for _, source := range []string{"notes", "video", "slack"} {
+		if days := c.Retention.Days(source); days < 0 {
+			return fmt.Errorf("retention %s_days must be >= 0, got %d", source, days)
+		}
+	}
+	if c.Feeds.BaseURL != "" {
+		if u, err := url.Parse(c.Feeds.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("feeds base_url must be an absolute http(s) URL, got %q", c.Feeds.BaseURL)
+		}
+	}
+	if _, _, err := c.Window(time.Now()); err != nil {
+		return err
 	}
 	if c.LLM.Provider != "anthropic" && c.LLM.Provider != "openai" {
 		return fmt.Errorf("llm provider must be 'anthropic' or 'openai', got %q", c.LLM.Provider)
 	}
+	if err := validateStages(c.LLM); err != nil {
+		return err
+	}
 	if !c.Offline {
-		switch c.LLM.Provider {
-		case "anthropic":
-			if c.LLM.AnthropicKey == "" {
-				return fmt.Errorf("ANTHROPIC_API_KEY is required when using anthropic provider")
-			}
-		case "openai":
-			if c.LLM.OpenAIKey == "" {
-				return fmt.Errorf("OPENAI_API_KEY is required when using openai provider")
+		providers := []string{c.LLM.Provider}
+		for _, stage := range LLMStages {
+			providers = append(providers, c.LLM.Stage(stage).Provider)
+		}
+		for _, fb := range c.LLM.Fallbacks {
+			providers = append(providers, fb.Provider)
+		}
+		for _, provider := range providers {
+			switch provider {
+			case "anthropic":
+				if c.LLM.AnthropicKey == "" {
+					return fmt.Errorf("ANTHROPIC_API_KEY is required when using anthropic provider")
+				}
+			case "openai":
+				if c.LLM.OpenAIKey == "" {
+					return fmt.Errorf("OPENAI_API_KEY is required when using openai provider")
+				}
 			}
 		}
 	}
 	return nil
 }
+
+// validateStages checks the per-stage LLM overrides and the fallback chain.
+// A stage that switches provider must name a model, since the top-level one
+// belongs to the other provider.
+func validateStages(llm LLMConfig) error {
+	for name, sc := range llm.Stages {
+		if !slices.Contains(LLMStages, name) {
+			return fmt.Errorf("llm stage %q: unknown stage, must be one of %s", name, strings.Join(LLMStages, ", "))
+		}
+		if sc.Provider != "" && sc.Provider != "anthropic" && sc.Provider != "openai" {
+			return fmt.Errorf("llm stage %q: provider must be 'anthropic' or 'openai', got %q", name, sc.Provider)
+		}
+		if llm.Stage(name).Model == "" {
+			return fmt.Errorf("llm stage %q: model is required when the provider differs from llm provider", name)
+		}
+		if sc.MaxTokens < 0 {
+			return fmt.Errorf("llm stage %q: max_tokens must be >= 0, got %d", name, sc.MaxTokens)
+		}
+		if sc.Temperature < 0 || sc.Temperature > 2 {
+			return fmt.Errorf("llm stage %q: temperature must be between 0 and 2, got %g", name, sc.Temperature)
+		}
+	}
+	for i, fb := range llm.Fallbacks {
+		if fb.Provider != "anthropic" && fb.Provider != "openai" {
+			return fmt.Errorf("llm fallback #%d: provider must be 'anthropic' or 'openai', got %q", i+1, fb.Provider)
+		}
+		if fb.Model == "" {
+			return fmt.Errorf("llm fallback #%d: model is required", i+1)
+		}
+	}
+	return nil
+}
+
+// profileNamePattern matches valid relevance profile names.
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// validateProfiles checks that every profile has a unique, file-safe name,
+// an audience and something to score by.
+func validateProfiles(profiles []ProfileConfig) error {
+	seen := make(map[string]bool, len(profiles))
+	for i, p := range profiles {
+		if !profileNamePattern.MatchString(p.Name) {
+			return fmt.Errorf("profile #%d: name must be lowercase letters, digits and hyphens, got %q", i+1, p.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("profile %q is defined twice", p.Name)
+		}
+		seen[p.Name] = true
+		if p.Audience == "" {
+			return fmt.Errorf("profile %q: audience is required", p.Name)
+		}
+		if len(p.Criteria) == 0 && len(p.Keywords) == 0 {
+			return fmt.Errorf("profile %q: criteria or keywords are required", p.Name)
+		}
+	}
+	return nil
+}
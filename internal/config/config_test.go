@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -82,6 +83,118 @@ func TestValidate(t *testing.T) {
 			modify:  func(c *Config) { c.Workers = 0; c.LLM.AnthropicKey = "k" },
 			wantErr: true,
 		},
+		{
+			name:    "html format",
+			modify:  func(c *Config) { c.Format = "html"; c.LLM.AnthropicKey = "k" },
+			wantErr: false,
+		},
+		{
+			name:    "missing template",
+			modify:  func(c *Config) { c.Template = "/nonexistent/digest.md.tmpl"; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "missing prompts dir",
+			modify:  func(c *Config) { c.PromptsDir = "/nonexistent/prompts"; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "feeds base URL",
+			modify:  func(c *Config) { c.Feeds.BaseURL = "https://digest.example.com"; c.LLM.AnthropicKey = "k" },
+			wantErr: false,
+		},
+		{
+			name:    "relative feeds base URL",
+			modify:  func(c *Config) { c.Feeds.BaseURL = "reports/"; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "negative feeds max entries",
+			modify:  func(c *Config) { c.Feeds.MaxEntries = -1; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "negative max cost",
+			modify:  func(c *Config) { c.MaxCost = -1; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "negative max tokens",
+			modify:  func(c *Config) { c.MaxTokens = -1; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "budget",
+			modify:  func(c *Config) { c.MaxCost = 2.5; c.MaxTokens = 500000; c.LLM.AnthropicKey = "k" },
+			wantErr: false,
+		},
+		{
+			name:    "negative cache retention",
+			modify:  func(c *Config) { c.Cache.RetentionDays = -1; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "negative source retention",
+			modify:  func(c *Config) { c.Retention.VideoDays = -30; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name: "source retention",
+			modify: func(c *Config) {
+				c.Retention = RetentionConfig{NotesDays: 730, SlackDays: 180}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: false,
+		},
+		{
+			name: "cache retention",
+			modify: func(c *Config) {
+				c.Cache = CacheConfig{RetentionDays: 90, PruneStalePrompts: true}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: false,
+		},
+		{
+			name: "relevance profile",
+			modify: func(c *Config) {
+				c.Profiles = []ProfileConfig{{Name: "logs", Audience: "the Logs team", Keywords: []string{"log bridge"}}}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: false,
+		},
+		{
+			name: "profile name with spaces",
+			modify: func(c *Config) {
+				c.Profiles = []ProfileConfig{{Name: "Logs Team", Audience: "the Logs team", Keywords: []string{"log bridge"}}}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate profile",
+			modify: func(c *Config) {
+				p := ProfileConfig{Name: "logs", Audience: "the Logs team", Keywords: []string{"log bridge"}}
+				c.Profiles = []ProfileConfig{p, p}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: true,
+		},
+		{
+			name: "profile without audience",
+			modify: func(c *Config) {
+				c.Profiles = []ProfileConfig{{Name: "logs", Keywords: []string{"log bridge"}}}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: true,
+		},
+		{
+			name: "profile without criteria or keywords",
+			modify: func(c *Config) {
+				c.Profiles = []ProfileConfig{{Name: "logs", Audience: "the Logs team"}}
+				c.LLM.AnthropicKey = "k"
+			},
+			wantErr: true,
+		},
 		{
 			name:    "invalid format",
 			modify:  func(c *Config) { c.Format = "xml"; c.LLM.AnthropicKey = "k" },
@@ -102,11 +215,97 @@ func TestValidate(t *testing.T) {
 			modify:  func(c *Config) { c.LLM.Provider = "openai" },
 			wantErr: true,
 		},
+		{
+			name:    "invalid week",
+			modify:  func(c *Config) { c.Week = "2026-W60"; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "week with since",
+			modify:  func(c *Config) { c.Week = "2026-W41"; c.Since = "2026-10-01"; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
+		{
+			name:    "invalid timezone",
+			modify:  func(c *Config) { c.Timezone = "Mars/Olympus"; c.LLM.AnthropicKey = "k" },
+			wantErr: true,
+		},
 		{
 			name:    "valid openai config",
 			modify:  func(c *Config) { c.LLM.Provider = "openai"; c.LLM.OpenAIKey = "sk-test" },
 			wantErr: false,
 		},
+		{
+			name: "valid stage overrides",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.OpenAIKey = "k"
+				c.LLM.Stages = map[string]StageLLMConfig{
+					"notes": {Model: "claude-3-5-haiku-20241022", MaxTokens: 2048},
+					"slack": {Provider: "openai", Model: "gpt-4o-mini", Temperature: 0.2},
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown stage",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.Stages = map[string]StageLLMConfig{"cross_sig": {Model: "m"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "stage provider without model",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.OpenAIKey = "k"
+				c.LLM.Stages = map[string]StageLLMConfig{"video": {Provider: "openai"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "stage provider without key",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.Stages = map[string]StageLLMConfig{"video": {Provider: "openai", Model: "gpt-4o-mini"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid fallback",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.OpenAIKey = "k"
+				c.LLM.Fallbacks = []FallbackLLMConfig{{Provider: "openai", Model: "gpt-4o"}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "fallback without model",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.OpenAIKey = "k"
+				c.LLM.Fallbacks = []FallbackLLMConfig{{Provider: "openai"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "fallback without key",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.Fallbacks = []FallbackLLMConfig{{Provider: "openai", Model: "gpt-4o"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid stage temperature",
+			modify: func(c *Config) {
+				c.LLM.AnthropicKey = "k"
+				c.LLM.Stages = map[string]StageLLMConfig{"relevance": {Temperature: 3}}
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,3 +319,140 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestLLMConfig_Stage(t *testing.T) {
+	llm := LLMConfig{
+		Provider: "anthropic",
+		Model:    "claude-sonnet-4-20250514",
+		Stages: map[string]StageLLMConfig{
+			"notes": {Model: "claude-3-5-haiku-20241022", MaxTokens: 2048},
+			"slack": {Provider: "openai", Model: "gpt-4o-mini"},
+			"video": {Provider: "openai"},
+		},
+	}
+	tests := []struct {
+		stage string
+		want  StageLLMConfig
+	}{
+		{"notes", StageLLMConfig{Provider: "anthropic", Model: "claude-3-5-haiku-20241022", MaxTokens: 2048}},
+		{"slack", StageLLMConfig{Provider: "openai", Model: "gpt-4o-mini"}},
+		// The top-level model belongs to the other provider.
+		{"video", StageLLMConfig{Provider: "openai"}},
+		{"relevance", StageLLMConfig{Provider: "anthropic", Model: "claude-sonnet-4-20250514"}},
+	}
+	for _, tt := range tests {
+		if got := llm.Stage(tt.stage); got != tt.want {
+			t.Errorf("Stage(%q) = %+v, want %+v", tt.stage, got, tt.want)
+		}
+	}
+}
+
+func TestParseISOWeek(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   bool
+	}{
+		{"2026-W41", "2026-10-05", false},
+		{"2026-w01", "2025-12-29", false}, // week 1 starts in the previous year
+		{"2020-W53", "2020-12-28", false},
+		{"2026W10", "2026-03-02", false},
+		{"2027-W53", "", true}, // 2027 has 52 weeks
+		{"2026-W00", "", true},
+		{"2026-10-05", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseISOWeek(tt.input, time.UTC)
+			if (err != nil) != tt.err {
+				t.Fatalf("ParseISOWeek(%q) error = %v, wantErr %v", tt.input, err, tt.err)
+			}
+			if !tt.err && got.Format("2006-01-02") != tt.want {
+				t.Errorf("ParseISOWeek(%q) = %s, want %s", tt.input, got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	now := time.Date(2026, 10, 18, 3, 30, 0, 0, time.UTC) // Oct 17, 23:30 in New York
+
+	tests := []struct {
+		name      string
+		modify    func(*Config)
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "lookback starts at beginning of day",
+			modify:    func(c *Config) {},
+			wantStart: time.Date(2026, 10, 10, 0, 0, 0, 0, ny),
+			wantEnd:   now,
+		},
+		{
+			name:      "calendar months",
+			modify:    func(c *Config) { c.LookbackMonths = 1 },
+			wantStart: time.Date(2026, 9, 17, 0, 0, 0, 0, ny),
+			wantEnd:   now,
+		},
+		{
+			name:      "since and until are whole days",
+			modify:    func(c *Config) { c.Since = "2026-09-01"; c.Until = "2026-09-30" },
+			wantStart: time.Date(2026, 9, 1, 0, 0, 0, 0, ny),
+			wantEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, ny).Add(-time.Nanosecond),
+		},
+		{
+			name:      "until applies lookback",
+			modify:    func(c *Config) { c.Until = "2026-09-30" },
+			wantStart: time.Date(2026, 9, 23, 0, 0, 0, 0, ny),
+			wantEnd:   time.Date(2026, 10, 1, 0, 0, 0, 0, ny).Add(-time.Nanosecond),
+		},
+		{
+			name:      "iso week is Monday to Sunday",
+			modify:    func(c *Config) { c.Week = "2026-W41" },
+			wantStart: time.Date(2026, 10, 5, 0, 0, 0, 0, ny),
+			wantEnd:   time.Date(2026, 10, 12, 0, 0, 0, 0, ny).Add(-time.Nanosecond),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Timezone = "America/New_York"
+			tt.modify(cfg)
+			start, end, err := cfg.Window(now)
+			if err != nil {
+				t.Fatalf("Window() error: %v", err)
+			}
+			if !start.Equal(tt.wantStart) {
+				t.Errorf("start = %v, want %v", start, tt.wantStart)
+			}
+			if !end.Equal(tt.wantEnd) {
+				t.Errorf("end = %v, want %v", end, tt.wantEnd)
+			}
+		})
+	}
+
+	cfg := DefaultConfig()
+	cfg.Since = "2026-10-20"
+	cfg.Until = "2026-10-01"
+	if _, _, err := cfg.Window(now); err == nil {
+		t.Error("Window() with since after until should fail")
+	}
+}
+
+func TestProfileConfig_OutputDir(t *testing.T) {
+	p := ProfileConfig{Name: "logs"}
+	if got, want := p.OutputDir("reports"), filepath.Join("reports", "logs"); got != want {
+		t.Errorf("OutputDir = %q, want %q", got, want)
+	}
+	p.Output = "/srv/logs-digest"
+	if got := p.OutputDir("reports"); got != "/srv/logs-digest" {
+		t.Errorf("OutputDir with output = %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+// Package email sends digests to subscribers through an SMTP server, as
+// multipart text and HTML messages restricted to each subscriber's SIGs.
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/registry"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// Mailer sends stored digests to the configured subscriptions and records
+// each send, so sending the same report again skips recipients who already
+// have it.
+type Mailer struct {
+	smtp   *smtpSender
+	from   *mail.Address
+	subs   []subscription
+	store  *store.Store
+	logger *slog.Logger
+}
+
+// subscription is a validated config.EmailSubscription.
+type subscription struct {
+	addr *mail.Address
+	sigs []string // SIG names or IDs, matched by registry.MatchSIGFilter; empty means all
+}
+
+// Result lists what SendDigest did for each recipient.
+type Result struct {
+	Sent []string
+	// AlreadySent lists recipients skipped because the report, or another
+	// digest of the same week, was sent to them before.
+	AlreadySent []string
+	// NoSIGs lists recipients skipped because none of their SIGs are in the
+	// digest.
+	NoSIGs []string
+}
+
+// New validates cfg and creates a Mailer that records sends in s.
+func New(cfg config.EmailConfig, s *store.Store) (*Mailer, error) {
+	sender, err := newSMTPSender(cfg.SMTP)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.From == "" {
+		return nil, errors.New("email from address is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("email from address: %w", err)
+	}
+	if len(cfg.Subscriptions) == 0 {
+		return nil, errors.New("no email subscriptions configured")
+	}
+
+	m := &Mailer{smtp: sender, from: from, store: s, logger: slog.Default()}
+	for i, sc := range cfg.Subscriptions {
+		addr, err := mail.ParseAddress(sc.Email)
+		if err != nil {
+			return nil, fmt.Errorf("email subscription #%d: %w", i+1, err)
+		}
+		m.subs = append(m.subs, subscription{addr: addr, sigs: sc.SIGs})
+	}
+	return m, nil
+}
+
+// SetLogger replaces the mailer's logger.
+func (m *Mailer) SetLogger(l *slog.Logger) {
+	m.logger = l
+}
+
+// SendDigest emails digest, stored as rec, to every subscriber who has not
+// received it, or another digest of the same SIGs and window, yet; or to
+// all of them with force. A failed recipient does
+// not stop the others; their errors are joined.
+func (m *Mailer) SendDigest(ctx context.Context, rec *store.Report, digest *analysis.DigestReport, force bool) (*Result, error) {
+	res := &Result{}
+	var errs []error
+	for _, sub := range m.subs {
+		to := sub.addr.Address
+		log := m.logger.With("recipient", to, "report_id", rec.ID)
+
+		if !force {
+			sent, err := m.store.EmailSent(rec.ID, to)
+			if err != nil {
+				return res, fmt.Errorf("checking send history: %w", err)
+			}
+			if sent {
+				log.Info("digest already sent, skipping")
+				res.AlreadySent = append(res.AlreadySent, to)
+				continue
+			}
+		}
+
+		filtered := filterDigest(digest, sub.sigs)
+		if len(filtered.SIGReports) == 0 {
+			log.Info("none of the subscribed SIGs are in the digest, skipping")
+			res.NoSIGs = append(res.NoSIGs, to)
+			continue
+		}
+
+		msg, err := buildMessage(m.from, sub.addr, filtered)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", to, err))
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		if err := m.smtp.send(ctx, m.from.Address, []string{to}, msg.data); err != nil {
+			log.Error("sending digest failed", "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", to, err))
+			continue
+		}
+		if err := m.store.RecordEmailSend(&store.EmailSend{
+			ReportID:  rec.ID,
+			Recipient: to,
+			SIGIDs:    sentSIGIDs(filtered, sub.sigs),
+			MessageID: msg.id,
+		}); err != nil {
+			return res, fmt.Errorf("recording send to %s: %w", to, err)
+		}
+		log.Info("digest sent", "sigs", len(filtered.SIGReports))
+		res.Sent = append(res.Sent, to)
+	}
+	return res, errors.Join(errs...)
+}
+
+// filterDigest returns a copy of digest with only the SIG reports in sigs,
+// or digest itself when sigs is empty. Cross-SIG themes and run stats span
+// all SIGs, so they are left out of filtered copies.
+func filterDigest(digest *analysis.DigestReport, sigs []string) *analysis.DigestReport {
+	if len(sigs) == 0 {
+		return digest
+	}
+	filtered := &analysis.DigestReport{
+		DateRangeStart:    digest.DateRangeStart,
+		DateRangeEnd:      digest.DateRangeEnd,
+		PreviousDigestEnd: digest.PreviousDigestEnd,
+	}
+	for _, sr := range digest.SIGReports {
+		if registry.MatchSIGFilter(sr.SIGID, sigs) {
+			filtered.SIGReports = append(filtered.SIGReports, sr)
+		}
+	}
+	return filtered
+}
+
+// sentSIGIDs returns the comma-separated IDs of the SIGs in filtered, the
+// digest sent to a subscriber of sigs, or "" if the subscriber gets all
+// SIGs.
+func sentSIGIDs(filtered *analysis.DigestReport, sigs []string) string {
+	if len(sigs) == 0 {
+		return ""
+	}
+	ids := make([]string, len(filtered.SIGReports))
+	for i, sr := range filtered.SIGReports {
+		ids[i] = sr.SIGID
+	}
+	return strings.Join(ids, ",")
+}
+
+// subject returns the email subject for a digest.
+func subject(digest *analysis.DigestReport) string {
+	if digest.DateRangeStart == digest.DateRangeEnd {
+		return "OTel Weekly Digest — " + digest.DateRangeEnd
+	}
+	return fmt.Sprintf("OTel Weekly Digest — %s to %s", digest.DateRangeStart, digest.DateRangeEnd)
+}
+
+// renderBodies renders the plain text and HTML parts of a digest email.
+func renderBodies(digest *analysis.DigestReport) (text, html string, err error) {
+	text, err = report.RenderDigestMarkdown(digest)
+	if err != nil {
+		return "", "", fmt.Errorf("rendering text: %w", err)
+	}
+	html, err = report.RenderDigestEmailHTML(digest)
+	if err != nil {
+		return "", "", fmt.Errorf("rendering HTML: %w", err)
+	}
+	return text, html, nil
+}
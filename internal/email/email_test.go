@@ -0,0 +1,475 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// smtpMessage is a message received by the fake SMTP server.
+type smtpMessage struct {
+	from string
+	to   []string
+	data []byte
+	tls  bool
+	auth string
+}
+
+// fakeSMTP is a local SMTP stand-in that accepts every message, except for
+// recipients containing "reject", and keeps them for inspection.
+type fakeSMTP struct {
+	ln       net.Listener
+	tlsConf  *tls.Config // advertises STARTTLS when set
+	mu       sync.Mutex
+	messages []smtpMessage
+}
+
+func newFakeSMTP(t *testing.T, tlsConf *tls.Config) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	f := &fakeSMTP{ln: ln, tlsConf: tlsConf}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeSMTP) port() int {
+	return f.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeSMTP) received() []smtpMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]smtpMessage(nil), f.messages...)
+}
+
+func (f *fakeSMTP) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	tp := textproto.NewConn(conn)
+	var msg smtpMessage
+	reply := func(format string, args ...any) { _ = tp.PrintfLine(format, args...) }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250-fake")
+			if f.tlsConf != nil && !msg.tls {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready")
+			tc := tls.Server(conn, f.tlsConf)
+			if err := tc.Handshake(); err != nil {
+				return
+			}
+			conn = tc
+			tp = textproto.NewConn(tc)
+			msg.tls = true
+		case "AUTH":
+			_, resp, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(resp)
+			msg.auth = string(decoded)
+			reply("235 ok")
+		case "MAIL":
+			msg.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			reply("250 ok")
+		case "RCPT":
+			rcpt := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+			if strings.Contains(rcpt, "reject") {
+				reply("550 no such user")
+				continue
+			}
+			msg.to = append(msg.to, rcpt)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = data
+			f.mu.Lock()
+			f.messages = append(f.messages, msg)
+			f.mu.Unlock()
+			msg = smtpMessage{tls: msg.tls, auth: msg.auth}
+			reply("250 queued")
+		case "RSET", "NOOP":
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unknown command")
+		}
+	}
+}
+
+func testDigest() *analysis.DigestReport {
+	return &analysis.DigestReport{
+		DateRangeStart: "2026-02-11",
+		DateRangeEnd:   "2026-02-18",
+		SIGReports: []*analysis.SIGReport{
+			{
+				SIGID:   "collector",
+				SIGName: "Collector",
+				RelevanceReport: &analysis.RelevanceReport{
+					HighItems: []string{"**OTLP/HTTP Partial Success** — affects Datadog OTLP ingest."},
+				},
+			},
+			{
+				SIGID:   "specification",
+				SIGName: "Specification",
+				RelevanceReport: &analysis.RelevanceReport{
+					MediumItems: []string{"**Profiling Signal OTEP** — new profiling signal."},
+				},
+			},
+		},
+		CrossSIGThemes: "Both SIGs discussed OTLP.",
+	}
+}
+
+func newTestStore(t *testing.T) (*store.Store, *store.Report) {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	rec := &store.Report{ReportType: "digest", DateRangeStart: time.Now().AddDate(0, 0, -7), DateRangeEnd: time.Now()}
+	if err := s.InsertReport(rec); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+	return s, rec
+}
+
+func testConfig(port int, subs ...config.EmailSubscription) config.EmailConfig {
+	return config.EmailConfig{
+		SMTP:          config.SMTPConfig{Host: "127.0.0.1", Port: port, TLS: "none"},
+		From:          "OTel Digest <digest@example.com>",
+		Subscriptions: subs,
+	}
+}
+
+// parts decodes a received multipart/alternative message into its subject
+// and a body per content type.
+func parts(t *testing.T, data []byte) (string, map[string]string) {
+	t.Helper()
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decoding subject: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v; want multipart/alternative", mediaType, err)
+	}
+	bodies := map[string]string{}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := mr.NextPart() // decodes quoted-printable
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(p)
+		bodies[ct] = string(body)
+	}
+	return subject, bodies
+}
+
+func TestMailer_SendDigest(t *testing.T) {
+	srv := newFakeSMTP(t, nil)
+	s, rec := newTestStore(t)
+	m, err := New(testConfig(srv.port(),
+		config.EmailSubscription{Email: "cto@example.com"},
+		config.EmailSubscription{Email: "Collector Lead <lead@example.com>", SIGs: []string{"Collector"}},
+	), s)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	res, err := m.SendDigest(context.Background(), rec, testDigest(), false)
+	if err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+	if len(res.Sent) != 2 {
+		t.Fatalf("sent to %v, want both subscribers", res.Sent)
+	}
+
+	msgs := srv.received()
+	if len(msgs) != 2 {
+		t.Fatalf("server received %d messages, want 2", len(msgs))
+	}
+	if msgs[0].from != "digest@example.com" || msgs[0].to[0] != "cto@example.com" {
+		t.Errorf("envelope = %s -> %v, want digest@example.com -> cto@example.com", msgs[0].from, msgs[0].to)
+	}
+
+	subject, bodies := parts(t, msgs[0].data)
+	if subject != "OTel Weekly Digest — 2026-02-11 to 2026-02-18" {
+		t.Errorf("subject = %q", subject)
+	}
+	if !strings.Contains(bodies["text/plain"], "**OTLP/HTTP Partial Success**") || !strings.Contains(bodies["text/plain"], "Specification") {
+		t.Errorf("text part missing the full digest:\n%s", bodies["text/plain"])
+	}
+	if !strings.Contains(bodies["text/html"], "<strong>OTLP/HTTP Partial Success</strong>") {
+		t.Errorf("html part missing the bold topic:\n%s", bodies["text/html"])
+	}
+
+	_, lead := parts(t, msgs[1].data)
+	if !strings.Contains(lead["text/plain"], "Collector") || strings.Contains(lead["text/plain"], "Specification") {
+		t.Errorf("collector subscriber got other SIGs:\n%s", lead["text/plain"])
+	}
+	if strings.Contains(lead["text/plain"], "Cross-SIG Themes") {
+		t.Error("filtered digest should leave out cross-SIG themes")
+	}
+
+	sends, err := s.ListEmailSends(rec.ID)
+	if err != nil || len(sends) != 2 {
+		t.Fatalf("ListEmailSends = %d, %v; want 2", len(sends), err)
+	}
+	if sends[1].SIGIDs != "collector" || sends[1].MessageID == "" {
+		t.Errorf("recorded send = %+v, want collector with a message ID", sends[1])
+	}
+
+	// Sending again skips everyone; force sends anyway.
+	res, err = m.SendDigest(context.Background(), rec, testDigest(), false)
+	if err != nil || len(res.AlreadySent) != 2 || len(res.Sent) != 0 {
+		t.Errorf("re-send result = %+v, %v; want both already sent", res, err)
+	}
+	if n := len(srv.received()); n != 2 {
+		t.Errorf("server received %d messages after re-send, want still 2", n)
+	}
+	if res, err := m.SendDigest(context.Background(), rec, testDigest(), true); err != nil || len(res.Sent) != 2 {
+		t.Errorf("forced re-send result = %+v, %v; want both sent", res, err)
+	}
+}
+
+func TestMailer_SkipsRerunOfSameWeek(t *testing.T) {
+	srv := newFakeSMTP(t, nil)
+	s, rec := newTestStore(t)
+	m, err := New(testConfig(srv.port(), config.EmailSubscription{Email: "cto@example.com"}), s)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if res, err := m.SendDigest(context.Background(), rec, testDigest(), false); err != nil || len(res.Sent) != 1 {
+		t.Fatalf("SendDigest = %+v, %v; want sent", res, err)
+	}
+
+	// A second report run over the same week records a new digest row.
+	rerun := &store.Report{ReportType: rec.ReportType, SIGSet: rec.SIGSet, DateRangeStart: rec.DateRangeStart, DateRangeEnd: rec.DateRangeEnd}
+	if err := s.InsertReport(rerun); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+	res, err := m.SendDigest(context.Background(), rerun, testDigest(), false)
+	if err != nil || len(res.AlreadySent) != 1 || len(res.Sent) != 0 {
+		t.Errorf("re-run result = %+v, %v; want already sent", res, err)
+	}
+	if n := len(srv.received()); n != 1 {
+		t.Errorf("server received %d messages, want 1", n)
+	}
+
+	// The next week is sent.
+	next := &store.Report{ReportType: rec.ReportType, DateRangeStart: rec.DateRangeEnd, DateRangeEnd: rec.DateRangeEnd.AddDate(0, 0, 7)}
+	if err := s.InsertReport(next); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+	if res, err := m.SendDigest(context.Background(), next, testDigest(), false); err != nil || len(res.Sent) != 1 {
+		t.Errorf("next week result = %+v, %v; want sent", res, err)
+	}
+}
+
+func TestMailer_SubscriptionMatchesPrefixes(t *testing.T) {
+	srv := newFakeSMTP(t, nil)
+	s, rec := newTestStore(t)
+	m, err := New(testConfig(srv.port(),
+		config.EmailSubscription{Email: "docs@example.com", SIGs: []string{"communications"}},
+	), s)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	// Registry IDs of SIGs with a parenthetical description carry it on.
+	digest := testDigest()
+	digest.SIGReports = append(digest.SIGReports, &analysis.SIGReport{
+		SIGID:   "communications-(website-documentation-etc)",
+		SIGName: "Communications (website, documentation, etc.)",
+		RelevanceReport: &analysis.RelevanceReport{
+			MediumItems: []string{"**Docs Site Redesign** — new navigation."},
+		},
+	})
+	res, err := m.SendDigest(context.Background(), rec, digest, false)
+	if err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+	if len(res.Sent) != 1 || len(res.NoSIGs) != 0 {
+		t.Fatalf("result = %+v, want the communications subscriber mailed", res)
+	}
+	_, bodies := parts(t, srv.received()[0].data)
+	if !strings.Contains(bodies["text/plain"], "Docs Site Redesign") || strings.Contains(bodies["text/plain"], "Specification") {
+		t.Errorf("subscriber got the wrong SIGs:\n%s", bodies["text/plain"])
+	}
+	sends, err := s.ListEmailSends(rec.ID)
+	if err != nil || len(sends) != 1 || sends[0].SIGIDs != "communications-(website-documentation-etc)" {
+		t.Errorf("recorded sends = %+v, %v; want the communications SIG", sends, err)
+	}
+}
+
+func TestMailer_SkipsAndFailures(t *testing.T) {
+	srv := newFakeSMTP(t, nil)
+	s, rec := newTestStore(t)
+	m, err := New(testConfig(srv.port(),
+		config.EmailSubscription{Email: "go@example.com", SIGs: []string{"go-sdk"}},
+		config.EmailSubscription{Email: "reject@example.com"},
+		config.EmailSubscription{Email: "ok@example.com"},
+	), s)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	res, err := m.SendDigest(context.Background(), rec, testDigest(), false)
+	if err == nil || !strings.Contains(err.Error(), "reject@example.com") {
+		t.Errorf("err = %v, want the rejected recipient's error", err)
+	}
+	if len(res.NoSIGs) != 1 || res.NoSIGs[0] != "go@example.com" {
+		t.Errorf("NoSIGs = %v, want go@example.com", res.NoSIGs)
+	}
+	if len(res.Sent) != 1 || res.Sent[0] != "ok@example.com" {
+		t.Errorf("Sent = %v, want ok@example.com despite the earlier failure", res.Sent)
+	}
+	if sent, _ := s.EmailSent(rec.ID, "reject@example.com"); sent {
+		t.Error("failed send was recorded")
+	}
+}
+
+func TestMailer_StartTLSAndAuth(t *testing.T) {
+	// Borrow httptest's self-signed certificate for 127.0.0.1.
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+
+	srv := newFakeSMTP(t, &tls.Config{Certificates: ts.TLS.Certificates})
+	s, rec := newTestStore(t)
+	cfg := testConfig(srv.port(), config.EmailSubscription{Email: "cto@example.com"})
+	cfg.SMTP.TLS = "starttls"
+	cfg.SMTP.Username = "digest"
+	cfg.SMTP.Password = "secret"
+	m, err := New(cfg, s)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	m.smtp.tlsConfig.RootCAs = roots
+
+	if _, err := m.SendDigest(context.Background(), rec, testDigest(), false); err != nil {
+		t.Fatalf("SendDigest: %v", err)
+	}
+	msgs := srv.received()
+	if len(msgs) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(msgs))
+	}
+	if !msgs[0].tls {
+		t.Error("message was sent without STARTTLS")
+	}
+	if msgs[0].auth != "\x00digest\x00secret" {
+		t.Errorf("auth = %q, want PLAIN credentials", msgs[0].auth)
+	}
+}
+
+func TestMailer_StartTLSRequired(t *testing.T) {
+	srv := newFakeSMTP(t, nil)
+	s, rec := newTestStore(t)
+	cfg := testConfig(srv.port(), config.EmailSubscription{Email: "cto@example.com"})
+	cfg.SMTP.TLS = ""
+	m, err := New(cfg, s)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	_, err = m.SendDigest(context.Background(), rec, testDigest(), false)
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("err = %v, want a STARTTLS error", err)
+	}
+	if len(srv.received()) != 0 {
+		t.Error("message was sent over an unencrypted connection")
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	valid := testConfig(25, config.EmailSubscription{Email: "a@example.com"})
+	tests := []struct {
+		name   string
+		modify func(*config.EmailConfig)
+	}{
+		{"no host", func(c *config.EmailConfig) { c.SMTP.Host = "" }},
+		{"bad tls", func(c *config.EmailConfig) { c.SMTP.TLS = "ssl" }},
+		{"no from", func(c *config.EmailConfig) { c.From = "" }},
+		{"bad from", func(c *config.EmailConfig) { c.From = "not an address" }},
+		{"no subscriptions", func(c *config.EmailConfig) { c.Subscriptions = nil }},
+		{"bad recipient", func(c *config.EmailConfig) { c.Subscriptions[0].Email = "nobody" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			cfg.Subscriptions = append([]config.EmailSubscription(nil), valid.Subscriptions...)
+			tt.modify(&cfg)
+			if _, err := New(cfg, nil); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+
+	m, err := New(valid, nil)
+	if err != nil {
+		t.Fatalf("New(valid): %v", err)
+	}
+	if m.smtp.addr != net.JoinHostPort("127.0.0.1", strconv.Itoa(25)) {
+		t.Errorf("addr = %q", m.smtp.addr)
+	}
+	valid.SMTP.Port, valid.SMTP.TLS = 0, "tls"
+	if m, _ := New(valid, nil); m.smtp.addr != "127.0.0.1:465" {
+		t.Errorf("implicit TLS addr = %q, want port 465", m.smtp.addr)
+	}
+}
@@ -0,0 +1,123 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+)
+
+// smtpTimeout bounds a whole SMTP conversation.
+const smtpTimeout = time.Minute
+
+// smtpSender delivers messages through one SMTP server.
+type smtpSender struct {
+	host      string
+	addr      string
+	username  string
+	password  string
+	tlsMode   string
+	tlsConfig *tls.Config
+}
+
+func newSMTPSender(cfg config.SMTPConfig) (*smtpSender, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("email smtp host is required")
+	}
+	mode := cfg.TLS
+	if mode == "" {
+		mode = "starttls"
+	}
+	port := cfg.Port
+	switch mode {
+	case "starttls", "none":
+		if port == 0 {
+			port = 587
+		}
+	case "tls":
+		if port == 0 {
+			port = 465
+		}
+	default:
+		return nil, fmt.Errorf("email smtp tls must be 'starttls', 'tls' or 'none', got %q", cfg.TLS)
+	}
+	return &smtpSender{
+		host:      cfg.Host,
+		addr:      net.JoinHostPort(cfg.Host, strconv.Itoa(port)),
+		username:  cfg.Username,
+		password:  cfg.Password,
+		tlsMode:   mode,
+		tlsConfig: &tls.Config{ServerName: cfg.Host},
+	}, nil
+}
+
+// send delivers msg from from to the to addresses in one SMTP session.
+func (s *smtpSender) send(ctx context.Context, from string, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if s.tlsMode == "tls" {
+		d := &tls.Dialer{Config: s.tlsConfig}
+		conn, err = d.DialContext(ctx, "tcp", s.addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", s.addr, err)
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP greeting: %w", err)
+	}
+	defer c.Close()
+
+	if s.tlsMode == "starttls" {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS (set tls: none for unencrypted relays)", s.addr)
+		}
+		if err := c.StartTLS(s.tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+	if s.username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return fmt.Errorf("%s does not support authentication", s.addr)
+		}
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return fmt.Errorf("MAIL FROM: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("RCPT TO %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("DATA: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("sending message: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,252 @@
+// Package eval measures report quality over a fixed corpus of fixture
+// cases, so prompt and model changes can be compared before they ship.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"go.yaml.in/yaml/v3"
+)
+
+// Case is one fixture: a SIG's sources for a week, the items a good
+// report contains, and the relevance response the mock provider replays.
+type Case struct {
+	// Name is the fixture file's base name without extension.
+	Name string `yaml:"-"`
+
+	SIGID   string `yaml:"sig"`
+	SIGName string `yaml:"sig_name"`
+	Start   string `yaml:"start"` // YYYY-MM-DD
+	End     string `yaml:"end"`   // YYYY-MM-DD
+
+	Notes      string         `yaml:"notes"`
+	Transcript string         `yaml:"transcript"`
+	Slack      []SlackMessage `yaml:"slack"`
+
+	Expected []Expectation `yaml:"expected"`
+
+	// MockResponse is the relevance report returned by the mock provider.
+	MockResponse string `yaml:"mock_response"`
+
+	start, end time.Time
+}
+
+// SlackMessage is one message of a case's Slack channel.
+type SlackMessage struct {
+	User string `yaml:"user"`
+	Text string `yaml:"text"`
+}
+
+// Expectation is a topic a good report lists at the given level.
+type Expectation struct {
+	Topic string `yaml:"topic"`
+	Level string `yaml:"level"` // "high", "medium" or "low"
+	// Aliases are other names the topic may be reported under.
+	Aliases []string `yaml:"aliases"`
+}
+
+// LoadCases reads every *.yaml case in dir, sorted by name.
+func LoadCases(dir string) ([]*Case, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no eval cases (*.yaml) in %s", dir)
+	}
+	sort.Strings(paths)
+
+	var cases []*Case
+	for _, path := range paths {
+		c, err := loadCase(path)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+func loadCase(path string) (*Case, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading eval case: %w", err)
+	}
+	var c Case
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing eval case %s: %w", path, err)
+	}
+	c.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("eval case %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+func (c *Case) validate() error {
+	if c.SIGID == "" || c.SIGName == "" {
+		return fmt.Errorf("sig and sig_name are required")
+	}
+	var err error
+	if c.start, err = time.Parse("2006-01-02", c.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if c.end, err = time.Parse("2006-01-02", c.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if c.Notes == "" && c.Transcript == "" && len(c.Slack) == 0 {
+		return fmt.Errorf("no sources: set notes, transcript or slack")
+	}
+	if len(c.Expected) == 0 {
+		return fmt.Errorf("no expected items")
+	}
+	for _, e := range c.Expected {
+		if e.Topic == "" {
+			return fmt.Errorf("expected item without a topic")
+		}
+		if e.Level != "high" && e.Level != "medium" && e.Level != "low" {
+			return fmt.Errorf("expected %q: level must be high, medium or low, got %q", e.Topic, e.Level)
+		}
+	}
+	return nil
+}
+
+// Config is one configuration under evaluation.
+type Config struct {
+	// Label names the configuration in comparisons.
+	Label string
+	// Provider is "anthropic", "openai" or "mock".
+	Provider string
+	Model    string
+	APIKey   string
+	// PromptsDir overrides the embedded prompts, as in the report command.
+	PromptsDir string
+}
+
+// String describes the configuration.
+func (c Config) String() string {
+	s := c.Provider
+	if c.Provider != "mock" {
+		s += "/" + c.Model
+	}
+	if c.PromptsDir != "" {
+		s += ", prompts from " + c.PromptsDir
+	}
+	return s
+}
+
+// newClient returns the LLM client for a case. The mock provider replays
+// the case's recorded relevance response.
+func (c Config) newClient(ec *Case) (analysis.LLMClient, error) {
+	switch c.Provider {
+	case "anthropic":
+		return analysis.NewAnthropicClient(c.APIKey, c.Model), nil
+	case "openai":
+		return analysis.NewOpenAIClient(c.APIKey, c.Model), nil
+	case "mock":
+		return analysis.NewMockClient(ec.MockResponse), nil
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", c.Provider)
+	}
+}
+
+// Run analyzes every case with cfg and scores the reports. A case whose
+// analysis fails is recorded with its error; Run only fails if the
+// configuration itself is unusable.
+func Run(ctx context.Context, cfg Config, cases []*Case) (*Result, error) {
+	prompts, err := analysis.LoadPrompts(cfg.PromptsDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading prompts: %w", err)
+	}
+
+	// A fresh store per run keeps the analysis cache from answering for
+	// another configuration or an earlier run.
+	s, err := store.New(":memory:")
+	if err != nil {
+		return nil, fmt.Errorf("creating eval store: %w", err)
+	}
+	defer s.Close()
+
+	res := &Result{Config: cfg}
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		llm, err := cfg.newClient(c)
+		if err != nil {
+			return nil, err
+		}
+		report, err := analyze(ctx, llm, s, prompts, c)
+		if err != nil {
+			res.Cases = append(res.Cases, &CaseResult{Case: c, Err: err})
+			continue
+		}
+		res.Cases = append(res.Cases, Score(c, report))
+	}
+	return res, nil
+}
+
+// analyze runs the summarize, synthesize and relevance stages over a case,
+// as the pipeline does for a SIG.
+func analyze(ctx context.Context, llm analysis.LLMClient, s *store.Store, prompts *analysis.Prompts, c *Case) (*analysis.RelevanceReport, error) {
+	summarizer := analysis.NewSummarizer(llm, s)
+	summarizer.SetPrompts(prompts)
+	synthesizer := analysis.NewSynthesizer(llm, s)
+	synthesizer.SetPrompts(prompts)
+	scorer := analysis.NewRelevanceScorer(llm, s, "")
+	scorer.SetPrompts(prompts)
+
+	tokens := 0
+	var summaries []*analysis.SourceSummary
+	if c.Notes != "" {
+		sum, err := summarizer.SummarizeMeetingNotes(ctx, c.SIGID, c.SIGName, []*store.MeetingNote{
+			{SIGID: c.SIGID, MeetingDate: c.start, RawText: c.Notes},
+		}, c.start, c.end)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, sum)
+	}
+	if c.Transcript != "" {
+		sum, err := summarizer.SummarizeVideoTranscripts(ctx, c.SIGID, c.SIGName, []*store.VideoTranscript{
+			{SIGID: c.SIGID, RecordingDate: c.start, Transcript: c.Transcript},
+		}, c.start, c.end)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, sum)
+	}
+	if len(c.Slack) > 0 {
+		var msgs []*store.SlackMessage
+		for _, m := range c.Slack {
+			msgs = append(msgs, &store.SlackMessage{SIGID: c.SIGID, UserName: m.User, Text: m.Text, MessageDate: c.start})
+		}
+		sum, err := summarizer.SummarizeSlackMessages(ctx, c.SIGID, c.SIGName, msgs, c.start, c.end)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, sum)
+	}
+	for _, sum := range summaries {
+		tokens += sum.TokensUsed
+	}
+
+	synthesis, err := synthesizer.Synthesize(ctx, c.SIGID, c.SIGName, summaries, c.start, c.end)
+	if err != nil {
+		return nil, err
+	}
+	report, err := scorer.Score(ctx, c.SIGID, c.SIGName, synthesis, c.start, c.end)
+	if err != nil {
+		return nil, err
+	}
+	report.TokensUsed += tokens + synthesis.TokensUsed
+	return report, nil
+}
@@ -0,0 +1,197 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+const corpus = "../../testdata/eval"
+
+func TestLoadCases_Corpus(t *testing.T) {
+	cases, err := LoadCases(corpus)
+	if err != nil {
+		t.Fatalf("LoadCases: %v", err)
+	}
+	if len(cases) < 8 {
+		t.Errorf("corpus has %d cases, want at least 8", len(cases))
+	}
+	for _, c := range cases {
+		if c.MockResponse == "" {
+			t.Errorf("case %s has no mock_response", c.Name)
+		}
+	}
+	if cases[0].Name != "collector-otlp-partial-success" {
+		t.Errorf("first case = %s, want cases sorted by name", cases[0].Name)
+	}
+}
+
+func TestLoadCases_Invalid(t *testing.T) {
+	tests := []struct {
+		name, yaml, wantErr string
+	}{
+		{"no sig", "start: 2026-02-11\nend: 2026-02-18\nnotes: x\n", "sig and sig_name"},
+		{"bad date", "sig: a\nsig_name: A\nstart: Feb 11\nend: 2026-02-18\nnotes: x\n", "start"},
+		{"no sources", "sig: a\nsig_name: A\nstart: 2026-02-11\nend: 2026-02-18\n", "no sources"},
+		{"no expected", "sig: a\nsig_name: A\nstart: 2026-02-11\nend: 2026-02-18\nnotes: x\n", "no expected"},
+		{"bad level", "sig: a\nsig_name: A\nstart: 2026-02-11\nend: 2026-02-18\nnotes: x\nexpected:\n  - topic: T\n    level: urgent\n", "level must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "case.yaml"), []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadCases(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadCases error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadCases(t.TempDir()); err == nil {
+		t.Error("LoadCases should fail for a directory without cases")
+	}
+}
+
+func TestScore(t *testing.T) {
+	c := &Case{Expected: []Expectation{
+		{Topic: "OTLP/HTTP Partial Success", Level: "high"},
+		{Topic: "Batch Processor Deprecation", Level: "high", Aliases: []string{"exporter batching"}},
+		{Topic: "Filelog Receiver", Level: "low"},
+		{Topic: "Profiling", Level: "medium"},
+	}}
+	response := "#### HIGH Relevance\n" +
+		"- **OTLP/HTTP  partial success** — Rejected points are logged.\n\n" +
+		"#### MEDIUM Relevance\n" +
+		"- **Exporter Batching** — Batching moves into exporterhelper.\n\n" +
+		"#### LOW Relevance\n" +
+		"- **Filelog Receiver Beta** — Now beta.\n"
+	report := &analysis.RelevanceReport{
+		Report:      response,
+		HighItems:   []string{"**OTLP/HTTP  partial success** — Rejected points are logged."},
+		MediumItems: []string{"**Exporter Batching** — Batching moves into exporterhelper."},
+		LowItems:    []string{"**Filelog Receiver Beta** — Now beta."},
+	}
+
+	res := Score(c, report)
+	if res.Found != 3 || res.Expected != 4 {
+		t.Errorf("recall = %d/%d, want 3/4", res.Found, res.Expected)
+	}
+	if res.HighFound != 1 || res.HighExpected != 2 {
+		t.Errorf("HIGH recall = %d/%d, want 1/2", res.HighFound, res.HighExpected)
+	}
+	if res.LevelAgreed != 2 {
+		t.Errorf("level agreement = %d/%d, want 2/3", res.LevelAgreed, res.Found)
+	}
+	if len(res.Missed) != 1 || res.Missed[0] != "Profiling" {
+		t.Errorf("missed = %v", res.Missed)
+	}
+	if res.FormatPassed != res.FormatChecks || len(res.Violations) != 0 {
+		t.Errorf("format violations = %v", res.Violations)
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		report   string
+		wantViol []string
+	}{
+		{
+			name:   "compliant with status tags",
+			report: "#### HIGH Relevance\n- [NEW] **Topic** — why.\n#### MEDIUM Relevance\nNone this period.\n#### LOW Relevance\n`None this period.`\n",
+		},
+		{
+			name:     "missing section",
+			report:   "#### HIGH Relevance\n- **Topic** — why.\n#### LOW Relevance\nNone this period.\n",
+			wantViol: []string{"section headers"},
+		},
+		{
+			name:     "loose items and prose",
+			report:   "#### HIGH Relevance\n- Topic: why\n- Other\n#### MEDIUM Relevance\n#### LOW Relevance\nThis was a quiet week.\n## Executive Summary\n",
+			wantViol: []string{`"- Topic: why" and 1 more`, "outside the sections", "Executive Summary"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, checks := checkFormat(tt.report)
+			if checks != 4 {
+				t.Errorf("checks = %d, want 4", checks)
+			}
+			if len(violations) != len(tt.wantViol) {
+				t.Fatalf("violations = %v, want %d", violations, len(tt.wantViol))
+			}
+			for i, want := range tt.wantViol {
+				if !strings.Contains(violations[i], want) {
+					t.Errorf("violation %d = %q, want it to contain %q", i, violations[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRun_Mock(t *testing.T) {
+	cases, err := LoadCases(corpus)
+	if err != nil {
+		t.Fatalf("LoadCases: %v", err)
+	}
+	res, err := Run(context.Background(), Config{Label: "A", Provider: "mock"}, cases)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if res.Errors() != 0 {
+		for _, c := range res.Cases {
+			if c.Err != nil {
+				t.Errorf("case %s: %v", c.Case.Name, c.Err)
+			}
+		}
+	}
+	if len(res.Cases) != len(cases) {
+		t.Fatalf("got %d case results, want %d", len(res.Cases), len(cases))
+	}
+	// The recorded responses are mostly, but deliberately not entirely,
+	// right, so every metric is exercised.
+	for name, r := range map[string]Ratio{
+		"recall":            res.Recall(),
+		"HIGH recall":       res.HighRecall(),
+		"level agreement":   res.LevelAgreement(),
+		"format compliance": res.FormatCompliance(),
+	} {
+		if r.Of == 0 || r.N == 0 || r.N == r.Of {
+			t.Errorf("%s = %d/%d, want partial agreement", name, r.N, r.Of)
+		}
+	}
+	if res.Tokens() == 0 {
+		t.Error("token usage not recorded")
+	}
+
+	// The mock passes sources through the summary stages unchanged, so the
+	// pipeline's prompts are still exercised; an override that breaks a
+	// template fails the run.
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "notes.tmpl"), []byte("{{/* version: 2 */}}{{.Missing}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = Run(context.Background(), Config{Provider: "mock", PromptsDir: dir}, cases)
+	if err != nil {
+		t.Fatalf("Run with broken override: %v", err)
+	}
+	if res.Errors() == 0 {
+		t.Error("cases with notes should fail to render the broken notes prompt")
+	}
+}
+
+func TestRun_UnknownProvider(t *testing.T) {
+	cases, err := LoadCases(corpus)
+	if err != nil {
+		t.Fatalf("LoadCases: %v", err)
+	}
+	if _, err := Run(context.Background(), Config{Provider: "bogus"}, cases); err == nil {
+		t.Error("Run should reject an unknown provider")
+	}
+}
@@ -0,0 +1,212 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+// CaseResult is the score of one case's report.
+type CaseResult struct {
+	Case   *Case
+	Report *analysis.RelevanceReport
+	// Err is set when the case could not be analyzed; the counts are zero.
+	Err error
+
+	// Found of Expected topics appear at any level.
+	Found, Expected int
+	// HighFound of HighExpected HIGH topics appear at HIGH.
+	HighFound, HighExpected int
+	// LevelAgreed of the Found topics appear at their expected level.
+	LevelAgreed int
+	// FormatPassed of FormatChecks format checks pass; Violations describes
+	// the failures.
+	FormatPassed, FormatChecks int
+	Violations                 []string
+	// Missed lists the expected topics that were not reported.
+	Missed []string
+}
+
+// Result is the score of one configuration over all cases.
+type Result struct {
+	Config Config
+	Cases  []*CaseResult
+}
+
+// Ratio is a count of successes out of a total.
+type Ratio struct {
+	N, Of int
+}
+
+// Percent returns the ratio as a percentage, or 0 if the total is 0.
+func (r Ratio) Percent() float64 {
+	if r.Of == 0 {
+		return 0
+	}
+	return 100 * float64(r.N) / float64(r.Of)
+}
+
+// Recall is the share of expected topics reported at any level.
+func (r *Result) Recall() Ratio {
+	return r.sum(func(c *CaseResult) (int, int) { return c.Found, c.Expected })
+}
+
+// HighRecall is the share of expected HIGH topics reported at HIGH.
+func (r *Result) HighRecall() Ratio {
+	return r.sum(func(c *CaseResult) (int, int) { return c.HighFound, c.HighExpected })
+}
+
+// LevelAgreement is the share of reported expected topics at the expected
+// level.
+func (r *Result) LevelAgreement() Ratio {
+	return r.sum(func(c *CaseResult) (int, int) { return c.LevelAgreed, c.Found })
+}
+
+// FormatCompliance is the share of format checks passed.
+func (r *Result) FormatCompliance() Ratio {
+	return r.sum(func(c *CaseResult) (int, int) { return c.FormatPassed, c.FormatChecks })
+}
+
+// Errors is the number of cases that could not be analyzed.
+func (r *Result) Errors() int {
+	n := 0
+	for _, c := range r.Cases {
+		if c.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Tokens is the total token usage across cases.
+func (r *Result) Tokens() int {
+	n := 0
+	for _, c := range r.Cases {
+		if c.Report != nil {
+			n += c.Report.TokensUsed
+		}
+	}
+	return n
+}
+
+func (r *Result) sum(f func(*CaseResult) (int, int)) Ratio {
+	var total Ratio
+	for _, c := range r.Cases {
+		n, of := f(c)
+		total.N += n
+		total.Of += of
+	}
+	return total
+}
+
+// Score compares a relevance report with the case's expectations.
+func Score(c *Case, report *analysis.RelevanceReport) *CaseResult {
+	res := &CaseResult{Case: c, Report: report, Expected: len(c.Expected)}
+	levels := map[string][]string{
+		"high":   report.HighItems,
+		"medium": report.MediumItems,
+		"low":    report.LowItems,
+	}
+	for _, e := range c.Expected {
+		if e.Level == "high" {
+			res.HighExpected++
+		}
+		level := findTopic(e, levels)
+		if level == "" {
+			res.Missed = append(res.Missed, e.Topic)
+			continue
+		}
+		res.Found++
+		if level == e.Level {
+			res.LevelAgreed++
+			if level == "high" {
+				res.HighFound++
+			}
+		}
+	}
+	res.Violations, res.FormatChecks = checkFormat(report.Report)
+	res.FormatPassed = res.FormatChecks - len(res.Violations)
+	return res
+}
+
+// findTopic returns the highest level at which an item names the expected
+// topic or one of its aliases, or "" if none does.
+func findTopic(e Expectation, levels map[string][]string) string {
+	names := append([]string{e.Topic}, e.Aliases...)
+	for _, level := range []string{"high", "medium", "low"} {
+		for _, item := range levels[level] {
+			text := normalize(item)
+			for _, name := range names {
+				if strings.Contains(text, normalize(name)) {
+					return level
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+var (
+	// itemPattern is the bullet format the relevance prompt asks for:
+	// "- **Topic Name** — what and why."
+	itemPattern = regexp.MustCompile(`^[-*] (\[[A-Z]+\] )?\*\*[^*]+\*\* — \S`)
+	// headerPattern matches the three section headers.
+	headerPattern = regexp.MustCompile(`^#### (HIGH|MEDIUM|LOW) Relevance$`)
+)
+
+// bannedSections are the sections the relevance prompt forbids.
+var bannedSections = []string{"Overall Assessment", "Analysis Summary", "Recommendation", "Executive Summary"}
+
+// checkFormat checks a relevance response against the format the prompt
+// asks for, returning the violations and the number of checks made.
+func checkFormat(report string) ([]string, int) {
+	var violations []string
+	headers := map[string]bool{}
+	var badItems, prose []string
+	for _, line := range strings.Split(report, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case headerPattern.MatchString(line):
+			headers[headerPattern.FindStringSubmatch(line)[1]] = true
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			if !itemPattern.MatchString(line) {
+				badItems = append(badItems, line)
+			}
+		case line == "None this period." || line == "`None this period.`":
+		default:
+			prose = append(prose, line)
+		}
+	}
+
+	if len(headers) != 3 {
+		violations = append(violations, "missing one of the HIGH, MEDIUM and LOW section headers")
+	}
+	if len(badItems) > 0 {
+		violations = append(violations, "items not in '**Topic** — summary' form: "+quoteFirst(badItems))
+	}
+	if len(prose) > 0 {
+		violations = append(violations, "text outside the sections: "+quoteFirst(prose))
+	}
+	for _, banned := range bannedSections {
+		if strings.Contains(report, banned) {
+			violations = append(violations, "forbidden section "+banned)
+			break
+		}
+	}
+	return violations, 4
+}
+
+// quoteFirst quotes the first line of lines, noting how many more there are.
+func quoteFirst(lines []string) string {
+	if len(lines) > 1 {
+		return fmt.Sprintf("%q and %d more", lines[0], len(lines)-1)
+	}
+	return fmt.Sprintf("%q", lines[0])
+}
@@ -0,0 +1,73 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// atomFeed is an Atom (RFC 4287) feed document.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  *atomPerson `xml:"author,omitempty"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+
+	file string // path relative to the output directory
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomCategory struct {
+	Term  string `xml:"term,attr"`
+	Label string `xml:"label,attr,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func htmlContent(html string) atomContent {
+	return atomContent{Type: "html", Body: html}
+}
+
+// add appends e unless the feed already holds limit entries.
+func (f *atomFeed) add(limit int, e atomEntry) {
+	if len(f.Entries) < limit {
+		f.Entries = append(f.Entries, e)
+	}
+}
+
+// latest returns the newest entry update time, which is the feed's update
+// time. An empty feed uses the Unix epoch so its content stays stable.
+func (f *atomFeed) latest() string {
+	latest := time.Unix(0, 0).UTC().Format(time.RFC3339)
+	for _, e := range f.Entries {
+		// RFC 3339 UTC timestamps sort lexically.
+		if e.Updated > latest {
+			latest = e.Updated
+		}
+	}
+	return latest
+}
@@ -0,0 +1,302 @@
+// Package feed writes Atom feeds of the stored digest history: one of
+// digests, one of HIGH relevance items across all SIGs and, optionally, one
+// of all items per SIG. Entry IDs are derived from the SIG set or SIG, the
+// ISO week and the item topic, so regenerating the feeds after a re-run
+// does not make readers show duplicates.
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// Dir is the directory feeds are written to, relative to the output
+// directory.
+const Dir = "feeds"
+
+// defaultMaxEntries caps the entries per feed when the config does not.
+const defaultMaxEntries = 50
+
+// idPrefix starts every feed and entry ID. Tag URIs (RFC 4151) are
+// permanent and do not depend on where the feeds are hosted.
+const idPrefix = "tag:otel-sig-tracker,2026:"
+
+// Write regenerates the feeds in outDir/feeds from every digest stored in
+// s and returns the paths written. A digest generated again for the same
+// week and SIG set replaces the older one rather than adding an entry.
+func Write(s *store.Store, outDir string, cfg config.FeedConfig) ([]string, error) {
+	recs, err := s.ListReports("digest", 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing digests: %w", err)
+	}
+	b := &builder{cfg: cfg, max: cfg.MaxEntries, sigs: map[string]*atomFeed{}}
+	if b.max == 0 {
+		b.max = defaultMaxEntries
+	}
+	b.digests = b.newFeed("digests", "OTel SIG Weekly Digests")
+	b.high = b.newFeed("high", "OTel SIG Tracker: HIGH relevance items")
+
+	seen := map[string]bool{}
+	// Reports are listed newest first, so the latest run of a week wins.
+	for _, rec := range recs {
+		if rec.Payload == "" {
+			continue
+		}
+		digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+		if err != nil {
+			return nil, fmt.Errorf("report %d: %w", rec.ID, err)
+		}
+		b.addDigest(rec, digest, seen)
+	}
+
+	feeds := []*atomFeed{b.digests, b.high}
+	if cfg.PerSIG {
+		for _, id := range b.sigOrder {
+			feeds = append(feeds, b.sigs[id])
+		}
+	}
+	var paths []string
+	for _, f := range feeds {
+		p := filepath.Join(outDir, filepath.FromSlash(f.file))
+		if err := writeFeed(p, f); err != nil {
+			return paths, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// builder accumulates the entries of every feed.
+type builder struct {
+	cfg      config.FeedConfig
+	max      int
+	digests  *atomFeed
+	high     *atomFeed
+	sigs     map[string]*atomFeed
+	sigOrder []string
+}
+
+func (b *builder) newFeed(name, title string) *atomFeed {
+	f := &atomFeed{
+		ID:    idPrefix + "feed/" + name,
+		Title: title,
+		file:  path.Join(Dir, name+".xml"),
+	}
+	if b.cfg.BaseURL != "" {
+		f.Links = []atomLink{{Rel: "self", Href: b.url(f.file)}}
+	}
+	return f
+}
+
+// addDigest adds the entries for one stored digest to every feed it
+// belongs in, skipping entries whose IDs are already in seen.
+func (b *builder) addDigest(rec *store.Report, digest *analysis.DigestReport, seen map[string]bool) {
+	week := report.DigestWeek(digest.DateRangeEnd, rec.DateRangeEnd)
+	updated := rec.CreatedAt
+	if updated.IsZero() {
+		updated = rec.DateRangeEnd
+	}
+	published := rec.DateRangeEnd
+	reportURL := ""
+	if b.cfg.BaseURL != "" && rec.FilePath != "" {
+		reportURL = b.url(filepath.Base(rec.FilePath))
+	}
+
+	sigSet := rec.SIGSet
+	if sigSet == "" {
+		sigSet = "all"
+	}
+	if id := idPrefix + "digest/" + sigSet + "/" + week; !seen[id] {
+		seen[id] = true
+		title := "OTel Weekly Digest — " + week
+		if sigSet != "all" {
+			title += " (" + strings.ReplaceAll(sigSet, ",", ", ") + ")"
+		}
+		b.digests.add(b.max, atomEntry{
+			ID:        id,
+			Title:     title,
+			Updated:   atomTime(updated),
+			Published: atomTime(published),
+			Links:     alternate(reportURL),
+			Content:   htmlContent(digestSummary(digest)),
+		})
+	}
+
+	for _, sr := range digest.SIGReports {
+		rr := sr.RelevanceReport
+		if rr == nil {
+			continue
+		}
+		sigID := report.Slugify(strings.TrimPrefix(report.SIGAnchor(sr), "sig-"))
+		link := reportURL
+		if link != "" && strings.HasSuffix(link, ".html") {
+			link += "#" + report.SIGAnchor(sr)
+		}
+		for i, items := range [][]string{rr.HighItems, rr.MediumItems, rr.LowItems} {
+			level := []string{"HIGH", "MEDIUM", "LOW"}[i]
+			for _, text := range items {
+				topic := analysis.ItemTopic(text)
+				id := itemID(sigID, week, topic)
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				e := atomEntry{
+					ID:         id,
+					Title:      fmt.Sprintf("[%s] %s", sr.SIGName, topic),
+					Updated:    atomTime(updated),
+					Published:  atomTime(published),
+					Links:      alternate(link),
+					Categories: []atomCategory{{Term: level}, {Term: sigID, Label: sr.SIGName}},
+					Content:    htmlContent(itemSummary(sr, text, rr.ItemStatuses[text])),
+				}
+				if level == "HIGH" {
+					b.high.add(b.max, e)
+				}
+				if b.cfg.PerSIG {
+					b.sigFeed(sigID, sr.SIGName).add(b.max, e)
+				}
+			}
+		}
+	}
+}
+
+// sigFeed returns the feed of a SIG, creating it on first use.
+func (b *builder) sigFeed(id, name string) *atomFeed {
+	if f, ok := b.sigs[id]; ok {
+		return f
+	}
+	f := b.newFeed("sigs/"+id, "OTel SIG Tracker: "+name)
+	f.ID = idPrefix + "feed/sig/" + id
+	b.sigs[id] = f
+	b.sigOrder = append(b.sigOrder, id)
+	return f
+}
+
+// url returns the absolute URL of a path under the output directory.
+func (b *builder) url(rel string) string {
+	return strings.TrimRight(b.cfg.BaseURL, "/") + "/" + rel
+}
+
+// itemID returns the permanent ID of a relevance item: the same topic
+// reported for a SIG in the same week always gets the same ID.
+func itemID(sigID, week, topic string) string {
+	slug := report.Slugify(topic)
+	if slug == "" {
+		slug = "item"
+	}
+	return idPrefix + "item/" + sigID + "/" + week + "/" + slug
+}
+
+var summaryTemplate = template.Must(template.New("summary").Parse(`
+{{- define "digest" -}}
+{{- if .SIGs}}<ul>
+{{- range .SIGs}}
+<li><strong>{{.Name}}</strong>: {{.High}} high, {{.Medium}} medium, {{.Low}} low
+{{- if .Top}}<ul>{{range .Top}}<li>{{.}}</li>{{end}}</ul>{{end}}</li>
+{{- end}}
+</ul>
+{{- else}}<p>No SIG activity this week.</p>{{end}}
+{{- end}}
+{{- define "item" -}}
+<p>{{if .Status}}<strong>{{.Status}}</strong> {{end}}{{.Text}}</p>
+{{- if or .NotesLink .RecordingLink}}
+<p>{{if .NotesLink}}<a href="{{.NotesLink}}">Meeting Notes</a>{{end}}{{if and .NotesLink .RecordingLink}} | {{end}}{{if .RecordingLink}}<a href="{{.RecordingLink}}">Recording</a>{{end}}</p>
+{{- end}}
+{{- end}}`))
+
+type summarySIG struct {
+	Name              string
+	High, Medium, Low int
+	Top               []template.HTML // HIGH items
+}
+
+// digestSummary renders a digest as a short HTML list of its active SIGs
+// and their HIGH items.
+func digestSummary(digest *analysis.DigestReport) string {
+	var data struct{ SIGs []summarySIG }
+	for _, sr := range digest.SIGReports {
+		rr := sr.RelevanceReport
+		if rr == nil || len(rr.HighItems)+len(rr.MediumItems)+len(rr.LowItems) == 0 {
+			continue
+		}
+		s := summarySIG{Name: sr.SIGName, High: len(rr.HighItems), Medium: len(rr.MediumItems), Low: len(rr.LowItems)}
+		for _, text := range rr.HighItems {
+			s.Top = append(s.Top, report.ItemHTML(text))
+		}
+		data.SIGs = append(data.SIGs, s)
+	}
+	return execute("digest", data)
+}
+
+// itemSummary renders one relevance item with its status and source links.
+func itemSummary(sr *analysis.SIGReport, text string, status analysis.ItemStatus) string {
+	if status != analysis.ItemNew && status != analysis.ItemUpdated {
+		status = ""
+	}
+	return execute("item", struct {
+		Status                   analysis.ItemStatus
+		Text                     template.HTML
+		NotesLink, RecordingLink string
+	}{status, report.ItemHTML(text), sr.NotesLink, sr.RecordingLink})
+}
+
+func execute(name string, data any) string {
+	var buf bytes.Buffer
+	// The templates only range over plain values, so they cannot fail.
+	_ = summaryTemplate.ExecuteTemplate(&buf, name, data)
+	return buf.String()
+}
+
+func alternate(href string) []atomLink {
+	if href == "" {
+		return nil
+	}
+	return []atomLink{{Rel: "alternate", Type: mimeType(href), Href: href}}
+}
+
+func mimeType(href string) string {
+	switch path.Ext(strings.SplitN(href, "#", 2)[0]) {
+	case ".html":
+		return "text/html"
+	case ".json":
+		return "application/json"
+	case ".md":
+		return "text/markdown"
+	}
+	return ""
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// writeFeed encodes f as an Atom document at p.
+func writeFeed(p string, f *atomFeed) error {
+	f.Updated = f.latest()
+	f.Author = &atomPerson{Name: "otel-sig-tracker"}
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", f.file, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("creating feed directory: %w", err)
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err := os.WriteFile(p, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", f.file, err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package feed
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func insertDigest(t *testing.T, s *store.Store, sigSet, file string, digest *analysis.DigestReport) {
+	t.Helper()
+	payload, err := report.MarshalDigest(digest)
+	if err != nil {
+		t.Fatalf("MarshalDigest: %v", err)
+	}
+	start, _ := time.Parse("2006-01-02", digest.DateRangeStart)
+	end, _ := time.Parse("2006-01-02", digest.DateRangeEnd)
+	rec := &store.Report{ReportType: "digest", SIGSet: sigSet, DateRangeStart: start, DateRangeEnd: end, FilePath: file, Payload: string(payload)}
+	if err := s.InsertReport(rec); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+}
+
+func testDigest(start, end, highItem string) *analysis.DigestReport {
+	return &analysis.DigestReport{
+		DateRangeStart: start,
+		DateRangeEnd:   end,
+		SIGReports: []*analysis.SIGReport{
+			{
+				SIGID:     "collector",
+				SIGName:   "Collector",
+				NotesLink: "https://docs.google.com/document/d/abc",
+				RelevanceReport: &analysis.RelevanceReport{
+					HighItems:    []string{highItem},
+					LowItems:     []string{"**Batch Processor Deprecation** — moving to exporters."},
+					ItemStatuses: map[string]analysis.ItemStatus{highItem: analysis.ItemNew},
+				},
+			},
+			{
+				SIGID:   "specification",
+				SIGName: "Specification",
+				RelevanceReport: &analysis.RelevanceReport{
+					MediumItems: []string{"**Profiling Signal OTEP** — new profiling signal."},
+				},
+			},
+		},
+	}
+}
+
+func readFeed(t *testing.T, path string) *atomFeed {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading feed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), xml.Header) {
+		t.Errorf("%s has no XML declaration", path)
+	}
+	var f atomFeed
+	if err := xml.Unmarshal(data, &f); err != nil {
+		t.Fatalf("decoding %s: %v", path, err)
+	}
+	return &f
+}
+
+func entryIDs(f *atomFeed) []string {
+	var ids []string
+	for _, e := range f.Entries {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestWrite(t *testing.T) {
+	s := newTestStore(t)
+	insertDigest(t, s, "all", "reports/2026-02-11-weekly-digest.md",
+		testDigest("2026-02-04", "2026-02-11", "**OTLP/HTTP Partial Success** — affects OTLP ingest."))
+	insertDigest(t, s, "all", "reports/2026-02-18-weekly-digest.html",
+		testDigest("2026-02-11", "2026-02-18", "**OTLP/HTTP Partial Success** — now merged."))
+	out := t.TempDir()
+
+	paths, err := Write(s, out, config.FeedConfig{BaseURL: "https://digest.example.com/", PerSIG: true})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	want := []string{"digests.xml", "high.xml", "sigs/collector.xml", "sigs/specification.xml"}
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	for i, w := range want {
+		if paths[i] != filepath.Join(out, Dir, filepath.FromSlash(w)) {
+			t.Errorf("paths[%d] = %s, want feeds/%s", i, paths[i], w)
+		}
+	}
+
+	digests := readFeed(t, paths[0])
+	if digests.ID != "tag:otel-sig-tracker,2026:feed/digests" || len(digests.Links) != 1 || digests.Links[0].Href != "https://digest.example.com/feeds/digests.xml" {
+		t.Errorf("digests feed id/link = %s %+v", digests.ID, digests.Links)
+	}
+	if got := entryIDs(digests); len(got) != 2 || got[0] != "tag:otel-sig-tracker,2026:digest/all/2026-W08" || got[1] != "tag:otel-sig-tracker,2026:digest/all/2026-W07" {
+		t.Errorf("digest entry IDs = %v", got)
+	}
+	if e := digests.Entries[0]; e.Links[0].Href != "https://digest.example.com/2026-02-18-weekly-digest.html" || !strings.Contains(e.Content.Body, "<strong>Collector</strong>: 1 high, 0 medium, 1 low") {
+		t.Errorf("digest entry = %+v", e)
+	}
+	if digests.Updated != digests.Entries[0].Updated {
+		t.Errorf("feed updated = %s, want latest entry %s", digests.Updated, digests.Entries[0].Updated)
+	}
+
+	high := readFeed(t, paths[1])
+	if got := entryIDs(high); len(got) != 2 || got[0] != "tag:otel-sig-tracker,2026:item/collector/2026-W08/otlp-http-partial-success" {
+		t.Errorf("high entry IDs = %v", got)
+	}
+	e := high.Entries[0]
+	if e.Title != "[Collector] OTLP/HTTP Partial Success" {
+		t.Errorf("title = %q", e.Title)
+	}
+	if e.Links[0].Href != "https://digest.example.com/2026-02-18-weekly-digest.html#sig-collector" {
+		t.Errorf("link = %q", e.Links[0].Href)
+	}
+	if !strings.Contains(e.Content.Body, "<strong>NEW</strong>") || !strings.Contains(e.Content.Body, "now merged") || !strings.Contains(e.Content.Body, "Meeting Notes") {
+		t.Errorf("content = %q", e.Content.Body)
+	}
+	if high.Entries[1].Links[0].Href != "https://digest.example.com/2026-02-11-weekly-digest.md" {
+		t.Errorf("Markdown report link = %q, want no anchor", high.Entries[1].Links[0].Href)
+	}
+
+	// The per-SIG feed holds items of every level.
+	if got := entryIDs(readFeed(t, paths[2])); len(got) != 4 {
+		t.Errorf("collector feed has %d entries, want 4: %v", len(got), got)
+	}
+}
+
+func TestWrite_StableAcrossReruns(t *testing.T) {
+	s := newTestStore(t)
+	digest := testDigest("2026-02-11", "2026-02-18", "**OTLP/HTTP Partial Success** — now merged.")
+	insertDigest(t, s, "all", "", digest)
+	out := t.TempDir()
+	paths, err := Write(s, out, config.FeedConfig{})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	before := entryIDs(readFeed(t, paths[1]))
+
+	// The same week generated again, with the item reworded, and a run
+	// limited to the Collector, which reports the same item.
+	insertDigest(t, s, "all", "", testDigest("2026-02-11", "2026-02-18", "**OTLP/HTTP Partial Success** — merged in v1.5."))
+	insertDigest(t, s, "collector", "", testDigest("2026-02-11", "2026-02-18", "**OTLP/HTTP Partial Success** — merged in v1.5."))
+	if paths, err = Write(s, out, config.FeedConfig{}); err != nil {
+		t.Fatalf("second Write: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("paths = %v, want only the digest and HIGH feeds", paths)
+	}
+	high := readFeed(t, paths[1])
+	if after := entryIDs(high); len(after) != 1 || after[0] != before[0] {
+		t.Errorf("HIGH entry IDs after re-run = %v, want %v", after, before)
+	}
+	if !strings.Contains(high.Entries[0].Content.Body, "merged in v1.5") {
+		t.Errorf("entry should show the latest run's text, got %q", high.Entries[0].Content.Body)
+	}
+	if got := entryIDs(readFeed(t, paths[0])); len(got) != 2 {
+		t.Errorf("digest entries = %v, want one per SIG set", got)
+	}
+	if len(high.Links) != 0 || len(high.Entries[0].Links) != 0 {
+		t.Error("feeds without a base URL should have no links")
+	}
+}
+
+func TestWrite_MaxEntries(t *testing.T) {
+	s := newTestStore(t)
+	for _, end := range []string{"2026-02-04", "2026-02-11", "2026-02-18"} {
+		insertDigest(t, s, "all", "", testDigest(end, end, "**OTLP/HTTP Partial Success** — "+end))
+	}
+	paths, err := Write(s, t.TempDir(), config.FeedConfig{MaxEntries: 2})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	high := readFeed(t, paths[1])
+	if len(high.Entries) != 2 || !strings.Contains(high.Entries[0].ID, "2026-W08") {
+		t.Errorf("HIGH entries = %v, want the 2 newest", entryIDs(high))
+	}
+}
+
+func TestWrite_Empty(t *testing.T) {
+	paths, err := Write(newTestStore(t), t.TempDir(), config.FeedConfig{})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	f := readFeed(t, paths[0])
+	if len(f.Entries) != 0 || f.Updated != "1970-01-01T00:00:00Z" {
+		t.Errorf("empty feed = %+v", f)
+	}
+}
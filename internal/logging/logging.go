@@ -0,0 +1,85 @@
+// Package logging builds the structured logger shared by the pipeline,
+// fetchers and analysis components.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Attribute keys carried by records about a SIG's work.
+const (
+	KeySIG    = "sig"
+	KeySource = "source"
+	KeyStage  = "stage"
+)
+
+// Options selects where logs go, their format and the minimum level.
+type Options struct {
+	// File receives logs instead of stderr, keeping the terminal clean for
+	// the command's summary. It is appended to.
+	File string
+	// Format is "text" (the default) or "json".
+	Format string
+	// Level is "debug", "info" (the default), "warn" or "error".
+	Level string
+}
+
+// ParseLevel parses a level name. Empty means info.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("log level must be 'debug', 'info', 'warn' or 'error', got %q", s)
+}
+
+// New returns a logger writing records at level or above to w in format.
+func New(w io.Writer, format string, level slog.Level) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("log format must be 'text' or 'json', got %q", format)
+}
+
+// Setup builds the logger described by opts and installs it as the slog
+// default, which also routes the standard log package through it. The
+// returned function closes the log file, if any.
+func Setup(opts Options) (*slog.Logger, func() error, error) {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var w io.Writer = os.Stderr
+	closeFn := func() error { return nil }
+	if opts.File != "" {
+		f, err := os.OpenFile(opts.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening log file: %w", err)
+		}
+		w = f
+		closeFn = f.Close
+	}
+
+	logger, err := New(w, opts.Format, level)
+	if err != nil {
+		closeFn()
+		return nil, nil, err
+	}
+	slog.SetDefault(logger)
+	return logger, closeFn, nil
+}
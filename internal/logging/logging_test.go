@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"":      slog.LevelInfo,
+		"info":  slog.LevelInfo,
+		"DEBUG": slog.LevelDebug,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+	for in, want := range tests {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel(\"loud\") should fail")
+	}
+}
+
+func TestNew_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "json", slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	logger.With(KeySIG, "collector", KeyStage, "fetch").Info("fetched", KeySource, "slack")
+	logger.Debug("hidden")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d records, want 1 (debug is below the level)", len(lines))
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	if rec["sig"] != "collector" || rec["stage"] != "fetch" || rec["source"] != "slack" || rec["msg"] != "fetched" {
+		t.Errorf("record = %v", rec)
+	}
+
+	if _, err := New(&buf, "xml", slog.LevelInfo); err == nil {
+		t.Error("New with format xml should fail")
+	}
+}
+
+func TestSetup_LogFile(t *testing.T) {
+	defer slog.SetDefault(slog.Default())
+	defer log.SetOutput(log.Writer())
+	defer log.SetFlags(log.Flags())
+
+	path := filepath.Join(t.TempDir(), "run.log")
+	_, closeFn, err := Setup(Options{File: path, Level: "debug"})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	slog.Debug("structured", KeySIG, "collector")
+	log.Printf("legacy %d", 1)
+	if err := closeFn(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	got := string(data)
+	if !strings.Contains(got, "sig=collector") || !strings.Contains(got, "legacy 1") {
+		t.Errorf("log file = %q, want both structured and standard log records", got)
+	}
+}
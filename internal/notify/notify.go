@@ -0,0 +1,261 @@
+// Package notify delivers notifications about notable pipeline events, such
+// as a HIGH relevance item for a watched SIG or a failed run, to webhooks,
+// Slack incoming webhooks and local commands.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/registry"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
+)
+
+// Notification events a hook can select.
+const (
+	EventHighItem        = "high_item"
+	EventRunFailed       = "run_failed"
+	EventRunCompleted    = "run_completed"
+	EventSlackAuthFailed = "slack_auth_failed"
+)
+
+var knownEvents = []string{EventHighItem, EventRunFailed, EventRunCompleted, EventSlackAuthFailed}
+
+const (
+	defaultRetries = 3
+	defaultTimeout = 10 * time.Second
+	// queueSize bounds the notifications waiting for delivery; more are
+	// dropped with a warning rather than blocking the pipeline.
+	queueSize = 256
+)
+
+// Notification is the payload delivered to hooks. Webhooks receive it as
+// JSON, and command hooks on stdin.
+type Notification struct {
+	Event string    `json:"event"`
+	Time  time.Time `json:"time"`
+	SIGID string    `json:"sig_id,omitempty"`
+	RunID int64     `json:"run_id,omitempty"`
+	// Status is the run's ledger status for run events.
+	Status string `json:"status,omitempty"`
+	// Text is the item for high_item and a summary otherwise.
+	Text  string `json:"text"`
+	Error string `json:"error,omitempty"`
+}
+
+// sender delivers a notification to one destination.
+type sender interface {
+	send(ctx context.Context, n Notification) error
+}
+
+// hook is a configured destination and the notifications it wants.
+type hook struct {
+	name    string
+	sender  sender
+	events  []string
+	sigs    []string
+	retries int
+	timeout time.Duration
+}
+
+// Notifier turns pipeline events into notifications and delivers them to
+// the configured hooks in the background. It implements pipeline.Observer.
+type Notifier struct {
+	hooks   []*hook
+	queue   chan Notification
+	done    chan struct{}
+	backoff time.Duration
+	logger  *slog.Logger
+
+	closeOnce sync.Once
+}
+
+// New creates a notifier for hooks and starts its delivery worker. Close
+// must be called to deliver pending notifications and stop it.
+func New(hooks []config.NotifyHook) (*Notifier, error) {
+	n := &Notifier{
+		queue:   make(chan Notification, queueSize),
+		done:    make(chan struct{}),
+		backoff: time.Second,
+		logger:  slog.Default(),
+	}
+	for i, hc := range hooks {
+		h, err := newHook(hc)
+		if err != nil {
+			name := hc.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return nil, fmt.Errorf("notify hook %s: %w", name, err)
+		}
+		n.hooks = append(n.hooks, h)
+	}
+	go n.run()
+	return n, nil
+}
+
+func newHook(hc config.NotifyHook) (*hook, error) {
+	h := &hook{
+		name:    hc.Name,
+		events:  hc.Events,
+		retries: hc.Retries,
+		timeout: hc.Timeout,
+	}
+	switch hc.Type {
+	case "webhook", "slack":
+		if hc.URL == "" {
+			return nil, fmt.Errorf("%s hook needs a url", hc.Type)
+		}
+		h.sender = &webhookSender{
+			url:    hc.URL,
+			slack:  hc.Type == "slack",
+			client: &http.Client{Transport: telemetry.Transport(nil, "notify")},
+		}
+	case "command":
+		if len(hc.Command) == 0 {
+			return nil, errors.New("command hook needs a command")
+		}
+		h.sender = &commandSender{argv: hc.Command}
+	default:
+		return nil, fmt.Errorf("type must be 'webhook', 'slack' or 'command', got %q", hc.Type)
+	}
+	if h.name == "" {
+		h.name = hc.Type
+	}
+	for _, e := range hc.Events {
+		if !slices.Contains(knownEvents, e) {
+			return nil, fmt.Errorf("unknown event %q (use %v)", e, knownEvents)
+		}
+	}
+	h.sigs = hc.SIGs
+	if h.retries == 0 {
+		h.retries = defaultRetries
+	} else if h.retries < 0 {
+		h.retries = 0
+	}
+	if h.timeout <= 0 {
+		h.timeout = defaultTimeout
+	}
+	return h, nil
+}
+
+// SetLogger replaces the notifier's logger.
+func (n *Notifier) SetLogger(l *slog.Logger) {
+	n.logger = l
+}
+
+// OnEvent queues the notification for e, if e is notable.
+func (n *Notifier) OnEvent(e pipeline.Event) {
+	note, ok := fromEvent(e)
+	if !ok {
+		return
+	}
+	select {
+	case n.queue <- note:
+	default:
+		n.logger.Warn("notification queue full, dropping notification", "event", note.Event, logging.KeySIG, note.SIGID)
+	}
+}
+
+// Close delivers the queued notifications and stops the worker, giving up
+// when ctx is done. The notifier must be unsubscribed from pipelines first.
+func (n *Notifier) Close(ctx context.Context) error {
+	n.closeOnce.Do(func() { close(n.queue) })
+	select {
+	case <-n.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("delivering notifications: %w", ctx.Err())
+	}
+}
+
+// run delivers queued notifications until the queue is closed.
+func (n *Notifier) run() {
+	defer close(n.done)
+	for note := range n.queue {
+		for _, h := range n.hooks {
+			if h.wants(note) {
+				n.deliver(h, note)
+			}
+		}
+	}
+}
+
+// wants reports whether h is configured to receive note.
+func (h *hook) wants(note Notification) bool {
+	if len(h.events) > 0 && !slices.Contains(h.events, note.Event) {
+		return false
+	}
+	// Run-wide notifications carry no SIG and pass the SIG filter.
+	if note.SIGID != "" && !registry.MatchSIGFilter(note.SIGID, h.sigs) {
+		return false
+	}
+	return true
+}
+
+// deliver sends note to h, retrying failures with exponential backoff.
+func (n *Notifier) deliver(h *hook, note Notification) {
+	log := n.logger.With("hook", h.name, "event", note.Event)
+	if note.SIGID != "" {
+		log = log.With(logging.KeySIG, note.SIGID)
+	}
+	backoff := n.backoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+		err := h.sender.send(ctx, note)
+		cancel()
+		if err == nil {
+			log.Info("notification delivered", "attempts", attempt+1)
+			return
+		}
+		if attempt >= h.retries {
+			log.Error("notification delivery failed", "attempts", attempt+1, "err", err)
+			return
+		}
+		log.Warn("notification delivery failed, retrying", "attempt", attempt+1, "retry_in", backoff, "err", err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// fromEvent returns the notification for a pipeline event, if any.
+func fromEvent(e pipeline.Event) (Notification, bool) {
+	note := Notification{Time: e.Time, SIGID: e.SIGID, RunID: e.RunID}
+	if e.Err != nil {
+		note.Error = e.Err.Error()
+	}
+	switch e.Type {
+	case pipeline.EventHighItem:
+		note.Event = EventHighItem
+		note.Text = e.Item
+	case pipeline.EventSlackAuthFailed:
+		note.Event = EventSlackAuthFailed
+		note.Text = "Slack rejected the stored credentials; run `otel-sig-scraper slack-login` to renew them."
+	case pipeline.EventRunFinished:
+		note.Status = e.State
+		switch e.State {
+		case store.RunCompleted:
+			note.Event = EventRunCompleted
+			note.Text = fmt.Sprintf("Run %d completed.", e.RunID)
+		case store.RunFailed, store.RunPartial:
+			note.Event = EventRunFailed
+			note.Text = fmt.Sprintf("Run %d %s.", e.RunID, e.State)
+		default:
+			// Interrupted runs were stopped on purpose.
+			return Notification{}, false
+		}
+	default:
+		return Notification{}, false
+	}
+	return note, true
+}
@@ -0,0 +1,209 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// recorder is a local HTTP stand-in that records request bodies and fails
+// the first failures requests.
+type recorder struct {
+	mu       sync.Mutex
+	bodies   []string
+	failures int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failures > 0 {
+		r.failures--
+		http.Error(w, "try later", http.StatusServiceUnavailable)
+		return
+	}
+	r.bodies = append(r.bodies, string(body))
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.bodies...)
+}
+
+func newTestNotifier(t *testing.T, hooks ...config.NotifyHook) *Notifier {
+	t.Helper()
+	n, err := New(hooks)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	n.backoff = time.Millisecond
+	return n
+}
+
+func closeNotifier(t *testing.T, n *Notifier) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := n.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestNotifier_WebhookFiltersByEventAndSIG(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newTestNotifier(t, config.NotifyHook{
+		Type:   "webhook",
+		URL:    srv.URL,
+		Events: []string{EventHighItem, EventRunFailed},
+		SIGs:   []string{"Collector"},
+	})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventHighItem, SIGID: "collector", Item: "OTLP partial success is now stable"})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventHighItem, SIGID: "java-sdk", Item: "not watched"})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventRunFinished, RunID: 7, State: store.RunCompleted})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventRunFinished, RunID: 8, State: store.RunFailed, Err: errors.New("boom")})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventRunFinished, RunID: 9, State: store.RunInterrupted})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventSIGState, SIGID: "collector", State: pipeline.StateDone})
+	closeNotifier(t, n)
+
+	got := rec.received()
+	if len(got) != 2 {
+		t.Fatalf("received %d notifications, want 2: %v", len(got), got)
+	}
+	var high, failed Notification
+	if err := json.Unmarshal([]byte(got[0]), &high); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(got[1]), &failed); err != nil {
+		t.Fatal(err)
+	}
+	if high.Event != EventHighItem || high.SIGID != "collector" || high.Text != "OTLP partial success is now stable" {
+		t.Errorf("high item notification = %+v", high)
+	}
+	if failed.Event != EventRunFailed || failed.RunID != 8 || failed.Status != store.RunFailed || failed.Error != "boom" {
+		t.Errorf("run failed notification = %+v", failed)
+	}
+}
+
+func TestNotifier_SIGFilterMatchesPrefixes(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	// Registry IDs of SIGs with a parenthetical description carry it on.
+	n := newTestNotifier(t, config.NotifyHook{
+		Type:   "webhook",
+		URL:    srv.URL,
+		Events: []string{EventHighItem},
+		SIGs:   []string{"Communications"},
+	})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventHighItem, SIGID: "communications-(website-documentation-etc)", Item: "New docs site"})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventHighItem, SIGID: "communicationsx", Item: "not watched"})
+	closeNotifier(t, n)
+
+	got := rec.received()
+	if len(got) != 1 || !strings.Contains(got[0], "New docs site") {
+		t.Errorf("received %v, want only the communications notification", got)
+	}
+}
+
+func TestNotifier_RetriesFailedDeliveries(t *testing.T) {
+	rec := &recorder{failures: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newTestNotifier(t, config.NotifyHook{Type: "webhook", URL: srv.URL})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventSlackAuthFailed, SIGID: "collector"})
+	closeNotifier(t, n)
+
+	if got := rec.received(); len(got) != 1 || !strings.Contains(got[0], EventSlackAuthFailed) {
+		t.Errorf("received %v, want the notification after two retries", got)
+	}
+
+	// Without retries a failure is given up on.
+	rec = &recorder{failures: 1}
+	srv2 := httptest.NewServer(rec)
+	defer srv2.Close()
+	n = newTestNotifier(t, config.NotifyHook{Type: "webhook", URL: srv2.URL, Retries: -1})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventSlackAuthFailed})
+	closeNotifier(t, n)
+	if got := rec.received(); len(got) != 0 {
+		t.Errorf("received %v, want nothing without retries", got)
+	}
+}
+
+func TestNotifier_SlackWebhookFormatting(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n := newTestNotifier(t, config.NotifyHook{Type: "slack", URL: srv.URL})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventHighItem, SIGID: "collector", Item: "New OTLP exporter defaults"})
+	closeNotifier(t, n)
+
+	got := rec.received()
+	if len(got) != 1 {
+		t.Fatalf("received %d messages, want 1", len(got))
+	}
+	var msg map[string]string
+	if err := json.Unmarshal([]byte(got[0]), &msg); err != nil {
+		t.Fatal(err)
+	}
+	if len(msg) != 1 || !strings.Contains(msg["text"], "*HIGH relevance item in collector*") ||
+		!strings.Contains(msg["text"], "New OTLP exporter defaults") {
+		t.Errorf("slack message = %v, want a text field with the item", msg)
+	}
+}
+
+func TestNotifier_CommandHook(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.txt")
+	n := newTestNotifier(t, config.NotifyHook{
+		Type:    "command",
+		Command: []string{"sh", "-c", `{ echo "$OTEL_SIG_EVENT $OTEL_SIG_SIG"; cat; } > "$0"`, out},
+	})
+	n.OnEvent(pipeline.Event{Type: pipeline.EventHighItem, SIGID: "collector", Item: "Sampling spec change"})
+	closeNotifier(t, n)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("command did not run: %v", err)
+	}
+	env, body, _ := strings.Cut(string(data), "\n")
+	if env != "high_item collector" {
+		t.Errorf("command environment = %q, want %q", env, "high_item collector")
+	}
+	var note Notification
+	if err := json.Unmarshal([]byte(body), &note); err != nil || note.Text != "Sampling spec change" {
+		t.Errorf("command stdin = %q (%v), want the notification JSON", body, err)
+	}
+}
+
+func TestNew_InvalidHooks(t *testing.T) {
+	for _, hc := range []config.NotifyHook{
+		{Type: "pager"},
+		{Type: "webhook"},
+		{Type: "command"},
+		{Type: "slack", URL: "http://localhost", Events: []string{"everything"}},
+	} {
+		if _, err := New([]config.NotifyHook{hc}); err == nil {
+			t.Errorf("New(%+v) should fail", hc)
+		}
+	}
+}
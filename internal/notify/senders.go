@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// webhookSender POSTs notifications as JSON. With slack set the body is a
+// Slack incoming-webhook message instead of the raw notification.
+type webhookSender struct {
+	url    string
+	slack  bool
+	client *http.Client
+}
+
+func (s *webhookSender) send(ctx context.Context, n Notification) error {
+	var payload any = n
+	if s.slack {
+		payload = map[string]string{"text": slackText(n)}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// slackText formats a notification as Slack mrkdwn.
+func slackText(n Notification) string {
+	var b strings.Builder
+	switch n.Event {
+	case EventHighItem:
+		fmt.Fprintf(&b, ":rotating_light: *HIGH relevance item in %s*\n%s", n.SIGID, n.Text)
+	case EventRunFailed:
+		fmt.Fprintf(&b, ":x: *%s*", n.Text)
+	case EventRunCompleted:
+		fmt.Fprintf(&b, ":white_check_mark: *%s*", n.Text)
+	case EventSlackAuthFailed:
+		fmt.Fprintf(&b, ":key: *Slack credentials expired*\n%s", n.Text)
+	default:
+		b.WriteString(n.Text)
+	}
+	if n.Error != "" {
+		fmt.Fprintf(&b, "\n```%s```", n.Error)
+	}
+	return b.String()
+}
+
+// commandSender runs a local command with the notification as JSON on
+// stdin. OTEL_SIG_EVENT and OTEL_SIG_SIG are set in its environment.
+type commandSender struct {
+	argv []string
+}
+
+func (s *commandSender) send(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, s.argv[0], s.argv[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(), "OTEL_SIG_EVENT="+n.Event, "OTEL_SIG_SIG="+n.SIGID)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("running %s: %w: %s", s.argv[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package pipeline
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+)
+
+// Window is a single reporting period.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BackfillResult summarizes a backfill run.
+type BackfillResult struct {
+	Generated int // digests written by this run
+	Skipped   int // windows that already had a digest
+	Failed    int // windows whose analysis failed
+	// Errors holds the error of each failed window.
+	Errors []error
+}
+
+// BackfillWindows splits the days from..to (inclusive) into consecutive
+// windows of the given interval, such as "1w", "14d" or "1m". Windows start
+// at the beginning of from's day; the last one is cut short at the end of
+// to's day.
+func BackfillWindows(from, to time.Time, interval string) ([]Window, error) {
+	step, err := intervalStep(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	first := config.StartOfDay(from)
+	last := config.EndOfDay(to)
+	if first.After(last) {
+		return nil, fmt.Errorf("--from %s is after --to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+
+	var windows []Window
+	for start := first; start.Before(last); {
+		next := step(start)
+		end := next.Add(-time.Nanosecond)
+		if end.After(last) {
+			end = last
+		}
+		windows = append(windows, Window{Start: start, End: end})
+		start = next
+	}
+	return windows, nil
+}
+
+// intervalStep returns a function advancing a window start by interval.
+// Intervals must be whole days or calendar months.
+func intervalStep(interval string) (func(time.Time) time.Time, error) {
+	if months, ok := config.ParseLookbackMonths(interval); ok {
+		return func(t time.Time) time.Time { return t.AddDate(0, months, 0) }, nil
+	}
+	d, err := config.ParseLookback(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval: %w", err)
+	}
+	if d < 24*time.Hour || d%(24*time.Hour) != 0 {
+		return nil, fmt.Errorf("invalid interval %q: must be a whole number of days, weeks or months", interval)
+	}
+	days := int(d / (24 * time.Hour))
+	return func(t time.Time) time.Time { return t.AddDate(0, 0, days) }, nil
+}
+
+// Backfill writes one digest per window. Windows that already have a digest
+// for the configured SIG set are skipped, so an interrupted backfill can be
+// rerun with the same arguments to pick up where it stopped. Sources are
+// fetched once for the span of the remaining windows unless running offline;
+// analysis reuses the analysis cache.
+func (p *Pipeline) Backfill(ctx context.Context, windows []Window) (*BackfillResult, error) {
+	result := &BackfillResult{}
+	sigSet := sigSetKey(p.cfg.SIGs)
+
+	var pending []Window
+	for _, w := range windows {
+		_, err := p.store.FindReport(digestReportType, sigSet, w.Start, w.End)
+		switch {
+		case err == nil:
+			p.logger.Info("digest already exists, skipping",
+				"start", w.Start.Format("2006-01-02"), "end", w.End.Format("2006-01-02"))
+			result.Skipped++
+		case err == sql.ErrNoRows:
+			pending = append(pending, w)
+		default:
+			return result, fmt.Errorf("checking existing digests: %w", err)
+		}
+	}
+	if len(pending) == 0 {
+		p.logger.Info("backfill has nothing left to do")
+		return result, nil
+	}
+
+	if !p.cfg.Offline {
+		if err := p.FetchRange(ctx, pending[0].Start, pending[len(pending)-1].End); err != nil {
+			return result, fmt.Errorf("fetch phase: %w", err)
+		}
+	}
+
+	for i, w := range pending {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		p.logger.Info("backfilling window", "window", i+1, "of", len(pending))
+		// The run ledger records one analysis per SIG; each window is
+		// analyzed afresh.
+		p.forgetSteps(stageAnalyze)
+		if err := p.AnalyzeRange(ctx, w.Start, w.End); err != nil {
+			p.logger.Warn("backfill failed",
+				"start", w.Start.Format("2006-01-02"), "end", w.End.Format("2006-01-02"), "err", err)
+			result.Failed++
+			result.Errors = append(result.Errors, fmt.Errorf("window %s..%s: %w",
+				w.Start.Format("2006-01-02"), w.End.Format("2006-01-02"), err))
+			continue
+		}
+		result.Generated++
+	}
+	return result, nil
+}
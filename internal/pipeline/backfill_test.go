@@ -0,0 +1,181 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func TestBackfillWindows(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		interval string
+		want     [][2]string
+	}{
+		{
+			name: "weekly", from: day(10, 5), to: day(10, 18), interval: "1w",
+			want: [][2]string{{"2026-10-05", "2026-10-11"}, {"2026-10-12", "2026-10-18"}},
+		},
+		{
+			name: "last window cut short", from: day(10, 5), to: day(10, 14), interval: "1w",
+			want: [][2]string{{"2026-10-05", "2026-10-11"}, {"2026-10-12", "2026-10-14"}},
+		},
+		{
+			name: "calendar months", from: day(1, 1), to: day(3, 31), interval: "1m",
+			want: [][2]string{{"2026-01-01", "2026-01-31"}, {"2026-02-01", "2026-02-28"}, {"2026-03-01", "2026-03-31"}},
+		},
+		{
+			name: "single day", from: day(10, 5), to: day(10, 5), interval: "1d",
+			want: [][2]string{{"2026-10-05", "2026-10-05"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			windows, err := BackfillWindows(tt.from, tt.to, tt.interval)
+			if err != nil {
+				t.Fatalf("BackfillWindows error: %v", err)
+			}
+			if len(windows) != len(tt.want) {
+				t.Fatalf("got %d windows, want %d", len(windows), len(tt.want))
+			}
+			for i, w := range windows {
+				got := [2]string{w.Start.Format("2006-01-02"), w.End.Format("2006-01-02")}
+				if got != tt.want[i] {
+					t.Errorf("window %d = %v, want %v", i, got, tt.want[i])
+				}
+				if !w.End.Equal(config.EndOfDay(w.End)) {
+					t.Errorf("window %d ends at %v, want end of day", i, w.End)
+				}
+			}
+		})
+	}
+
+	for _, interval := range []string{"12h", "36h", "abc"} {
+		if _, err := BackfillWindows(day(10, 5), day(10, 18), interval); err == nil {
+			t.Errorf("BackfillWindows with interval %q should fail", interval)
+		}
+	}
+	if _, err := BackfillWindows(day(10, 18), day(10, 5), "1w"); err == nil {
+		t.Error("BackfillWindows with from after to should fail")
+	}
+}
+
+func TestBackfill_SkipsExistingDigests(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DBPath = filepath.Join(t.TempDir(), "test.db")
+	cfg.OutputDir = t.TempDir()
+	cfg.LLM.AnthropicKey = "test-key"
+	cfg.SkipSlack = true
+	cfg.Offline = true
+
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer p.Close()
+
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+
+	windows, err := BackfillWindows(
+		time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), "1w")
+	if err != nil {
+		t.Fatalf("BackfillWindows: %v", err)
+	}
+
+	// Pretend the first week was generated by an interrupted earlier run.
+	if err := p.store.InsertReport(&store.Report{
+		ReportType:     digestReportType,
+		SIGSet:         sigSetKey(cfg.SIGs),
+		DateRangeStart: windows[0].Start,
+		DateRangeEnd:   windows[0].End,
+	}); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+
+	result, err := p.Backfill(context.Background(), windows)
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if result.Skipped != 1 || result.Generated != 1 || result.Failed != 0 {
+		t.Errorf("first backfill = %+v, want 1 skipped, 1 generated", result)
+	}
+	if _, err := p.store.FindReport(digestReportType, sigSetKey(cfg.SIGs), windows[1].Start, windows[1].End); err != nil {
+		t.Errorf("second window digest not recorded: %v", err)
+	}
+
+	result, err = p.Backfill(context.Background(), windows)
+	if err != nil {
+		t.Fatalf("Backfill rerun: %v", err)
+	}
+	if result.Skipped != 2 || result.Generated != 0 {
+		t.Errorf("rerun = %+v, want everything skipped", result)
+	}
+}
+
+func TestBackfill_RecordsRun(t *testing.T) {
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	p.cfg.Offline = true
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+
+	windows, err := BackfillWindows(
+		time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), "1w")
+	if err != nil {
+		t.Fatalf("BackfillWindows: %v", err)
+	}
+	run, err := p.BeginRangeRun("backfill", windows[0].Start, windows[1].End)
+	if err != nil {
+		t.Fatalf("BeginRangeRun: %v", err)
+	}
+	result, err := p.Backfill(context.Background(), windows)
+	p.EndRun(err)
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if result.Generated != 2 {
+		t.Errorf("backfill = %+v, want 2 generated", result)
+	}
+
+	got, err := p.store.GetRun(run.ID)
+	if err != nil {
+		t.Fatalf("GetRun: %v", err)
+	}
+	if got.Command != "backfill" || got.Status != store.RunCompleted ||
+		!got.DateRangeStart.Equal(windows[0].Start) || !got.DateRangeEnd.Truncate(time.Second).Equal(windows[1].End.Truncate(time.Second)) {
+		t.Errorf("run = %+v, want a completed backfill over both windows", got)
+	}
+
+	// Each window is analyzed on its own, not served the first window's
+	// result from the ledger.
+	steps, err := p.store.GetRunSteps(run.ID)
+	if err != nil {
+		t.Fatalf("GetRunSteps: %v", err)
+	}
+	var analyzed bool
+	for _, st := range steps {
+		if st.Stage != stageAnalyze {
+			continue
+		}
+		analyzed = true
+		if !strings.Contains(st.Result, windows[1].Start.Format("2006-01-02")) {
+			t.Errorf("analysis step result = %s, want the second window", st.Result)
+		}
+	}
+	if !analyzed {
+		t.Error("no analysis step recorded")
+	}
+}
@@ -0,0 +1,118 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// batchCostFactor is the share of the usual price a batched call costs.
+const batchCostFactor = 0.5
+
+// summaryStages are the analysis stages that summarize one source each.
+var summaryStages = []string{"notes", "video", "slack"}
+
+// newBatchModels returns the model and request settings of each summary
+// stage that runs on Anthropic, the provider batch mode supports.
+func newBatchModels(cfg config.LLMConfig) map[string]analysis.BatchModel {
+	models := make(map[string]analysis.BatchModel)
+	for _, stage := range summaryStages {
+		sc := cfg.Stage(stage)
+		if sc.Provider == "anthropic" {
+			models[stage] = analysis.BatchModel{Model: sc.Model, MaxTokens: sc.MaxTokens, Temperature: sc.Temperature}
+		}
+	}
+	return models
+}
+
+// batched reports whether the calls of stage go through a message batch,
+// and are billed at batchCostFactor.
+func (p *Pipeline) batched(stage string) bool {
+	_, ok := p.batchModels[stage]
+	return ok && p.batch != nil
+}
+
+// batchSummaries sends the uncached per-source summaries of sigs to the
+// batch client as one batch, leaving them in the analysis cache for the
+// per-SIG analysis. Summaries the batch fails to produce, or all of them if
+// the batch itself fails, are then made as individual calls.
+func (p *Pipeline) batchSummaries(ctx context.Context, sigs []*store.SIG, start, end time.Time) {
+	log := p.logger.With(logging.KeyStage, stageAnalyze)
+	batch := p.summarizer.NewBatch(p.batchModels)
+	for _, sig := range sigs {
+		if p.completedAnalysis(sig.ID) != nil {
+			continue
+		}
+		if err := p.addToBatch(batch, sig, start, end); err != nil {
+			log.Warn("failed to add summaries to batch", logging.KeySIG, sig.ID, "err", err)
+		}
+	}
+	if batch.Len() == 0 {
+		return
+	}
+
+	log.Info("summarizing sources in a message batch", "requests", batch.Len())
+	results, err := batch.Run(ctx, p.batch)
+	if err != nil {
+		log.Warn("message batch failed, summarizing sources individually", "err", err)
+		return
+	}
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			log.Debug("batched summary failed", logging.KeySIG, r.SIGID, logging.KeySource, r.SourceType, "err", r.Err)
+			continue
+		}
+		sc := p.cfg.LLM.Stage(r.SourceType)
+		p.usage.add(r.SourceType, sc.Provider, sc.Model, r.Response)
+		p.emit(Event{
+			Type:    EventLLMUsage,
+			Phase:   stageAnalyze,
+			SIGID:   r.SIGID,
+			Tokens:  r.Response.TokensUsed,
+			CostUSD: responseCost(r.Response),
+		})
+	}
+	if failed > 0 {
+		log.Warn("some batched summaries failed and will be made individually", "failed", failed, "requests", len(results))
+	}
+}
+
+// addToBatch adds the summaries analyzeSIG would make for sig to batch.
+func (p *Pipeline) addToBatch(batch *analysis.SummaryBatch, sig *store.SIG, start, end time.Time) error {
+	notes, err := p.store.GetMeetingNotes(sig.ID, start, end)
+	if err != nil {
+		return fmt.Errorf("getting meeting notes: %w", err)
+	}
+	if len(notes) > 0 {
+		if err := batch.AddMeetingNotes(sig.ID, sig.Name, notes, start, end); err != nil {
+			return err
+		}
+	}
+	transcripts, err := p.store.GetVideoTranscripts(sig.ID, start, end)
+	if err != nil {
+		return fmt.Errorf("getting video transcripts: %w", err)
+	}
+	if len(transcripts) > 0 && !slices.Contains(p.budgetSkipped, "video") {
+		if err := batch.AddVideoTranscripts(sig.ID, sig.Name, transcripts, start, end); err != nil {
+			return err
+		}
+	}
+	messages, err := p.store.GetSlackMessages(sig.ID, start, end)
+	if err != nil {
+		return fmt.Errorf("getting slack messages: %w", err)
+	}
+	if len(messages) > 0 {
+		if err := batch.AddSlackMessages(sig.ID, sig.Name, messages, start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
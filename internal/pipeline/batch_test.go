@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func TestNewBatchModels(t *testing.T) {
+	cfg := config.DefaultConfig().LLM
+	cfg.Stages = map[string]config.StageLLMConfig{
+		"notes": {Model: "claude-3-5-haiku-20241022", MaxTokens: 1024},
+		"slack": {Provider: "openai", Model: "gpt-4o-mini"},
+	}
+	got := newBatchModels(cfg)
+	want := map[string]analysis.BatchModel{
+		"notes": {Model: "claude-3-5-haiku-20241022", MaxTokens: 1024},
+		"video": {Model: cfg.Model},
+	}
+	if len(got) != len(want) || got["notes"] != want["notes"] || got["video"] != want["video"] {
+		t.Errorf("batch models = %+v, want %+v", got, want)
+	}
+}
+
+// recordingBatch answers every request of a batch.
+type recordingBatch struct{ reqs []analysis.BatchRequest }
+
+func (b *recordingBatch) CompleteBatch(ctx context.Context, reqs []analysis.BatchRequest) ([]analysis.BatchResult, error) {
+	b.reqs = append(b.reqs, reqs...)
+	results := make([]analysis.BatchResult, len(reqs))
+	for i, r := range reqs {
+		results[i].Response = &analysis.CompletionResponse{Content: "Batched summary.", Provider: "anthropic", Model: r.Model, TokensUsed: 100, Batch: true}
+	}
+	return results, nil
+}
+
+func TestPipeline_BatchSummaries(t *testing.T) {
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	p.cfg.Offline = true
+	p.cfg.SIGs = []string{"collector"}
+	batch := &recordingBatch{}
+	p.batch = batch
+	// Individual summary calls would fail; only the batch can answer them.
+	for stage, c := range p.llm {
+		next := analysis.LLMClient(stubLLM{tokens: 100})
+		if slices.Contains(summaryStages, stage) {
+			next = unavailableLLM{}
+		}
+		c.(*usageClient).next = next
+	}
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+	if err := p.store.UpsertMeetingNote(&store.MeetingNote{
+		SIGID:       "collector",
+		DocID:       "doc",
+		MeetingDate: time.Now().Add(-24 * time.Hour),
+		RawText:     "Discussed the batch processor.",
+	}); err != nil {
+		t.Fatalf("UpsertMeetingNote: %v", err)
+	}
+
+	var usage []Event
+	p.Subscribe(ObserverFunc(func(e Event) {
+		if e.Type == EventLLMUsage {
+			usage = append(usage, e)
+		}
+	}))
+
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	if len(batch.reqs) != 1 || batch.reqs[0].Model != p.cfg.LLM.Model {
+		t.Fatalf("batch requests = %+v, want the notes summary", batch.reqs)
+	}
+	rec, err := p.store.LatestReport(digestReportType, "collector")
+	if err != nil {
+		t.Fatalf("LatestReport: %v", err)
+	}
+	digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+	if err != nil {
+		t.Fatalf("UnmarshalDigest: %v", err)
+	}
+	if sr := digest.SIGReports[0]; !slices.Equal(sr.SourcesUsed, []string{"notes"}) {
+		t.Errorf("SourcesUsed = %v, want the batched notes", sr.SourcesUsed)
+	}
+	// Batched calls are counted at the batch discount.
+	want := analysis.StageUsage{Stage: "notes", Provider: "anthropic", Model: p.cfg.LLM.Model, Calls: 1, TokensUsed: 100, EstimatedCostUSD: estimateCost(100) * batchCostFactor}
+	if got := digest.Stats.Stages; len(got) == 0 || got[0] != want {
+		t.Errorf("stage usage = %+v, want %+v first", got, want)
+	}
+	// The live usage events include the batched call.
+	if len(usage) == 0 || usage[0].SIGID != "collector" || usage[0].Tokens != 100 || usage[0].CostUSD != want.EstimatedCostUSD {
+		t.Errorf("usage events = %+v, want the batched notes summary first", usage)
+	}
+
+	// A second run finds the summary cached and submits no batch.
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	if len(batch.reqs) != 1 {
+		t.Errorf("batch requests = %d after a cached run, want still 1", len(batch.reqs))
+	}
+}
@@ -0,0 +1,317 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// estimatedOutputTokens is the assumed response size of an LLM call that is
+// not answered from the analysis cache.
+const estimatedOutputTokens = 1000
+
+// placeholderResult stands in for the result of an uncached call when
+// estimating the stages that consume it.
+var placeholderResult = strings.Repeat("x", 4*estimatedOutputTokens)
+
+// modelPrices are approximate list prices, in US dollars per million input
+// and output tokens, by model name prefix; longer prefixes come first.
+// Other models are priced at the flat costPerMillionTokens.
+var modelPrices = []struct {
+	prefix        string
+	input, output float64
+}{
+	{"claude-opus-4-5", 5, 25},
+	{"claude-opus-4", 15, 75},
+	{"claude-sonnet-4", 3, 15},
+	{"claude-3-7-sonnet", 3, 15},
+	{"claude-3-5-sonnet", 3, 15},
+	{"claude-haiku-4-5", 1, 5},
+	{"claude-3-5-haiku", 0.8, 4},
+	{"claude-3-haiku", 0.25, 1.25},
+	{"gpt-4o-mini", 0.15, 0.6},
+	{"gpt-4o", 2.5, 10},
+	{"gpt-4.1-mini", 0.4, 1.6},
+	{"gpt-4.1", 2, 8},
+}
+
+// estimateModelCost returns the estimated cost in USD of a model's input
+// and output tokens.
+func estimateModelCost(model string, input, output int) float64 {
+	for _, mp := range modelPrices {
+		if strings.HasPrefix(model, mp.prefix) {
+			return (float64(input)*mp.input + float64(output)*mp.output) / 1_000_000
+		}
+	}
+	return estimateCost(input + output)
+}
+
+// ErrBudgetExceeded is returned when the projected LLM usage of an analysis
+// run exceeds the configured budget even after degrading it.
+var ErrBudgetExceeded = errors.New("projected LLM usage exceeds the budget")
+
+// Estimate is the projected LLM usage of analyzing a window, worked out
+// from the store and the analysis cache without calling the LLM.
+type Estimate struct {
+	Start time.Time
+	End   time.Time
+	// Stages holds the projection of each analysis stage with calls, in
+	// stage order.
+	Stages []StageEstimate
+	// Skipped lists the sources the budget guard leaves unsummarized; the
+	// stages are projected without them. OverBudget is set when the run
+	// would still exceed the budget, and be aborted.
+	Skipped    []string
+	OverBudget bool
+}
+
+// StageEstimate is the projected LLM usage of one analysis stage.
+type StageEstimate struct {
+	Stage    string
+	Provider string
+	Model    string
+	// Calls counts the stage's requests, CacheHits those of them answered
+	// from the analysis cache at no cost.
+	Calls     int
+	CacheHits int
+	// InputTokens and OutputTokens approximate the requests and responses
+	// of the calls that are not cache hits. EstimatedCostUSD prices them at
+	// the model's list prices, where known.
+	InputTokens      int
+	OutputTokens     int
+	EstimatedCostUSD float64
+}
+
+// Tokens returns the projected input and output tokens of the stage.
+func (s StageEstimate) Tokens() int {
+	return s.InputTokens + s.OutputTokens
+}
+
+// Total sums the estimate over all stages.
+func (e *Estimate) Total() StageEstimate {
+	var t StageEstimate
+	for _, s := range e.Stages {
+		t.Calls += s.Calls
+		t.CacheHits += s.CacheHits
+		t.InputTokens += s.InputTokens
+		t.OutputTokens += s.OutputTokens
+		t.EstimatedCostUSD += s.EstimatedCostUSD
+	}
+	return t
+}
+
+// Estimate projects the LLM usage of analyzing the configured window with
+// the data already in the store, applying the budget guard as a run would.
+func (p *Pipeline) Estimate(ctx context.Context) (*Estimate, error) {
+	start, end, prev, err := p.window()
+	if err != nil {
+		return nil, err
+	}
+	priorItems, err := p.loadPriorItems(prev)
+	if err != nil {
+		p.logger.Warn("failed to load items of previous digest", logging.KeyStage, stageAnalyze, "err", err)
+	}
+	if prev != nil {
+		p.loadProfilePriors()
+	}
+	sigs, err := p.analysisSIGs()
+	if err != nil {
+		return nil, err
+	}
+	return p.planBudget(ctx, sigs, start, end, priorItems)
+}
+
+// applyBudget checks the projected LLM usage of analyzing sigs against the
+// configured budget. Over budget, the window's video summaries are skipped;
+// if that is not enough, ErrBudgetExceeded is returned.
+func (p *Pipeline) applyBudget(ctx context.Context, sigs []*store.SIG, start, end time.Time, prior map[string][]analysis.PriorItem) error {
+	p.budgetSkipped = nil
+	if p.cfg.MaxCost <= 0 && p.cfg.MaxTokens <= 0 {
+		return nil
+	}
+	est, err := p.planBudget(ctx, sigs, start, end, prior)
+	if err != nil {
+		return fmt.Errorf("estimating LLM usage: %w", err)
+	}
+	total := est.Total()
+	if est.OverBudget {
+		return fmt.Errorf("%w: %d tokens (~$%.2f) even without %s summaries",
+			ErrBudgetExceeded, total.Tokens(), total.EstimatedCostUSD, strings.Join(est.Skipped, ", "))
+	}
+	if len(est.Skipped) > 0 {
+		p.logger.Warn("projected LLM usage exceeds the budget, skipping summaries",
+			logging.KeyStage, stageAnalyze, "sources", est.Skipped,
+			"projected_tokens", total.Tokens(), "projected_cost_usd", total.EstimatedCostUSD)
+		p.budgetSkipped = est.Skipped
+	}
+	return nil
+}
+
+// planBudget estimates analyzing sigs and, when that exceeds the budget,
+// estimates it again without video summaries, the largest and least
+// essential source.
+func (p *Pipeline) planBudget(ctx context.Context, sigs []*store.SIG, start, end time.Time, prior map[string][]analysis.PriorItem) (*Estimate, error) {
+	est, err := p.estimateWindow(ctx, sigs, start, end, prior, nil)
+	if err != nil || p.withinBudget(est) {
+		return est, err
+	}
+	skip := []string{"video"}
+	est, err = p.estimateWindow(ctx, sigs, start, end, prior, skip)
+	if err != nil {
+		return nil, err
+	}
+	est.Skipped = skip
+	est.OverBudget = !p.withinBudget(est)
+	return est, nil
+}
+
+// withinBudget reports whether est fits the configured MaxCost and
+// MaxTokens.
+func (p *Pipeline) withinBudget(est *Estimate) bool {
+	total := est.Total()
+	if p.cfg.MaxCost > 0 && total.EstimatedCostUSD > p.cfg.MaxCost {
+		return false
+	}
+	return p.cfg.MaxTokens <= 0 || total.Tokens() <= p.cfg.MaxTokens
+}
+
+// estimateWindow projects the LLM usage of analyzing sigs over start..end
+// without summarizing the sources in skip. SIGs already analyzed in this
+// run are left out, as a resumed run skips them.
+func (p *Pipeline) estimateWindow(ctx context.Context, sigs []*store.SIG, start, end time.Time, prior map[string][]analysis.PriorItem, skip []string) (*Estimate, error) {
+	stages := make(map[string]*StageEstimate)
+	add := func(stage string, ce *analysis.CallEstimate) {
+		se, ok := stages[stage]
+		if !ok {
+			sc := p.cfg.LLM.Stage(stage)
+			se = &StageEstimate{Stage: stage, Provider: sc.Provider, Model: sc.Model}
+			stages[stage] = se
+		}
+		se.Calls++
+		if ce.Cached {
+			se.CacheHits++
+			return
+		}
+		se.InputTokens += ce.InputTokens
+		se.OutputTokens += estimatedOutputTokens
+		se.EstimatedCostUSD = estimateModelCost(se.Model, se.InputTokens, se.OutputTokens)
+		if p.batched(stage) {
+			se.EstimatedCostUSD *= batchCostFactor
+		}
+	}
+
+	for _, sig := range sigs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if p.completedAnalysis(sig.ID) != nil {
+			continue
+		}
+		if err := p.estimateSIG(sig, start, end, prior[sig.ID], skip, add); err != nil {
+			return nil, fmt.Errorf("SIG %s: %w", sig.ID, err)
+		}
+	}
+
+	est := &Estimate{Start: start, End: end}
+	for _, stage := range config.LLMStages {
+		if se, ok := stages[stage]; ok {
+			est.Stages = append(est.Stages, *se)
+		}
+	}
+	return est, nil
+}
+
+// estimateSIG passes the estimate of each LLM call analyzeSIG would make
+// for sig to add. Calls downstream of an uncached one are estimated with a
+// placeholder of the expected size in place of its result.
+func (p *Pipeline) estimateSIG(sig *store.SIG, start, end time.Time, prior []analysis.PriorItem, skip []string, add func(string, *analysis.CallEstimate)) error {
+	var summaries []*analysis.SourceSummary
+	summarize := func(sourceType string, estimate func() (*analysis.CallEstimate, error)) error {
+		ce, err := estimate()
+		if err != nil {
+			return err
+		}
+		add(sourceType, ce)
+		summaries = append(summaries, &analysis.SourceSummary{
+			SIGID:      sig.ID,
+			SourceType: sourceType,
+			Summary:    estimateResult(ce),
+		})
+		return nil
+	}
+
+	notes, err := p.store.GetMeetingNotes(sig.ID, start, end)
+	if err != nil {
+		return fmt.Errorf("getting meeting notes: %w", err)
+	}
+	if len(notes) > 0 && !slices.Contains(skip, "notes") {
+		if err := summarize("notes", func() (*analysis.CallEstimate, error) {
+			return p.summarizer.EstimateMeetingNotes(sig.ID, sig.Name, notes, start, end)
+		}); err != nil {
+			return err
+		}
+	}
+	transcripts, err := p.store.GetVideoTranscripts(sig.ID, start, end)
+	if err != nil {
+		return fmt.Errorf("getting video transcripts: %w", err)
+	}
+	if len(transcripts) > 0 && !slices.Contains(skip, "video") {
+		if err := summarize("video", func() (*analysis.CallEstimate, error) {
+			return p.summarizer.EstimateVideoTranscripts(sig.ID, sig.Name, transcripts, start, end)
+		}); err != nil {
+			return err
+		}
+	}
+	messages, err := p.store.GetSlackMessages(sig.ID, start, end)
+	if err != nil {
+		return fmt.Errorf("getting slack messages: %w", err)
+	}
+	if len(messages) > 0 && !slices.Contains(skip, "slack") {
+		if err := summarize("slack", func() (*analysis.CallEstimate, error) {
+			return p.summarizer.EstimateSlackMessages(sig.ID, sig.Name, messages, start, end)
+		}); err != nil {
+			return err
+		}
+	}
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	ce, err := p.synthesizer.Estimate(sig.ID, sig.Name, summaries, start, end)
+	if err != nil {
+		return err
+	}
+	add("synthesis", ce)
+	synthesis := &analysis.SynthesizedReport{SIGID: sig.ID, SIGName: sig.Name, Synthesis: estimateResult(ce)}
+
+	ce, err = p.scorer.Estimate(sig.ID, sig.Name, synthesis, start, end, prior)
+	if err != nil {
+		return err
+	}
+	add("relevance", ce)
+	for _, pr := range p.profiles {
+		ce, err := pr.scorer.Estimate(sig.ID, sig.Name, synthesis, start, end, pr.prior[sig.ID])
+		if err != nil {
+			return err
+		}
+		add("relevance", ce)
+	}
+	return nil
+}
+
+// estimateResult returns the cached result of ce, or a placeholder of the
+// expected size when the call would be made.
+func estimateResult(ce *analysis.CallEstimate) string {
+	if ce.Cached {
+		return ce.Result
+	}
+	return placeholderResult
+}
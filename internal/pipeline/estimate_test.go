@@ -0,0 +1,192 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// newEstimateTestPipeline returns a pipeline over a collector SIG with short
+// meeting notes and a long video transcript, answering every stage locally.
+func newEstimateTestPipeline(t *testing.T) *Pipeline {
+	t.Helper()
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	p.cfg.Offline = true
+	p.cfg.SIGs = []string{"collector"}
+	for _, c := range p.llm {
+		c.(*usageClient).next = stubLLM{tokens: 100}
+	}
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+	day := time.Now().Add(-24 * time.Hour)
+	if err := p.store.UpsertMeetingNote(&store.MeetingNote{
+		SIGID:       "collector",
+		DocID:       "doc",
+		MeetingDate: day,
+		RawText:     "Discussed the batch processor.",
+	}); err != nil {
+		t.Fatalf("UpsertMeetingNote: %v", err)
+	}
+	if err := p.store.UpsertVideoTranscript(&store.VideoTranscript{
+		SIGID:         "collector",
+		ZoomURL:       "https://zoom.us/rec/1",
+		RecordingDate: day,
+		Transcript:    strings.Repeat("We talked about the batch processor at length. ", 4000),
+	}); err != nil {
+		t.Fatalf("UpsertVideoTranscript: %v", err)
+	}
+	return p
+}
+
+func TestPipeline_Estimate(t *testing.T) {
+	p := newEstimateTestPipeline(t)
+
+	est, err := p.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	var stages []string
+	var cost float64
+	for _, s := range est.Stages {
+		stages = append(stages, s.Stage)
+		cost += s.EstimatedCostUSD
+		if s.Calls != 1 || s.CacheHits != 0 || s.InputTokens == 0 || s.OutputTokens != estimatedOutputTokens {
+			t.Errorf("%s estimate = %+v, want one uncached call", s.Stage, s)
+		}
+		if s.Provider != "anthropic" || s.Model != p.cfg.LLM.Model {
+			t.Errorf("%s model = %s/%s, want the configured one", s.Stage, s.Provider, s.Model)
+		}
+	}
+	if want := []string{"notes", "video", "synthesis", "relevance"}; !slices.Equal(stages, want) {
+		t.Errorf("stages = %v, want %v", stages, want)
+	}
+	total := est.Total()
+	if total.EstimatedCostUSD != cost || len(est.Skipped) != 0 || est.OverBudget {
+		t.Errorf("total = %+v (skipped %v), want the cost of its stages and no budget action", total, est.Skipped)
+	}
+	if got, _ := p.usage.snapshot(); len(got) != 0 {
+		t.Errorf("estimating made LLM calls: %+v", got)
+	}
+
+	// After a run, every call is answered from the analysis cache.
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	est, err = p.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	total = est.Total()
+	if total.Calls != 4 || total.CacheHits != 4 || total.Tokens() != 0 || total.EstimatedCostUSD != 0 {
+		t.Errorf("total after a run = %+v, want 4 cache hits at no cost", total)
+	}
+}
+
+func TestEstimateModelCost(t *testing.T) {
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"claude-sonnet-4-20250514", 3 + 15},
+		{"claude-3-5-haiku-20241022", 0.8 + 4},
+		{"gpt-4o-mini", 0.15 + 0.6},
+		{"gpt-4o", 2.5 + 10},
+		{"some-local-model", 2 * costPerMillionTokens},
+	}
+	for _, tt := range tests {
+		if got := estimateModelCost(tt.model, 1_000_000, 1_000_000); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("estimateModelCost(%s) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestPipeline_EstimateBatchDiscount(t *testing.T) {
+	p := newEstimateTestPipeline(t)
+	p.batch = &recordingBatch{}
+
+	est, err := p.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	var full float64
+	for _, s := range est.Stages {
+		want := estimateModelCost(s.Model, s.InputTokens, s.OutputTokens)
+		full += want
+		if slices.Contains(summaryStages, s.Stage) {
+			want *= batchCostFactor
+		}
+		if s.EstimatedCostUSD != want {
+			t.Errorf("%s cost = %v, want %v", s.Stage, s.EstimatedCostUSD, want)
+		}
+	}
+
+	// A budget the discounted run fits keeps the video summary.
+	p.cfg.MaxCost = (est.Total().EstimatedCostUSD + full) / 2
+	est, err = p.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	if len(est.Skipped) != 0 || est.OverBudget {
+		t.Errorf("estimate skipped %v, over budget %v; want the batched run within budget", est.Skipped, est.OverBudget)
+	}
+}
+
+func TestPipeline_BudgetSkipsVideo(t *testing.T) {
+	p := newEstimateTestPipeline(t)
+	// The transcript alone is ~48k tokens; the rest of the analysis fits.
+	p.cfg.MaxTokens = 20000
+
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	stages, _ := p.usage.snapshot()
+	for _, su := range stages {
+		if su.Stage == "video" {
+			t.Errorf("video was summarized despite the budget: %+v", su)
+		}
+	}
+	rec, err := p.store.LatestReport(digestReportType, "collector")
+	if err != nil {
+		t.Fatalf("LatestReport: %v", err)
+	}
+	digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+	if err != nil {
+		t.Fatalf("UnmarshalDigest: %v", err)
+	}
+	if got := digest.Stats.BudgetSkipped; !slices.Equal(got, []string{"video"}) {
+		t.Errorf("BudgetSkipped = %v, want [video]", got)
+	}
+	if sr := digest.SIGReports[0]; !slices.Contains(sr.SourcesMissing, "video") {
+		t.Errorf("SourcesMissing = %v, want video", sr.SourcesMissing)
+	}
+}
+
+func TestPipeline_BudgetExceeded(t *testing.T) {
+	p := newEstimateTestPipeline(t)
+	p.cfg.MaxCost = 0.001
+
+	err := p.AnalyzeOnly(context.Background())
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("AnalyzeOnly error = %v, want ErrBudgetExceeded", err)
+	}
+	if got, _ := p.usage.snapshot(); len(got) != 0 {
+		t.Errorf("an aborted run made LLM calls: %+v", got)
+	}
+
+	est, err := p.Estimate(context.Background())
+	if err != nil {
+		t.Fatalf("Estimate: %v", err)
+	}
+	if !est.OverBudget || !slices.Equal(est.Skipped, []string{"video"}) {
+		t.Errorf("estimate skipped %v, over budget %v; want video skipped and still over", est.Skipped, est.OverBudget)
+	}
+}
@@ -0,0 +1,198 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+// EventType identifies what an Event reports.
+type EventType string
+
+const (
+	// EventPhaseStarted reports that the fetch or analyze phase began
+	// working through Total SIGs.
+	EventPhaseStarted EventType = "phase_started"
+	// EventPhaseFinished reports that a phase ended, with Err set if it
+	// failed or was cancelled.
+	EventPhaseFinished EventType = "phase_finished"
+	// EventSIGState reports that a SIG entered State.
+	EventSIGState EventType = "sig_state"
+	// EventLLMUsage reports the tokens and estimated cost of one LLM call.
+	// Calls answered from the analysis cache are not reported.
+	EventLLMUsage EventType = "llm_usage"
+	// EventHighItem reports a HIGH relevance Item found for a SIG. Items a
+	// digest of an overlapping window already listed, and in
+	// since-last-report mode ongoing items, are not reported again.
+	EventHighItem EventType = "high_item"
+	// EventSlackAuthFailed reports that Slack rejected the stored
+	// credentials. It is sent at most once per pipeline.
+	EventSlackAuthFailed EventType = "slack_auth_failed"
+	// EventRunFinished reports the outcome of the run begun with BeginRun or
+	// ResumeRun: State is its ledger status and Err the run's error.
+	EventRunFinished EventType = "run_finished"
+)
+
+// SIG states carried by EventSIGState. Done, Failed and Skipped are final
+// for the phase.
+const (
+	StateFetchingNotes = "fetching notes"
+	StateFetchingVideo = "fetching video"
+	StateFetchingSlack = "fetching slack"
+	StateSummarizing   = "summarizing"
+	StateSynthesizing  = "synthesizing"
+	StateScoring       = "scoring"
+	StateDone          = "done"
+	StateFailed        = "failed"
+	// StateSkipped marks a SIG whose phase already completed earlier in a
+	// resumed run.
+	StateSkipped = "skipped"
+)
+
+// Event is a progress notification from a running pipeline.
+type Event struct {
+	Type EventType
+	Time time.Time
+	// Phase is "fetch" or "analyze".
+	Phase string
+	SIGID string
+	State string
+	// RunID is set on EventRunFinished.
+	RunID int64
+	// Item is the text of the item reported by EventHighItem.
+	Item string
+	// Total is the number of SIGs in the phase, set on EventPhaseStarted.
+	Total int
+	// Tokens and CostUSD are set on EventLLMUsage.
+	Tokens  int
+	CostUSD float64
+	Err     error
+}
+
+// Observer receives pipeline events. OnEvent is called synchronously from
+// the pipeline's workers, possibly concurrently, so it must be quick and
+// safe for concurrent use.
+type Observer interface {
+	OnEvent(Event)
+}
+
+// ObserverFunc adapts a function to the Observer interface.
+type ObserverFunc func(Event)
+
+// OnEvent calls f(e).
+func (f ObserverFunc) OnEvent(e Event) { f(e) }
+
+// costPerMillionTokens is the blended price used for cost estimates.
+const costPerMillionTokens = 3.0 // default Sonnet pricing
+
+// estimateCost returns the estimated cost in USD of tokens LLM tokens.
+func estimateCost(tokens int) float64 {
+	return float64(tokens) / 1_000_000 * costPerMillionTokens
+}
+
+// responseCost returns the estimated cost in USD of the call answered with
+// resp, at the batch discount if it was batched.
+func responseCost(resp *analysis.CompletionResponse) float64 {
+	cost := estimateCost(resp.TokensUsed)
+	if resp.Batch {
+		cost *= batchCostFactor
+	}
+	return cost
+}
+
+// observers is the set of subscribers of a pipeline.
+type observers struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]Observer
+}
+
+// Subscribe registers o to receive the pipeline's events and returns a
+// function that unregisters it.
+func (p *Pipeline) Subscribe(o Observer) (unsubscribe func()) {
+	p.observers.mu.Lock()
+	defer p.observers.mu.Unlock()
+	if p.observers.subs == nil {
+		p.observers.subs = make(map[int]Observer)
+	}
+	id := p.observers.nextID
+	p.observers.nextID++
+	p.observers.subs[id] = o
+	return func() {
+		p.observers.mu.Lock()
+		defer p.observers.mu.Unlock()
+		delete(p.observers.subs, id)
+	}
+}
+
+// emit delivers e to every subscriber.
+func (p *Pipeline) emit(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	p.observers.mu.Lock()
+	subs := make([]Observer, 0, len(p.observers.subs))
+	for _, o := range p.observers.subs {
+		subs = append(subs, o)
+	}
+	p.observers.mu.Unlock()
+	for _, o := range subs {
+		o.OnEvent(e)
+	}
+}
+
+// emitState reports that a SIG entered state during phase.
+func (p *Pipeline) emitState(phase, sigID, state string, err error) {
+	p.emit(Event{Type: EventSIGState, Phase: phase, SIGID: sigID, State: state, Err: err})
+}
+
+// finalState returns the state a SIG ends its phase in after err.
+func finalState(err error) string {
+	if err != nil {
+		return StateFailed
+	}
+	return StateDone
+}
+
+type sigContextKey struct{}
+
+// withSIG marks ctx as doing work for sigID, so LLM usage can be attributed.
+func withSIG(ctx context.Context, sigID string) context.Context {
+	return context.WithValue(ctx, sigContextKey{}, sigID)
+}
+
+// usageClient reports the token usage of each LLM call as an event and,
+// if usage is set, records it against its analysis stage.
+type usageClient struct {
+	next analysis.LLMClient
+	emit func(Event)
+
+	stage, provider, model string
+	usage                  *stageUsage
+}
+
+// DescribeModel returns the provider and model configured for the stage.
+func (c *usageClient) DescribeModel() (provider, model string) {
+	return c.provider, c.model
+}
+
+// Complete calls the wrapped client and reports its usage.
+func (c *usageClient) Complete(ctx context.Context, req *analysis.CompletionRequest) (*analysis.CompletionResponse, error) {
+	resp, err := c.next.Complete(ctx, req)
+	if resp != nil && c.usage != nil {
+		c.usage.add(c.stage, c.provider, c.model, resp)
+	}
+	if resp != nil && resp.TokensUsed > 0 && c.emit != nil {
+		sigID, _ := ctx.Value(sigContextKey{}).(string)
+		c.emit(Event{
+			Type:    EventLLMUsage,
+			Phase:   stageAnalyze,
+			SIGID:   sigID,
+			Tokens:  resp.TokensUsed,
+			CostUSD: responseCost(resp),
+		})
+	}
+	return resp, err
+}
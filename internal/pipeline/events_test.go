@@ -0,0 +1,165 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+type stubLLM struct{ tokens int }
+
+func (s stubLLM) Complete(ctx context.Context, req *analysis.CompletionRequest) (*analysis.CompletionResponse, error) {
+	return &analysis.CompletionResponse{Content: "ok", TokensUsed: s.tokens}, nil
+}
+
+func TestPipeline_EmitsAnalyzeEvents(t *testing.T) {
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	p.cfg.SIGs = []string{"collector", "java-sdk"}
+	for _, sig := range []*store.SIG{
+		{ID: "collector", Name: "Collector", Category: "implementation"},
+		{ID: "java-sdk", Name: "Java SDK", Category: "implementation"},
+	} {
+		if err := p.store.UpsertSIG(sig); err != nil {
+			t.Fatalf("UpsertSIG: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var events []Event
+	unsubscribe := p.Subscribe(ObserverFunc(func(e Event) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	}))
+
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+
+	if len(events) == 0 || events[0].Type != EventPhaseStarted || events[0].Phase != stageAnalyze || events[0].Total != 2 {
+		t.Fatalf("first event = %+v, want analyze phase start over 2 SIGs", events)
+	}
+	if last := events[len(events)-1]; last.Type != EventPhaseFinished || last.Err != nil {
+		t.Errorf("last event = %+v, want a successful phase finish", last)
+	}
+	done := map[string]bool{}
+	for _, e := range events {
+		if e.Type == EventSIGState && e.State == StateDone {
+			done[e.SIGID] = true
+		}
+		if e.Time.IsZero() {
+			t.Errorf("event %+v has no time", e)
+		}
+	}
+	if !done["collector"] || !done["java-sdk"] {
+		t.Errorf("done SIGs = %v, want collector and java-sdk", done)
+	}
+
+	unsubscribe()
+	n := len(events)
+	p.emit(Event{Type: EventPhaseStarted})
+	if len(events) != n {
+		t.Error("unsubscribed observer still received events")
+	}
+}
+
+func TestUsageClient_ReportsTokensPerSIG(t *testing.T) {
+	var got []Event
+	c := &usageClient{next: stubLLM{tokens: 2_000_000}, emit: func(e Event) { got = append(got, e) }}
+
+	if _, err := c.Complete(withSIG(context.Background(), "collector"), &analysis.CompletionRequest{}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d events, want 1", len(got))
+	}
+	e := got[0]
+	if e.Type != EventLLMUsage || e.SIGID != "collector" || e.Tokens != 2_000_000 || e.CostUSD != 6.0 {
+		t.Errorf("event = %+v, want 2M tokens ($6) for collector", e)
+	}
+
+	c.next = stubLLM{}
+	if _, err := c.Complete(context.Background(), &analysis.CompletionRequest{}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(got) != 1 {
+		t.Error("calls without token usage should not be reported")
+	}
+}
+
+func TestPipeline_EmitsRunFinished(t *testing.T) {
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	var got []Event
+	p.Subscribe(ObserverFunc(func(e Event) {
+		if e.Type == EventRunFinished {
+			got = append(got, e)
+		}
+	}))
+
+	run, err := p.BeginRun("fetch")
+	if err != nil {
+		t.Fatalf("BeginRun: %v", err)
+	}
+	runErr := errors.New("registry unreachable")
+	p.EndRun(runErr)
+
+	if len(got) != 1 {
+		t.Fatalf("got %d run finished events, want 1", len(got))
+	}
+	if e := got[0]; e.RunID != run.ID || e.State != store.RunFailed || !errors.Is(e.Err, runErr) {
+		t.Errorf("event = %+v, want run %d failed with the run error", e, run.ID)
+	}
+}
+
+func TestNewHighItems_SkipsReportedTopics(t *testing.T) {
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	start := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 10, 11, 23, 59, 59, 0, time.UTC)
+
+	rr := &analysis.RelevanceReport{HighItems: []string{
+		"**OTLP Partial Success** — Now returned by the exporter.",
+		"**Profiling Signal** — Alpha release scheduled.",
+	}}
+	if got := p.newHighItems("collector", rr, start, end); len(got) != 2 {
+		t.Fatalf("first analysis announces %v, want both items", got)
+	}
+
+	// A first run over the week recorded its digest.
+	r := &store.Report{ReportType: digestReportType, DateRangeStart: start, DateRangeEnd: end, FilePath: "digest.md", ContentHash: "h"}
+	if err := p.store.InsertReport(r); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+	if err := p.store.InsertReportItems(r.ID, []*store.ReportItem{
+		{SIGID: "collector", Level: "high", Topic: "OTLP partial  success", Text: rr.HighItems[0]},
+		{SIGID: "java-sdk", Level: "high", Topic: "Profiling Signal", Text: "**Profiling Signal** — Java."},
+	}); err != nil {
+		t.Fatalf("InsertReportItems: %v", err)
+	}
+
+	// Re-running the week, or a daily run overlapping it, only announces
+	// the topic not yet listed for the SIG.
+	got := p.newHighItems("collector", rr, start.AddDate(0, 0, 3), end.AddDate(0, 0, 3))
+	if len(got) != 1 || got[0] != rr.HighItems[1] {
+		t.Errorf("re-run announces %v, want only the profiling item", got)
+	}
+	// The following week announces both again.
+	if got := p.newHighItems("collector", rr, start.AddDate(0, 0, 7), end.AddDate(0, 0, 7)); len(got) != 2 {
+		t.Errorf("next week announces %v, want both items", got)
+	}
+
+	// Updated items are announced, ongoing ones never.
+	rr.ItemStatuses = map[string]analysis.ItemStatus{
+		rr.HighItems[0]: analysis.ItemUpdated,
+		rr.HighItems[1]: analysis.ItemOngoing,
+	}
+	got = p.newHighItems("collector", rr, start, end)
+	if len(got) != 1 || got[0] != rr.HighItems[0] {
+		t.Errorf("with statuses announces %v, want only the updated item", got)
+	}
+}
@@ -2,22 +2,36 @@ package pipeline
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/browser"
 	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/feed"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
 	"github.com/gordyrad/otel-sig-tracker/internal/registry"
 	"github.com/gordyrad/otel-sig-tracker/internal/report"
 	"github.com/gordyrad/otel-sig-tracker/internal/sources"
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 )
 
+// digestReportType is the report_type recorded in the store for weekly digests.
+const digestReportType = "digest"
+
 // PartialError indicates that some sources failed but others succeeded.
 // The pipeline was still able to produce output from available data.
 type PartialError struct {
@@ -32,7 +46,7 @@ func (e *PartialError) Error() string {
 type Pipeline struct {
 	cfg           *config.Config
 	store         *store.Store
-	llm           analysis.LLMClient
+	llm           map[string]analysis.LLMClient // by analysis stage
 	registry      *registry.Fetcher
 	docsFetcher   *sources.GoogleDocsFetcher
 	sheetsFetcher *sources.GoogleSheetsFetcher
@@ -43,26 +57,76 @@ type Pipeline struct {
 	scorer        *analysis.RelevanceScorer
 	mdGenerator   *report.MarkdownGenerator
 	jsonGenerator *report.JSONGenerator
+	htmlGenerator *report.HTMLGenerator
+	prompts       *analysis.Prompts
+
+	// profiles are the configured relevance profiles, scored after the
+	// built-in Datadog one with their own digests.
+	profiles []*profileRun
+
+	// usage records the current window's LLM calls per analysis stage.
+	usage *stageUsage
+	// fallbacks are the stage clients with a fallback chain.
+	fallbacks []*analysis.FallbackClient
+	// budgetSkipped are the sources the current window leaves unsummarized
+	// to stay within the configured budget.
+	budgetSkipped []string
+	// batch, set in batch mode, summarizes the sources of a window before
+	// its per-SIG analysis, with the models in batchModels.
+	batch       analysis.BatchCompleter
+	batchModels map[string]analysis.BatchModel
+
+	// run is the ledger entry of the current run, set by BeginRun or
+	// ResumeRun. doneSteps holds its completed per-SIG stages.
+	run       *store.Run
+	runMu     sync.Mutex
+	doneSteps map[string]*store.RunStep
+
+	// lockOwner identifies this pipeline's hold on the run lock while a run
+	// is active; stopHeartbeat stops refreshing it.
+	lockOwner     string
+	stopHeartbeat func()
+
+	// registryMaxAge, when > 0, reuses the stored SIG registry if it was
+	// refreshed more recently than this.
+	registryMaxAge time.Duration
+
+	logger    *slog.Logger
+	observers observers
+	// slackAuthOnce limits EventSlackAuthFailed to one per pipeline.
+	slackAuthOnce sync.Once
 }
 
+// stageReport labels log records about writing the digest. Unlike the fetch
+// and analyze stages it is not recorded per SIG in the run ledger.
+const stageReport = "report"
+
 // New initializes all components and returns a ready-to-run Pipeline.
 func New(cfg *config.Config) (*Pipeline, error) {
+	logger := slog.Default()
+
 	// Open the SQLite store.
 	s, err := store.New(cfg.DBPath)
 	if err != nil {
 		return nil, fmt.Errorf("opening store: %w", err)
 	}
 
-	// Create LLM client based on config.
-	var llm analysis.LLMClient
-	switch cfg.LLM.Provider {
-	case "anthropic":
-		llm = analysis.NewAnthropicClient(cfg.LLM.AnthropicKey, cfg.LLM.Model)
-	case "openai":
-		llm = analysis.NewOpenAIClient(cfg.LLM.OpenAIKey, cfg.LLM.Model)
-	default:
+	// Create an LLM client per analysis stage based on config.
+	usage := &stageUsage{}
+	clients, fallbacks, err := newStageClients(cfg.LLM, usage)
+	if err != nil {
 		s.Close()
-		return nil, fmt.Errorf("unsupported LLM provider: %s", cfg.LLM.Provider)
+		return nil, err
+	}
+	llm := make(map[string]analysis.LLMClient, len(clients))
+	for stage, c := range clients {
+		llm[stage] = c
+	}
+
+	// Reject an unusable date range before any work is done.
+	if _, _, err := cfg.Window(time.Now()); err != nil {
+		s.Close()
+		return nil, err
 	}
 
 	// Load custom context for relevance scoring.
@@ -77,30 +141,32 @@ func New(cfg *config.Config) (*Pipeline, error) {
 	sheetsFetcher := sources.NewGoogleSheetsFetcher()
 	zoomFetcher := sources.NewZoomFetcher(s)
 
-	// Load Slack credentials and create Slack fetcher if available.
-	var slackFetcher *sources.SlackFetcher
-	if !cfg.SkipSlack {
-		creds, err := sources.LoadSlackCredentials(cfg.Slack.CredentialsFile)
-		if err != nil {
-			log.Printf("warning: could not load slack credentials: %v", err)
-		}
-		if creds != nil {
-			slackFetcher = sources.NewSlackFetcher(s, creds.Token, creds.Cookie)
-		} else {
-			log.Printf("warning: no slack credentials found, slack fetching will be skipped")
-		}
-	}
+	slackFetcher := newSlackFetcher(cfg, s, logger)
 
 	// Create analysis components.
-	summarizer := analysis.NewSummarizer(llm, s)
-	synthesizer := analysis.NewSynthesizer(llm, s)
-	scorer := analysis.NewRelevanceScorer(llm, s, customContext)
-
-	// Create report generators.
-	mdGenerator := report.NewMarkdownGenerator(cfg.OutputDir)
-	jsonGenerator := report.NewJSONGenerator(cfg.OutputDir)
+	prompts, err := analysis.LoadPrompts(cfg.PromptsDir)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("loading prompts: %w", err)
+	}
+	summarizer := analysis.NewSummarizer(llm["notes"], s)
+	summarizer.SetSourceClient("video", llm["video"])
+	summarizer.SetSourceClient("slack", llm["slack"])
+	summarizer.SetPrompts(prompts)
+	synthesizer := analysis.NewSynthesizer(llm["synthesis"], s)
+	synthesizer.SetPrompts(prompts)
+	scorer := analysis.NewRelevanceScorer(llm["relevance"], s, customContext)
+	scorer.SetPrompts(prompts)
+
+	// In batch mode, summaries on Anthropic go through the Message Batches
+	// API before the per-SIG analysis.
+	var batch *analysis.AnthropicBatchClient
+	batchModels := newBatchModels(cfg.LLM)
+	if cfg.LLM.Batch && len(batchModels) > 0 {
+		batch = analysis.NewAnthropicBatchClient(cfg.LLM.AnthropicKey)
+	}
 
-	return &Pipeline{
+	p := &Pipeline{
 		cfg:           cfg,
 		store:         s,
 		llm:           llm,
@@ -112,22 +178,112 @@ func New(cfg *config.Config) (*Pipeline, error) {
 		summarizer:    summarizer,
 		synthesizer:   synthesizer,
 		scorer:        scorer,
-		mdGenerator:   mdGenerator,
-		jsonGenerator: jsonGenerator,
-	}, nil
+		prompts:       prompts,
+		usage:         usage,
+		fallbacks:     fallbacks,
+		batchModels:   batchModels,
+		logger:        logger,
+	}
+	if batch != nil {
+		p.batch = batch
+	}
+	for _, c := range clients {
+		c.emit = p.emit
+	}
+	if err := p.setOutput(); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// newSlackFetcher creates the Slack fetcher from the configured credentials.
+// It returns nil when Slack is skipped or no credentials are found.
+func newSlackFetcher(cfg *config.Config, s *store.Store, logger *slog.Logger) *sources.SlackFetcher {
+	if cfg.SkipSlack {
+		return nil
+	}
+	creds, err := sources.LoadSlackCredentials(cfg.Slack.CredentialsFile)
+	if err != nil {
+		logger.Warn("could not load slack credentials", "err", err)
+	}
+	if creds == nil {
+		logger.Warn("no slack credentials found, slack fetching will be skipped")
+		return nil
+	}
+	return sources.NewSlackFetcher(s, creds.Token, creds.Cookie)
+}
+
+// setOutput creates the report generators and profile runs, writing to the
+// configured output directory with the configured template.
+func (p *Pipeline) setOutput() error {
+	mdGenerator := report.NewMarkdownGenerator(p.cfg.OutputDir)
+	var tmpl *template.Template
+	if p.cfg.Template != "" {
+		var err error
+		tmpl, err = report.ParseTemplate(p.cfg.Template)
+		if err != nil {
+			return fmt.Errorf("loading report template %s: %w", p.cfg.Template, err)
+		}
+		mdGenerator.SetTemplate(tmpl)
+	}
+	p.mdGenerator = mdGenerator
+	p.jsonGenerator = report.NewJSONGenerator(p.cfg.OutputDir)
+	p.htmlGenerator = report.NewHTMLGenerator(p.cfg.OutputDir)
+	p.profiles = newProfileRuns(p.cfg, p.llm["relevance"], p.store, p.prompts, tmpl)
+	return nil
+}
+
+// SetLogger replaces the logger used by the pipeline, its fetchers and its
+// analysis components.
+func (p *Pipeline) SetLogger(l *slog.Logger) {
+	p.logger = l
+	p.docsFetcher.SetLogger(l)
+	p.sheetsFetcher.SetLogger(l)
+	p.zoomFetcher.SetLogger(l)
+	if p.slackFetcher != nil {
+		p.slackFetcher.SetLogger(l)
+	}
+	p.summarizer.SetLogger(l)
+	p.synthesizer.SetLogger(l)
+	p.scorer.SetLogger(l)
+	for _, pr := range p.profiles {
+		pr.scorer.SetLogger(l)
+	}
+	if bc, ok := p.batch.(*analysis.AnthropicBatchClient); ok {
+		bc.SetLogger(l)
+	}
+	for _, fc := range p.fallbacks {
+		fc.SetLogger(l)
+	}
+}
+
+// SetBrowserPool makes the Zoom fetcher load pages through pool, such as a
+// long-running process's shared browser.
+func (p *Pipeline) SetBrowserPool(pool *browser.Pool) {
+	p.zoomFetcher.SetPool(pool)
+}
+
+// SetRegistryMaxAge skips refetching the SIG registry while the stored copy
+// is younger than d. Zero, the default, refetches on every fetch.
+func (p *Pipeline) SetRegistryMaxAge(d time.Duration) {
+	p.registryMaxAge = d
 }
 
 // Close releases all resources held by the pipeline.
 func (p *Pipeline) Close() error {
 	if p.store != nil {
+		p.releaseRunLock()
 		return p.store.Close()
 	}
 	return nil
 }
 
 // Run executes the full pipeline: fetch sources, analyze, and generate reports.
-func (p *Pipeline) Run(ctx context.Context) error {
-	log.Println("pipeline: starting full run")
+func (p *Pipeline) Run(ctx context.Context) (err error) {
+	ctx, span := telemetry.Start(ctx, "pipeline.run")
+	defer func() { telemetry.End(span, err) }()
+	p.logger.Info("starting full run")
 
 	if err := p.FetchOnly(ctx); err != nil {
 		return fmt.Errorf("fetch phase: %w", err)
@@ -137,34 +293,41 @@ func (p *Pipeline) Run(ctx context.Context) error {
 		return fmt.Errorf("analyze phase: %w", err)
 	}
 
-	log.Println("pipeline: run complete")
+	p.logger.Info("run complete")
 	return nil
 }
 
 // FetchOnly executes only the data-fetching phase of the pipeline.
 func (p *Pipeline) FetchOnly(ctx context.Context) error {
-	log.Println("pipeline: starting fetch phase")
+	p.logger.Info("starting fetch phase", logging.KeyStage, stageFetch)
+
+	start, end, _, err := p.window()
+	if err != nil {
+		return err
+	}
+	return p.FetchRange(ctx, start, end)
+}
+
+// FetchRange fetches all sources for the configured SIGs between start and
+// end, regardless of the configured window.
+func (p *Pipeline) FetchRange(ctx context.Context, start, end time.Time) (err error) {
+	ctx, span := telemetry.Start(ctx, "pipeline.fetch", telemetry.AttrStage.String(stageFetch))
+	defer func() { telemetry.End(span, err) }()
 
-	end := time.Now()
-	start := end.Add(-p.cfg.Lookback)
-	log.Printf("pipeline: date range %s to %s",
-		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	p.logger.Info("fetching date range", logging.KeyStage, stageFetch,
+		"start", start.Format("2006-01-02"), "end", end.Format("2006-01-02"))
 
 	// Step 1: Fetch and update the SIG registry.
-	sigs, err := p.registry.FetchAndParse()
+	sigs, err := p.loadRegistry()
 	if err != nil {
-		return fmt.Errorf("fetching SIG registry: %w", err)
+		return err
 	}
-	for _, sig := range sigs {
-		if err := p.store.UpsertSIG(sig); err != nil {
-			log.Printf("warning: failed to upsert SIG %s: %v", sig.ID, err)
-		}
-	}
-	log.Printf("pipeline: loaded %d SIGs from registry", len(sigs))
 
 	// Step 2: Filter SIGs based on config.
 	filteredSIGs := filterSIGs(sigs, p.cfg.SIGs)
-	log.Printf("pipeline: processing %d SIGs after filtering", len(filteredSIGs))
+	p.logger.Info("processing SIGs after filtering", logging.KeyStage, stageFetch, "sigs", len(filteredSIGs))
+	p.emit(Event{Type: EventPhaseStarted, Phase: stageFetch, Total: len(filteredSIGs)})
+	defer func() { p.emit(Event{Type: EventPhaseFinished, Phase: stageFetch, Err: err}) }()
 
 	// Step 3: Fetch recordings list (needed for video transcripts).
 	var recordings []*sources.Recording
@@ -172,9 +335,9 @@ func (p *Pipeline) FetchOnly(ctx context.Context) error {
 		sigIDs := sigIDList(filteredSIGs)
 		recordings, err = p.sheetsFetcher.FetchRecordings(ctx, start, end, sigIDs)
 		if err != nil {
-			log.Printf("warning: failed to fetch recordings list: %v", err)
+			p.logger.Warn("failed to fetch recordings list", logging.KeyStage, stageFetch, logging.KeySource, "recordings", "err", err)
 		} else {
-			log.Printf("pipeline: found %d recordings", len(recordings))
+			p.logger.Info("found recordings", logging.KeyStage, stageFetch, logging.KeySource, "recordings", "count", len(recordings))
 		}
 	}
 
@@ -185,47 +348,90 @@ func (p *Pipeline) FetchOnly(ctx context.Context) error {
 	for _, sig := range filteredSIGs {
 		sig := sig // capture loop variable
 		g.Go(func() error {
-			return p.fetchSIG(gctx, sig, start, end, recordings)
+			if p.completedStep(sig.ID, stageFetch) != nil {
+				p.logger.Info("sources already fetched in this run, skipping", logging.KeySIG, sig.ID, logging.KeyStage, stageFetch)
+				p.emitState(stageFetch, sig.ID, StateSkipped, nil)
+				return nil
+			}
+			sigCtx, span := telemetry.Start(gctx, "pipeline.fetch_sig", telemetry.AttrSIGID.String(sig.ID))
+			err := p.fetchSIG(sigCtx, sig, start, end, recordings)
+			telemetry.End(span, err)
+			p.recordStep(sig.ID, stageFetch, err, nil)
+			p.emitState(stageFetch, sig.ID, finalState(err), err)
+			return nil
 		})
 	}
 
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("fetching SIG sources: %w", err)
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	log.Println("pipeline: fetch phase complete")
+	p.logger.Info("fetch phase complete", logging.KeyStage, stageFetch)
 	return nil
 }
 
 // AnalyzeOnly executes only the analysis and report generation phase,
 // using data already cached in the store.
 func (p *Pipeline) AnalyzeOnly(ctx context.Context) error {
-	log.Println("pipeline: starting analysis phase")
-	execStart := time.Now()
+	p.logger.Info("starting analysis phase", logging.KeyStage, stageAnalyze)
+
+	start, end, prev, err := p.window()
+	if err != nil {
+		return err
+	}
+	return p.analyzeWindow(ctx, start, end, prev)
+}
 
-	end := time.Now()
-	start := end.Add(-p.cfg.Lookback)
+// AnalyzeRange runs analysis and writes a digest for an explicit window,
+// using data already in the store.
+func (p *Pipeline) AnalyzeRange(ctx context.Context, start, end time.Time) error {
+	p.logger.Info("starting analysis for date range", logging.KeyStage, stageAnalyze,
+		"start", start.Format("2006-01-02"), "end", end.Format("2006-01-02"))
+	return p.analyzeWindow(ctx, start, end, nil)
+}
+
+// analyzeWindow analyzes every configured SIG over start..end and writes the
+// digest. prev is the digest to diff against in since-last-report mode.
+func (p *Pipeline) analyzeWindow(ctx context.Context, start, end time.Time, prev *store.Report) (err error) {
+	ctx, span := telemetry.Start(ctx, "pipeline.analyze", telemetry.AttrStage.String(stageAnalyze))
+	defer func() { telemetry.End(span, err) }()
+
+	execStart := time.Now()
+	p.usage.reset()
 	startStr := start.Format("2006-01-02")
 	endStr := end.Format("2006-01-02")
 
-	// Load all SIGs from the store, then apply prefix-aware filtering.
-	// ListSIGs with nil loads all; filterSIGs handles prefix matching
-	// for names like "communications" → "communications-(website-...)"
-	sigs, err := p.store.ListSIGs(nil)
+	// In since-last-report mode, load the previous digest's items so the
+	// relevance stage can classify this run's items against them.
+	priorItems, err := p.loadPriorItems(prev)
 	if err != nil {
-		return fmt.Errorf("listing SIGs from store: %w", err)
+		p.logger.Warn("failed to load items of previous digest", logging.KeyStage, stageAnalyze, "err", err)
 	}
-	if len(sigs) == 0 {
-		return fmt.Errorf("no SIGs found in store (run fetch first)")
+	if prev != nil {
+		p.loadProfilePriors()
 	}
 
-	// Deduplicate SIGs by ID (stale DB entries may produce duplicates).
-	sigs = deduplicateSIGs(sigs)
+	sigs, err := p.analysisSIGs()
+	if err != nil {
+		return err
+	}
 
-	// Apply prefix-aware filter (also excludes localization unless requested).
-	sigs = filterSIGs(sigs, p.cfg.SIGs)
+	p.pruneCache()
+
+	// Keep the run within the configured LLM budget.
+	if err := p.applyBudget(ctx, sigs, start, end, priorItems); err != nil {
+		return err
+	}
+	if p.batch != nil {
+		p.batchSummaries(ctx, sigs, start, end)
+	}
 
-	log.Printf("pipeline: analyzing %d SIGs", len(sigs))
+	p.logger.Info("analyzing SIGs", logging.KeyStage, stageAnalyze, "sigs", len(sigs))
+	p.emit(Event{Type: EventPhaseStarted, Phase: stageAnalyze, Total: len(sigs)})
+	defer func() { p.emit(Event{Type: EventPhaseFinished, Phase: stageAnalyze, Err: err}) }()
 
 	// Analyze each SIG concurrently.
 	var mu sync.Mutex
@@ -237,9 +443,22 @@ func (p *Pipeline) AnalyzeOnly(ctx context.Context) error {
 	for _, sig := range sigs {
 		sig := sig
 		g.Go(func() error {
-			sr, err := p.analyzeSIG(gctx, sig, start, end, startStr, endStr)
+			if sr := p.completedAnalysis(sig.ID); sr != nil {
+				p.logger.Info("already analyzed in this run, skipping", logging.KeySIG, sig.ID, logging.KeyStage, stageAnalyze)
+				p.emitState(stageAnalyze, sig.ID, StateSkipped, nil)
+				mu.Lock()
+				sigReports = append(sigReports, sr)
+				mu.Unlock()
+				return nil
+			}
+
+			sigCtx, span := telemetry.Start(withSIG(gctx, sig.ID), "pipeline.analyze_sig", telemetry.AttrSIGID.String(sig.ID))
+			sr, err := p.analyzeSIG(sigCtx, sig, start, end, startStr, endStr, priorItems[sig.ID])
+			telemetry.End(span, err)
+			p.recordStep(sig.ID, stageAnalyze, err, sr)
+			p.emitState(stageAnalyze, sig.ID, finalState(err), err)
 			if err != nil {
-				log.Printf("warning: analysis failed for SIG %s: %v", sig.ID, err)
+				p.logger.Warn("analysis failed", logging.KeySIG, sig.ID, logging.KeyStage, stageAnalyze, "err", err)
 				// Build a partial report even on failure.
 				sr = &analysis.SIGReport{
 					SIGID:          sig.ID,
@@ -261,6 +480,10 @@ func (p *Pipeline) AnalyzeOnly(ctx context.Context) error {
 	if err := g.Wait(); err != nil {
 		return fmt.Errorf("analyzing SIGs: %w", err)
 	}
+	// Don't write a digest from a cancelled run; it can be resumed instead.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Compute run stats.
 	runDuration := time.Since(execStart)
@@ -273,15 +496,15 @@ func (p *Pipeline) AnalyzeOnly(ctx context.Context) error {
 			totalCalls++ // relevance call
 			sigsWithData++
 		}
+		for _, rr := range sr.ProfileReports {
+			totalTokens += rr.TokensUsed
+		}
 	}
-	// Rough estimate: each SIG with data has ~3 summarize + 1 synthesize + 1 relevance = 5 calls.
-	totalCalls = sigsWithData * 5
+	// Rough estimate: each SIG with data has ~3 summarize + 1 synthesize + 1 relevance = 5 calls,
+	// plus one relevance call per profile.
+	totalCalls = sigsWithData * (5 + len(p.profiles))
 
-	costPerMillionTokens := 3.0 // default Sonnet pricing
-	if p.cfg.LLM.Provider == "openai" {
-		costPerMillionTokens = 3.0
-	}
-	estimatedCost := float64(totalTokens) / 1_000_000 * costPerMillionTokens
+	estimatedCost := estimateCost(totalTokens)
 
 	stats := &analysis.RunStats{
 		TotalTokensUsed:  totalTokens,
@@ -293,6 +516,8 @@ func (p *Pipeline) AnalyzeOnly(ctx context.Context) error {
 		DurationSeconds:  runDuration.Seconds(),
 		EstimatedCostUSD: estimatedCost,
 	}
+	stats.Stages, stats.Fallbacks = p.usage.snapshot()
+	stats.BudgetSkipped = p.budgetSkipped
 
 	// Generate digest report (the only output file).
 	digest := &analysis.DigestReport{
@@ -301,51 +526,156 @@ func (p *Pipeline) AnalyzeOnly(ctx context.Context) error {
 		SIGReports:     sigReports,
 		Stats:          stats,
 	}
+	if prev != nil {
+		digest.PreviousDigestEnd = prev.DateRangeEnd.Format("2006-01-02")
+	}
 
-	if err := p.generateDigestReport(digest); err != nil {
-		log.Printf("warning: failed to generate digest report: %v", err)
+	path, err := p.generateDigestReport(digest)
+	if err != nil {
+		p.logger.Warn("failed to generate digest report", logging.KeyStage, stageReport, "err", err)
+	} else if err := p.recordDigest(digestReportType, digest, path, start, end); err != nil {
+		p.logger.Warn("failed to record digest in store", logging.KeyStage, stageReport, "err", err)
+	} else {
+		p.writeFeeds()
+	}
+	for _, pr := range p.profiles {
+		p.writeProfileDigest(pr, digest, start, end)
 	}
 
-	log.Println("pipeline: analysis phase complete")
+	p.logger.Info("analysis phase complete", logging.KeyStage, stageAnalyze)
 	return nil
 }
 
-// fetchSIG fetches all available sources for a single SIG.
+// analysisSIGs returns the configured SIGs from the store.
+func (p *Pipeline) analysisSIGs() ([]*store.SIG, error) {
+	// Load all SIGs from the store, then apply prefix-aware filtering.
+	// ListSIGs with nil loads all; filterSIGs handles prefix matching
+	// for names like "communications" → "communications-(website-...)"
+	sigs, err := p.store.ListSIGs(nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing SIGs from store: %w", err)
+	}
+	if len(sigs) == 0 {
+		return nil, fmt.Errorf("no SIGs found in store (run fetch first)")
+	}
+
+	// Deduplicate SIGs by ID (stale DB entries may produce duplicates).
+	sigs = deduplicateSIGs(sigs)
+
+	// Apply prefix-aware filter (also excludes localization unless requested).
+	return filterSIGs(sigs, p.cfg.SIGs), nil
+}
+
+// loadRegistry fetches the SIG registry and stores it, or returns the stored
+// copy if it is recent enough for the configured registry max age.
+func (p *Pipeline) loadRegistry() ([]*store.SIG, error) {
+	if p.registryMaxAge > 0 {
+		stored, err := p.store.ListSIGs(nil)
+		if err == nil && len(stored) > 0 {
+			var newest time.Time
+			for _, sig := range stored {
+				if sig.UpdatedAt.After(newest) {
+					newest = sig.UpdatedAt
+				}
+			}
+			if time.Since(newest) < p.registryMaxAge {
+				stored = deduplicateSIGs(stored)
+				p.logger.Info("using stored SIG registry", logging.KeySource, "registry",
+					"sigs", len(stored), "age", time.Since(newest).Round(time.Minute))
+				return stored, nil
+			}
+		}
+	}
+
+	sigs, err := p.registry.FetchAndParse()
+	if err != nil {
+		return nil, fmt.Errorf("fetching SIG registry: %w", err)
+	}
+	for _, sig := range sigs {
+		if err := p.store.UpsertSIG(sig); err != nil {
+			p.logger.Warn("failed to upsert SIG", logging.KeySIG, sig.ID, logging.KeySource, "registry", "err", err)
+		}
+	}
+	p.logger.Info("loaded SIG registry", logging.KeySource, "registry", "sigs", len(sigs))
+	return sigs, nil
+}
+
+// fetchSIG fetches all available sources for a single SIG. Source failures
+// are logged and returned together; they do not stop the other sources.
 func (p *Pipeline) fetchSIG(ctx context.Context, sig *store.SIG, start, end time.Time, recordings []*sources.Recording) error {
-	log.Printf("pipeline: fetching sources for SIG %s", sig.ID)
+	log := p.logger.With(logging.KeySIG, sig.ID, logging.KeyStage, stageFetch)
+	log.Info("fetching sources")
+	var errs []error
 
 	// Fetch meeting notes.
 	if !p.cfg.SkipNotes && sig.NotesDocID != "" {
-		if err := p.docsFetcher.FetchMeetingNotes(ctx, sig, start, end); err != nil {
-			log.Printf("warning: failed to fetch meeting notes for %s: %v", sig.ID, err)
+		p.emitState(stageFetch, sig.ID, StateFetchingNotes, nil)
+		err := fetchSource(ctx, sig.ID, "notes", func(ctx context.Context) error {
+			return p.docsFetcher.FetchMeetingNotes(ctx, sig, start, end)
+		})
+		if err != nil {
+			log.Warn("failed to fetch meeting notes", logging.KeySource, "notes", "err", err)
+			errs = append(errs, fmt.Errorf("meeting notes: %w", err))
 		}
 	}
 
 	// Fetch video transcripts.
 	if !p.cfg.SkipVideos {
 		sigRecordings := filterRecordingsForSIG(recordings, sig.ID)
+		if len(sigRecordings) > 0 {
+			p.emitState(stageFetch, sig.ID, StateFetchingVideo, nil)
+		}
 		for _, rec := range sigRecordings {
-			if err := p.zoomFetcher.FetchTranscript(ctx, rec); err != nil {
-				log.Printf("warning: failed to fetch transcript for %s (%s): %v",
-					sig.ID, rec.ZoomURL, err)
+			err := fetchSource(ctx, sig.ID, "video", func(ctx context.Context) error {
+				return p.zoomFetcher.FetchTranscript(ctx, rec)
+			})
+			if err != nil {
+				log.Warn("failed to fetch transcript", logging.KeySource, "video", "url", rec.ZoomURL, "err", err)
+				errs = append(errs, fmt.Errorf("transcript %s: %w", rec.ZoomURL, err))
 			}
 		}
 	}
 
 	// Fetch Slack messages.
 	if !p.cfg.SkipSlack && p.slackFetcher != nil && sig.SlackChannelID != "" {
-		if err := p.slackFetcher.FetchMessages(ctx, sig, start, end); err != nil {
-			log.Printf("warning: failed to fetch slack messages for %s: %v", sig.ID, err)
+		p.emitState(stageFetch, sig.ID, StateFetchingSlack, nil)
+		err := fetchSource(ctx, sig.ID, "slack", func(ctx context.Context) error {
+			return p.slackFetcher.FetchMessages(ctx, sig, start, end)
+		})
+		if err != nil {
+			log.Warn("failed to fetch slack messages", logging.KeySource, "slack", "err", err)
+			errs = append(errs, fmt.Errorf("slack messages: %w", err))
+			if errors.Is(err, sources.ErrSlackAuth) {
+				p.slackAuthOnce.Do(func() {
+					p.emit(Event{Type: EventSlackAuthFailed, Phase: stageFetch, SIGID: sig.ID, Err: err})
+				})
+			}
 		}
 	}
 
-	return nil
+	return errors.Join(errs...)
+}
+
+// fetchSource runs fn, which fetches one source for a SIG, inside a span and
+// records its duration and outcome.
+func fetchSource(ctx context.Context, sigID, sourceType string, fn func(context.Context) error) error {
+	ctx, span := telemetry.Start(ctx, "fetch."+sourceType,
+		telemetry.AttrSIGID.String(sigID),
+		telemetry.AttrSourceType.String(sourceType),
+	)
+	start := time.Now()
+	err := fn(ctx)
+	telemetry.RecordFetch(ctx, sourceType, sigID, time.Since(start), err)
+	telemetry.End(span, err)
+	return err
 }
 
 // analyzeSIG runs the full analysis pipeline for a single SIG:
 // summarize each source, synthesize across sources, score for relevance.
-func (p *Pipeline) analyzeSIG(ctx context.Context, sig *store.SIG, start, end time.Time, startStr, endStr string) (*analysis.SIGReport, error) {
-	log.Printf("pipeline: analyzing SIG %s", sig.ID)
+// prior holds the SIG's items from the previous digest in since-last-report mode.
+func (p *Pipeline) analyzeSIG(ctx context.Context, sig *store.SIG, start, end time.Time, startStr, endStr string, prior []analysis.PriorItem) (*analysis.SIGReport, error) {
+	log := p.logger.With(logging.KeySIG, sig.ID, logging.KeyStage, stageAnalyze)
+	log.Info("analyzing SIG")
 
 	var sourcesUsed []string
 	var sourcesMissing []string
@@ -354,12 +684,13 @@ func (p *Pipeline) analyzeSIG(ctx context.Context, sig *store.SIG, start, end ti
 	// Summarize meeting notes.
 	notes, err := p.store.GetMeetingNotes(sig.ID, start, end)
 	if err != nil {
-		log.Printf("warning: failed to get meeting notes for %s: %v", sig.ID, err)
+		log.Warn("failed to get meeting notes", logging.KeySource, "notes", "err", err)
 	}
 	if len(notes) > 0 {
+		p.emitState(stageAnalyze, sig.ID, StateSummarizing, nil)
 		summary, err := p.summarizer.SummarizeMeetingNotes(ctx, sig.ID, sig.Name, notes, start, end)
 		if err != nil {
-			log.Printf("warning: failed to summarize meeting notes for %s: %v", sig.ID, err)
+			log.Warn("failed to summarize meeting notes", logging.KeySource, "notes", "err", err)
 			sourcesMissing = append(sourcesMissing, "notes")
 		} else {
 			summaries = append(summaries, summary)
@@ -372,12 +703,13 @@ func (p *Pipeline) analyzeSIG(ctx context.Context, sig *store.SIG, start, end ti
 	// Summarize video transcripts.
 	transcripts, err := p.store.GetVideoTranscripts(sig.ID, start, end)
 	if err != nil {
-		log.Printf("warning: failed to get video transcripts for %s: %v", sig.ID, err)
+		log.Warn("failed to get video transcripts", logging.KeySource, "video", "err", err)
 	}
-	if len(transcripts) > 0 {
+	if len(transcripts) > 0 && !slices.Contains(p.budgetSkipped, "video") {
+		p.emitState(stageAnalyze, sig.ID, StateSummarizing, nil)
 		summary, err := p.summarizer.SummarizeVideoTranscripts(ctx, sig.ID, sig.Name, transcripts, start, end)
 		if err != nil {
-			log.Printf("warning: failed to summarize video transcripts for %s: %v", sig.ID, err)
+			log.Warn("failed to summarize video transcripts", logging.KeySource, "video", "err", err)
 			sourcesMissing = append(sourcesMissing, "video")
 		} else {
 			summaries = append(summaries, summary)
@@ -390,12 +722,13 @@ func (p *Pipeline) analyzeSIG(ctx context.Context, sig *store.SIG, start, end ti
 	// Summarize Slack messages.
 	messages, err := p.store.GetSlackMessages(sig.ID, start, end)
 	if err != nil {
-		log.Printf("warning: failed to get slack messages for %s: %v", sig.ID, err)
+		log.Warn("failed to get slack messages", logging.KeySource, "slack", "err", err)
 	}
 	if len(messages) > 0 {
+		p.emitState(stageAnalyze, sig.ID, StateSummarizing, nil)
 		summary, err := p.summarizer.SummarizeSlackMessages(ctx, sig.ID, sig.Name, messages, start, end)
 		if err != nil {
-			log.Printf("warning: failed to summarize slack messages for %s: %v", sig.ID, err)
+			log.Warn("failed to summarize slack messages", logging.KeySource, "slack", "err", err)
 			sourcesMissing = append(sourcesMissing, "slack")
 		} else {
 			summaries = append(summaries, summary)
@@ -426,55 +759,262 @@ func (p *Pipeline) analyzeSIG(ctx context.Context, sig *store.SIG, start, end ti
 
 	// If we have no summaries, return the partial report.
 	if len(summaries) == 0 {
-		log.Printf("pipeline: no source data available for SIG %s, skipping analysis", sig.ID)
+		log.Info("no source data available, skipping analysis")
 		return sr, nil
 	}
 
 	// Synthesize across sources.
+	p.emitState(stageAnalyze, sig.ID, StateSynthesizing, nil)
 	synthesis, err := p.synthesizer.Synthesize(ctx, sig.ID, sig.Name, summaries, start, end)
 	if err != nil {
 		return sr, fmt.Errorf("synthesizing SIG %s: %w", sig.ID, err)
 	}
 
 	// Score for Datadog relevance.
-	relevance, err := p.scorer.Score(ctx, sig.ID, sig.Name, synthesis, start, end)
+	p.emitState(stageAnalyze, sig.ID, StateScoring, nil)
+	relevance, err := p.scorer.ScoreWithPrior(ctx, sig.ID, sig.Name, synthesis, start, end, prior)
 	if err != nil {
 		return sr, fmt.Errorf("scoring relevance for SIG %s: %w", sig.ID, err)
 	}
 	sr.RelevanceReport = relevance
+	for _, item := range p.newHighItems(sig.ID, relevance, start, end) {
+		p.emit(Event{Type: EventHighItem, Phase: stageAnalyze, SIGID: sig.ID, Item: item})
+	}
+	p.scoreProfiles(ctx, sig, sr, synthesis, start, end)
 
-	log.Printf("pipeline: analysis complete for SIG %s (sources: %v)", sig.ID, sourcesUsed)
+	log.Info("analysis complete", "sources", sourcesUsed)
 	return sr, nil
 }
 
-// generateDigestReport writes the weekly digest in the configured format.
-func (p *Pipeline) generateDigestReport(digest *analysis.DigestReport) error {
+// newHighItems returns the HIGH items of rr worth announcing: ONGOING items
+// are left out, and so are items whose topic a digest overlapping
+// start..end already listed as HIGH for the SIG, so re-runs over the same
+// week do not announce them again. UPDATED items are always announced.
+func (p *Pipeline) newHighItems(sigID string, rr *analysis.RelevanceReport, start, end time.Time) []string {
+	reported, err := p.store.ReportedTopics(digestReportType, sigID, "high", start, end)
+	if err != nil {
+		p.logger.Warn("failed to load reported HIGH items", logging.KeySIG, sigID, "err", err)
+	}
+	var items []string
+	for _, item := range rr.HighItems {
+		switch rr.ItemStatuses[item] {
+		case analysis.ItemOngoing:
+			continue
+		case analysis.ItemUpdated:
+		default:
+			topic := analysis.ItemTopic(item)
+			if slices.ContainsFunc(reported, func(t string) bool { return analysis.SameTopic(t, topic) }) {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// generateDigestReport writes the weekly digest in the configured format and
+// returns the path of the primary output file.
+func (p *Pipeline) generateDigestReport(digest *analysis.DigestReport) (string, error) {
+	return p.writeDigest(digest, p.mdGenerator, p.jsonGenerator, p.htmlGenerator)
+}
+
+// writeDigest writes digest with the generator for the configured format,
+// or as both Markdown and JSON by default, and returns the path of the
+// primary output file.
+func (p *Pipeline) writeDigest(digest *analysis.DigestReport, mdGenerator *report.MarkdownGenerator, jsonGenerator *report.JSONGenerator, htmlGenerator *report.HTMLGenerator) (string, error) {
 	switch p.cfg.Format {
 	case "markdown":
-		path, err := p.mdGenerator.GenerateDigestReport(digest)
+		path, err := mdGenerator.GenerateDigestReport(digest)
 		if err != nil {
-			return err
+			return "", err
 		}
-		log.Printf("pipeline: wrote markdown digest %s", path)
+		p.logger.Info("wrote markdown digest", logging.KeyStage, stageReport, "path", path)
+		return path, nil
 	case "json":
-		path, err := p.jsonGenerator.GenerateDigestReport(digest)
+		path, err := jsonGenerator.GenerateDigestReport(digest)
 		if err != nil {
-			return err
+			return "", err
 		}
-		log.Printf("pipeline: wrote JSON digest %s", path)
+		p.logger.Info("wrote JSON digest", logging.KeyStage, stageReport, "path", path)
+		return path, nil
+	case "html":
+		path, err := htmlGenerator.GenerateDigestReport(digest)
+		if err != nil {
+			return "", err
+		}
+		p.logger.Info("wrote HTML digest", logging.KeyStage, stageReport, "path", path)
+		return path, nil
 	default:
-		if path, err := p.mdGenerator.GenerateDigestReport(digest); err != nil {
-			log.Printf("warning: failed to write markdown digest: %v", err)
+		mdPath, err := mdGenerator.GenerateDigestReport(digest)
+		if err != nil {
+			p.logger.Warn("failed to write markdown digest", logging.KeyStage, stageReport, "err", err)
 		} else {
-			log.Printf("pipeline: wrote markdown digest %s", path)
+			p.logger.Info("wrote markdown digest", logging.KeyStage, stageReport, "path", mdPath)
 		}
-		if path, err := p.jsonGenerator.GenerateDigestReport(digest); err != nil {
-			log.Printf("warning: failed to write JSON digest: %v", err)
+		jsonPath, err := jsonGenerator.GenerateDigestReport(digest)
+		if err != nil {
+			p.logger.Warn("failed to write JSON digest", logging.KeyStage, stageReport, "err", err)
 		} else {
-			log.Printf("pipeline: wrote JSON digest %s", path)
+			p.logger.Info("wrote JSON digest", logging.KeyStage, stageReport, "path", jsonPath)
 		}
+		if mdPath != "" {
+			return mdPath, nil
+		}
+		return jsonPath, nil
 	}
-	return nil
+}
+
+// writeFeeds regenerates the Atom feeds from the stored digest history.
+// Failures are logged; the digest itself has already been written.
+func (p *Pipeline) writeFeeds() {
+	if p.cfg.Feeds.Disabled {
+		return
+	}
+	paths, err := feed.Write(p.store, p.cfg.OutputDir, p.cfg.Feeds)
+	if err != nil {
+		p.logger.Warn("failed to write feeds", logging.KeyStage, stageReport, "err", err)
+		return
+	}
+	p.logger.Info("wrote feeds", logging.KeyStage, stageReport, "dir", filepath.Join(p.cfg.OutputDir, feed.Dir), "feeds", len(paths))
+}
+
+// window returns the time range for this run, as resolved by
+// config.Window or recorded in the run ledger. In since-last-report mode the
+// range starts on the day the previous digest for the same SIG set ended,
+// which is returned alongside it.
+func (p *Pipeline) window() (start, end time.Time, prev *store.Report, err error) {
+	start, end, inRun := p.runWindow()
+	if !inRun {
+		start, end, err = p.cfg.Window(time.Now())
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, fmt.Errorf("resolving date range: %w", err)
+		}
+	}
+	if !p.cfg.SinceLastReport {
+		return start, end, nil, nil
+	}
+
+	prev, err = p.store.LatestReport(digestReportType, sigSetKey(p.cfg.SIGs))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			p.logger.Info("no previous digest for this SIG set, using lookback window")
+		} else {
+			p.logger.Warn("failed to look up previous digest", "err", err)
+		}
+		return start, end, nil, nil
+	}
+	p.logger.Info("diffing against previous digest", "previous_end", prev.DateRangeEnd.Format("2006-01-02"))
+	if inRun {
+		// The recorded window already starts at the previous digest.
+		return start, end, prev, nil
+	}
+
+	// Report dates are stored as plain days; re-anchor the day in the
+	// window's time zone.
+	y, m, d := prev.DateRangeEnd.Date()
+	start = time.Date(y, m, d, 0, 0, 0, 0, end.Location())
+	return start, end, prev, nil
+}
+
+// loadPriorItems returns the relevance items of a previous digest grouped by
+// SIG ID. A nil report yields a nil map.
+func (p *Pipeline) loadPriorItems(prev *store.Report) (map[string][]analysis.PriorItem, error) {
+	if prev == nil {
+		return nil, nil
+	}
+	items, err := p.store.GetReportItems(prev.ID)
+	if err != nil {
+		return nil, err
+	}
+	bySIG := make(map[string][]analysis.PriorItem)
+	for _, item := range items {
+		bySIG[item.SIGID] = append(bySIG[item.SIGID], analysis.PriorItem{
+			Level: item.Level,
+			Topic: item.Topic,
+			Text:  item.Text,
+		})
+	}
+	return bySIG, nil
+}
+
+// recordDigest stores the digest as reportType with its relevance items so
+// later runs can diff against it.
+func (p *Pipeline) recordDigest(reportType string, digest *analysis.DigestReport, path string, start, end time.Time) error {
+	payload, err := report.MarshalDigest(digest)
+	if err != nil {
+		return err
+	}
+
+	r := &store.Report{
+		ReportType:     reportType,
+		SIGSet:         sigSetKey(p.cfg.SIGs),
+		DateRangeStart: start,
+		DateRangeEnd:   end,
+		FilePath:       path,
+		ContentHash:    fmt.Sprintf("%x", sha256.Sum256(payload)),
+		Payload:        string(payload),
+	}
+	if err := p.store.InsertReport(r); err != nil {
+		return fmt.Errorf("inserting report: %w", err)
+	}
+
+	return p.store.InsertReportItems(r.ID, digestItems(digest))
+}
+
+// digestItems flattens the relevance items of every SIG in a digest into
+// store records. Ongoing items are recorded with level "ongoing".
+func digestItems(digest *analysis.DigestReport) []*store.ReportItem {
+	var items []*store.ReportItem
+	for _, sr := range digest.SIGReports {
+		rr := sr.RelevanceReport
+		if rr == nil {
+			continue
+		}
+		add := func(level string, texts []string) {
+			for _, text := range texts {
+				items = append(items, &store.ReportItem{
+					SIGID:  sr.SIGID,
+					Level:  level,
+					Topic:  analysis.ItemTopic(text),
+					Text:   text,
+					Status: string(rr.ItemStatuses[text]),
+				})
+			}
+		}
+		add("high", rr.HighItems)
+		add("medium", rr.MediumItems)
+		add("low", rr.LowItems)
+		for _, text := range rr.OngoingItems {
+			items = append(items, &store.ReportItem{
+				SIGID:  sr.SIGID,
+				Level:  "ongoing",
+				Topic:  analysis.ItemTopic(text),
+				Text:   text,
+				Status: string(analysis.ItemOngoing),
+			})
+		}
+	}
+	return items
+}
+
+// sigSetKey returns a canonical key for a SIG filter so digests for the same
+// set of SIGs can be matched regardless of flag order. An empty filter is "all".
+func sigSetKey(filterIDs []string) string {
+	if len(filterIDs) == 0 {
+		return "all"
+	}
+	ids := make([]string, 0, len(filterIDs))
+	seen := make(map[string]bool, len(filterIDs))
+	for _, id := range filterIDs {
+		n := registry.NormalizeSIGID(id)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		ids = append(ids, n)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
 }
 
 // filterSIGs returns only the SIGs whose IDs match the provided filter list.
@@ -493,21 +1033,10 @@ func filterSIGs(sigs []*store.SIG, filterIDs []string) []*store.SIG {
 		return filtered
 	}
 
-	normalized := make([]string, len(filterIDs))
-	for i, id := range filterIDs {
-		normalized[i] = registry.NormalizeSIGID(id)
-	}
-
 	var filtered []*store.SIG
 	for _, sig := range sigs {
-		for _, filterID := range normalized {
-			// Exact match or prefix match — handles registry names that
-			// include parenthetical descriptions like
-			// "communications-(website-documentation-etc)".
-			if sig.ID == filterID || strings.HasPrefix(sig.ID, filterID+"-") {
-				filtered = append(filtered, sig)
-				break
-			}
+		if registry.MatchSIGFilter(sig.ID, filterIDs) {
+			filtered = append(filtered, sig)
 		}
 	}
 	return filtered
@@ -545,3 +1074,24 @@ func filterRecordingsForSIG(recordings []*sources.Recording, sigID string) []*so
 	}
 	return filtered
 }
+
+// pruneCache applies the configured analysis cache retention. Failing to
+// prune does not fail the run.
+func (p *Pipeline) pruneCache() {
+	cc := p.cfg.Cache
+	if cc.RetentionDays <= 0 && !cc.PruneStalePrompts {
+		return
+	}
+	var prompts *analysis.Prompts
+	if cc.PruneStalePrompts {
+		prompts = p.prompts
+	}
+	maxAge := time.Duration(cc.RetentionDays) * 24 * time.Hour
+	n, err := analysis.PruneCache(p.store, maxAge, prompts)
+	if err != nil {
+		p.logger.Warn("failed to prune analysis cache", logging.KeyStage, stageAnalyze, "err", err)
+	}
+	if n > 0 {
+		p.logger.Info("pruned analysis cache", logging.KeyStage, stageAnalyze, "entries", n)
+	}
+}
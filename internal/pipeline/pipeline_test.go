@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
 	"github.com/gordyrad/otel-sig-tracker/internal/config"
 	"github.com/gordyrad/otel-sig-tracker/internal/sources"
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
@@ -371,3 +372,48 @@ func TestDeduplicateSIGs_Empty(t *testing.T) {
 		t.Errorf("deduplicateSIGs(nil): got %d, want 0", len(result))
 	}
 }
+
+func TestSigSetKey(t *testing.T) {
+	if got := sigSetKey(nil); got != "all" {
+		t.Errorf("sigSetKey(nil) = %q, want %q", got, "all")
+	}
+	a := sigSetKey([]string{"specification", "Collector"})
+	b := sigSetKey([]string{"collector", "specification", "collector"})
+	if a != b {
+		t.Errorf("sigSetKey should be order and case insensitive: %q vs %q", a, b)
+	}
+	if a != "collector,specification" {
+		t.Errorf("sigSetKey = %q, want %q", a, "collector,specification")
+	}
+}
+
+func TestDigestItems(t *testing.T) {
+	digest := &analysis.DigestReport{
+		SIGReports: []*analysis.SIGReport{
+			{SIGID: "empty"},
+			{
+				SIGID: "collector",
+				RelevanceReport: &analysis.RelevanceReport{
+					HighItems:    []string{"**OTLP** — partial success"},
+					LowItems:     []string{"**Docs** — updates"},
+					OngoingItems: []string{"**Sampling** — still discussed"},
+					ItemStatuses: map[string]analysis.ItemStatus{"**OTLP** — partial success": analysis.ItemNew},
+				},
+			},
+		},
+	}
+
+	items := digestItems(digest)
+	if len(items) != 3 {
+		t.Fatalf("digestItems returned %d items, want 3", len(items))
+	}
+	if items[0].Level != "high" || items[0].Topic != "OTLP" || items[0].Status != "NEW" {
+		t.Errorf("first item = %+v", items[0])
+	}
+	if items[1].Level != "low" || items[1].Status != "" {
+		t.Errorf("second item = %+v", items[1])
+	}
+	if items[2].Level != "ongoing" || items[2].Status != "ONGOING" || items[2].Topic != "Sampling" {
+		t.Errorf("third item = %+v", items[2])
+	}
+}
@@ -0,0 +1,135 @@
+package pipeline
+
+import (
+	"context"
+	"database/sql"
+	"text/template"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// profileRun scores SIGs against one configured relevance profile and
+// writes its digest.
+type profileRun struct {
+	profile       *analysis.RelevanceProfile
+	scorer        *analysis.RelevanceScorer
+	mdGenerator   *report.MarkdownGenerator
+	jsonGenerator *report.JSONGenerator
+	htmlGenerator *report.HTMLGenerator
+
+	// prev is the profile's previous digest and prior its items by SIG ID,
+	// set in since-last-report mode.
+	prev  *store.Report
+	prior map[string][]analysis.PriorItem
+}
+
+// newProfileRuns creates a profileRun for every configured profile. The
+// profiles share the pipeline's LLM client, prompts and Markdown template.
+func newProfileRuns(cfg *config.Config, llm analysis.LLMClient, s *store.Store, prompts *analysis.Prompts, tmpl *template.Template) []*profileRun {
+	var runs []*profileRun
+	for _, pc := range cfg.Profiles {
+		profile := &analysis.RelevanceProfile{
+			Name:     pc.Name,
+			Audience: pc.Audience,
+			Criteria: pc.Criteria,
+			Keywords: pc.Keywords,
+			Rubric:   analysis.Rubric{High: pc.Rubric.High, Medium: pc.Rubric.Medium, Low: pc.Rubric.Low},
+		}
+		scorer := analysis.NewRelevanceScorer(llm, s, "")
+		scorer.SetProfile(profile)
+		scorer.SetPrompts(prompts)
+		dir := pc.OutputDir(cfg.OutputDir)
+		pr := &profileRun{
+			profile:       profile,
+			scorer:        scorer,
+			mdGenerator:   report.NewMarkdownGenerator(dir),
+			jsonGenerator: report.NewJSONGenerator(dir),
+			htmlGenerator: report.NewHTMLGenerator(dir),
+		}
+		if tmpl != nil {
+			pr.mdGenerator.SetTemplate(tmpl)
+		}
+		runs = append(runs, pr)
+	}
+	return runs
+}
+
+// reportType is the type the profile's digests are stored as, keeping
+// their history apart from the built-in digest's.
+func (pr *profileRun) reportType() string {
+	return digestReportType + ":" + pr.profile.Name
+}
+
+// loadProfilePriors looks up each profile's previous digest for the SIG set
+// and loads its items, so profile items are classified against what that
+// profile reported before.
+func (p *Pipeline) loadProfilePriors() {
+	for _, pr := range p.profiles {
+		log := p.logger.With(logging.KeyStage, stageAnalyze, "profile", pr.profile.Name)
+		prev, err := p.store.LatestReport(pr.reportType(), sigSetKey(p.cfg.SIGs))
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Warn("failed to look up previous profile digest", "err", err)
+			}
+			continue
+		}
+		prior, err := p.loadPriorItems(prev)
+		if err != nil {
+			log.Warn("failed to load items of previous profile digest", "err", err)
+			continue
+		}
+		pr.prev, pr.prior = prev, prior
+	}
+}
+
+// scoreProfiles scores a SIG's synthesis against every profile and records
+// the reports in sr.ProfileReports. A failed profile is logged and left
+// out; the built-in report is unaffected.
+func (p *Pipeline) scoreProfiles(ctx context.Context, sig *store.SIG, sr *analysis.SIGReport, synthesis *analysis.SynthesizedReport, start, end time.Time) {
+	for _, pr := range p.profiles {
+		rr, err := pr.scorer.ScoreWithPrior(ctx, sig.ID, sig.Name, synthesis, start, end, pr.prior[sig.ID])
+		if err != nil {
+			p.logger.Warn("profile scoring failed", logging.KeySIG, sig.ID, logging.KeyStage, stageAnalyze,
+				"profile", pr.profile.Name, "err", err)
+			continue
+		}
+		if sr.ProfileReports == nil {
+			sr.ProfileReports = make(map[string]*analysis.RelevanceReport, len(p.profiles))
+		}
+		sr.ProfileReports[pr.profile.Name] = rr
+	}
+}
+
+// writeProfileDigest writes and records the profile's view of digest: the
+// same SIGs and sources, with the profile's relevance reports.
+func (p *Pipeline) writeProfileDigest(pr *profileRun, digest *analysis.DigestReport, start, end time.Time) {
+	log := p.logger.With(logging.KeyStage, stageReport, "profile", pr.profile.Name)
+	pd := &analysis.DigestReport{
+		DateRangeStart: digest.DateRangeStart,
+		DateRangeEnd:   digest.DateRangeEnd,
+		Stats:          digest.Stats,
+	}
+	if pr.prev != nil {
+		pd.PreviousDigestEnd = pr.prev.DateRangeEnd.Format("2006-01-02")
+	}
+	for _, sr := range digest.SIGReports {
+		psr := *sr
+		psr.RelevanceReport = sr.ProfileReports[pr.profile.Name]
+		psr.ProfileReports = nil
+		pd.SIGReports = append(pd.SIGReports, &psr)
+	}
+
+	path, err := p.writeDigest(pd, pr.mdGenerator, pr.jsonGenerator, pr.htmlGenerator)
+	if err != nil {
+		log.Warn("failed to generate profile digest", "err", err)
+		return
+	}
+	if err := p.recordDigest(pr.reportType(), pd, path, start, end); err != nil {
+		log.Warn("failed to record profile digest in store", "err", err)
+	}
+}
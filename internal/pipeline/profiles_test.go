@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func TestProfiles_WriteOwnDigests(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	cfg := config.DefaultConfig()
+	cfg.DBPath = dbPath
+	cfg.OutputDir = filepath.Join(filepath.Dir(dbPath), "reports")
+	cfg.LLM.AnthropicKey = "test-key"
+	cfg.SkipSlack = true
+	cfg.Offline = true
+	cfg.SIGs = []string{"collector"}
+	cfg.Profiles = []config.ProfileConfig{
+		{Name: "logs", Audience: "the Logs team", Keywords: []string{"log bridge"}},
+		{Name: "ospo", Audience: "the OSPO", Keywords: []string{"governance"}, Output: filepath.Join(filepath.Dir(dbPath), "ospo")},
+	}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	if len(p.profiles) != 2 || p.profiles[0].reportType() != "digest:logs" {
+		t.Fatalf("profiles = %+v", p.profiles)
+	}
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+
+	// Reuse a recorded analysis so no LLM calls are needed. The OSPO
+	// profile failed for this SIG and has no report.
+	if _, err := p.BeginRun("report"); err != nil {
+		t.Fatalf("BeginRun: %v", err)
+	}
+	p.recordStep("collector", stageAnalyze, nil, &analysis.SIGReport{
+		SIGID:           "collector",
+		SIGName:         "Collector",
+		RelevanceReport: &analysis.RelevanceReport{HighItems: []string{"**Datadog Exporter** — built-in lens"}},
+		ProfileReports: map[string]*analysis.RelevanceReport{
+			"logs": {HighItems: []string{"**Log Bridge API** — logs lens"}},
+		},
+	})
+	err = p.AnalyzeOnly(context.Background())
+	p.EndRun(err)
+	if err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+
+	read := func(reportType string) string {
+		t.Helper()
+		rec, err := p.store.LatestReport(reportType, "collector")
+		if err != nil {
+			t.Fatalf("LatestReport(%s): %v", reportType, err)
+		}
+		data, err := os.ReadFile(rec.FilePath)
+		if err != nil {
+			t.Fatalf("reading %s digest: %v", reportType, err)
+		}
+		return string(data)
+	}
+
+	main := read(digestReportType)
+	if !strings.Contains(main, "built-in lens") || strings.Contains(main, "logs lens") {
+		t.Errorf("main digest should hold only the built-in profile's items:\n%s", main)
+	}
+	logs := read("digest:logs")
+	if !strings.Contains(logs, "logs lens") || strings.Contains(logs, "built-in lens") {
+		t.Errorf("logs digest should hold only the logs profile's items:\n%s", logs)
+	}
+	rec, _ := p.store.LatestReport("digest:logs", "collector")
+	if filepath.Dir(rec.FilePath) != filepath.Join(cfg.OutputDir, "logs") {
+		t.Errorf("logs digest written to %s, want under %s", rec.FilePath, filepath.Join(cfg.OutputDir, "logs"))
+	}
+	items, err := p.store.GetReportItems(rec.ID)
+	if err != nil || len(items) != 1 || items[0].Topic != "Log Bridge API" {
+		t.Errorf("logs digest items = %+v, %v", items, err)
+	}
+
+	ospo := read("digest:ospo")
+	if strings.Contains(ospo, "lens") {
+		t.Errorf("OSPO digest should show the SIG without items:\n%s", ospo)
+	}
+	rec, _ = p.store.LatestReport("digest:ospo", "collector")
+	if filepath.Dir(rec.FilePath) != cfg.Profiles[1].Output {
+		t.Errorf("OSPO digest written to %s, want under %s", rec.FilePath, cfg.Profiles[1].Output)
+	}
+}
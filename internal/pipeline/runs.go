@@ -0,0 +1,344 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// runLock is the store lock held by a run, so that only one fetch or report
+// runs against a database at a time. It is kept alive by a heartbeat and
+// expires if the holder dies.
+const (
+	runLock          = "pipeline"
+	runLockTTL       = 2 * time.Minute
+	runLockHeartbeat = 30 * time.Second
+)
+
+// ErrRunInProgress is returned by BeginRun and ResumeRun when another process
+// holds the database's run lock.
+var ErrRunInProgress = errors.New("another run is in progress on this database")
+
+// Run stages recorded per SIG in the run ledger.
+const (
+	stageFetch   = "fetch"
+	stageAnalyze = "analyze"
+)
+
+// runConfig is the snapshot of settings stored with each run and restored on
+// resume. Credentials are never stored.
+type runConfig struct {
+	SIGs            []string `json:"sigs,omitempty"`
+	Topics          []string `json:"topics,omitempty"`
+	OutputDir       string   `json:"output_dir"`
+	Format          string   `json:"format"`
+	Timezone        string   `json:"timezone,omitempty"`
+	SinceLastReport bool     `json:"since_last_report,omitempty"`
+	SkipVideos      bool     `json:"skip_videos,omitempty"`
+	SkipSlack       bool     `json:"skip_slack,omitempty"`
+	SkipNotes       bool     `json:"skip_notes,omitempty"`
+	Offline         bool     `json:"offline,omitempty"`
+	LLMProvider     string   `json:"llm_provider"`
+	LLMModel        string   `json:"llm_model"`
+}
+
+// BeginRun records a new run of command in the run ledger. Subsequent fetch
+// and analysis phases record per-SIG progress against it until EndRun.
+func (p *Pipeline) BeginRun(command string) (*store.Run, error) {
+	start, end, _, err := p.window()
+	if err != nil {
+		return nil, err
+	}
+	return p.BeginRangeRun(command, start, end)
+}
+
+// BeginRangeRun is BeginRun for a command covering start..end rather than
+// the configured window, such as a backfill.
+func (p *Pipeline) BeginRangeRun(command string, start, end time.Time) (*store.Run, error) {
+	snapshot, err := json.Marshal(runConfig{
+		SIGs:            p.cfg.SIGs,
+		Topics:          p.cfg.Topics,
+		OutputDir:       p.cfg.OutputDir,
+		Format:          p.cfg.Format,
+		Timezone:        p.cfg.Timezone,
+		SinceLastReport: p.cfg.SinceLastReport,
+		SkipVideos:      p.cfg.SkipVideos,
+		SkipSlack:       p.cfg.SkipSlack,
+		SkipNotes:       p.cfg.SkipNotes,
+		Offline:         p.cfg.Offline,
+		LLMProvider:     p.cfg.LLM.Provider,
+		LLMModel:        p.cfg.LLM.Model,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.acquireRunLock(); err != nil {
+		return nil, err
+	}
+
+	run := &store.Run{
+		Command:        command,
+		Config:         string(snapshot),
+		SIGSet:         sigSetKey(p.cfg.SIGs),
+		DateRangeStart: start,
+		DateRangeEnd:   end,
+		Status:         store.RunRunning,
+	}
+	if err := p.store.CreateRun(run); err != nil {
+		p.releaseRunLock()
+		return nil, fmt.Errorf("recording run: %w", err)
+	}
+	p.run = run
+	p.doneSteps = map[string]*store.RunStep{}
+	p.logger.Info("started run", "run", run.ID)
+	return run, nil
+}
+
+// ResumeRun continues an unfinished run of command. The run's stored
+// settings and window replace the current ones, and SIG stages that already
+// completed are skipped: fetches are not repeated and analysis results are
+// taken from the ledger.
+func (p *Pipeline) ResumeRun(id int64, command string) (*store.Run, error) {
+	run, err := p.store.GetRun(id)
+	if err != nil {
+		return nil, fmt.Errorf("loading run %d: %w", id, err)
+	}
+	if run.Command != command {
+		return nil, fmt.Errorf("run %d is a %s run, not %s", id, run.Command, command)
+	}
+	if run.Status == store.RunCompleted {
+		return nil, fmt.Errorf("run %d already completed", id)
+	}
+
+	var rc runConfig
+	if err := json.Unmarshal([]byte(run.Config), &rc); err != nil {
+		return nil, fmt.Errorf("decoding config of run %d: %w", id, err)
+	}
+	if err := p.acquireRunLock(); err != nil {
+		return nil, err
+	}
+	p.cfg.SIGs = rc.SIGs
+	p.cfg.Topics = rc.Topics
+	p.cfg.OutputDir = rc.OutputDir
+	p.cfg.Format = rc.Format
+	p.cfg.Timezone = rc.Timezone
+	p.cfg.SinceLastReport = rc.SinceLastReport
+	p.cfg.SkipVideos = rc.SkipVideos
+	p.cfg.SkipSlack = rc.SkipSlack
+	p.cfg.SkipNotes = rc.SkipNotes
+	p.cfg.Offline = rc.Offline
+	if rc.LLMProvider != p.cfg.LLM.Provider || rc.LLMModel != p.cfg.LLM.Model {
+		p.logger.Warn("resuming run with a different LLM", "run", id,
+			"run_llm", rc.LLMProvider+"/"+rc.LLMModel, "llm", p.cfg.LLM.Provider+"/"+p.cfg.LLM.Model)
+	}
+
+	loc, err := p.cfg.Location()
+	if err != nil {
+		p.releaseRunLock()
+		return nil, err
+	}
+	run.DateRangeStart = run.DateRangeStart.In(loc)
+	run.DateRangeEnd = run.DateRangeEnd.In(loc)
+
+	// The generators, profile runs and Slack fetcher were created for the
+	// current settings; recreate them for the run's.
+	if err := p.setOutput(); err != nil {
+		p.releaseRunLock()
+		return nil, err
+	}
+	for _, pr := range p.profiles {
+		pr.scorer.SetLogger(p.logger)
+	}
+	if p.slackFetcher == nil {
+		p.slackFetcher = newSlackFetcher(p.cfg, p.store, p.logger)
+		if p.slackFetcher != nil {
+			p.slackFetcher.SetLogger(p.logger)
+		}
+	}
+
+	steps, err := p.store.GetRunSteps(id)
+	if err != nil {
+		p.releaseRunLock()
+		return nil, fmt.Errorf("loading steps of run %d: %w", id, err)
+	}
+	done := make(map[string]*store.RunStep)
+	for _, st := range steps {
+		if st.Status == store.StepDone {
+			done[stepKey(st.SIGID, st.Stage)] = st
+		}
+	}
+
+	if err := p.store.UpdateRunStatus(id, store.RunRunning, ""); err != nil {
+		p.releaseRunLock()
+		return nil, fmt.Errorf("updating run %d: %w", id, err)
+	}
+	run.Status = store.RunRunning
+	p.run = run
+	p.doneSteps = done
+	p.logger.Info("resuming run", "run", id, "completed_steps", len(done))
+	return run, nil
+}
+
+// EndRun records the outcome of the current run and releases the run lock.
+// It is a no-op when no run was begun.
+func (p *Pipeline) EndRun(runErr error) {
+	if p.run == nil {
+		return
+	}
+	defer p.releaseRunLock()
+	status, msg := store.RunCompleted, ""
+	var pErr *PartialError
+	switch {
+	case runErr == nil:
+	case errors.As(runErr, &pErr):
+		status, msg = store.RunPartial, runErr.Error()
+	case errors.Is(runErr, context.Canceled):
+		status, msg = store.RunInterrupted, runErr.Error()
+	default:
+		status, msg = store.RunFailed, runErr.Error()
+	}
+	if err := p.store.UpdateRunStatus(p.run.ID, status, msg); err != nil {
+		p.logger.Warn("failed to record run status", "run", p.run.ID, "err", err)
+	}
+	p.run.Status = status
+	p.run.Error = msg
+	p.emit(Event{Type: EventRunFinished, RunID: p.run.ID, State: status, Err: runErr})
+}
+
+// acquireRunLock takes the database's run lock and starts a heartbeat that
+// keeps it until releaseRunLock.
+func (p *Pipeline) acquireRunLock() error {
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s:%d:%d", host, os.Getpid(), time.Now().UnixNano())
+	ok, err := p.store.AcquireLock(runLock, owner, runLockTTL)
+	if err != nil {
+		return fmt.Errorf("acquiring run lock: %w", err)
+	}
+	if !ok {
+		holder, _, _ := p.store.LockHolder(runLock)
+		return fmt.Errorf("%w (held by %s)", ErrRunInProgress, holder)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(runLockHeartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if ok, err := p.store.AcquireLock(runLock, owner, runLockTTL); err != nil || !ok {
+					p.logger.Warn("failed to extend run lock", "held", ok, "err", err)
+				}
+			}
+		}
+	}()
+
+	p.lockOwner = owner
+	p.stopHeartbeat = func() {
+		close(stop)
+		<-done
+	}
+	return nil
+}
+
+// releaseRunLock stops the heartbeat and releases the run lock, if held.
+func (p *Pipeline) releaseRunLock() {
+	if p.lockOwner == "" {
+		return
+	}
+	p.stopHeartbeat()
+	if err := p.store.ReleaseLock(runLock, p.lockOwner); err != nil {
+		p.logger.Warn("failed to release run lock", "err", err)
+	}
+	p.lockOwner = ""
+	p.stopHeartbeat = nil
+}
+
+// runWindow returns the window of the current run, if any.
+func (p *Pipeline) runWindow() (start, end time.Time, ok bool) {
+	if p.run == nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return p.run.DateRangeStart, p.run.DateRangeEnd, true
+}
+
+// completedStep returns the ledger entry for a stage the current run already
+// finished for a SIG, or nil.
+func (p *Pipeline) completedStep(sigID, stage string) *store.RunStep {
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
+	return p.doneSteps[stepKey(sigID, stage)]
+}
+
+// completedAnalysis returns the SIG report the current run already produced
+// for a SIG, or nil.
+func (p *Pipeline) completedAnalysis(sigID string) *analysis.SIGReport {
+	st := p.completedStep(sigID, stageAnalyze)
+	if st == nil || st.Result == "" {
+		return nil
+	}
+	var sr analysis.SIGReport
+	if err := json.Unmarshal([]byte(st.Result), &sr); err != nil {
+		p.logger.Warn("ignoring unreadable analysis result", logging.KeySIG, sigID, "err", err)
+		return nil
+	}
+	return &sr
+}
+
+// recordStep stores the outcome of a stage for a SIG in the current run.
+// result, if non-nil, is stored as JSON so a resumed run can reuse it.
+func (p *Pipeline) recordStep(sigID, stage string, stepErr error, result any) {
+	if p.run == nil {
+		return
+	}
+	st := &store.RunStep{RunID: p.run.ID, SIGID: sigID, Stage: stage, Status: store.StepDone}
+	if stepErr != nil {
+		st.Status = store.StepFailed
+		st.Error = stepErr.Error()
+	}
+	if result != nil && stepErr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			p.logger.Warn("failed to encode step result", logging.KeySIG, sigID, logging.KeyStage, stage, "err", err)
+		} else {
+			st.Result = string(data)
+		}
+	}
+	if err := p.store.SetRunStep(st); err != nil {
+		p.logger.Warn("failed to record run step", logging.KeySIG, sigID, logging.KeyStage, stage, "err", err)
+		return
+	}
+	if st.Status == store.StepDone {
+		p.runMu.Lock()
+		p.doneSteps[stepKey(sigID, stage)] = st
+		p.runMu.Unlock()
+	}
+}
+
+// forgetSteps drops the completed stage of every SIG from the current run,
+// so the next window analyzed in the same run does not reuse its results.
+func (p *Pipeline) forgetSteps(stage string) {
+	p.runMu.Lock()
+	defer p.runMu.Unlock()
+	for key, st := range p.doneSteps {
+		if st.Stage == stage {
+			delete(p.doneSteps, key)
+		}
+	}
+}
+
+func stepKey(sigID, stage string) string {
+	return sigID + "/" + stage
+}
@@ -0,0 +1,203 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func newRunTestPipeline(t *testing.T, dbPath string) *Pipeline {
+	t.Helper()
+	cfg := config.DefaultConfig()
+	cfg.DBPath = dbPath
+	cfg.OutputDir = filepath.Join(filepath.Dir(dbPath), "reports")
+	cfg.LLM.AnthropicKey = "test-key"
+	cfg.SkipSlack = true
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p
+}
+
+func TestRunLedger_ResumeSkipsCompletedAnalysis(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	p := newRunTestPipeline(t, dbPath)
+	p.cfg.SIGs = []string{"collector"}
+	p.cfg.Offline = true
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+
+	run, err := p.BeginRun("report")
+	if err != nil {
+		t.Fatalf("BeginRun: %v", err)
+	}
+	// Simulate a run that analyzed the SIG and was then interrupted.
+	p.recordStep("collector", stageAnalyze, nil, &analysis.SIGReport{
+		SIGID:   "collector",
+		SIGName: "Collector",
+		RelevanceReport: &analysis.RelevanceReport{
+			HighItems: []string{"Recorded before the interruption"},
+		},
+	})
+	p.EndRun(fmt.Errorf("analyze phase: %w", context.Canceled))
+
+	got, err := p.store.GetRun(run.ID)
+	if err != nil {
+		t.Fatalf("GetRun: %v", err)
+	}
+	if got.Status != store.RunInterrupted {
+		t.Errorf("status = %q, want %q", got.Status, store.RunInterrupted)
+	}
+
+	// A fresh process resumes with different flags; the run's settings win.
+	p2 := newRunTestPipeline(t, dbPath)
+	p2.cfg.SIGs = []string{"java-sdk"}
+	if _, err := p2.ResumeRun(run.ID, "fetch"); err == nil {
+		t.Error("ResumeRun with the wrong command should fail")
+	}
+	if _, err := p2.ResumeRun(run.ID, "report"); err != nil {
+		t.Fatalf("ResumeRun: %v", err)
+	}
+	if len(p2.cfg.SIGs) != 1 || p2.cfg.SIGs[0] != "collector" || !p2.cfg.Offline {
+		t.Errorf("restored config SIGs=%v offline=%v, want [collector] true", p2.cfg.SIGs, p2.cfg.Offline)
+	}
+
+	err = p2.AnalyzeOnly(context.Background())
+	p2.EndRun(err)
+	if err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+
+	digest, err := p2.store.LatestReport(digestReportType, "collector")
+	if err != nil {
+		t.Fatalf("LatestReport: %v", err)
+	}
+	data, err := os.ReadFile(digest.FilePath)
+	if err != nil {
+		t.Fatalf("reading digest: %v", err)
+	}
+	if !strings.Contains(string(data), "Recorded before the interruption") {
+		t.Error("resumed digest should reuse the recorded analysis result")
+	}
+
+	if _, err := p2.ResumeRun(run.ID, "report"); err == nil {
+		t.Error("ResumeRun of a completed run should fail")
+	}
+}
+
+func TestRunLedger_ResumeWritesToRunOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+
+	p := newRunTestPipeline(t, dbPath)
+	p.cfg.SIGs = []string{"collector"}
+	p.cfg.Offline = true
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+	run, err := p.BeginRun("report")
+	if err != nil {
+		t.Fatalf("BeginRun: %v", err)
+	}
+	p.EndRun(fmt.Errorf("analyze phase: %w", context.Canceled))
+
+	// The resuming process is started with another --output-dir.
+	cfg := config.DefaultConfig()
+	cfg.DBPath = dbPath
+	cfg.OutputDir = filepath.Join(dir, "elsewhere")
+	cfg.LLM.AnthropicKey = "test-key"
+	cfg.SkipSlack = true
+	p2, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { p2.Close() })
+	if _, err := p2.ResumeRun(run.ID, "report"); err != nil {
+		t.Fatalf("ResumeRun: %v", err)
+	}
+	if want := filepath.Join(dir, "reports"); cfg.OutputDir != want {
+		t.Errorf("restored output dir = %q, want %q", cfg.OutputDir, want)
+	}
+	err = p2.AnalyzeOnly(context.Background())
+	p2.EndRun(err)
+	if err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+
+	digest, err := p2.store.LatestReport(digestReportType, "collector")
+	if err != nil {
+		t.Fatalf("LatestReport: %v", err)
+	}
+	if got := filepath.Dir(digest.FilePath); got != cfg.OutputDir {
+		t.Errorf("digest written to %s, want the run's output dir %s", got, cfg.OutputDir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "elsewhere")); !os.IsNotExist(err) {
+		t.Errorf("resumed run wrote to the new --output-dir (stat err %v)", err)
+	}
+}
+
+func TestRunLedger_SingleFlight(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	p1 := newRunTestPipeline(t, dbPath)
+	p2 := newRunTestPipeline(t, dbPath)
+
+	if _, err := p1.BeginRun("fetch"); err != nil {
+		t.Fatalf("BeginRun: %v", err)
+	}
+	if _, err := p2.BeginRun("report"); !errors.Is(err, ErrRunInProgress) {
+		t.Fatalf("concurrent BeginRun error = %v, want ErrRunInProgress", err)
+	}
+
+	p1.EndRun(nil)
+	if _, err := p2.BeginRun("report"); err != nil {
+		t.Fatalf("BeginRun after the first run ended: %v", err)
+	}
+	p2.EndRun(nil)
+}
+
+func TestPipeline_PrunesCacheOnRun(t *testing.T) {
+	p := newEstimateTestPipeline(t)
+	p.cfg.SkipVideos = true
+	p.cfg.Cache = config.CacheConfig{PruneStalePrompts: true}
+	day := time.Now().AddDate(0, 0, -30)
+	for _, ac := range []*store.AnalysisCache{
+		{CacheKey: "stale", SIGID: "collector", SourceType: "notes", PromptVersion: "notes@0:old"},
+		{CacheKey: "unversioned", SIGID: "collector", SourceType: "notes"},
+	} {
+		ac.DateRangeStart, ac.DateRangeEnd, ac.Result, ac.Model = day, day, "Old summary.", "claude"
+		if err := p.store.PutAnalysisCache(ac); err != nil {
+			t.Fatalf("PutAnalysisCache: %v", err)
+		}
+	}
+
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	if _, err := p.store.GetAnalysisCache("stale"); err == nil {
+		t.Error("entry of an old prompt version survived the run")
+	}
+	if _, err := p.store.GetAnalysisCache("unversioned"); err != nil {
+		t.Errorf("entry without a prompt version was pruned: %v", err)
+	}
+	// The run's own results are current.
+	stats, err := p.store.AnalysisCacheStats(store.AnalysisCacheFilter{SIGIDs: []string{"collector"}, Since: time.Now().AddDate(0, 0, -7)})
+	if err != nil {
+		t.Fatalf("AnalysisCacheStats: %v", err)
+	}
+	if len(stats) == 0 {
+		t.Error("the run cached no results")
+	}
+}
@@ -0,0 +1,173 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+)
+
+// newStageClients creates the LLM client of every analysis stage in
+// config.LLMStages, each with the stage's provider, model and request
+// settings followed by the configured fallback chain, traced and reporting
+// its usage to usage. It also returns the fallback clients, so their logger
+// can be replaced.
+func newStageClients(cfg config.LLMConfig, usage *stageUsage) (map[string]*usageClient, []*analysis.FallbackClient, error) {
+	clients := make(map[string]*usageClient, len(config.LLMStages))
+	var fallbacks []*analysis.FallbackClient
+	for _, stage := range config.LLMStages {
+		sc := cfg.Stage(stage)
+		llm, err := newProviderClient(cfg, sc.Provider, sc.Model)
+		if err != nil {
+			return nil, nil, err
+		}
+		if chain := fallbackChain(cfg, sc); len(chain) > 0 {
+			targets := []analysis.FallbackTarget{{Provider: sc.Provider, Model: sc.Model, Client: llm}}
+			for _, fb := range chain {
+				fc, err := newProviderClient(cfg, fb.Provider, fb.Model)
+				if err != nil {
+					return nil, nil, err
+				}
+				targets = append(targets, analysis.FallbackTarget{Provider: fb.Provider, Model: fb.Model, Client: fc})
+			}
+			fallback := analysis.NewFallbackClient(targets...)
+			fallbacks = append(fallbacks, fallback)
+			llm = fallback
+		}
+		if sc.MaxTokens > 0 || sc.Temperature > 0 {
+			llm = &requestDefaultsClient{next: llm, maxTokens: sc.MaxTokens, temperature: sc.Temperature}
+		}
+		clients[stage] = &usageClient{
+			next:     llm,
+			stage:    stage,
+			provider: sc.Provider,
+			model:    sc.Model,
+			usage:    usage,
+		}
+	}
+	return clients, fallbacks, nil
+}
+
+// newProviderClient creates a traced client of provider's API for model.
+func newProviderClient(cfg config.LLMConfig, provider, model string) (analysis.LLMClient, error) {
+	var llm analysis.LLMClient
+	switch provider {
+	case "anthropic":
+		llm = analysis.NewAnthropicClient(cfg.AnthropicKey, model)
+	case "openai":
+		llm = analysis.NewOpenAIClient(cfg.OpenAIKey, model)
+	default:
+		return nil, fmt.Errorf("unsupported LLM provider: %s", provider)
+	}
+	return analysis.NewTracedClient(llm, provider, model), nil
+}
+
+// fallbackChain returns the configured fallbacks of a stage, leaving out
+// the stage's own provider and model.
+func fallbackChain(cfg config.LLMConfig, sc config.StageLLMConfig) []config.FallbackLLMConfig {
+	var chain []config.FallbackLLMConfig
+	for _, fb := range cfg.Fallbacks {
+		if fb.Provider != sc.Provider || fb.Model != sc.Model {
+			chain = append(chain, fb)
+		}
+	}
+	return chain
+}
+
+// requestDefaultsClient fills in the max tokens and temperature of requests
+// that leave them unset.
+type requestDefaultsClient struct {
+	next        analysis.LLMClient
+	maxTokens   int
+	temperature float64
+}
+
+// Complete calls the wrapped client with the stage's request settings.
+func (c *requestDefaultsClient) Complete(ctx context.Context, req *analysis.CompletionRequest) (*analysis.CompletionResponse, error) {
+	r := *req
+	if r.MaxTokens == 0 {
+		r.MaxTokens = c.maxTokens
+	}
+	if r.Temperature == 0 {
+		r.Temperature = c.temperature
+	}
+	return c.next.Complete(ctx, &r)
+}
+
+// stageUsage accumulates the LLM calls of each analysis stage during a run.
+type stageUsage struct {
+	mu        sync.Mutex
+	stages    map[string]*analysis.StageUsage
+	fallbacks map[fallbackKey]*analysis.FallbackUsage
+}
+
+// fallbackKey identifies the fallback provider and model of a stage.
+type fallbackKey struct{ stage, provider, model string }
+
+// reset clears the usage recorded so far.
+func (u *stageUsage) reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.stages = nil
+	u.fallbacks = nil
+}
+
+// add records one call of stage, configured with provider and model, that
+// was answered with resp.
+func (u *stageUsage) add(stage, provider, model string, resp *analysis.CompletionResponse) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.stages == nil {
+		u.stages = make(map[string]*analysis.StageUsage)
+	}
+	su, ok := u.stages[stage]
+	if !ok {
+		su = &analysis.StageUsage{Stage: stage, Provider: provider, Model: model}
+		u.stages[stage] = su
+	}
+	su.Calls++
+	su.TokensUsed += resp.TokensUsed
+	su.EstimatedCostUSD += responseCost(resp)
+
+	if resp.Fallback {
+		if u.fallbacks == nil {
+			u.fallbacks = make(map[fallbackKey]*analysis.FallbackUsage)
+		}
+		key := fallbackKey{stage, resp.Provider, resp.Model}
+		fu, ok := u.fallbacks[key]
+		if !ok {
+			fu = &analysis.FallbackUsage{Stage: stage, Provider: resp.Provider, Model: resp.Model}
+			u.fallbacks[key] = fu
+		}
+		fu.Calls++
+	}
+}
+
+// snapshot returns the usage of the stages that made calls and the calls
+// answered by fallbacks, both in stage order.
+func (u *stageUsage) snapshot() ([]analysis.StageUsage, []analysis.FallbackUsage) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	var stages []analysis.StageUsage
+	for _, stage := range config.LLMStages {
+		if su, ok := u.stages[stage]; ok {
+			stages = append(stages, *su)
+		}
+	}
+	var fallbacks []analysis.FallbackUsage
+	for _, fu := range u.fallbacks {
+		fallbacks = append(fallbacks, *fu)
+	}
+	sort.Slice(fallbacks, func(i, j int) bool {
+		a, b := fallbacks[i], fallbacks[j]
+		if a.Stage != b.Stage {
+			return slices.Index(config.LLMStages, a.Stage) < slices.Index(config.LLMStages, b.Stage)
+		}
+		return a.Provider+"/"+a.Model < b.Provider+"/"+b.Model
+	})
+	return stages, fallbacks
+}
@@ -0,0 +1,225 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func TestNewStageClients(t *testing.T) {
+	cfg := config.DefaultConfig().LLM
+	cfg.AnthropicKey = "test-key"
+	cfg.OpenAIKey = "test-key"
+	cfg.Stages = map[string]config.StageLLMConfig{
+		"notes": {Model: "claude-3-5-haiku-20241022"},
+		"slack": {Provider: "openai", Model: "gpt-4o-mini", MaxTokens: 1024},
+	}
+
+	clients, fallbacks, err := newStageClients(cfg, &stageUsage{})
+	if err != nil {
+		t.Fatalf("newStageClients: %v", err)
+	}
+	if len(clients) != len(config.LLMStages) {
+		t.Fatalf("got %d clients, want one per stage", len(clients))
+	}
+	if len(fallbacks) != 0 {
+		t.Errorf("got %d fallback clients without a fallback chain", len(fallbacks))
+	}
+	for stage, want := range map[string]string{
+		"notes":     "anthropic/claude-3-5-haiku-20241022",
+		"video":     "anthropic/" + cfg.Model,
+		"slack":     "openai/gpt-4o-mini",
+		"synthesis": "anthropic/" + cfg.Model,
+		"relevance": "anthropic/" + cfg.Model,
+	} {
+		c := clients[stage]
+		if got := c.provider + "/" + c.model; got != want || c.stage != stage {
+			t.Errorf("%s client = %s (stage %q), want %s", stage, got, c.stage, want)
+		}
+		// The analysis cache is keyed on the model a client describes.
+		if provider, model := c.DescribeModel(); provider+"/"+model != want {
+			t.Errorf("%s client describes %s/%s, want %s", stage, provider, model, want)
+		}
+	}
+
+	// The fallback chain skips a stage's own provider and model.
+	cfg.Fallbacks = []config.FallbackLLMConfig{{Provider: "openai", Model: "gpt-4o-mini"}}
+	_, fallbacks, err = newStageClients(cfg, &stageUsage{})
+	if err != nil {
+		t.Fatalf("newStageClients: %v", err)
+	}
+	if len(fallbacks) != len(config.LLMStages)-1 {
+		t.Errorf("got %d fallback clients, want one per stage but slack", len(fallbacks))
+	}
+
+	cfg.Stages = map[string]config.StageLLMConfig{"video": {Provider: "bogus", Model: "m"}}
+	if _, _, err := newStageClients(cfg, &stageUsage{}); err == nil {
+		t.Error("newStageClients should reject an unknown provider")
+	}
+}
+
+type recordingLLM struct{ req *analysis.CompletionRequest }
+
+func (r *recordingLLM) Complete(ctx context.Context, req *analysis.CompletionRequest) (*analysis.CompletionResponse, error) {
+	r.req = req
+	return &analysis.CompletionResponse{Content: "ok"}, nil
+}
+
+func TestRequestDefaultsClient(t *testing.T) {
+	next := &recordingLLM{}
+	c := &requestDefaultsClient{next: next, maxTokens: 1024, temperature: 0.1}
+
+	req := &analysis.CompletionRequest{UserPrompt: "hi"}
+	if _, err := c.Complete(context.Background(), req); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if next.req.MaxTokens != 1024 || next.req.Temperature != 0.1 || next.req.UserPrompt != "hi" {
+		t.Errorf("request = %+v, want the stage defaults filled in", next.req)
+	}
+	if req.MaxTokens != 0 {
+		t.Error("the caller's request should not be modified")
+	}
+
+	if _, err := c.Complete(context.Background(), &analysis.CompletionRequest{MaxTokens: 50, Temperature: 0.9}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if next.req.MaxTokens != 50 || next.req.Temperature != 0.9 {
+		t.Errorf("request = %+v, want explicit settings kept", next.req)
+	}
+}
+
+func TestPipeline_ReportsStageUsage(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	cfg := config.DefaultConfig()
+	cfg.DBPath = dbPath
+	cfg.OutputDir = filepath.Join(filepath.Dir(dbPath), "reports")
+	cfg.LLM.AnthropicKey = "test-key"
+	cfg.LLM.Stages = map[string]config.StageLLMConfig{"notes": {Model: "claude-3-5-haiku-20241022"}}
+	cfg.SkipSlack = true
+	cfg.Offline = true
+	cfg.SIGs = []string{"collector"}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { p.Close() })
+
+	// Answer every stage locally, with the token counts telling them apart.
+	for stage, tokens := range map[string]int{"notes": 100, "synthesis": 200, "relevance": 400} {
+		p.llm[stage].(*usageClient).next = stubLLM{tokens: tokens}
+	}
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+	if err := p.store.UpsertMeetingNote(&store.MeetingNote{
+		SIGID:       "collector",
+		DocID:       "doc",
+		MeetingDate: time.Now().Add(-24 * time.Hour),
+		RawText:     "Discussed the batch processor.",
+	}); err != nil {
+		t.Fatalf("UpsertMeetingNote: %v", err)
+	}
+
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	rec, err := p.store.LatestReport(digestReportType, "collector")
+	if err != nil {
+		t.Fatalf("LatestReport: %v", err)
+	}
+	digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+	if err != nil {
+		t.Fatalf("UnmarshalDigest: %v", err)
+	}
+
+	want := []analysis.StageUsage{
+		{Stage: "notes", Provider: "anthropic", Model: "claude-3-5-haiku-20241022", Calls: 1, TokensUsed: 100, EstimatedCostUSD: estimateCost(100)},
+		{Stage: "synthesis", Provider: "anthropic", Model: cfg.LLM.Model, Calls: 1, TokensUsed: 200, EstimatedCostUSD: estimateCost(200)},
+		{Stage: "relevance", Provider: "anthropic", Model: cfg.LLM.Model, Calls: 1, TokensUsed: 400, EstimatedCostUSD: estimateCost(400)},
+	}
+	got := digest.Stats.Stages
+	if len(got) != len(want) {
+		t.Fatalf("stage usage = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("stage usage[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A second run is answered from the analysis cache and makes no calls.
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	if got, _ := p.usage.snapshot(); len(got) != 0 {
+		t.Errorf("stage usage of a cached run = %+v, want none", got)
+	}
+}
+
+type unavailableLLM struct{}
+
+func (unavailableLLM) Complete(ctx context.Context, req *analysis.CompletionRequest) (*analysis.CompletionResponse, error) {
+	return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+}
+
+func TestPipeline_ReportsFallbacks(t *testing.T) {
+	p := newRunTestPipeline(t, filepath.Join(t.TempDir(), "test.db"))
+	p.cfg.Offline = true
+	p.cfg.SIGs = []string{"collector"}
+
+	// The relevance stage's provider is down; its fallback answers.
+	for stage, c := range p.llm {
+		next := analysis.LLMClient(stubLLM{tokens: 100})
+		if stage == "relevance" {
+			next = analysis.NewFallbackClient(
+				analysis.FallbackTarget{Provider: "anthropic", Model: p.cfg.LLM.Model, Client: unavailableLLM{}},
+				analysis.FallbackTarget{Provider: "openai", Model: "gpt-4o", Client: stubLLM{tokens: 100}},
+			)
+		}
+		c.(*usageClient).next = next
+	}
+	if err := p.store.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+	if err := p.store.UpsertMeetingNote(&store.MeetingNote{
+		SIGID:       "collector",
+		DocID:       "doc",
+		MeetingDate: time.Now().Add(-24 * time.Hour),
+		RawText:     "Discussed the batch processor.",
+	}); err != nil {
+		t.Fatalf("UpsertMeetingNote: %v", err)
+	}
+
+	if err := p.AnalyzeOnly(context.Background()); err != nil {
+		t.Fatalf("AnalyzeOnly: %v", err)
+	}
+	rec, err := p.store.LatestReport(digestReportType, "collector")
+	if err != nil {
+		t.Fatalf("LatestReport: %v", err)
+	}
+	digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+	if err != nil {
+		t.Fatalf("UnmarshalDigest: %v", err)
+	}
+	want := analysis.FallbackUsage{Stage: "relevance", Provider: "openai", Model: "gpt-4o", Calls: 1}
+	if got := digest.Stats.Fallbacks; len(got) != 1 || got[0] != want {
+		t.Errorf("fallbacks = %+v, want %+v", got, want)
+	}
+	data, err := os.ReadFile(rec.FilePath)
+	if err != nil {
+		t.Fatalf("reading digest: %v", err)
+	}
+	if !strings.Contains(string(data), "- relevance: 1 call(s) by openai `gpt-4o`") {
+		t.Errorf("digest should warn about the fallback:\n%s", data)
+	}
+}
@@ -0,0 +1,133 @@
+package progress
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+)
+
+func feed(o pipeline.Observer, start time.Time) {
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	o.OnEvent(pipeline.Event{Type: pipeline.EventPhaseStarted, Phase: "analyze", Total: 4, Time: at(0)})
+	o.OnEvent(pipeline.Event{Type: pipeline.EventSIGState, Phase: "analyze", SIGID: "collector", State: pipeline.StateSummarizing, Time: at(time.Second)})
+	o.OnEvent(pipeline.Event{Type: pipeline.EventSIGState, Phase: "analyze", SIGID: "java-sdk", State: pipeline.StateScoring, Time: at(time.Second)})
+	o.OnEvent(pipeline.Event{Type: pipeline.EventSIGState, Phase: "analyze", SIGID: "go-sdk", State: pipeline.StateDone, Time: at(10 * time.Second)})
+	o.OnEvent(pipeline.Event{Type: pipeline.EventSIGState, Phase: "analyze", SIGID: "specification", State: pipeline.StateFailed, Err: errors.New("rate limited"), Time: at(20 * time.Second)})
+	o.OnEvent(pipeline.Event{Type: pipeline.EventLLMUsage, Phase: "analyze", SIGID: "collector", Tokens: 1500, CostUSD: 0.0045, Time: at(20 * time.Second)})
+	o.OnEvent(pipeline.Event{Type: pipeline.EventLLMUsage, Phase: "analyze", SIGID: "go-sdk", Tokens: 151_000, CostUSD: 0.453, Time: at(20 * time.Second)})
+}
+
+func TestTracker_Snapshot(t *testing.T) {
+	start := time.Date(2026, 10, 12, 9, 0, 0, 0, time.UTC)
+	tr := NewTracker()
+	tr.now = func() time.Time { return start.Add(30 * time.Second) }
+	feed(tr, start)
+
+	snap := tr.Snapshot()
+	if snap.Phase != "analyze" || snap.Total != 4 || snap.Done != 1 || snap.Failed != 1 || snap.Active != 2 {
+		t.Errorf("snapshot counts = %+v, want 1 done, 1 failed, 2 active of 4", snap)
+	}
+	if snap.Tokens != 152_500 {
+		t.Errorf("tokens = %d, want 152500", snap.Tokens)
+	}
+	if snap.Elapsed != 30*time.Second {
+		t.Errorf("elapsed = %s, want 30s", snap.Elapsed)
+	}
+	// Two of four SIGs finished in 30s, so two more take about 30s.
+	if snap.ETA != 30*time.Second {
+		t.Errorf("ETA = %s, want 30s", snap.ETA)
+	}
+	if got := snap.SIGs[len(snap.SIGs)-1]; got.Finished() {
+		t.Errorf("last SIG = %+v, want active SIGs listed last", got)
+	}
+	for _, s := range snap.SIGs {
+		if s.ID == "specification" && s.Err != "rate limited" {
+			t.Errorf("failed SIG error = %q, want %q", s.Err, "rate limited")
+		}
+	}
+
+	tr.OnEvent(pipeline.Event{Type: pipeline.EventPhaseFinished, Phase: "analyze", Time: start.Add(25 * time.Second)})
+	snap = tr.Snapshot()
+	if !snap.Finished || snap.ETA != 0 || snap.Elapsed != 25*time.Second {
+		t.Errorf("finished snapshot = %+v, want no ETA and 25s elapsed", snap)
+	}
+
+	// A new phase resets per-SIG state but keeps the run's token total.
+	tr.OnEvent(pipeline.Event{Type: pipeline.EventPhaseStarted, Phase: "fetch", Total: 2, Time: start})
+	snap = tr.Snapshot()
+	if snap.Phase != "fetch" || len(snap.SIGs) != 0 || snap.Tokens != 152_500 || snap.Finished {
+		t.Errorf("new phase snapshot = %+v", snap)
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	got := StatusLine(Snapshot{
+		Phase: "analyze", Total: 40, Done: 12, Failed: 1, Active: 4,
+		Tokens: 152_300, CostUSD: 0.4569,
+		Elapsed: 123 * time.Second, ETA: 190 * time.Second,
+	})
+	want := "analyze: 12/40 SIGs done, 1 failed, 4 active | 152,300 tokens ($0.46) | 2m3s elapsed, ETA 3m10s"
+	if got != want {
+		t.Errorf("StatusLine =\n  %q\nwant\n  %q", got, want)
+	}
+}
+
+func TestRenderer_Plain(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf, false)
+	feed(r, time.Now())
+	r.OnEvent(pipeline.Event{Type: pipeline.EventPhaseFinished, Phase: "analyze", Time: time.Now()})
+
+	out := buf.String()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("plain output contains escape sequences: %q", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want a line at phase start and end:\n%s", len(lines), out)
+	}
+	if !strings.HasPrefix(lines[0], "analyze: 0/4 SIGs done") || !strings.HasSuffix(lines[1], "finished") {
+		t.Errorf("unexpected status lines:\n%s", out)
+	}
+
+	if _, err := r.Write([]byte("log line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), "finished\nlog line\n") {
+		t.Errorf("plain Write should pass logs through, got %q", buf.String())
+	}
+}
+
+func TestRenderer_TTYRedrawsAroundLogs(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRenderer(&buf, true)
+	feed(r, time.Now())
+	r.refresh()
+
+	out := buf.String()
+	if !strings.Contains(out, "collector") || !strings.Contains(out, pipeline.StateSummarizing) {
+		t.Errorf("status block should list active SIGs, got %q", out)
+	}
+	if strings.Contains(out, "go-sdk") {
+		t.Errorf("status block should omit finished SIGs, got %q", out)
+	}
+	if r.lines != 3 {
+		t.Errorf("block lines = %d, want status plus 2 active SIGs", r.lines)
+	}
+
+	buf.Reset()
+	if _, err := r.Write([]byte("level=INFO msg=hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	out = buf.String()
+	if !strings.HasPrefix(out, "\x1b[3F\x1b[J") {
+		t.Errorf("log write should first erase the block, got %q", out)
+	}
+	if i, j := strings.Index(out, "msg=hello"), strings.Index(out, "analyze:"); i < 0 || j < i {
+		t.Errorf("log line should be followed by the redrawn block, got %q", out)
+	}
+}
@@ -0,0 +1,205 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+)
+
+const (
+	// ttyInterval is how often the terminal view is redrawn.
+	ttyInterval = 200 * time.Millisecond
+	// plainInterval is how often a status line is printed without a terminal.
+	plainInterval = 15 * time.Second
+	// maxActiveLines bounds the per-SIG lines of the terminal view.
+	maxActiveLines = 8
+)
+
+// Renderer displays a run's progress. On a terminal it redraws a status
+// block in place, listing the SIGs being worked on; otherwise it prints a
+// status line when a phase starts or ends and periodically in between.
+type Renderer struct {
+	w        io.Writer
+	tty      bool
+	interval time.Duration
+	tracker  *Tracker
+
+	mu    sync.Mutex
+	lines int // lines of the status block currently on screen
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewRenderer returns a renderer writing to w, drawing in place if tty.
+func NewRenderer(w io.Writer, tty bool) *Renderer {
+	interval := plainInterval
+	if tty {
+		interval = ttyInterval
+	}
+	return &Renderer{w: w, tty: tty, interval: interval, tracker: NewTracker()}
+}
+
+// IsTerminal reports whether f is a character device such as a terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Tracker returns the tracker behind the display.
+func (r *Renderer) Tracker() *Tracker {
+	return r.tracker
+}
+
+// OnEvent implements pipeline.Observer.
+func (r *Renderer) OnEvent(e pipeline.Event) {
+	r.tracker.OnEvent(e)
+	if !r.tty && (e.Type == pipeline.EventPhaseStarted || e.Type == pipeline.EventPhaseFinished) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		fmt.Fprintln(r.w, StatusLine(r.tracker.Snapshot()))
+	}
+}
+
+// Start begins refreshing the display until Stop is called.
+func (r *Renderer) Start() {
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.refresh()
+			}
+		}
+	}()
+}
+
+// Stop stops refreshing and leaves the final state on screen.
+func (r *Renderer) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+	r.stop = nil
+	if r.tty {
+		r.refresh()
+	}
+}
+
+// Write writes p, typically a log record, above the status block so the
+// two do not garble each other.
+func (r *Renderer) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.tty {
+		return r.w.Write(p)
+	}
+	r.clear()
+	n, err := r.w.Write(p)
+	r.draw(r.tracker.Snapshot())
+	return n, err
+}
+
+// refresh redraws the status block, or prints a status line without a
+// terminal.
+func (r *Renderer) refresh() {
+	snap := r.tracker.Snapshot()
+	if snap.Phase == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.tty {
+		if !snap.Finished {
+			fmt.Fprintln(r.w, StatusLine(snap))
+		}
+		return
+	}
+	r.clear()
+	r.draw(snap)
+}
+
+// clear erases the status block.
+func (r *Renderer) clear() {
+	if r.lines > 0 {
+		// Move to the start of the block's first line and erase to the end
+		// of the screen.
+		fmt.Fprintf(r.w, "\x1b[%dF\x1b[J", r.lines)
+		r.lines = 0
+	}
+}
+
+// draw writes the status block for snap.
+func (r *Renderer) draw(snap Snapshot) {
+	if snap.Phase == "" {
+		return
+	}
+	lines := []string{StatusLine(snap)}
+	var active []SIGProgress
+	for _, s := range snap.SIGs {
+		if !s.Finished() {
+			active = append(active, s)
+		}
+	}
+	for i, s := range active {
+		if i == maxActiveLines {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(active)-maxActiveLines))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("  %-32s %s", s.ID, s.State))
+	}
+	for _, l := range lines {
+		fmt.Fprintln(r.w, l)
+	}
+	r.lines = len(lines)
+}
+
+// StatusLine summarizes snap on one line, e.g.
+//
+//	analyze: 12/40 SIGs done, 1 failed, 4 active | 152,300 tokens ($0.46) | 2m3s elapsed, ETA 3m10s
+func StatusLine(snap Snapshot) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %d/%d SIGs done", snap.Phase, snap.Done, snap.Total)
+	if snap.Failed > 0 {
+		fmt.Fprintf(&b, ", %d failed", snap.Failed)
+	}
+	if snap.Active > 0 {
+		fmt.Fprintf(&b, ", %d active", snap.Active)
+	}
+	if snap.Tokens > 0 {
+		fmt.Fprintf(&b, " | %s tokens ($%.2f)", groupDigits(snap.Tokens), snap.CostUSD)
+	}
+	fmt.Fprintf(&b, " | %s elapsed", snap.Elapsed.Round(time.Second))
+	switch {
+	case snap.Finished:
+		b.WriteString(", finished")
+	case snap.ETA > 0:
+		fmt.Fprintf(&b, ", ETA %s", snap.ETA.Round(time.Second))
+	}
+	return b.String()
+}
+
+// groupDigits formats n with comma thousands separators.
+func groupDigits(n int) string {
+	s := strconv.Itoa(n)
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+// Package progress turns pipeline events into a live view of a run, drawn
+// on a terminal or printed as periodic status lines.
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+)
+
+// SIGProgress is the latest state of one SIG in the current phase.
+type SIGProgress struct {
+	ID      string
+	State   string
+	Err     string
+	Updated time.Time
+}
+
+// Finished reports whether the SIG is done with the current phase.
+func (s SIGProgress) Finished() bool {
+	switch s.State {
+	case pipeline.StateDone, pipeline.StateFailed, pipeline.StateSkipped:
+		return true
+	}
+	return false
+}
+
+// Snapshot is the progress of a run at one point in time.
+type Snapshot struct {
+	// Phase is the current or last phase, "fetch" or "analyze".
+	Phase string
+	// Total is the number of SIGs in the phase; Done counts those finished
+	// successfully or skipped, Failed those that failed, and Active those
+	// being worked on.
+	Total  int
+	Done   int
+	Failed int
+	Active int
+	// SIGs lists the SIGs seen in the phase, in the order they started.
+	SIGs []SIGProgress
+	// Tokens and CostUSD total the LLM usage of the whole run.
+	Tokens  int
+	CostUSD float64
+	// Elapsed is the time spent in the phase. ETA estimates the time left
+	// from the average pace so far; it is zero until a SIG has finished.
+	Elapsed time.Duration
+	ETA     time.Duration
+	// Finished is set once the phase has ended.
+	Finished bool
+}
+
+// Tracker aggregates pipeline events into a Snapshot. It implements
+// pipeline.Observer and is safe for concurrent use, so any frontend can
+// subscribe one and poll it.
+type Tracker struct {
+	mu         sync.Mutex
+	phase      string
+	phaseStart time.Time
+	phaseEnd   time.Time
+	total      int
+	sigs       map[string]*SIGProgress
+	order      []string
+	tokens     int
+	cost       float64
+	now        func() time.Time
+}
+
+// NewTracker returns an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{sigs: make(map[string]*SIGProgress), now: time.Now}
+}
+
+// OnEvent applies e to the tracked state.
+func (t *Tracker) OnEvent(e pipeline.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch e.Type {
+	case pipeline.EventPhaseStarted:
+		t.phase = e.Phase
+		t.phaseStart = e.Time
+		t.phaseEnd = time.Time{}
+		t.total = e.Total
+		t.sigs = make(map[string]*SIGProgress)
+		t.order = nil
+	case pipeline.EventPhaseFinished:
+		t.phaseEnd = e.Time
+	case pipeline.EventSIGState:
+		s, ok := t.sigs[e.SIGID]
+		if !ok {
+			s = &SIGProgress{ID: e.SIGID}
+			t.sigs[e.SIGID] = s
+			t.order = append(t.order, e.SIGID)
+		}
+		s.State = e.State
+		s.Updated = e.Time
+		if e.Err != nil {
+			s.Err = e.Err.Error()
+		}
+	case pipeline.EventLLMUsage:
+		t.tokens += e.Tokens
+		t.cost += e.CostUSD
+	}
+}
+
+// Snapshot returns the current progress.
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snap := Snapshot{
+		Phase:    t.phase,
+		Total:    t.total,
+		Tokens:   t.tokens,
+		CostUSD:  t.cost,
+		Finished: !t.phaseEnd.IsZero(),
+	}
+	for _, id := range t.order {
+		s := *t.sigs[id]
+		snap.SIGs = append(snap.SIGs, s)
+		switch {
+		case s.State == pipeline.StateFailed:
+			snap.Failed++
+		case s.Finished():
+			snap.Done++
+		default:
+			snap.Active++
+		}
+	}
+	// Keep SIGs that are still being worked on at the end of the list, in
+	// the order they started, so a redrawn view stays stable.
+	sort.SliceStable(snap.SIGs, func(a, b int) bool {
+		return snap.SIGs[a].Finished() && !snap.SIGs[b].Finished()
+	})
+
+	if t.phaseStart.IsZero() {
+		return snap
+	}
+	end := t.now()
+	if snap.Finished {
+		end = t.phaseEnd
+	}
+	snap.Elapsed = end.Sub(t.phaseStart)
+	if finished := snap.Done + snap.Failed; finished > 0 && finished < snap.Total && !snap.Finished {
+		snap.ETA = snap.Elapsed / time.Duration(finished) * time.Duration(snap.Total-finished)
+	}
+	return snap
+}
@@ -0,0 +1,207 @@
+// Package publish posts rendered reports to external destinations such as
+// Slack channels.
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
+)
+
+const (
+	slackAPIBase = "https://slack.com/api"
+	// maxRateLimitRetries bounds how often a rate-limited post is retried.
+	maxRateLimitRetries = 3
+)
+
+// SlackPublisher posts Block Kit messages to Slack, either with a bot token
+// through chat.postMessage or through an incoming webhook. Only the bot
+// token can thread replies under the parent message; an incoming webhook
+// posts them to the channel in order.
+type SlackPublisher struct {
+	token      string
+	channel    string
+	webhookURL string
+	apiBase    string
+	client     *http.Client
+}
+
+// NewSlackBotPublisher creates a publisher that posts to channel (a name
+// such as #otel-digest or a channel ID) using a bot token with the
+// chat:write scope.
+func NewSlackBotPublisher(token, channel string) *SlackPublisher {
+	return &SlackPublisher{
+		token:   token,
+		channel: channel,
+		apiBase: slackAPIBase,
+		client:  newHTTPClient(),
+	}
+}
+
+// NewSlackWebhookPublisher creates a publisher that posts to an incoming
+// webhook URL.
+func NewSlackWebhookPublisher(webhookURL string) *SlackPublisher {
+	return &SlackPublisher{
+		webhookURL: webhookURL,
+		client:     newHTTPClient(),
+	}
+}
+
+// ErrNoSlackTarget is returned by NewSlackPublisher when neither a bot
+// token with a channel nor a webhook URL is configured.
+var ErrNoSlackTarget = errors.New("set a bot token and channel, or an incoming webhook URL")
+
+// NewSlackPublisher picks the bot token publisher when token is set and the
+// webhook publisher otherwise.
+func NewSlackPublisher(token, channel, webhookURL string) (*SlackPublisher, error) {
+	switch {
+	case token != "":
+		if channel == "" {
+			return nil, errors.New("a channel is required when publishing with a bot token")
+		}
+		return NewSlackBotPublisher(token, channel), nil
+	case webhookURL != "":
+		return NewSlackWebhookPublisher(webhookURL), nil
+	}
+	return nil, ErrNoSlackTarget
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: telemetry.Transport(nil, "publish"),
+	}
+}
+
+// SetAPIBase replaces the Slack Web API base URL, e.g. to target a test
+// server.
+func (p *SlackPublisher) SetAPIBase(url string) {
+	p.apiBase = strings.TrimRight(url, "/")
+}
+
+// Publish posts parent and then each reply. With a bot token the replies are
+// threaded under the parent and the parent's message timestamp is returned;
+// with a webhook the timestamp is empty.
+func (p *SlackPublisher) Publish(ctx context.Context, parent report.SlackMessage, replies []report.SlackMessage) (string, error) {
+	if p.webhookURL != "" {
+		for i, msg := range append([]report.SlackMessage{parent}, replies...) {
+			if err := p.postWebhook(ctx, msg); err != nil {
+				return "", fmt.Errorf("posting message %d: %w", i+1, err)
+			}
+		}
+		return "", nil
+	}
+
+	resp, err := p.postMessage(ctx, p.channel, "", parent)
+	if err != nil {
+		return "", fmt.Errorf("posting digest: %w", err)
+	}
+	// Reply in the channel ID Slack resolved, which thread_ts requires.
+	for i, msg := range replies {
+		if _, err := p.postMessage(ctx, resp.Channel, resp.TS, msg); err != nil {
+			return resp.TS, fmt.Errorf("posting thread reply %d: %w", i+1, err)
+		}
+	}
+	return resp.TS, nil
+}
+
+// postMessageRequest is the chat.postMessage request body.
+type postMessageRequest struct {
+	Channel     string              `json:"channel"`
+	ThreadTS    string              `json:"thread_ts,omitempty"`
+	Text        string              `json:"text"`
+	Blocks      []report.SlackBlock `json:"blocks"`
+	UnfurlLinks bool                `json:"unfurl_links"`
+	UnfurlMedia bool                `json:"unfurl_media"`
+}
+
+// postMessageResponse is the chat.postMessage response envelope.
+type postMessageResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+func (p *SlackPublisher) postMessage(ctx context.Context, channel, threadTS string, msg report.SlackMessage) (*postMessageResponse, error) {
+	body, err := json.Marshal(postMessageRequest{
+		Channel:  channel,
+		ThreadTS: threadTS,
+		Text:     msg.Text,
+		Blocks:   msg.Blocks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.post(ctx, p.apiBase+"/chat.postMessage", body, "Bearer "+p.token)
+	if err != nil {
+		return nil, err
+	}
+	var resp postMessageResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("slack API error: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+func (p *SlackPublisher) postWebhook(ctx context.Context, msg report.SlackMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = p.post(ctx, p.webhookURL, body, "")
+	return err
+}
+
+// post sends a JSON body to url and returns the response body, waiting out
+// rate limits as instructed by Retry-After.
+func (p *SlackPublisher) post(ctx context.Context, url string, body []byte, auth string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			wait, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+			select {
+			case <-time.After(time.Duration(max(wait, 1)) * time.Second):
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if resp.StatusCode >= 300 {
+			return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+		}
+		return data, nil
+	}
+}
@@ -0,0 +1,161 @@
+package publish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+)
+
+// fakeSlack is a minimal chat.postMessage and incoming webhook server.
+type fakeSlack struct {
+	mu        sync.Mutex
+	posts     []postMessageRequest
+	auth      []string
+	rateLimit int // respond 429 to this many requests first
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.rateLimit > 0 {
+		f.rateLimit--
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	var req postMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	f.posts = append(f.posts, req)
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+	switch r.URL.Path {
+	case "/webhook":
+		_, _ = w.Write([]byte("ok"))
+	case "/api/chat.postMessage":
+		if r.Header.Get("Authorization") != "Bearer xoxb-test" {
+			_ = json.NewEncoder(w).Encode(postMessageResponse{Error: "invalid_auth"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(postMessageResponse{
+			OK:      true,
+			Channel: "C123",
+			TS:      "1700000000." + strconv.Itoa(len(f.posts)),
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func testMessages() (report.SlackMessage, []report.SlackMessage) {
+	section := func(text string) report.SlackBlock {
+		return report.SlackBlock{Type: "section", Text: &report.SlackText{Type: "mrkdwn", Text: text}}
+	}
+	parent := report.SlackMessage{Text: "digest", Blocks: []report.SlackBlock{section("*Top Takeaways*")}}
+	replies := []report.SlackMessage{
+		{Text: "Collector", Blocks: []report.SlackBlock{section("*Collector*")}},
+		{Text: "Java SDK", Blocks: []report.SlackBlock{section("*Java SDK*")}},
+	}
+	return parent, replies
+}
+
+func TestSlackPublisher_BotThreadsReplies(t *testing.T) {
+	fake := &fakeSlack{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	p := NewSlackBotPublisher("xoxb-test", "#otel-digest")
+	p.SetAPIBase(srv.URL + "/api/")
+	parent, replies := testMessages()
+
+	ts, err := p.Publish(context.Background(), parent, replies)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if ts != "1700000000.1" {
+		t.Errorf("ts = %q, want the parent's timestamp", ts)
+	}
+	if len(fake.posts) != 3 {
+		t.Fatalf("got %d posts, want 3", len(fake.posts))
+	}
+	if first := fake.posts[0]; first.Channel != "#otel-digest" || first.ThreadTS != "" || first.Blocks[0].Text.Text != "*Top Takeaways*" {
+		t.Errorf("parent post = %+v, want the takeaways in #otel-digest", first)
+	}
+	for i, post := range fake.posts[1:] {
+		if post.Channel != "C123" || post.ThreadTS != "1700000000.1" {
+			t.Errorf("reply %d = channel %q thread %q, want a reply in C123's thread", i, post.Channel, post.ThreadTS)
+		}
+		if post.Text != replies[i].Text {
+			t.Errorf("reply %d text = %q, want %q", i, post.Text, replies[i].Text)
+		}
+	}
+	for _, auth := range fake.auth {
+		if auth != "Bearer xoxb-test" {
+			t.Errorf("Authorization = %q, want the bot token", auth)
+		}
+	}
+}
+
+func TestSlackPublisher_APIError(t *testing.T) {
+	srv := httptest.NewServer(&fakeSlack{})
+	defer srv.Close()
+
+	p := NewSlackBotPublisher("xoxb-wrong", "#otel-digest")
+	p.SetAPIBase(srv.URL + "/api")
+	parent, replies := testMessages()
+
+	if _, err := p.Publish(context.Background(), parent, replies); err == nil {
+		t.Fatal("expected an error for a rejected token")
+	}
+}
+
+func TestSlackPublisher_Webhook(t *testing.T) {
+	fake := &fakeSlack{rateLimit: 1}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	p := NewSlackWebhookPublisher(srv.URL + "/webhook")
+	parent, replies := testMessages()
+
+	ts, err := p.Publish(context.Background(), parent, replies)
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if ts != "" {
+		t.Errorf("ts = %q, want none for a webhook", ts)
+	}
+	if len(fake.posts) != 3 {
+		t.Fatalf("got %d posts, want 3 after the rate limit retry", len(fake.posts))
+	}
+	want := []string{"digest", "Collector", "Java SDK"}
+	for i, post := range fake.posts {
+		if post.Text != want[i] || post.ThreadTS != "" || fake.auth[i] != "" {
+			t.Errorf("post %d = %+v, want %q without thread or auth", i, post, want[i])
+		}
+	}
+}
+
+func TestNewSlackPublisher(t *testing.T) {
+	if p, err := NewSlackPublisher("xoxb", "#c", "https://hooks.example"); err != nil || p.token != "xoxb" {
+		t.Errorf("token and webhook: got %+v, %v; want the bot publisher", p, err)
+	}
+	if p, err := NewSlackPublisher("", "", "https://hooks.example"); err != nil || p.webhookURL == "" {
+		t.Errorf("webhook only: got %+v, %v; want the webhook publisher", p, err)
+	}
+	if _, err := NewSlackPublisher("xoxb", "", ""); err == nil {
+		t.Error("expected an error for a bot token without a channel")
+	}
+	if _, err := NewSlackPublisher("", "", ""); err != ErrNoSlackTarget {
+		t.Errorf("err = %v, want ErrNoSlackTarget", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 )
 
 const (
@@ -23,7 +24,7 @@ type Fetcher struct {
 // NewFetcher creates a new registry Fetcher.
 func NewFetcher() *Fetcher {
 	return &Fetcher{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: telemetry.Transport(nil, "registry")},
 	}
 }
 
@@ -155,6 +156,25 @@ func NormalizeSIGID(name string) string {
 	return s
 }
 
+// MatchSIGFilter reports whether the SIG sigID is selected by filter, a list
+// of SIG names or IDs. Each entry is normalized with NormalizeSIGID and
+// matches that ID or any ID it is a prefix of up to a dash, which handles
+// registry names that include parenthetical descriptions like
+// "communications-(website-documentation-etc)". An empty filter selects
+// every SIG.
+func MatchSIGFilter(sigID string, filter []string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, f := range filter {
+		id := NormalizeSIGID(f)
+		if sigID == id || strings.HasPrefix(sigID, id+"-") {
+			return true
+		}
+	}
+	return false
+}
+
 // splitTableRow splits a markdown table row into cells.
 func splitTableRow(line string) []string {
 	line = strings.Trim(line, "|")
@@ -101,6 +101,27 @@ func TestNormalizeSIGID(t *testing.T) {
 	}
 }
 
+func TestMatchSIGFilter(t *testing.T) {
+	tests := []struct {
+		sigID  string
+		filter []string
+		want   bool
+	}{
+		{"collector", nil, true},
+		{"collector", []string{"Collector"}, true},
+		{"java-sdk-plus-instrumentation", []string{"java"}, true},
+		{"java-sdk-plus-instrumentation", []string{"Java: SDK"}, true},
+		{"javascript-sdk", []string{"java"}, false},
+		{"collector", []string{"go", "java"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchSIGFilter(tt.sigID, tt.filter); got != tt.want {
+			t.Errorf("MatchSIGFilter(%q, %q) = %v, want %v", tt.sigID, tt.filter, got, tt.want)
+		}
+	}
+}
+
 func TestMatchSheetNameToSIG(t *testing.T) {
 	tests := []struct {
 		input string
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxSlugLen bounds the length of slugs, which are used in file names.
+const maxSlugLen = 60
+
+// Slugify turns a topic or name into lowercase ASCII letters and digits
+// separated by single hyphens, for use in file names and identifiers.
+func Slugify(s string) string {
+	var sb strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if hyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			hyphen = false
+			if sb.Len() >= maxSlugLen {
+				break
+			}
+			continue
+		}
+		hyphen = true
+	}
+	return sb.String()
+}
+
+// DigestWeek names the ISO week of a digest's end date (YYYY-MM-DD), e.g.
+// "2026-W08", falling back to the stored end time when the date does not
+// parse.
+func DigestWeek(end string, fallback time.Time) string {
+	t, err := time.Parse("2006-01-02", end)
+	if err != nil {
+		t = fallback
+	}
+	year, w := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, w)
+}
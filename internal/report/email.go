@@ -0,0 +1,93 @@
+package report
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+var digestEmailTemplate = template.Must(template.New("digest_email.html").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"badgeStyle": func() template.CSS {
+		return "display:inline-block;padding:0 6px;border-radius:3px;background:#e8f0fe;color:#1a56db;font-size:12px;font-weight:600;"
+	},
+}).ParseFS(templateFS, "templates/digest_email.html"))
+
+// emailDigest is the view of a digest passed to the email template.
+type emailDigest struct {
+	Title       string
+	Generated   string
+	PreviousEnd string
+	Active      int
+	Quiet       int
+	Takeaways   []emailItem
+	SIGs        []emailSIG
+	QuietNames  []string
+	Themes      []template.HTML
+}
+
+type emailItem struct {
+	SIG    string
+	Status analysis.ItemStatus
+	Text   template.HTML
+}
+
+type emailSIG struct {
+	Name          string
+	Items         []emailItem
+	Ongoing       []string
+	NotesLink     string
+	RecordingLink string
+	SlackChannel  string
+}
+
+// RenderDigestEmailHTML renders a weekly digest as an HTML email body. It
+// uses inline styles only and no scripts, which mail clients strip.
+func RenderDigestEmailHTML(digest *analysis.DigestReport) (string, error) {
+	active, quiet := partitionSIGs(deduplicateDigestSIGs(digest.SIGReports))
+	view := emailDigest{
+		Title:       "OTel Weekly Digest — " + formatDateRange(digest.DateRangeStart, digest.DateRangeEnd),
+		Generated:   time.Now().UTC().Format("2006-01-02 15:04 UTC"),
+		PreviousEnd: digest.PreviousDigestEnd,
+		Active:      len(active),
+		Quiet:       len(quiet),
+	}
+	for _, t := range topTakeaways(active) {
+		view.Takeaways = append(view.Takeaways, emailItem{SIG: t.sigName, Status: badgeStatus(t.status), Text: ItemHTML(t.item)})
+	}
+	for _, sr := range active {
+		rr := sr.RelevanceReport
+		hs := emailSIG{
+			Name:          sr.SIGName,
+			NotesLink:     sr.NotesLink,
+			RecordingLink: sr.RecordingLink,
+			SlackChannel:  sr.SlackChannel,
+		}
+		for _, level := range [][]string{rr.HighItems, rr.MediumItems, rr.LowItems} {
+			for _, item := range level {
+				hs.Items = append(hs.Items, emailItem{Status: badgeStatus(rr.ItemStatuses[item]), Text: ItemHTML(item)})
+			}
+		}
+		for _, item := range rr.OngoingItems {
+			hs.Ongoing = append(hs.Ongoing, analysis.ItemTopic(item))
+		}
+		view.SIGs = append(view.SIGs, hs)
+	}
+	for _, sr := range quiet {
+		view.QuietNames = append(view.QuietNames, sr.SIGName)
+	}
+	for _, para := range strings.Split(digest.CrossSIGThemes, "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			view.Themes = append(view.Themes, inlineHTML(para))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := digestEmailTemplate.Execute(&buf, view); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,242 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// slackWorkspaceURL is the CNCF Slack workspace SIG channels live in.
+const slackWorkspaceURL = "https://cloud-native.slack.com"
+
+var digestHTMLTemplate = template.Must(template.New("digest.html").Funcs(template.FuncMap{
+	"join":   strings.Join,
+	"lower":  strings.ToLower,
+	"tokens": formatTokens,
+}).ParseFS(templateFS, "templates/digest.html"))
+
+// HTMLGenerator writes self-contained HTML reports to disk.
+type HTMLGenerator struct {
+	outputDir string
+}
+
+// NewHTMLGenerator creates a new HTMLGenerator that writes to outputDir.
+func NewHTMLGenerator(outputDir string) *HTMLGenerator {
+	return &HTMLGenerator{outputDir: outputDir}
+}
+
+// GenerateDigestReport generates a weekly digest HTML page and returns the file path.
+func (g *HTMLGenerator) GenerateDigestReport(digest *analysis.DigestReport) (string, error) {
+	if err := os.MkdirAll(g.outputDir, 0o755); err != nil {
+		return "", fmt.Errorf("creating output directory: %w", err)
+	}
+
+	page, err := RenderDigestHTML(digest)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(g.outputDir, digestHTMLFilename(digest.DateRangeEnd))
+	if err := os.WriteFile(filePath, []byte(page), 0o644); err != nil {
+		return "", fmt.Errorf("writing HTML digest: %w", err)
+	}
+	return filePath, nil
+}
+
+// htmlPage is the view of a digest passed to the HTML page template.
+type htmlPage struct {
+	Home        string // link back to the archive index, if any
+	Title       string
+	Generated   string
+	PreviousEnd string
+	Active      int
+	Quiet       int
+	Takeaways   []htmlItem
+	SIGs        []htmlSIG
+	QuietNames  []string
+	Themes      []template.HTML
+	Rows        []htmlStatsRow
+	Stats       *analysis.RunStats
+}
+
+type htmlItem struct {
+	SIG    string
+	Anchor string
+	Level  string // "HIGH", "MEDIUM" or "LOW"
+	Status analysis.ItemStatus
+	Text   template.HTML
+}
+
+type htmlSIG struct {
+	Name              string
+	Anchor            string
+	High, Medium, Low int
+	Items             []htmlItem
+	Ongoing           []string
+	NotesLink         string
+	RecordingLink     string
+	SlackChannel      string
+	SlackLink         string
+}
+
+type htmlStatsRow struct {
+	SIG, Notes, Video, Slack, Status string
+	High, Medium, Low                int
+}
+
+// RenderDigestHTML renders a weekly digest as a self-contained HTML page
+// with a table of contents, relevance badges, collapsible per-SIG sections
+// and a sortable processing stats table. Styles and scripts are inline.
+func RenderDigestHTML(digest *analysis.DigestReport) (string, error) {
+	return RenderArchiveDigestHTML(digest, "")
+}
+
+// RenderArchiveDigestHTML renders digest like RenderDigestHTML, with a link
+// to home at the top of the contents when home is not empty. It is used for
+// digest pages of the static site archive.
+func RenderArchiveDigestHTML(digest *analysis.DigestReport, home string) (string, error) {
+	deduped := deduplicateDigestSIGs(digest.SIGReports)
+	active, quiet := partitionSIGs(deduped)
+	page := htmlPage{
+		Home:        home,
+		Title:       "OTel Weekly Digest — " + formatDateRange(digest.DateRangeStart, digest.DateRangeEnd),
+		Generated:   time.Now().UTC().Format("2006-01-02 15:04 UTC"),
+		PreviousEnd: digest.PreviousDigestEnd,
+		Active:      len(active),
+		Quiet:       len(quiet),
+		Stats:       digest.Stats,
+	}
+
+	anchors := make(map[string]string, len(active))
+	for _, sr := range active {
+		anchors[sr.SIGName] = SIGAnchor(sr)
+	}
+	for _, t := range topTakeaways(active) {
+		page.Takeaways = append(page.Takeaways, htmlItem{
+			SIG:    t.sigName,
+			Anchor: anchors[t.sigName],
+			Level:  "HIGH",
+			Status: badgeStatus(t.status),
+			Text:   ItemHTML(t.item),
+		})
+	}
+
+	for _, sr := range active {
+		rr := sr.RelevanceReport
+		hs := htmlSIG{
+			Name:          sr.SIGName,
+			Anchor:        anchors[sr.SIGName],
+			High:          len(rr.HighItems),
+			Medium:        len(rr.MediumItems),
+			Low:           len(rr.LowItems),
+			NotesLink:     sr.NotesLink,
+			RecordingLink: sr.RecordingLink,
+			SlackChannel:  sr.SlackChannel,
+			SlackLink:     slackChannelURL(sr.SlackChannel),
+		}
+		for _, level := range []struct {
+			name  string
+			items []string
+		}{{"HIGH", rr.HighItems}, {"MEDIUM", rr.MediumItems}, {"LOW", rr.LowItems}} {
+			for _, item := range level.items {
+				hs.Items = append(hs.Items, htmlItem{Level: level.name, Status: badgeStatus(rr.ItemStatuses[item]), Text: ItemHTML(item)})
+			}
+		}
+		for _, item := range rr.OngoingItems {
+			hs.Ongoing = append(hs.Ongoing, analysis.ItemTopic(item))
+		}
+		page.SIGs = append(page.SIGs, hs)
+	}
+
+	for _, sr := range quiet {
+		page.QuietNames = append(page.QuietNames, sr.SIGName)
+	}
+	for _, para := range strings.Split(digest.CrossSIGThemes, "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			page.Themes = append(page.Themes, inlineHTML(para))
+		}
+	}
+
+	for _, sr := range deduped {
+		row := htmlStatsRow{
+			SIG:    sr.SIGName,
+			Notes:  sourceStatus("notes", sr.SourcesUsed, sr.SourcesMissing),
+			Video:  sourceStatus("video", sr.SourcesUsed, sr.SourcesMissing),
+			Slack:  sourceStatus("slack", sr.SourcesUsed, sr.SourcesMissing),
+			Status: sigStatus(sr),
+		}
+		if rr := sr.RelevanceReport; rr != nil {
+			row.High, row.Medium, row.Low = len(rr.HighItems), len(rr.MediumItems), len(rr.LowItems)
+		}
+		page.Rows = append(page.Rows, row)
+	}
+
+	var buf bytes.Buffer
+	if err := digestHTMLTemplate.Execute(&buf, page); err != nil {
+		return "", fmt.Errorf("rendering HTML digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// SIGAnchor returns the fragment ID of a SIG's section.
+func SIGAnchor(sr *analysis.SIGReport) string {
+	id := sr.SIGID
+	if id == "" {
+		id = normalizeSIGName(sr.SIGName)
+	}
+	return "sig-" + strings.ToLower(strings.ReplaceAll(id, " ", "-"))
+}
+
+// slackChannelURL returns a link that opens a channel, given by name, in
+// the CNCF Slack workspace.
+func slackChannelURL(channel string) string {
+	if channel == "" {
+		return ""
+	}
+	return slackWorkspaceURL + "/app_redirect?channel=" + url.QueryEscape(strings.TrimPrefix(channel, "#"))
+}
+
+// badgeStatus returns the status shown as a badge: NEW and UPDATED only,
+// matching statusBadge.
+func badgeStatus(status analysis.ItemStatus) analysis.ItemStatus {
+	switch status {
+	case analysis.ItemNew, analysis.ItemUpdated:
+		return status
+	}
+	return ""
+}
+
+// ItemHTML renders a relevance item with its bold topic as HTML.
+func ItemHTML(item string) template.HTML {
+	return inlineHTML(ensureBoldTopic(item))
+}
+
+// inlineHTML converts the inline Markdown used in reports to HTML: the text
+// is escaped, then **bold** and [text](url) are turned into tags.
+func inlineHTML(md string) template.HTML {
+	s := html.EscapeString(md)
+	s = mdBoldPattern.ReplaceAllString(s, "<strong>$1</strong>")
+	s = mdLinkPattern.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	return template.HTML(s)
+}
+
+// digestHTMLFilename generates a filename like "2026-02-19-weekly-digest.html".
+func digestHTMLFilename(dateEnd string) string {
+	date := dateEnd
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	return fmt.Sprintf("%s-weekly-digest.html", date)
+}
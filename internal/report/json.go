@@ -39,12 +39,14 @@ type jsonSIGReport struct {
 
 // jsonRelevance is the JSON-serializable form of a relevance report.
 type jsonRelevance struct {
-	Report      string   `json:"report"`
-	HighItems   []string `json:"high_items"`
-	MediumItems []string `json:"medium_items"`
-	LowItems    []string `json:"low_items"`
-	Model       string   `json:"model"`
-	TokensUsed  int      `json:"tokens_used"`
+	Report       string            `json:"report"`
+	HighItems    []string          `json:"high_items"`
+	MediumItems  []string          `json:"medium_items"`
+	LowItems     []string          `json:"low_items"`
+	ItemStatuses map[string]string `json:"item_statuses,omitempty"`
+	OngoingItems []string          `json:"ongoing_items,omitempty"`
+	Model        string            `json:"model"`
+	TokensUsed   int               `json:"tokens_used"`
 }
 
 // jsonRunStats is the JSON-serializable form of run statistics.
@@ -57,6 +59,29 @@ type jsonRunStats struct {
 	SIGsWithData     int     `json:"sigs_with_data"`
 	DurationSeconds  float64 `json:"duration_seconds"`
 	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+
+	Stages        []jsonStageUsage    `json:"stages,omitempty"`
+	Fallbacks     []jsonFallbackUsage `json:"fallbacks,omitempty"`
+	BudgetSkipped []string            `json:"budget_skipped,omitempty"`
+}
+
+// jsonFallbackUsage is the JSON-serializable form of calls answered by a
+// fallback provider.
+type jsonFallbackUsage struct {
+	Stage    string `json:"stage"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Calls    int    `json:"calls"`
+}
+
+// jsonStageUsage is the JSON-serializable form of one stage's LLM usage.
+type jsonStageUsage struct {
+	Stage            string  `json:"stage"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	Calls            int     `json:"calls"`
+	TokensUsed       int     `json:"tokens_used"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
 }
 
 // jsonDigestReport is the JSON-serializable form of a digest report.
@@ -67,6 +92,7 @@ type jsonDigestReport struct {
 	SIGReports     []*jsonSIGReport `json:"sig_reports"`
 	CrossSIGThemes string           `json:"cross_sig_themes,omitempty"`
 	Stats          *jsonRunStats    `json:"stats,omitempty"`
+	PreviousDigest string           `json:"previous_digest_end,omitempty"`
 	GeneratedAt    string           `json:"generated_at"`
 }
 
@@ -99,11 +125,30 @@ func (g *JSONGenerator) GenerateDigestReport(digest *analysis.DigestReport) (str
 		return "", fmt.Errorf("creating output directory: %w", err)
 	}
 
+	data, err := MarshalDigest(digest)
+	if err != nil {
+		return "", err
+	}
+
+	filename := digestJSONFilename(digest.DateRangeEnd)
+	filePath := filepath.Join(g.outputDir, filename)
+
+	if err := os.WriteFile(filePath, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing digest report JSON: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// MarshalDigest encodes a digest report in the same JSON form written by
+// GenerateDigestReport.
+func MarshalDigest(digest *analysis.DigestReport) ([]byte, error) {
 	jd := &jsonDigestReport{
 		DateRangeStart: digest.DateRangeStart,
 		DateRangeEnd:   digest.DateRangeEnd,
 		SIGCount:       len(digest.SIGReports),
 		CrossSIGThemes: digest.CrossSIGThemes,
+		PreviousDigest: digest.PreviousDigestEnd,
 		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
 	}
 
@@ -117,6 +162,13 @@ func (g *JSONGenerator) GenerateDigestReport(digest *analysis.DigestReport) (str
 			SIGsWithData:     digest.Stats.SIGsWithData,
 			DurationSeconds:  digest.Stats.DurationSeconds,
 			EstimatedCostUSD: digest.Stats.EstimatedCostUSD,
+			BudgetSkipped:    digest.Stats.BudgetSkipped,
+		}
+		for _, su := range digest.Stats.Stages {
+			jd.Stats.Stages = append(jd.Stats.Stages, jsonStageUsage(su))
+		}
+		for _, fu := range digest.Stats.Fallbacks {
+			jd.Stats.Fallbacks = append(jd.Stats.Fallbacks, jsonFallbackUsage(fu))
 		}
 	}
 
@@ -126,17 +178,85 @@ func (g *JSONGenerator) GenerateDigestReport(digest *analysis.DigestReport) (str
 
 	data, err := json.MarshalIndent(jd, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("marshaling digest report to JSON: %w", err)
+		return nil, fmt.Errorf("marshaling digest report to JSON: %w", err)
 	}
+	return data, nil
+}
 
-	filename := digestJSONFilename(digest.DateRangeEnd)
-	filePath := filepath.Join(g.outputDir, filename)
+// UnmarshalDigest decodes a digest report written by MarshalDigest, such as
+// the payload of a stored report.
+func UnmarshalDigest(data []byte) (*analysis.DigestReport, error) {
+	var jd jsonDigestReport
+	if err := json.Unmarshal(data, &jd); err != nil {
+		return nil, fmt.Errorf("decoding digest report JSON: %w", err)
+	}
 
-	if err := os.WriteFile(filePath, data, 0o644); err != nil {
-		return "", fmt.Errorf("writing digest report JSON: %w", err)
+	digest := &analysis.DigestReport{
+		DateRangeStart:    jd.DateRangeStart,
+		DateRangeEnd:      jd.DateRangeEnd,
+		CrossSIGThemes:    jd.CrossSIGThemes,
+		PreviousDigestEnd: jd.PreviousDigest,
+	}
+	if jd.Stats != nil {
+		digest.Stats = &analysis.RunStats{
+			TotalTokensUsed:  jd.Stats.TotalTokensUsed,
+			TotalLLMCalls:    jd.Stats.TotalLLMCalls,
+			Model:            jd.Stats.Model,
+			Provider:         jd.Stats.Provider,
+			SIGsProcessed:    jd.Stats.SIGsProcessed,
+			SIGsWithData:     jd.Stats.SIGsWithData,
+			DurationSeconds:  jd.Stats.DurationSeconds,
+			EstimatedCostUSD: jd.Stats.EstimatedCostUSD,
+			BudgetSkipped:    jd.Stats.BudgetSkipped,
+		}
+		for _, su := range jd.Stats.Stages {
+			digest.Stats.Stages = append(digest.Stats.Stages, analysis.StageUsage(su))
+		}
+		for _, fu := range jd.Stats.Fallbacks {
+			digest.Stats.Fallbacks = append(digest.Stats.Fallbacks, analysis.FallbackUsage(fu))
+		}
+	}
+	for _, jr := range jd.SIGReports {
+		digest.SIGReports = append(digest.SIGReports, fromJSONSIGReport(jr))
 	}
+	return digest, nil
+}
 
-	return filePath, nil
+// fromJSONSIGReport converts the JSON form of a SIG report back to an
+// analysis.SIGReport.
+func fromJSONSIGReport(jr *jsonSIGReport) *analysis.SIGReport {
+	sr := &analysis.SIGReport{
+		SIGID:          jr.SIGID,
+		SIGName:        jr.SIGName,
+		Category:       jr.Category,
+		DateRangeStart: jr.DateRangeStart,
+		DateRangeEnd:   jr.DateRangeEnd,
+		SourcesUsed:    jr.SourcesUsed,
+		SourcesMissing: jr.SourcesMissing,
+		NotesLink:      jr.NotesLink,
+		RecordingLink:  jr.RecordingLink,
+		SlackChannel:   jr.SlackChannel,
+	}
+	if jr.Relevance != nil {
+		sr.RelevanceReport = &analysis.RelevanceReport{
+			SIGID:        jr.SIGID,
+			SIGName:      jr.SIGName,
+			Report:       jr.Relevance.Report,
+			HighItems:    jr.Relevance.HighItems,
+			MediumItems:  jr.Relevance.MediumItems,
+			LowItems:     jr.Relevance.LowItems,
+			Model:        jr.Relevance.Model,
+			TokensUsed:   jr.Relevance.TokensUsed,
+			OngoingItems: jr.Relevance.OngoingItems,
+		}
+		if len(jr.Relevance.ItemStatuses) > 0 {
+			sr.RelevanceReport.ItemStatuses = make(map[string]analysis.ItemStatus, len(jr.Relevance.ItemStatuses))
+			for item, status := range jr.Relevance.ItemStatuses {
+				sr.RelevanceReport.ItemStatuses[item] = analysis.ItemStatus(status)
+			}
+		}
+	}
+	return sr
 }
 
 // toJSONSIGReport converts an analysis.SIGReport to its JSON-serializable form.
@@ -164,6 +284,13 @@ func toJSONSIGReport(report *analysis.SIGReport) *jsonSIGReport {
 			Model:       report.RelevanceReport.Model,
 			TokensUsed:  report.RelevanceReport.TokensUsed,
 		}
+		if len(report.RelevanceReport.ItemStatuses) > 0 {
+			jr.Relevance.ItemStatuses = make(map[string]string, len(report.RelevanceReport.ItemStatuses))
+			for item, status := range report.RelevanceReport.ItemStatuses {
+				jr.Relevance.ItemStatuses[item] = string(status)
+			}
+		}
+		jr.Relevance.OngoingItems = report.RelevanceReport.OngoingItems
 	}
 
 	return jr
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 	"time"
 	"unicode"
 
@@ -16,6 +17,7 @@ import (
 // MarkdownGenerator writes Markdown-formatted reports to disk.
 type MarkdownGenerator struct {
 	outputDir string
+	template  *template.Template
 }
 
 // NewMarkdownGenerator creates a new MarkdownGenerator that writes to outputDir.
@@ -23,6 +25,12 @@ func NewMarkdownGenerator(outputDir string) *MarkdownGenerator {
 	return &MarkdownGenerator{outputDir: outputDir}
 }
 
+// SetTemplate replaces the default digest template, e.g. with one loaded by
+// ParseTemplate.
+func (g *MarkdownGenerator) SetTemplate(t *template.Template) {
+	g.template = t
+}
+
 // GenerateSIGReport generates a per-SIG Markdown report and returns the file path.
 func (g *MarkdownGenerator) GenerateSIGReport(report *analysis.SIGReport) (string, error) {
 	if err := os.MkdirAll(g.outputDir, 0o755); err != nil {
@@ -47,6 +55,7 @@ func (g *MarkdownGenerator) GenerateSIGReport(report *analysis.SIGReport) (strin
 	// Relevance items as a flat priority-ordered list (no H/M/L headers)
 	if report.RelevanceReport != nil {
 		writeRelevanceItemsFlat(&b, report.RelevanceReport)
+		writeOngoingItems(&b, report.RelevanceReport)
 	}
 
 	// Inline data sources
@@ -69,131 +78,69 @@ func (g *MarkdownGenerator) GenerateDigestReport(digest *analysis.DigestReport)
 		return "", fmt.Errorf("creating output directory: %w", err)
 	}
 
-	// Deduplicate SIG reports by normalized name.
-	deduped := deduplicateDigestSIGs(digest.SIGReports)
-
-	// Partition into active (has relevance data) and quiet (no data).
-	var active, quiet []*analysis.SIGReport
-	for _, sr := range deduped {
-		if sr.RelevanceReport != nil && totalRelevanceItems(sr.RelevanceReport) > 0 {
-			active = append(active, sr)
-		} else {
-			quiet = append(quiet, sr)
-		}
-	}
-
-	var b strings.Builder
-
-	// Title
-	dateRange := formatDateRange(digest.DateRangeStart, digest.DateRangeEnd)
-	fmt.Fprintf(&b, "# OTel Weekly Digest — %s\n\n", dateRange)
-
-	// Metadata line
-	fmt.Fprintf(&b, "> %d SIGs with activity | %d quiet | Generated: %s\n\n",
-		len(active),
-		len(quiet),
-		time.Now().UTC().Format("2006-01-02 15:04 UTC"),
-	)
-
-	// Top Takeaways — top high-relevance items across all SIGs
-	writeTopTakeaways(&b, active)
-
-	// SIG-by-SIG Summaries (only active SIGs, flat priority-ordered items)
-	b.WriteString("## SIG-by-SIG Summaries\n\n")
-	for _, sr := range active {
-		fmt.Fprintf(&b, "### %s\n\n", sr.SIGName)
-		writeRelevanceItemsFlat(&b, sr.RelevanceReport)
-		writeDataSources(&b, sr)
-	}
-
-	// Quiet This Week — one-line list of inactive SIGs
-	if len(quiet) > 0 {
-		b.WriteString("## Quiet This Week\n\n")
-		names := make([]string, len(quiet))
-		for i, sr := range quiet {
-			names[i] = sr.SIGName
-		}
-		fmt.Fprintf(&b, "%s\n\n", strings.Join(names, ", "))
-	}
-
-	// Cross-SIG Themes
-	if digest.CrossSIGThemes != "" {
-		b.WriteString("## Cross-SIG Themes\n\n")
-		b.WriteString(digest.CrossSIGThemes)
-		b.WriteString("\n\n")
-	}
-
-	// Appendix: Processing Stats (uses deduped list)
-	b.WriteString("## Appendix: Processing Stats\n\n")
-	b.WriteString("| SIG | Notes | Video | Slack | Status |\n")
-	b.WriteString("|-----|-------|-------|-------|--------|\n")
-	for _, sr := range deduped {
-		notes := sourceStatus("notes", sr.SourcesUsed, sr.SourcesMissing)
-		video := sourceStatus("video", sr.SourcesUsed, sr.SourcesMissing)
-		slack := sourceStatus("slack", sr.SourcesUsed, sr.SourcesMissing)
-		status := sigStatus(sr)
-		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
-			sr.SIGName, notes, video, slack, status,
-		)
-	}
-	b.WriteString("\n")
-
-	// Appendix: Run Info
-	if digest.Stats != nil {
-		b.WriteString("## Appendix: Run Info\n\n")
-		b.WriteString("| Metric | Value |\n")
-		b.WriteString("|--------|-------|\n")
-		fmt.Fprintf(&b, "| LLM Provider | %s |\n", digest.Stats.Provider)
-		fmt.Fprintf(&b, "| Model | `%s` |\n", digest.Stats.Model)
-		fmt.Fprintf(&b, "| Total Tokens Used | %s |\n", formatTokens(digest.Stats.TotalTokensUsed))
-		fmt.Fprintf(&b, "| LLM Calls | %d |\n", digest.Stats.TotalLLMCalls)
-		fmt.Fprintf(&b, "| Estimated Cost | $%.2f |\n", digest.Stats.EstimatedCostUSD)
-		fmt.Fprintf(&b, "| SIGs Processed | %d |\n", digest.Stats.SIGsProcessed)
-		fmt.Fprintf(&b, "| SIGs With Data | %d |\n", digest.Stats.SIGsWithData)
-		fmt.Fprintf(&b, "| Duration | %.1fs |\n", digest.Stats.DurationSeconds)
-		b.WriteString("\n")
+	content, err := executeTemplate(g.template, digest)
+	if err != nil {
+		return "", err
 	}
 
-	// Write file
 	filename := digestFilename(digest.DateRangeEnd)
 	filePath := filepath.Join(g.outputDir, filename)
 
-	if err := os.WriteFile(filePath, []byte(b.String()), 0o644); err != nil {
+	if err := os.WriteFile(filePath, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("writing digest report: %w", err)
 	}
 
 	return filePath, nil
 }
 
-// writeTopTakeaways collects high-relevance items across SIGs and writes the top 10
-// with [SIG] attribution.
-func writeTopTakeaways(b *strings.Builder, active []*analysis.SIGReport) {
-	type attributed struct {
-		sigName string
-		item    string
+// RenderDigestMarkdown renders a weekly digest as Markdown with the default
+// template.
+func RenderDigestMarkdown(digest *analysis.DigestReport) (string, error) {
+	return executeTemplate(nil, digest)
+}
+
+// partitionSIGs splits SIG reports into active ones, with relevance items or
+// ongoing items, and quiet ones without.
+func partitionSIGs(reports []*analysis.SIGReport) (active, quiet []*analysis.SIGReport) {
+	for _, sr := range reports {
+		if sr.RelevanceReport != nil && (totalRelevanceItems(sr.RelevanceReport) > 0 || len(sr.RelevanceReport.OngoingItems) > 0) {
+			active = append(active, sr)
+		} else {
+			quiet = append(quiet, sr)
+		}
 	}
-	var items []attributed
+	return active, quiet
+}
+
+// maxTakeaways is the number of items listed under Top Takeaways.
+const maxTakeaways = 10
+
+// takeaway is a high-relevance item attributed to its SIG.
+type takeaway struct {
+	sigName string
+	item    string
+	status  analysis.ItemStatus
+}
+
+// topTakeaways collects up to maxTakeaways high-relevance items across SIGs.
+func topTakeaways(active []*analysis.SIGReport) []takeaway {
+	var items []takeaway
 	for _, sr := range active {
 		if sr.RelevanceReport == nil {
 			continue
 		}
 		for _, item := range sr.RelevanceReport.HighItems {
-			items = append(items, attributed{sigName: sr.SIGName, item: item})
+			items = append(items, takeaway{
+				sigName: sr.SIGName,
+				item:    item,
+				status:  sr.RelevanceReport.ItemStatuses[item],
+			})
 		}
 	}
-	if len(items) == 0 {
-		return
-	}
-
-	b.WriteString("## Top Takeaways\n\n")
-	limit := 10
-	if len(items) < limit {
-		limit = len(items)
+	if len(items) > maxTakeaways {
+		items = items[:maxTakeaways]
 	}
-	for i := 0; i < limit; i++ {
-		fmt.Fprintf(b, "- [%s] %s\n", items[i].sigName, ensureBoldTopic(items[i].item))
-	}
-	b.WriteString("\n")
+	return items
 }
 
 // writeRelevanceItemsFlat renders high, medium, low items as one flat priority-ordered
@@ -207,17 +154,40 @@ func writeRelevanceItemsFlat(b *strings.Builder, rr *analysis.RelevanceReport) {
 		return
 	}
 	for _, item := range rr.HighItems {
-		fmt.Fprintf(b, "- %s\n", ensureBoldTopic(item))
+		fmt.Fprintf(b, "- %s%s\n", statusBadge(rr.ItemStatuses[item]), ensureBoldTopic(item))
 	}
 	for _, item := range rr.MediumItems {
-		fmt.Fprintf(b, "- %s\n", ensureBoldTopic(item))
+		fmt.Fprintf(b, "- %s%s\n", statusBadge(rr.ItemStatuses[item]), ensureBoldTopic(item))
 	}
 	for _, item := range rr.LowItems {
-		fmt.Fprintf(b, "- %s\n", ensureBoldTopic(item))
+		fmt.Fprintf(b, "- %s%s\n", statusBadge(rr.ItemStatuses[item]), ensureBoldTopic(item))
 	}
 	b.WriteString("\n")
 }
 
+// writeOngoingItems renders topics unchanged since the previous digest as a
+// single collapsed line. Nothing is written if there are none.
+func writeOngoingItems(b *strings.Builder, rr *analysis.RelevanceReport) {
+	if rr == nil || len(rr.OngoingItems) == 0 {
+		return
+	}
+	topics := make([]string, len(rr.OngoingItems))
+	for i, item := range rr.OngoingItems {
+		topics[i] = analysis.ItemTopic(item)
+	}
+	fmt.Fprintf(b, "_Ongoing:_ %s\n\n", strings.Join(topics, ", "))
+}
+
+// statusBadge returns an inline badge for NEW and UPDATED items, or an empty
+// string for items without a status.
+func statusBadge(status analysis.ItemStatus) string {
+	switch status {
+	case analysis.ItemNew, analysis.ItemUpdated:
+		return "`" + string(status) + "` "
+	}
+	return ""
+}
+
 // writeDataSources renders a compact inline sources line for a SIG report.
 // If no links are present, nothing is written.
 func writeDataSources(b *strings.Builder, sr *analysis.SIGReport) {
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
 )
@@ -90,6 +93,10 @@ func newTestDigestReport() *analysis.DigestReport {
 			SIGsWithData:     2,
 			DurationSeconds:  12.5,
 			EstimatedCostUSD: 0.03,
+			Stages: []analysis.StageUsage{
+				{Stage: "notes", Provider: "anthropic", Model: "claude-3-5-haiku-20241022", Calls: 2, TokensUsed: 800, EstimatedCostUSD: 0.01},
+				{Stage: "relevance", Provider: "anthropic", Model: "claude-sonnet-4-20250514", Calls: 2, TokensUsed: 1500, EstimatedCostUSD: 0.02},
+			},
 		},
 	}
 }
@@ -357,6 +364,39 @@ func TestMarkdownGenerator_GenerateDigestReport(t *testing.T) {
 	if !strings.Contains(content, "$0.03") {
 		t.Error("digest should contain estimated cost in Run Info")
 	}
+	if !strings.Contains(content, "| notes | anthropic | `claude-3-5-haiku-20241022` | 2 | 800 | $0.01 |") {
+		t.Error("digest should contain per-stage model usage in Run Info")
+	}
+	if strings.Contains(content, "Warning") {
+		t.Error("digest without fallbacks should not warn about them")
+	}
+
+	digest.Stats.Fallbacks = []analysis.FallbackUsage{{Stage: "notes", Provider: "openai", Model: "gpt-4o-mini", Calls: 2}}
+	filePath, err = gen.GenerateDigestReport(digest)
+	if err != nil {
+		t.Fatalf("GenerateDigestReport: %v", err)
+	}
+	data, err = os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("reading digest: %v", err)
+	}
+	if !strings.Contains(string(data), "## Appendix: Run Info\n\n> **Warning:** the configured LLM provider was unavailable for some calls, which fallback providers answered:\n> - notes: 2 call(s) by openai `gpt-4o-mini`\n\n| Metric |") {
+		t.Errorf("digest should warn about fallbacks at the top of Run Info:\n%s", data)
+	}
+
+	digest.Stats.Fallbacks = nil
+	digest.Stats.BudgetSkipped = []string{"video"}
+	filePath, err = gen.GenerateDigestReport(digest)
+	if err != nil {
+		t.Fatalf("GenerateDigestReport: %v", err)
+	}
+	data, err = os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("reading digest: %v", err)
+	}
+	if !strings.Contains(string(data), "## Appendix: Run Info\n\n> **Note:** video summaries were skipped to keep the run within its LLM budget.\n\n| Metric |") {
+		t.Errorf("digest should note the sources skipped for the budget:\n%s", data)
+	}
 }
 
 func TestMarkdownGenerator_GenerateDigestReport_NoCrossSIGThemes(t *testing.T) {
@@ -991,3 +1031,397 @@ func TestWriteDataSources(t *testing.T) {
 		})
 	}
 }
+
+func TestMarkdownGenerator_GenerateDigestReport_SinceLastReport(t *testing.T) {
+	dir := t.TempDir()
+	gen := NewMarkdownGenerator(dir)
+	digest := newTestDigestReport()
+	digest.PreviousDigestEnd = "2026-02-11"
+
+	rr := digest.SIGReports[0].RelevanceReport
+	rr.ItemStatuses = map[string]analysis.ItemStatus{
+		rr.HighItems[0]: analysis.ItemUpdated,
+		rr.LowItems[0]:  analysis.ItemNew,
+	}
+	rr.OngoingItems = []string{"**Sampling Roadmap** — Still under discussion."}
+
+	filePath, err := gen.GenerateDigestReport(digest)
+	if err != nil {
+		t.Fatalf("GenerateDigestReport failed: %v", err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("reading digest file: %v", err)
+	}
+	content := string(data)
+
+	if !strings.Contains(content, "previous digest ending 2026-02-11") {
+		t.Error("digest should reference the previous digest")
+	}
+	if !strings.Contains(content, "- [Collector] `UPDATED` **OTLP/HTTP Partial Success**") {
+		t.Error("top takeaways should carry the UPDATED badge")
+	}
+	if !strings.Contains(content, "- `NEW` **Batch Processor Memory**") {
+		t.Error("SIG summary should carry the NEW badge")
+	}
+	if !strings.Contains(content, "_Ongoing:_ Sampling Roadmap") {
+		t.Error("ongoing items should be collapsed into a topic list")
+	}
+	if strings.Contains(content, "Still under discussion") {
+		t.Error("ongoing item descriptions should not be rendered")
+	}
+}
+
+func TestUnmarshalDigest_RoundTrip(t *testing.T) {
+	digest := newTestDigestReport()
+	digest.Stats.Fallbacks = []analysis.FallbackUsage{{Stage: "relevance", Provider: "openai", Model: "gpt-4o", Calls: 1}}
+	digest.Stats.BudgetSkipped = []string{"video"}
+	digest.SIGReports[0].RelevanceReport.ItemStatuses = map[string]analysis.ItemStatus{
+		digest.SIGReports[0].RelevanceReport.HighItems[0]: analysis.ItemNew,
+	}
+
+	data, err := MarshalDigest(digest)
+	if err != nil {
+		t.Fatalf("MarshalDigest: %v", err)
+	}
+	got, err := UnmarshalDigest(data)
+	if err != nil {
+		t.Fatalf("UnmarshalDigest: %v", err)
+	}
+
+	if got.DateRangeEnd != digest.DateRangeEnd || got.CrossSIGThemes != digest.CrossSIGThemes {
+		t.Errorf("digest = %+v, want the original range and themes", got)
+	}
+	if len(got.SIGReports) != 3 {
+		t.Fatalf("got %d SIG reports, want 3", len(got.SIGReports))
+	}
+	rr := got.SIGReports[0].RelevanceReport
+	if rr == nil || rr.HighItems[0] != digest.SIGReports[0].RelevanceReport.HighItems[0] {
+		t.Fatalf("relevance = %+v, want the original high item", rr)
+	}
+	if rr.ItemStatuses[rr.HighItems[0]] != analysis.ItemNew {
+		t.Errorf("item status = %q, want NEW", rr.ItemStatuses[rr.HighItems[0]])
+	}
+	if got.SIGReports[2].RelevanceReport != nil {
+		t.Error("SIG without relevance data should decode without a relevance report")
+	}
+	if got.Stats == nil || got.Stats.TotalTokensUsed != 2300 {
+		t.Errorf("stats = %+v, want 2300 tokens", got.Stats)
+	}
+	if got.Stats != nil && (len(got.Stats.Fallbacks) != 1 || got.Stats.Fallbacks[0] != digest.Stats.Fallbacks[0]) {
+		t.Errorf("fallback usage = %+v, want %+v", got.Stats.Fallbacks, digest.Stats.Fallbacks)
+	}
+	if got.Stats != nil && !slices.Equal(got.Stats.BudgetSkipped, digest.Stats.BudgetSkipped) {
+		t.Errorf("budget skipped = %v, want %v", got.Stats.BudgetSkipped, digest.Stats.BudgetSkipped)
+	}
+	if got.Stats != nil && (len(got.Stats.Stages) != 2 || got.Stats.Stages[0] != digest.Stats.Stages[0]) {
+		t.Errorf("stage usage = %+v, want %+v", got.Stats.Stages, digest.Stats.Stages)
+	}
+
+	if _, err := UnmarshalDigest([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Slack rendering tests
+// ---------------------------------------------------------------------------
+
+// slackMessageText joins the text of every block in msg.
+func slackMessageText(msg SlackMessage) string {
+	var parts []string
+	for _, b := range msg.Blocks {
+		if b.Text != nil {
+			parts = append(parts, b.Text.Text)
+		}
+		for _, e := range b.Elements {
+			parts = append(parts, e.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func TestRenderSlackDigest(t *testing.T) {
+	parent, replies := RenderSlackDigest(newTestDigestReport())
+
+	if parent.Blocks[0].Type != "header" || parent.Blocks[0].Text.Text != "OTel Weekly Digest — 2026-02-11 to 2026-02-18" {
+		t.Errorf("first block = %+v, want the digest header", parent.Blocks[0])
+	}
+	text := slackMessageText(parent)
+	for _, want := range []string{
+		"2 SIGs with activity | 1 quiet",
+		"*Top Takeaways*",
+		"• [Collector] *OTLP/HTTP Partial Success* — ",
+		"• [Specification] *Profiling Signal OTEP*",
+		"*Quiet this week:* Empty SIG",
+		"Both SIGs discussed improvements",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("parent message missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "**") {
+		t.Error("parent message still contains Markdown bold")
+	}
+
+	if len(replies) != 2 {
+		t.Fatalf("got %d replies, want one per active SIG", len(replies))
+	}
+	collector := slackMessageText(replies[0])
+	for _, want := range []string{
+		"*Collector*",
+		"• *Pipeline Fan-out/Fan-in*",
+		"<https://docs.google.com/document/d/1r2JC5MB7ab|Meeting Notes>",
+		"<https://zoom.us/rec/share/abc123|Recording>",
+		"Slack: `#otel-collector`",
+	} {
+		if !strings.Contains(collector, want) {
+			t.Errorf("collector reply missing %q:\n%s", want, collector)
+		}
+	}
+	if replies[0].Text != "Collector" {
+		t.Errorf("reply fallback text = %q, want the SIG name", replies[0].Text)
+	}
+}
+
+func TestRenderSlackDigest_Limits(t *testing.T) {
+	digest := newTestDigestReport()
+	digest.DateRangeStart = strings.Repeat("x", 200)
+	rr := digest.SIGReports[0].RelevanceReport
+	rr.LowItems = nil
+	for i := 0; i < 400; i++ {
+		rr.LowItems = append(rr.LowItems, "**Topic** — "+strings.Repeat("detail ", 60))
+	}
+	rr.LowItems = append(rr.LowItems, strings.Repeat("é", 4000))
+
+	parent, replies := RenderSlackDigest(digest)
+	if got := len(parent.Blocks[0].Text.Text); got > slackMaxHeaderChars {
+		t.Errorf("header is %d bytes, limit %d", got, slackMaxHeaderChars)
+	}
+
+	var collector int
+	for _, msg := range append([]SlackMessage{parent}, replies...) {
+		if len(msg.Blocks) > slackMaxBlocks {
+			t.Errorf("message has %d blocks, limit %d", len(msg.Blocks), slackMaxBlocks)
+		}
+		for _, b := range msg.Blocks {
+			if b.Text != nil && len(b.Text.Text) > slackMaxSectionChars {
+				t.Errorf("block text is %d bytes, limit %d", len(b.Text.Text), slackMaxSectionChars)
+			}
+			if b.Text != nil && !utf8.ValidString(b.Text.Text) {
+				t.Error("truncated block text is not valid UTF-8")
+			}
+		}
+		if msg.Text == "Collector" {
+			collector++
+		}
+	}
+	if collector < 2 {
+		t.Errorf("collector details span %d messages, want them split across several", collector)
+	}
+}
+
+func TestSlackMrkdwn(t *testing.T) {
+	got := slackMrkdwn("**A <b> & c** — see [the PR](https://github.com/x/y/pull/1)")
+	want := "*A &lt;b&gt; &amp; c* — see <https://github.com/x/y/pull/1|the PR>"
+	if got != want {
+		t.Errorf("slackMrkdwn = %q, want %q", got, want)
+	}
+}
+
+func TestRenderDigestEmailHTML(t *testing.T) {
+	digest := newTestDigestReport()
+	rr := digest.SIGReports[0].RelevanceReport
+	rr.LowItems = append(rr.LowItems, "**<script>** — see [the PR](https://github.com/x/y/pull/1)")
+	rr.ItemStatuses = map[string]analysis.ItemStatus{rr.HighItems[0]: analysis.ItemUpdated}
+
+	out, err := RenderDigestEmailHTML(digest)
+	if err != nil {
+		t.Fatalf("RenderDigestEmailHTML: %v", err)
+	}
+	for _, want := range []string{
+		"<title>OTel Weekly Digest — 2026-02-11 to 2026-02-18</title>",
+		"2 SIGs with activity | 1 quiet",
+		"<strong>[Collector]</strong>",
+		">UPDATED</span> <strong>OTLP/HTTP Partial Success</strong>",
+		"<h3 style=\"font-size:16px;margin:20px 0 6px;\">Specification</h3>",
+		"<strong>&lt;script&gt;</strong> — see <a href=\"https://github.com/x/y/pull/1\">the PR</a>",
+		"<a href=\"https://zoom.us/rec/share/abc123\">Recording</a>",
+		"Empty SIG",
+		"Both SIGs discussed improvements to the OTLP protocol.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+	if strings.Contains(out, "<script>") {
+		t.Error("item text was not escaped")
+	}
+}
+
+func TestHTMLGenerator_GenerateDigestReport(t *testing.T) {
+	dir := t.TempDir()
+	digest := newTestDigestReport()
+	rr := digest.SIGReports[0].RelevanceReport
+	rr.ItemStatuses = map[string]analysis.ItemStatus{rr.HighItems[0]: analysis.ItemNew}
+	rr.LowItems = append(rr.LowItems, "**<b>Injected</b>** — [link](https://example.com/?a=1&b=2)")
+	digest.Stats.Fallbacks = []analysis.FallbackUsage{{Stage: "relevance", Provider: "openai", Model: "gpt-4o", Calls: 1}}
+	digest.Stats.BudgetSkipped = []string{"video"}
+
+	filePath, err := NewHTMLGenerator(dir).GenerateDigestReport(digest)
+	if err != nil {
+		t.Fatalf("GenerateDigestReport failed: %v", err)
+	}
+	if filepath.Base(filePath) != "2026-02-18-weekly-digest.html" {
+		t.Errorf("filename = %q, want 2026-02-18-weekly-digest.html", filepath.Base(filePath))
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("reading HTML digest: %v", err)
+	}
+	out := string(data)
+
+	for _, want := range []string{
+		"<title>OTel Weekly Digest — 2026-02-11 to 2026-02-18</title>",
+		// Table of contents with links to each active SIG.
+		`<li><a href="#top-takeaways">Top Takeaways</a></li>`,
+		`<li><a href="#sig-collector">Collector</a></li>`,
+		`<li><a href="#sig-specification">Specification</a></li>`,
+		`<li><a href="#run-info">Run Info</a></li>`,
+		// Takeaways link to the SIG section and carry level and status badges.
+		`<a href="#sig-collector">[Collector]</a> <span class="badge badge-high">HIGH</span> <span class="badge badge-status">NEW</span> <strong>OTLP/HTTP Partial Success</strong>`,
+		// Collapsible SIG sections with per-level counts.
+		`<details class="sig" id="sig-collector" open>`,
+		`<span class="badge badge-medium">1 MEDIUM</span>`,
+		`<span class="badge badge-low">LOW</span> <strong>Batch Processor Memory</strong>`,
+		// Source links.
+		`<a href="https://docs.google.com/document/d/1r2JC5MB7ab">Meeting Notes</a>`,
+		`<a href="https://zoom.us/rec/share/abc123">Recording</a>`,
+		`<a href="https://cloud-native.slack.com/app_redirect?channel=otel-collector"><code>#otel-collector</code></a>`,
+		// Sortable stats table including quiet SIGs.
+		`<table class="sortable">`,
+		`<tr><td>Empty SIG</td>`,
+		`<th data-type="number">High</th>`,
+		"<tr><th>Total Tokens Used</th><td>2k</td></tr>",
+		"<li>relevance: 1 call(s) by openai <code>gpt-4o</code></li>",
+		`<div class="warning"><strong>Note:</strong> video summaries were skipped to keep the run within its LLM budget.</div>`,
+		`<tr><td>relevance</td><td>anthropic</td><td><code>claude-sonnet-4-20250514</code></td><td class="num">2</td><td class="num">1k</td><td class="num">$0.02</td></tr>`,
+		"Both SIGs discussed improvements to the OTLP protocol.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("HTML digest missing %q", want)
+		}
+	}
+
+	if strings.Contains(out, "<b>Injected</b>") {
+		t.Error("item HTML was not escaped")
+	}
+	if !strings.Contains(out, `<a href="https://example.com/?a=1&amp;b=2">link</a>`) {
+		t.Error("Markdown link in an item was not rendered")
+	}
+	for _, external := range []string{`<link `, `src="http`, `@import`} {
+		if strings.Contains(out, external) {
+			t.Errorf("HTML digest references an external asset (%q)", external)
+		}
+	}
+	if !strings.Contains(out, `id="quiet"`) {
+		t.Error("quiet SIGs section missing")
+	}
+}
+
+func TestMarkdownGenerator_CustomTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tmplPath := filepath.Join(dir, "leadership.md.tmpl")
+	custom := `# {{.DateRange}} for leadership
+{{range .Takeaways}}* {{upper .SIG}}: {{.Topic}} ({{.Level}})
+{{end}}{{range .Active}}{{.Name}}: {{len .High}}/{{len .Medium}}/{{len .Low}} {{join .Sources ", "}}
+{{end}}Quiet: {{join (names .Quiet) ", "}}
+{{with .Stats}}Tokens: {{tokens .TotalTokensUsed}}{{end}}
+`
+	if err := os.WriteFile(tmplPath, []byte(custom), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplate(tmplPath)
+	if err != nil {
+		t.Fatalf("ParseTemplate: %v", err)
+	}
+
+	gen := NewMarkdownGenerator(filepath.Join(dir, "out"))
+	gen.SetTemplate(tmpl)
+	filePath, err := gen.GenerateDigestReport(newTestDigestReport())
+	if err != nil {
+		t.Fatalf("GenerateDigestReport: %v", err)
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# 2026-02-11 to 2026-02-18 for leadership
+* COLLECTOR: OTLP/HTTP Partial Success (HIGH)
+* SPECIFICATION: Profiling Signal OTEP (HIGH)
+Collector: 1/1/1 [Meeting Notes](https://docs.google.com/document/d/1r2JC5MB7ab), [Recording](https://zoom.us/rec/share/abc123), Slack: ` + "`#otel-collector`" + `
+Specification: 1/0/0 [Meeting Notes](https://docs.google.com/document/d/spec456), Slack: ` + "`#otel-specification`" + `
+Quiet: Empty SIG
+Tokens: 2k
+`
+	if string(data) != want {
+		t.Errorf("custom template output:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestParseTemplate_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ParseTemplate(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+	bad := filepath.Join(dir, "bad.tmpl")
+	if err := os.WriteFile(bad, []byte("{{range .SIGs}}unclosed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseTemplate(bad); err == nil {
+		t.Error("expected an error for an unclosed action")
+	}
+
+	// Unknown fields fail at render time with the template's name.
+	unknown := filepath.Join(dir, "unknown.tmpl")
+	if err := os.WriteFile(unknown, []byte("{{.NoSuchField}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseTemplate(unknown)
+	if err != nil {
+		t.Fatalf("ParseTemplate: %v", err)
+	}
+	gen := NewMarkdownGenerator(dir)
+	gen.SetTemplate(tmpl)
+	if _, err := gen.GenerateDigestReport(newTestDigestReport()); err == nil || !strings.Contains(err.Error(), "unknown.tmpl") {
+		t.Errorf("err = %v, want an execution error naming unknown.tmpl", err)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"OTLP/HTTP Partial Success": "otlp-http-partial-success",
+		"  Go 1.25 support! ":       "go-1-25-support",
+		"—":                         "",
+		strings.Repeat("a", 100):    strings.Repeat("a", maxSlugLen),
+	}
+	for in, want := range tests {
+		if got := Slugify(in); got != want {
+			t.Errorf("Slugify(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDigestWeek(t *testing.T) {
+	if got := DigestWeek("2026-01-01", time.Time{}); got != "2026-W01" {
+		t.Errorf("DigestWeek(2026-01-01) = %q", got)
+	}
+	if got := DigestWeek("2027-01-01", time.Time{}); got != "2026-W53" {
+		t.Errorf("DigestWeek(2027-01-01) = %q", got)
+	}
+	fallback := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
+	if got := DigestWeek("", fallback); got != "2026-W08" {
+		t.Errorf("DigestWeek fallback = %q", got)
+	}
+}
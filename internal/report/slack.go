@@ -0,0 +1,194 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+// Slack Block Kit limits. Messages exceeding them are rejected by the API.
+const (
+	slackMaxHeaderChars  = 150
+	slackMaxSectionChars = 3000
+	slackMaxBlocks       = 50
+)
+
+// SlackMessage is a Slack message rendered as Block Kit. Text is the
+// notification fallback shown where blocks are not rendered.
+type SlackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []SlackBlock `json:"blocks"`
+}
+
+// SlackBlock is a Block Kit layout block. Only header, section and context
+// blocks are used.
+type SlackBlock struct {
+	Type     string       `json:"type"`
+	Text     *SlackText   `json:"text,omitempty"`
+	Elements []*SlackText `json:"elements,omitempty"`
+}
+
+// SlackText is a Block Kit text object.
+type SlackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// RenderSlackDigest renders a digest as a parent message holding the Top
+// Takeaways and replies, meant to be threaded under it, holding the per-SIG
+// details. Every message stays within Slack's block and text limits; content
+// that does not fit is split across further replies.
+func RenderSlackDigest(digest *analysis.DigestReport) (parent SlackMessage, replies []SlackMessage) {
+	active, quiet := partitionSIGs(deduplicateDigestSIGs(digest.SIGReports))
+	title := "OTel Weekly Digest — " + formatDateRange(digest.DateRangeStart, digest.DateRangeEnd)
+
+	blocks := []SlackBlock{
+		headerBlock(title),
+		contextBlock(fmt.Sprintf("%d SIGs with activity | %d quiet", len(active), len(quiet))),
+	}
+	if digest.PreviousDigestEnd != "" {
+		blocks = append(blocks, contextBlock(fmt.Sprintf("Changes since the previous digest ending %s.", digest.PreviousDigestEnd)))
+	}
+	if items := topTakeaways(active); len(items) > 0 {
+		lines := []string{"*Top Takeaways*"}
+		for _, t := range items {
+			lines = append(lines, fmt.Sprintf("• [%s] %s%s", slackEscape(t.sigName), statusBadge(t.status), slackMrkdwn(ensureBoldTopic(t.item))))
+		}
+		blocks = append(blocks, sectionBlocks(lines)...)
+	}
+	if len(quiet) > 0 {
+		names := make([]string, len(quiet))
+		for i, sr := range quiet {
+			names[i] = slackEscape(sr.SIGName)
+		}
+		blocks = append(blocks, sectionBlocks([]string{"*Quiet this week:* " + strings.Join(names, ", ")})...)
+	}
+	if digest.CrossSIGThemes != "" {
+		blocks = append(blocks, sectionBlocks(append([]string{"*Cross-SIG Themes*"}, strings.Split(slackMrkdwn(digest.CrossSIGThemes), "\n")...))...)
+	}
+	if len(active) > 0 {
+		blocks = append(blocks, contextBlock("Per-SIG details are in the thread."))
+	}
+	msgs := splitMessages(title, blocks)
+	parent, replies = msgs[0], msgs[1:]
+	for _, sr := range active {
+		replies = append(replies, splitMessages(sr.SIGName, sigBlocks(sr))...)
+	}
+	return parent, replies
+}
+
+// sigBlocks renders one SIG's relevance items, ongoing topics and sources.
+func sigBlocks(sr *analysis.SIGReport) []SlackBlock {
+	lines := []string{"*" + slackEscape(sr.SIGName) + "*"}
+	rr := sr.RelevanceReport
+	for _, level := range [][]string{rr.HighItems, rr.MediumItems, rr.LowItems} {
+		for _, item := range level {
+			lines = append(lines, "• "+statusBadge(rr.ItemStatuses[item])+slackMrkdwn(ensureBoldTopic(item)))
+		}
+	}
+	blocks := sectionBlocks(lines)
+
+	if len(rr.OngoingItems) > 0 {
+		topics := make([]string, len(rr.OngoingItems))
+		for i, item := range rr.OngoingItems {
+			topics[i] = slackEscape(analysis.ItemTopic(item))
+		}
+		blocks = append(blocks, contextBlock(truncate("_Ongoing:_ "+strings.Join(topics, ", "), slackMaxSectionChars)))
+	}
+
+	var sources []string
+	if sr.NotesLink != "" {
+		sources = append(sources, fmt.Sprintf("<%s|Meeting Notes>", sr.NotesLink))
+	}
+	if sr.RecordingLink != "" {
+		sources = append(sources, fmt.Sprintf("<%s|Recording>", sr.RecordingLink))
+	}
+	if sr.SlackChannel != "" {
+		sources = append(sources, "Slack: `"+slackEscape(sr.SlackChannel)+"`")
+	}
+	if len(sources) > 0 {
+		blocks = append(blocks, contextBlock("Sources: "+strings.Join(sources, " | ")))
+	}
+	return blocks
+}
+
+// splitMessages packs blocks into as many messages as the per-message block
+// limit requires, each with text as its fallback.
+func splitMessages(text string, blocks []SlackBlock) []SlackMessage {
+	var msgs []SlackMessage
+	for len(blocks) > 0 {
+		n := min(len(blocks), slackMaxBlocks)
+		msgs = append(msgs, SlackMessage{Text: text, Blocks: blocks[:n]})
+		blocks = blocks[n:]
+	}
+	return msgs
+}
+
+// sectionBlocks joins lines into mrkdwn sections, starting a new section
+// whenever the next line would exceed the section text limit.
+func sectionBlocks(lines []string) []SlackBlock {
+	var blocks []SlackBlock
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			blocks = append(blocks, SlackBlock{Type: "section", Text: &SlackText{Type: "mrkdwn", Text: cur.String()}})
+			cur.Reset()
+		}
+	}
+	for _, line := range lines {
+		line = truncate(line, slackMaxSectionChars)
+		if cur.Len() > 0 && cur.Len()+1+len(line) > slackMaxSectionChars {
+			flush()
+		}
+		if cur.Len() > 0 {
+			cur.WriteByte('\n')
+		}
+		cur.WriteString(line)
+	}
+	flush()
+	return blocks
+}
+
+func headerBlock(text string) SlackBlock {
+	return SlackBlock{Type: "header", Text: &SlackText{Type: "plain_text", Text: truncate(text, slackMaxHeaderChars)}}
+}
+
+func contextBlock(text string) SlackBlock {
+	return SlackBlock{Type: "context", Elements: []*SlackText{{Type: "mrkdwn", Text: text}}}
+}
+
+// truncate shortens s to at most limit bytes, ending it with an ellipsis and
+// never splitting a UTF-8 sequence.
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	const ellipsis = "…"
+	cut := limit - len(ellipsis)
+	for cut > 0 && !isRuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + ellipsis
+}
+
+func isRuneStart(b byte) bool { return b&0xC0 != 0x80 }
+
+var (
+	mdBoldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	mdLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+)
+
+// slackMrkdwn converts the Markdown used in report items to Slack mrkdwn:
+// **bold** becomes *bold* and [text](url) becomes <url|text>.
+func slackMrkdwn(s string) string {
+	s = slackEscape(s)
+	s = mdBoldPattern.ReplaceAllString(s, "*$1*")
+	return mdLinkPattern.ReplaceAllString(s, "<$2|$1>")
+}
+
+// slackEscape escapes the characters Slack treats as control sequences.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,225 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+)
+
+// DigestData is the data model passed to digest templates. SIG lists are
+// deduplicated by name and keep the digest's order.
+type DigestData struct {
+	// Title is "OTel Weekly Digest — <DateRange>".
+	Title string
+	// DateRange is "<start> to <end>", or a single date.
+	DateRange      string
+	DateRangeStart string
+	DateRangeEnd   string
+	// GeneratedAt is the render time in UTC.
+	GeneratedAt time.Time
+	// PreviousDigestEnd is the end of the digest this one was diffed
+	// against (--since-last-report), or empty.
+	PreviousDigestEnd string
+	// Takeaways are the top HIGH relevance items across SIGs.
+	Takeaways []Takeaway
+	// Active SIGs have relevance or ongoing items; Quiet SIGs have none.
+	Active []*SIGData
+	Quiet  []*SIGData
+	// SIGs lists every SIG, active and quiet.
+	SIGs           []*SIGData
+	CrossSIGThemes string
+	// Stats is nil when run statistics were not recorded.
+	Stats *analysis.RunStats
+	// Digest is the underlying report.
+	Digest *analysis.DigestReport
+}
+
+// SIGData is one SIG in DigestData.
+type SIGData struct {
+	ID       string
+	Name     string
+	Category string
+	// Items lists the HIGH, then MEDIUM, then LOW relevance items.
+	Items  []Item
+	High   []Item
+	Medium []Item
+	Low    []Item
+	// Ongoing lists the topics of items unchanged since the previous digest.
+	Ongoing       []string
+	NotesLink     string
+	RecordingLink string
+	SlackChannel  string
+	// Sources holds the Markdown source links, e.g. "[Recording](url)".
+	Sources []string
+	// Notes, Video and Slack are ✓ (used), ✗ (failed) or — (not tried).
+	Notes string
+	Video string
+	Slack string
+	// Status is "Complete", "Partial" or "No data".
+	Status string
+	// Report is the underlying SIG report.
+	Report *analysis.SIGReport
+}
+
+// Item is one relevance item.
+type Item struct {
+	// Text is the item as scored, usually "**Topic** — detail".
+	Text  string
+	Topic string
+	// Level is "HIGH", "MEDIUM" or "LOW".
+	Level string
+	// Status is "NEW", "UPDATED", "ONGOING" or empty.
+	Status string
+	// Badge is "`NEW` " or "`UPDATED` ", and empty otherwise.
+	Badge string
+	// Bold is Text with its topic in bold.
+	Bold string
+}
+
+// Takeaway is a top item attributed to its SIG.
+type Takeaway struct {
+	SIG string
+	Item
+}
+
+var templateFuncs = template.FuncMap{
+	"join":   strings.Join,
+	"lower":  strings.ToLower,
+	"upper":  strings.ToUpper,
+	"tokens": formatTokens,
+	"names": func(sigs []*SIGData) []string {
+		names := make([]string, len(sigs))
+		for i, s := range sigs {
+			names[i] = s.Name
+		}
+		return names
+	},
+}
+
+// defaultDigestTemplate is the built-in Markdown digest layout.
+var defaultDigestTemplate = template.Must(template.New("digest.md.tmpl").Funcs(templateFuncs).
+	ParseFS(templateFS, "templates/digest.md.tmpl"))
+
+// ParseTemplate parses a digest template file. Besides the text/template
+// builtins, templates can call join, lower, upper, tokens (e.g. "152k") and
+// names (the names of a list of SIGs).
+func ParseTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// NewDigestData builds the template data model for a digest.
+func NewDigestData(digest *analysis.DigestReport) *DigestData {
+	deduped := deduplicateDigestSIGs(digest.SIGReports)
+	active, quiet := partitionSIGs(deduped)
+	dateRange := formatDateRange(digest.DateRangeStart, digest.DateRangeEnd)
+	d := &DigestData{
+		Title:             "OTel Weekly Digest — " + dateRange,
+		DateRange:         dateRange,
+		DateRangeStart:    digest.DateRangeStart,
+		DateRangeEnd:      digest.DateRangeEnd,
+		GeneratedAt:       time.Now().UTC(),
+		PreviousDigestEnd: digest.PreviousDigestEnd,
+		CrossSIGThemes:    digest.CrossSIGThemes,
+		Stats:             digest.Stats,
+		Digest:            digest,
+	}
+
+	byReport := make(map[*analysis.SIGReport]*SIGData, len(deduped))
+	for _, sr := range deduped {
+		sd := newSIGData(sr)
+		byReport[sr] = sd
+		d.SIGs = append(d.SIGs, sd)
+	}
+	for _, sr := range active {
+		d.Active = append(d.Active, byReport[sr])
+	}
+	for _, sr := range quiet {
+		d.Quiet = append(d.Quiet, byReport[sr])
+	}
+	for _, t := range topTakeaways(active) {
+		d.Takeaways = append(d.Takeaways, Takeaway{SIG: t.sigName, Item: newItem(t.item, "HIGH", t.status)})
+	}
+	return d
+}
+
+func newSIGData(sr *analysis.SIGReport) *SIGData {
+	sd := &SIGData{
+		ID:            sr.SIGID,
+		Name:          sr.SIGName,
+		Category:      sr.Category,
+		NotesLink:     sr.NotesLink,
+		RecordingLink: sr.RecordingLink,
+		SlackChannel:  sr.SlackChannel,
+		Notes:         sourceStatus("notes", sr.SourcesUsed, sr.SourcesMissing),
+		Video:         sourceStatus("video", sr.SourcesUsed, sr.SourcesMissing),
+		Slack:         sourceStatus("slack", sr.SourcesUsed, sr.SourcesMissing),
+		Status:        sigStatus(sr),
+		Report:        sr,
+	}
+	if sr.NotesLink != "" {
+		sd.Sources = append(sd.Sources, fmt.Sprintf("[Meeting Notes](%s)", sr.NotesLink))
+	}
+	if sr.RecordingLink != "" {
+		sd.Sources = append(sd.Sources, fmt.Sprintf("[Recording](%s)", sr.RecordingLink))
+	}
+	if sr.SlackChannel != "" {
+		sd.Sources = append(sd.Sources, fmt.Sprintf("Slack: `%s`", sr.SlackChannel))
+	}
+
+	rr := sr.RelevanceReport
+	if rr == nil {
+		return sd
+	}
+	for _, item := range rr.HighItems {
+		sd.High = append(sd.High, newItem(item, "HIGH", rr.ItemStatuses[item]))
+	}
+	for _, item := range rr.MediumItems {
+		sd.Medium = append(sd.Medium, newItem(item, "MEDIUM", rr.ItemStatuses[item]))
+	}
+	for _, item := range rr.LowItems {
+		sd.Low = append(sd.Low, newItem(item, "LOW", rr.ItemStatuses[item]))
+	}
+	sd.Items = append(append(append(sd.Items, sd.High...), sd.Medium...), sd.Low...)
+	for _, item := range rr.OngoingItems {
+		sd.Ongoing = append(sd.Ongoing, analysis.ItemTopic(item))
+	}
+	return sd
+}
+
+func newItem(text, level string, status analysis.ItemStatus) Item {
+	return Item{
+		Text:   text,
+		Topic:  analysis.ItemTopic(text),
+		Level:  level,
+		Status: string(status),
+		Badge:  statusBadge(status),
+		Bold:   ensureBoldTopic(text),
+	}
+}
+
+// executeTemplate renders digest with tmpl, or the default template if tmpl
+// is nil.
+func executeTemplate(tmpl *template.Template, digest *analysis.DigestReport) (string, error) {
+	if tmpl == nil {
+		tmpl = defaultDigestTemplate
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, NewDigestData(digest)); err != nil {
+		return "", fmt.Errorf("executing template %s: %w", tmpl.Name(), err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job runs next.
+type Schedule interface {
+	// Next returns the first run time strictly after after.
+	Next(after time.Time) time.Time
+}
+
+// intervalSchedule runs a job at a fixed interval.
+type intervalSchedule struct {
+	every time.Duration
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.every)
+}
+
+// calendarSchedule runs a job at a wall-clock time on selected weekdays.
+type calendarSchedule struct {
+	days   [7]bool // indexed by time.Weekday
+	hour   int
+	minute int
+	loc    *time.Location
+}
+
+func (s calendarSchedule) Next(after time.Time) time.Time {
+	local := after.In(s.loc)
+	for i := 0; i <= 7; i++ {
+		day := local.AddDate(0, 0, i)
+		t := time.Date(day.Year(), day.Month(), day.Day(), s.hour, s.minute, 0, 0, s.loc)
+		if s.days[t.Weekday()] && t.After(after) {
+			return t
+		}
+	}
+	// Unreachable: at least one weekday is always selected.
+	return after.Add(7 * 24 * time.Hour)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseSchedule parses a schedule expression:
+//
+//	every 6h            fixed interval (any Go duration of at least a minute)
+//	daily 08:00         every day at 08:00
+//	weekdays 08:00      Monday to Friday at 08:00
+//	mon,thu 08:00       on the listed days at 08:00 ("monday" and "mondays" also work)
+//
+// Wall-clock times are interpreted in loc.
+func ParseSchedule(expr string, loc *time.Location) (Schedule, error) {
+	fields := strings.Fields(strings.ToLower(expr))
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid schedule %q: want e.g. \"every 6h\" or \"mon 08:00\"", expr)
+	}
+
+	if fields[0] == "every" {
+		d, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		if d < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be at least 1m", expr)
+		}
+		return intervalSchedule{every: d}, nil
+	}
+
+	s := calendarSchedule{loc: loc}
+	switch fields[0] {
+	case "daily":
+		for i := range s.days {
+			s.days[i] = true
+		}
+	case "weekdays":
+		for d := time.Monday; d <= time.Friday; d++ {
+			s.days[d] = true
+		}
+	default:
+		for _, name := range strings.Split(fields[0], ",") {
+			d, ok := parseWeekday(name)
+			if !ok {
+				return nil, fmt.Errorf("invalid schedule %q: unknown day %q", expr, name)
+			}
+			s.days[d] = true
+		}
+	}
+
+	hh, mm, ok := strings.Cut(fields[1], ":")
+	hour, errH := strconv.Atoi(hh)
+	minute, errM := strconv.Atoi(mm)
+	if !ok || errH != nil || errM != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return nil, fmt.Errorf("invalid schedule %q: time must be HH:MM", expr)
+	}
+	s.hour, s.minute = hour, minute
+	return s, nil
+}
+
+// parseWeekday accepts "mon", "monday" and "mondays".
+func parseWeekday(name string) (time.Weekday, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	d, ok := weekdayNames[name[:3]]
+	if !ok {
+		return 0, false
+	}
+	full := strings.ToLower(d.String())
+	if name != full[:3] && name != full && name != full+"s" {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,62 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// Wednesday 2026-10-14 09:30 New York time.
+	after := time.Date(2026, 10, 14, 9, 30, 0, 0, ny)
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"every 6h", after.Add(6 * time.Hour)},
+		{"daily 08:00", time.Date(2026, 10, 15, 8, 0, 0, 0, ny)},
+		{"daily 10:00", time.Date(2026, 10, 14, 10, 0, 0, 0, ny)},
+		{"weekdays 08:00", time.Date(2026, 10, 15, 8, 0, 0, 0, ny)},
+		{"mon 08:00", time.Date(2026, 10, 19, 8, 0, 0, 0, ny)},
+		{"Mondays 08:00", time.Date(2026, 10, 19, 8, 0, 0, 0, ny)},
+		{"mon,wed 09:30", time.Date(2026, 10, 19, 9, 30, 0, 0, ny)},
+		{"sat,sunday 07:15", time.Date(2026, 10, 17, 7, 15, 0, 0, ny)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.expr, ny)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := s.Next(after); !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q).Next = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{"", "every", "every 10s", "daily", "daily 25:00", "daily 8", "mo 08:00", "mondayz 08:00", "hourly 00:00"} {
+		if _, err := ParseSchedule(expr, ny); err == nil {
+			t.Errorf("ParseSchedule(%q) should fail", expr)
+		}
+	}
+}
+
+func TestCalendarSchedule_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	s, err := ParseSchedule("daily 08:00", ny)
+	if err != nil {
+		t.Fatalf("ParseSchedule: %v", err)
+	}
+	// Clocks go back on 2026-11-01; 08:00 local stays 08:00 local.
+	got := s.Next(time.Date(2026, 10, 31, 9, 0, 0, 0, ny))
+	want := time.Date(2026, 11, 1, 8, 0, 0, 0, ny)
+	if !got.Equal(want) || got.Sub(time.Date(2026, 10, 31, 8, 0, 0, 0, ny)) != 25*time.Hour {
+		t.Errorf("Next across DST = %s, want %s (25h after the previous run)", got, want)
+	}
+}
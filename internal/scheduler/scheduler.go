@@ -0,0 +1,118 @@
+// Package scheduler runs recurring fetch and report jobs for the daemon.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+// Runner executes one job. The context is cancelled only if the job is still
+// running when the shutdown timeout expires.
+type Runner func(ctx context.Context, job JobSpec) error
+
+// Scheduler runs the jobs of a Spec on their schedules, one at a time.
+type Scheduler struct {
+	jobs            []*scheduledJob
+	runner          Runner
+	shutdownTimeout time.Duration
+	now             func() time.Time
+	logger          *slog.Logger
+}
+
+type scheduledJob struct {
+	spec     JobSpec
+	schedule Schedule
+	next     time.Time
+}
+
+// New creates a scheduler for spec's jobs.
+func New(spec *Spec, runner Runner) (*Scheduler, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	s := &Scheduler{
+		runner:          runner,
+		shutdownTimeout: spec.ShutdownTimeout,
+		now:             time.Now,
+		logger:          slog.Default(),
+	}
+	for _, j := range spec.Jobs {
+		sched, err := spec.schedule(j)
+		if err != nil {
+			return nil, err
+		}
+		s.jobs = append(s.jobs, &scheduledJob{spec: j, schedule: sched})
+	}
+	return s, nil
+}
+
+// Run schedules jobs until ctx is cancelled. Jobs run sequentially; a job
+// that comes due while another runs starts when it finishes, and runs missed
+// meanwhile are skipped rather than queued. On cancellation a running job is
+// given the shutdown timeout to finish, so in-flight LLM calls complete and
+// their results are cached, before its context is cancelled too.
+func (s *Scheduler) Run(ctx context.Context) {
+	now := s.now()
+	for _, j := range s.jobs {
+		j.next = j.schedule.Next(now)
+		s.logger.Info("scheduled job", "job", j.spec.Name, "next", j.next.Format(time.RFC3339))
+	}
+
+	for {
+		sort.SliceStable(s.jobs, func(a, b int) bool { return s.jobs[a].next.Before(s.jobs[b].next) })
+		j := s.jobs[0]
+
+		timer := time.NewTimer(time.Until(j.next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !s.runJob(ctx, j) {
+			return
+		}
+		j.next = j.schedule.Next(s.now())
+		s.logger.Info("scheduled job", "job", j.spec.Name, "next", j.next.Format(time.RFC3339))
+	}
+}
+
+// runJob runs j and reports whether the scheduler should keep going.
+func (s *Scheduler) runJob(ctx context.Context, j *scheduledJob) bool {
+	log := s.logger.With("job", j.spec.Name)
+	log.Info("starting job")
+	start := s.now()
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- s.runner(jobCtx, j.spec) }()
+
+	var err error
+	keepGoing := true
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		keepGoing = false
+		log.Info("shutting down, waiting for running job", "timeout", s.shutdownTimeout)
+		timer := time.NewTimer(s.shutdownTimeout)
+		select {
+		case err = <-done:
+			timer.Stop()
+		case <-timer.C:
+			log.Warn("job did not finish in time, cancelling")
+			cancel()
+			err = <-done
+		}
+	}
+
+	if err != nil {
+		log.Warn("job failed", "duration", s.now().Sub(start).Round(time.Second), "err", err)
+	} else {
+		log.Info("job finished", "duration", s.now().Sub(start).Round(time.Second))
+	}
+	return keepGoing
+}
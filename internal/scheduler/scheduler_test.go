@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestScheduler builds a scheduler whose jobs run at a short interval.
+func newTestScheduler(runner Runner, shutdownTimeout time.Duration, names ...string) *Scheduler {
+	s := &Scheduler{runner: runner, shutdownTimeout: shutdownTimeout, now: time.Now, logger: slog.Default()}
+	for _, name := range names {
+		s.jobs = append(s.jobs, &scheduledJob{
+			spec:     JobSpec{Name: name},
+			schedule: intervalSchedule{every: 10 * time.Millisecond},
+		})
+	}
+	return s
+}
+
+func TestScheduler_RunsJobsSequentially(t *testing.T) {
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	running := 0
+	overlapped := false
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestScheduler(func(ctx context.Context, job JobSpec) error {
+		mu.Lock()
+		running++
+		overlapped = overlapped || running > 1
+		counts[job.Name]++
+		done := counts["a"] >= 3 && counts["b"] >= 3
+		mu.Unlock()
+
+		time.Sleep(2 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		if done {
+			cancel()
+		}
+		return nil
+	}, time.Second, "a", "b")
+
+	finished := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts["a"] < 3 || counts["b"] < 3 {
+		t.Errorf("job runs = %v, want at least 3 each", counts)
+	}
+	if overlapped {
+		t.Error("jobs ran concurrently")
+	}
+}
+
+func TestScheduler_ShutdownLetsRunningJobFinish(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var jobErr error
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestScheduler(func(jobCtx context.Context, job JobSpec) error {
+		close(started)
+		select {
+		case <-release:
+		case <-jobCtx.Done():
+		}
+		jobErr = jobCtx.Err()
+		return jobErr
+	}, 5*time.Second, "slow")
+
+	finished := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(finished)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-finished:
+		t.Fatal("scheduler returned before the running job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-finished
+	if jobErr != nil {
+		t.Errorf("running job's context was cancelled: %v", jobErr)
+	}
+}
+
+func TestScheduler_ShutdownTimeoutCancelsJob(t *testing.T) {
+	started := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	s := newTestScheduler(func(jobCtx context.Context, job JobSpec) error {
+		close(started)
+		<-jobCtx.Done()
+		return jobCtx.Err()
+	}, 20*time.Millisecond, "stuck")
+
+	finished := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(finished)
+	}()
+	<-started
+	cancel()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not cancel the job after the shutdown timeout")
+	}
+}
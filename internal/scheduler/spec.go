@@ -0,0 +1,168 @@
+package scheduler
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"go.yaml.in/yaml/v3"
+)
+
+// DefaultShutdownTimeout is how long a shutdown waits for an in-flight job
+// before cancelling it.
+const DefaultShutdownTimeout = 5 * time.Minute
+
+// Spec is the daemon's job file.
+type Spec struct {
+	// Timezone is the default zone for job schedules and report windows.
+	Timezone string `yaml:"timezone"`
+	// RegistryRefresh is how long a fetched SIG registry is reused before
+	// it is fetched again. Zero refetches it on every fetch.
+	RegistryRefresh time.Duration `yaml:"registry_refresh"`
+	// ShutdownTimeout bounds how long a shutdown waits for a running job.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	Jobs            []JobSpec     `yaml:"jobs"`
+}
+
+// JobSpec is one recurring job.
+type JobSpec struct {
+	Name     string `yaml:"name"`
+	Command  string `yaml:"command"`  // "fetch" or "report"
+	Schedule string `yaml:"schedule"` // see ParseSchedule
+	Timezone string `yaml:"timezone"` // overrides Spec.Timezone
+
+	SIGs     []string `yaml:"sigs"`
+	Lookback string   `yaml:"lookback"`
+	// Window "last-week" reports on the previous ISO week, as of the run.
+	Window          string `yaml:"window"`
+	SinceLastReport bool   `yaml:"since_last_report"`
+	Offline         bool   `yaml:"offline"`
+	SkipVideos      bool   `yaml:"skip_videos"`
+	SkipSlack       bool   `yaml:"skip_slack"`
+	SkipNotes       bool   `yaml:"skip_notes"`
+	OutputDir       string `yaml:"output_dir"`
+	Format          string `yaml:"format"`
+}
+
+// LoadSpec reads and validates a job file.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading job file: %w", err)
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing job file %s: %w", path, err)
+	}
+	if spec.ShutdownTimeout == 0 {
+		spec.ShutdownTimeout = DefaultShutdownTimeout
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("job file %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Validate checks the spec's jobs and their schedules.
+func (s *Spec) Validate() error {
+	if len(s.Jobs) == 0 {
+		return fmt.Errorf("no jobs defined")
+	}
+	names := make(map[string]bool)
+	for _, j := range s.Jobs {
+		if j.Name == "" {
+			return fmt.Errorf("every job needs a name")
+		}
+		if names[j.Name] {
+			return fmt.Errorf("duplicate job name %q", j.Name)
+		}
+		names[j.Name] = true
+
+		if j.Command != "fetch" && j.Command != "report" {
+			return fmt.Errorf("job %q: command must be 'fetch' or 'report', got %q", j.Name, j.Command)
+		}
+		if j.Window != "" && j.Window != "last-week" {
+			return fmt.Errorf("job %q: window must be 'last-week', got %q", j.Name, j.Window)
+		}
+		if _, err := s.schedule(j); err != nil {
+			return fmt.Errorf("job %q: %w", j.Name, err)
+		}
+		if j.Lookback != "" {
+			if _, err := config.ParseLookback(j.Lookback); err != nil {
+				return fmt.Errorf("job %q: %w", j.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// location returns the time zone for a job.
+func (s *Spec) location(j JobSpec) (*time.Location, error) {
+	c := config.Config{Timezone: s.timezone(j)}
+	return c.Location()
+}
+
+func (s *Spec) timezone(j JobSpec) string {
+	if j.Timezone != "" {
+		return j.Timezone
+	}
+	return s.Timezone
+}
+
+func (s *Spec) schedule(j JobSpec) (Schedule, error) {
+	loc, err := s.location(j)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSchedule(j.Schedule, loc)
+}
+
+// Config applies a job to a copy of base, resolving a "last-week" window
+// against now, and validates the result.
+func (s *Spec) Config(j JobSpec, base *config.Config, now time.Time) (*config.Config, error) {
+	c := *base
+	if tz := s.timezone(j); tz != "" {
+		c.Timezone = tz
+	}
+	if len(j.SIGs) > 0 {
+		c.SIGs = j.SIGs
+	}
+	if j.Lookback != "" {
+		d, err := config.ParseLookback(j.Lookback)
+		if err != nil {
+			return nil, err
+		}
+		c.Lookback = d
+		c.LookbackMonths, _ = config.ParseLookbackMonths(j.Lookback)
+	}
+	c.Since, c.Until, c.Week = "", "", ""
+	if j.Window == "last-week" {
+		loc, err := c.Location()
+		if err != nil {
+			return nil, err
+		}
+		year, week := now.In(loc).AddDate(0, 0, -7).ISOWeek()
+		c.Week = fmt.Sprintf("%d-W%02d", year, week)
+	}
+	c.SinceLastReport = j.SinceLastReport
+	c.Offline = c.Offline || j.Offline
+	c.SkipVideos = c.SkipVideos || j.SkipVideos
+	c.SkipSlack = c.SkipSlack || j.SkipSlack
+	c.SkipNotes = c.SkipNotes || j.SkipNotes
+	if j.OutputDir != "" {
+		c.OutputDir = j.OutputDir
+	}
+	if j.Format != "" {
+		c.Format = j.Format
+	}
+
+	if j.Command == "report" {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+	} else if _, _, err := c.Window(now); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+)
+
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jobs.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoadSpec(t *testing.T) {
+	path := writeSpec(t, `
+timezone: America/New_York
+registry_refresh: 24h
+jobs:
+  - name: fetch
+    command: fetch
+    schedule: every 6h
+  - name: weekly-digest
+    command: report
+    schedule: mon 08:00
+    window: last-week
+  - name: slack-daily
+    command: report
+    schedule: weekdays 09:00
+    timezone: UTC
+    lookback: 1d
+    skip_videos: true
+    skip_notes: true
+`)
+	spec, err := LoadSpec(path)
+	if err != nil {
+		t.Fatalf("LoadSpec: %v", err)
+	}
+	if len(spec.Jobs) != 3 || spec.RegistryRefresh != 24*time.Hour {
+		t.Fatalf("spec = %+v, want 3 jobs and a 24h registry refresh", spec)
+	}
+	if spec.ShutdownTimeout != DefaultShutdownTimeout {
+		t.Errorf("ShutdownTimeout = %s, want the default", spec.ShutdownTimeout)
+	}
+	if j := spec.Jobs[2]; !j.SkipVideos || !j.SkipNotes || j.SkipSlack || j.Lookback != "1d" {
+		t.Errorf("slack-daily job = %+v", j)
+	}
+}
+
+func TestLoadSpec_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no jobs":        `timezone: UTC`,
+		"bad command":    "jobs:\n  - {name: a, command: delete, schedule: every 1h}",
+		"bad schedule":   "jobs:\n  - {name: a, command: fetch, schedule: sometimes}",
+		"duplicate name": "jobs:\n  - {name: a, command: fetch, schedule: every 1h}\n  - {name: a, command: fetch, schedule: every 2h}",
+		"bad timezone":   "jobs:\n  - {name: a, command: fetch, schedule: daily 08:00, timezone: Mars/Base}",
+		"bad window":     "jobs:\n  - {name: a, command: report, schedule: daily 08:00, window: yesterday}",
+	}
+	for name, content := range tests {
+		if _, err := LoadSpec(writeSpec(t, content)); err == nil {
+			t.Errorf("%s: LoadSpec should fail", name)
+		}
+	}
+}
+
+func TestSpecConfig(t *testing.T) {
+	spec := &Spec{Timezone: "UTC"}
+	base := config.DefaultConfig()
+	base.LLM.AnthropicKey = "test-key"
+	base.Since = "2026-01-01"
+
+	job := JobSpec{
+		Name: "weekly", Command: "report", Schedule: "mon 08:00",
+		SIGs: []string{"collector"}, Window: "last-week", SkipSlack: true,
+	}
+	// Monday 2026-10-19: the previous ISO week is 2026-W42.
+	c, err := spec.Config(job, base, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Config: %v", err)
+	}
+	if c.Week != "2026-W42" || c.Since != "" {
+		t.Errorf("Week = %q, Since = %q; want 2026-W42 and no since", c.Week, c.Since)
+	}
+	if !c.SkipSlack || len(c.SIGs) != 1 || c.Timezone != "UTC" {
+		t.Errorf("config = %+v, want the job's SIGs, skip_slack and time zone", c)
+	}
+	if base.Week != "" || base.SkipSlack || base.Since != "2026-01-01" {
+		t.Error("job settings must not leak into the base config")
+	}
+
+	job.Format = "pdf"
+	if _, err := spec.Config(job, base, time.Now()); err == nil || !strings.Contains(err.Error(), "format") {
+		t.Errorf("Config with an invalid format: err = %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/progress"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// Job statuses before a job's run finishes. Finished jobs take the status of
+// their run (store.RunCompleted, store.RunFailed, ...).
+const (
+	jobQueued  = "queued"
+	jobRunning = "running"
+)
+
+// maxQueuedJobs bounds the number of jobs waiting to run.
+const maxQueuedJobs = 32
+
+// jobRequest is the body of POST /api/jobs.
+type jobRequest struct {
+	Command         string   `json:"command"` // "fetch" or "report"
+	SIGs            []string `json:"sigs,omitempty"`
+	Lookback        string   `json:"lookback,omitempty"`
+	Since           string   `json:"since,omitempty"`
+	Until           string   `json:"until,omitempty"`
+	Week            string   `json:"week,omitempty"`
+	Offline         bool     `json:"offline,omitempty"`
+	SkipVideos      bool     `json:"skip_videos,omitempty"`
+	SkipSlack       bool     `json:"skip_slack,omitempty"`
+	SkipNotes       bool     `json:"skip_notes,omitempty"`
+	SinceLastReport bool     `json:"since_last_report,omitempty"`
+}
+
+// job is a fetch or report run requested through the API.
+type job struct {
+	ID         int64      `json:"id"`
+	Command    string     `json:"command"`
+	Status     string     `json:"status"`
+	RunID      int64      `json:"run_id,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Request    jobRequest `json:"request"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// Progress is the live state of the job's pipeline once it has started.
+	Progress *progressJSON `json:"progress,omitempty"`
+
+	cfg      *config.Config
+	progress *progress.Tracker
+}
+
+// snapshot returns a copy of j with its progress filled in.
+func (j *job) snapshot() job {
+	c := *j
+	if j.progress != nil {
+		c.Progress = toProgressJSON(j.progress.Snapshot())
+	}
+	return c
+}
+
+// jobQueue tracks jobs and feeds them one at a time to the worker, so runs
+// never compete for the SQLite file or the LLM rate limit.
+type jobQueue struct {
+	mu     sync.Mutex
+	nextID int64
+	jobs   map[int64]*job
+	order  []int64
+	queue  chan *job
+}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		jobs:  make(map[int64]*job),
+		queue: make(chan *job, maxQueuedJobs),
+	}
+}
+
+// add registers and enqueues a job. It fails if the queue is full.
+func (q *jobQueue) add(j *job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	j.ID = q.nextID
+	j.Status = jobQueued
+	j.CreatedAt = time.Now().UTC()
+	select {
+	case q.queue <- j:
+	default:
+		q.nextID--
+		return fmt.Errorf("job queue is full")
+	}
+	q.jobs[j.ID] = j
+	q.order = append(q.order, j.ID)
+	return nil
+}
+
+// update applies fn to a job under the queue lock.
+func (q *jobQueue) update(j *job, fn func(*job)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	fn(j)
+}
+
+// get returns a copy of a job.
+func (q *jobQueue) get(id int64) (job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j, ok := q.jobs[id]
+	if !ok {
+		return job{}, false
+	}
+	return j.snapshot(), true
+}
+
+// list returns copies of all jobs, newest first.
+func (q *jobQueue) list() []job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]job, 0, len(q.order))
+	for i := len(q.order) - 1; i >= 0; i-- {
+		out = append(out, q.jobs[q.order[i]].snapshot())
+	}
+	return out
+}
+
+// RunJobs executes queued jobs one at a time until ctx is cancelled. A job
+// running at that point is interrupted and recorded as such in the run
+// ledger, from where it can be resumed with the CLI.
+func (s *Server) RunJobs(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			s.abandonQueuedJobs()
+			return
+		case j := <-s.jobs.queue:
+			s.runJob(ctx, j)
+		}
+	}
+}
+
+func (s *Server) runJob(ctx context.Context, j *job) {
+	now := time.Now().UTC()
+	s.jobs.update(j, func(j *job) {
+		j.Status = jobRunning
+		j.StartedAt = &now
+	})
+	slog.Info("server: starting job", "job", j.ID, "command", j.Command)
+
+	status, runID, err := s.execute(ctx, j)
+
+	finished := time.Now().UTC()
+	s.jobs.update(j, func(j *job) {
+		j.Status = status
+		j.RunID = runID
+		j.FinishedAt = &finished
+		if err != nil {
+			j.Error = err.Error()
+		}
+	})
+	slog.Info("server: job finished", "job", j.ID, "status", status)
+}
+
+// execute runs a job's pipeline and returns the final run status.
+func (s *Server) execute(ctx context.Context, j *job) (string, int64, error) {
+	p, err := s.newPipeline(j.cfg)
+	if err != nil {
+		return store.RunFailed, 0, fmt.Errorf("creating pipeline: %w", err)
+	}
+	defer p.Close()
+
+	tracker := progress.NewTracker()
+	defer p.Subscribe(tracker)()
+	for _, o := range s.observers {
+		defer p.Subscribe(o)()
+	}
+	s.jobs.update(j, func(j *job) { j.progress = tracker })
+
+	run, err := p.BeginRun(j.Command)
+	if err != nil {
+		return store.RunFailed, 0, err
+	}
+	s.jobs.update(j, func(j *job) { j.RunID = run.ID })
+
+	switch {
+	case j.Command == "fetch":
+		err = p.FetchOnly(ctx)
+	case j.cfg.Offline:
+		err = p.AnalyzeOnly(ctx)
+	default:
+		err = p.Run(ctx)
+	}
+	p.EndRun(err)
+	return run.Status, run.ID, err
+}
+
+// abandonQueuedJobs marks jobs that never started as failed on shutdown.
+func (s *Server) abandonQueuedJobs() {
+	for {
+		select {
+		case j := <-s.jobs.queue:
+			s.jobs.update(j, func(j *job) {
+				j.Status = store.RunFailed
+				j.Error = "server shut down before the job started"
+			})
+		default:
+			return
+		}
+	}
+}
+
+func (s *Server) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	var req jobRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+
+	cfg, err := s.jobConfig(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	j := &job{Command: req.Command, Request: req, cfg: cfg}
+	if err := s.jobs.add(j); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	got, _ := s.jobs.get(j.ID)
+	w.Header().Set("Location", fmt.Sprintf("/api/jobs/%d", j.ID))
+	writeJSON(w, http.StatusAccepted, got)
+}
+
+func (s *Server) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.jobs.list())
+}
+
+func (s *Server) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid id %q", r.PathValue("id")))
+		return
+	}
+	j, ok := s.jobs.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("job %d not found", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+// jobConfig applies a job request to a copy of the server's configuration
+// and validates the result.
+func (s *Server) jobConfig(req jobRequest) (*config.Config, error) {
+	if req.Command != "fetch" && req.Command != "report" {
+		return nil, fmt.Errorf("command must be 'fetch' or 'report', got %q", req.Command)
+	}
+
+	c := *s.cfg
+	c.SIGs = req.SIGs
+	if req.Lookback != "" {
+		d, err := config.ParseLookback(req.Lookback)
+		if err != nil {
+			return nil, err
+		}
+		c.Lookback = d
+		c.LookbackMonths, _ = config.ParseLookbackMonths(req.Lookback)
+	}
+	c.Since, c.Until, c.Week = req.Since, req.Until, req.Week
+	c.Offline = c.Offline || req.Offline
+	c.SkipVideos = c.SkipVideos || req.SkipVideos
+	c.SkipSlack = c.SkipSlack || req.SkipSlack
+	c.SkipNotes = c.SkipNotes || req.SkipNotes
+	c.SinceLastReport = req.SinceLastReport
+
+	if req.Command == "report" {
+		if err := c.Validate(); err != nil {
+			return nil, err
+		}
+	} else if _, _, err := c.Window(time.Now()); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/progress"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// sigJSON is the API representation of a SIG.
+type sigJSON struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Category         string    `json:"category"`
+	MeetingTime      string    `json:"meeting_time,omitempty"`
+	NotesDocID       string    `json:"notes_doc_id,omitempty"`
+	SlackChannelID   string    `json:"slack_channel_id,omitempty"`
+	SlackChannelName string    `json:"slack_channel_name,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func toSIGJSON(sig *store.SIG) sigJSON {
+	return sigJSON{
+		ID:               sig.ID,
+		Name:             sig.Name,
+		Category:         sig.Category,
+		MeetingTime:      sig.MeetingTime,
+		NotesDocID:       sig.NotesDocID,
+		SlackChannelID:   sig.SlackChannelID,
+		SlackChannelName: sig.SlackChannelName,
+		UpdatedAt:        sig.UpdatedAt,
+	}
+}
+
+// noteJSON is the API representation of stored meeting notes.
+type noteJSON struct {
+	ID          int64     `json:"id"`
+	SIGID       string    `json:"sig_id"`
+	DocID       string    `json:"doc_id"`
+	MeetingDate string    `json:"meeting_date"`
+	Text        string    `json:"text"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// transcriptJSON is the API representation of a stored video transcript.
+type transcriptJSON struct {
+	ID              int64     `json:"id"`
+	SIGID           string    `json:"sig_id"`
+	ZoomURL         string    `json:"zoom_url"`
+	RecordingDate   time.Time `json:"recording_date"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Source          string    `json:"source"`
+	Transcript      string    `json:"transcript"`
+	FetchedAt       time.Time `json:"fetched_at"`
+}
+
+// messageJSON is the API representation of a stored Slack message.
+type messageJSON struct {
+	ID          int64     `json:"id"`
+	SIGID       string    `json:"sig_id"`
+	ChannelID   string    `json:"channel_id"`
+	MessageTS   string    `json:"message_ts"`
+	ThreadTS    string    `json:"thread_ts,omitempty"`
+	UserName    string    `json:"user_name,omitempty"`
+	Text        string    `json:"text"`
+	MessageDate time.Time `json:"message_date"`
+}
+
+// runJSON is the API representation of a run ledger entry.
+type runJSON struct {
+	ID             int64           `json:"id"`
+	Command        string          `json:"command"`
+	Status         string          `json:"status"`
+	Error          string          `json:"error,omitempty"`
+	SIGSet         string          `json:"sig_set"`
+	DateRangeStart time.Time       `json:"date_range_start"`
+	DateRangeEnd   time.Time       `json:"date_range_end"`
+	Config         json.RawMessage `json:"config,omitempty"`
+	StartedAt      time.Time       `json:"started_at"`
+	FinishedAt     *time.Time      `json:"finished_at,omitempty"`
+	Steps          []runStepJSON   `json:"steps,omitempty"`
+}
+
+// runStepJSON is the API representation of one per-SIG stage of a run.
+type runStepJSON struct {
+	SIGID     string    `json:"sig_id"`
+	Stage     string    `json:"stage"`
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func toRunJSON(run *store.Run, steps []*store.RunStep) runJSON {
+	out := runJSON{
+		ID:             run.ID,
+		Command:        run.Command,
+		Status:         run.Status,
+		Error:          run.Error,
+		SIGSet:         run.SIGSet,
+		DateRangeStart: run.DateRangeStart,
+		DateRangeEnd:   run.DateRangeEnd,
+		StartedAt:      run.StartedAt,
+	}
+	if json.Valid([]byte(run.Config)) {
+		out.Config = json.RawMessage(run.Config)
+	}
+	if !run.FinishedAt.IsZero() {
+		finished := run.FinishedAt
+		out.FinishedAt = &finished
+	}
+	for _, st := range steps {
+		out.Steps = append(out.Steps, runStepJSON{
+			SIGID:     st.SIGID,
+			Stage:     st.Stage,
+			Status:    st.Status,
+			Error:     st.Error,
+			UpdatedAt: st.UpdatedAt,
+		})
+	}
+	return out
+}
+
+// reportJSON is the API representation of a stored report. Report holds the
+// report's JSON payload, as written by the JSON generator, and is only
+// included when a single report is requested.
+type reportJSON struct {
+	ID             int64            `json:"id"`
+	Type           string           `json:"type"`
+	SIGID          string           `json:"sig_id,omitempty"`
+	SIGSet         string           `json:"sig_set,omitempty"`
+	DateRangeStart string           `json:"date_range_start"`
+	DateRangeEnd   string           `json:"date_range_end"`
+	FilePath       string           `json:"file_path"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Report         json.RawMessage  `json:"report,omitempty"`
+	Items          []reportItemJSON `json:"items,omitempty"`
+}
+
+// reportItemJSON is the API representation of a stored relevance item.
+type reportItemJSON struct {
+	SIGID  string `json:"sig_id"`
+	Level  string `json:"level"`
+	Topic  string `json:"topic"`
+	Text   string `json:"text"`
+	Status string `json:"status,omitempty"`
+}
+
+func toReportJSON(r *store.Report, items []*store.ReportItem, withPayload bool) reportJSON {
+	out := reportJSON{
+		ID:             r.ID,
+		Type:           r.ReportType,
+		SIGID:          r.SIGID,
+		SIGSet:         r.SIGSet,
+		DateRangeStart: r.DateRangeStart.Format("2006-01-02"),
+		DateRangeEnd:   r.DateRangeEnd.Format("2006-01-02"),
+		FilePath:       r.FilePath,
+		CreatedAt:      r.CreatedAt,
+	}
+	if withPayload && json.Valid([]byte(r.Payload)) {
+		out.Report = json.RawMessage(r.Payload)
+	}
+	for _, item := range items {
+		out.Items = append(out.Items, reportItemJSON{
+			SIGID:  item.SIGID,
+			Level:  item.Level,
+			Topic:  item.Topic,
+			Text:   item.Text,
+			Status: item.Status,
+		})
+	}
+	return out
+}
+
+// searchResultJSON is the API representation of a search hit.
+type searchResultJSON struct {
+	SourceType string    `json:"source_type"`
+	ID         int64     `json:"id"`
+	SIGID      string    `json:"sig_id"`
+	Date       time.Time `json:"date"`
+	Snippet    string    `json:"snippet"`
+}
+
+// progressJSON is the API representation of a running job's progress.
+type progressJSON struct {
+	Phase          string            `json:"phase"`
+	Total          int               `json:"total"`
+	Done           int               `json:"done"`
+	Failed         int               `json:"failed"`
+	Active         int               `json:"active"`
+	Tokens         int               `json:"tokens"`
+	CostUSD        float64           `json:"cost_usd"`
+	ElapsedSeconds float64           `json:"elapsed_seconds"`
+	ETASeconds     float64           `json:"eta_seconds,omitempty"`
+	SIGs           []sigProgressJSON `json:"sigs"`
+}
+
+// sigProgressJSON is the state of one SIG within a job's current phase.
+type sigProgressJSON struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+func toProgressJSON(snap progress.Snapshot) *progressJSON {
+	out := &progressJSON{
+		Phase:          snap.Phase,
+		Total:          snap.Total,
+		Done:           snap.Done,
+		Failed:         snap.Failed,
+		Active:         snap.Active,
+		Tokens:         snap.Tokens,
+		CostUSD:        snap.CostUSD,
+		ElapsedSeconds: snap.Elapsed.Seconds(),
+		ETASeconds:     snap.ETA.Seconds(),
+		SIGs:           make([]sigProgressJSON, 0, len(snap.SIGs)),
+	}
+	for _, s := range snap.SIGs {
+		out.SIGs = append(out.SIGs, sigProgressJSON{ID: s.ID, State: s.State, Error: s.Err})
+	}
+	return out
+}
@@ -0,0 +1,429 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// Pipeline is the part of *pipeline.Pipeline that jobs drive.
+type Pipeline interface {
+	BeginRun(command string) (*store.Run, error)
+	EndRun(runErr error)
+	FetchOnly(ctx context.Context) error
+	Run(ctx context.Context) error
+	AnalyzeOnly(ctx context.Context) error
+	Subscribe(o pipeline.Observer) (unsubscribe func())
+	Close() error
+}
+
+// Server exposes the store and pipeline as a JSON HTTP API for the web UI.
+type Server struct {
+	cfg         *config.Config
+	store       *store.Store
+	token       string
+	jobs        *jobQueue
+	newPipeline func(*config.Config) (Pipeline, error)
+	observers   []pipeline.Observer
+}
+
+// New creates a Server backed by st. Every /api request must carry
+// "Authorization: Bearer <token>".
+func New(cfg *config.Config, st *store.Store, token string) *Server {
+	return &Server{
+		cfg:   cfg,
+		store: st,
+		token: token,
+		jobs:  newJobQueue(),
+		newPipeline: func(c *config.Config) (Pipeline, error) {
+			return pipeline.New(c)
+		},
+	}
+}
+
+// AddObserver subscribes o to the events of every job's pipeline. It must be
+// called before RunJobs.
+func (s *Server) AddObserver(o pipeline.Observer) {
+	s.observers = append(s.observers, o)
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+
+	api := http.NewServeMux()
+	api.HandleFunc("GET /api/sigs", s.handleListSIGs)
+	api.HandleFunc("GET /api/sigs/{id}", s.handleGetSIG)
+	api.HandleFunc("GET /api/sigs/{id}/notes", s.handleNotes)
+	api.HandleFunc("GET /api/sigs/{id}/transcripts", s.handleTranscripts)
+	api.HandleFunc("GET /api/sigs/{id}/messages", s.handleMessages)
+	api.HandleFunc("POST /api/jobs", s.handleCreateJob)
+	api.HandleFunc("GET /api/jobs", s.handleListJobs)
+	api.HandleFunc("GET /api/jobs/{id}", s.handleGetJob)
+	api.HandleFunc("GET /api/runs", s.handleListRuns)
+	api.HandleFunc("GET /api/runs/{id}", s.handleGetRun)
+	api.HandleFunc("GET /api/reports", s.handleListReports)
+	api.HandleFunc("GET /api/reports/{id}", s.handleGetReport)
+	api.HandleFunc("GET /api/search", s.handleSearch)
+	mux.Handle("/api/", s.requireToken(api))
+
+	return mux
+}
+
+// requireToken rejects requests without the configured bearer token.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="otel-sig-scraper"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleListSIGs(w http.ResponseWriter, r *http.Request) {
+	var filter []string
+	if v := r.URL.Query().Get("sigs"); v != "" {
+		filter = strings.Split(v, ",")
+	}
+	sigs, err := s.store.ListSIGs(filter)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]sigJSON, 0, len(sigs))
+	for _, sig := range sigs {
+		out = append(out, toSIGJSON(sig))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleGetSIG(w http.ResponseWriter, r *http.Request) {
+	sig, ok := s.lookupSIG(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, toSIGJSON(sig))
+}
+
+func (s *Server) handleNotes(w http.ResponseWriter, r *http.Request) {
+	sig, ok := s.lookupSIG(w, r)
+	if !ok {
+		return
+	}
+	start, end, ok := s.queryWindow(w, r)
+	if !ok {
+		return
+	}
+	notes, err := s.store.GetMeetingNotes(sig.ID, start, end)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]noteJSON, 0, len(notes))
+	for _, n := range notes {
+		out = append(out, noteJSON{
+			ID:          n.ID,
+			SIGID:       n.SIGID,
+			DocID:       n.DocID,
+			MeetingDate: n.MeetingDate.Format("2006-01-02"),
+			Text:        n.RawText,
+			FetchedAt:   n.FetchedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleTranscripts(w http.ResponseWriter, r *http.Request) {
+	sig, ok := s.lookupSIG(w, r)
+	if !ok {
+		return
+	}
+	start, end, ok := s.queryWindow(w, r)
+	if !ok {
+		return
+	}
+	transcripts, err := s.store.GetVideoTranscripts(sig.ID, start, end)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]transcriptJSON, 0, len(transcripts))
+	for _, vt := range transcripts {
+		out = append(out, transcriptJSON{
+			ID:              vt.ID,
+			SIGID:           vt.SIGID,
+			ZoomURL:         vt.ZoomURL,
+			RecordingDate:   vt.RecordingDate,
+			DurationMinutes: vt.DurationMinutes,
+			Source:          vt.TranscriptSource,
+			Transcript:      vt.Transcript,
+			FetchedAt:       vt.FetchedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleMessages(w http.ResponseWriter, r *http.Request) {
+	sig, ok := s.lookupSIG(w, r)
+	if !ok {
+		return
+	}
+	start, end, ok := s.queryWindow(w, r)
+	if !ok {
+		return
+	}
+	msgs, err := s.store.GetSlackMessages(sig.ID, start, end)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]messageJSON, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, messageJSON{
+			ID:          m.ID,
+			SIGID:       m.SIGID,
+			ChannelID:   m.ChannelID,
+			MessageTS:   m.MessageTS,
+			ThreadTS:    m.ThreadTS,
+			UserName:    m.UserName,
+			Text:        m.Text,
+			MessageDate: m.MessageDate,
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryLimit(w, r, 50)
+	if !ok {
+		return
+	}
+	runs, err := s.store.ListRuns(limit)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]runJSON, 0, len(runs))
+	for _, run := range runs {
+		out = append(out, toRunJSON(run, nil))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	run, err := s.store.GetRun(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %d not found", id))
+		return
+	} else if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	steps, err := s.store.GetRunSteps(id)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toRunJSON(run, steps))
+}
+
+func (s *Server) handleListReports(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryLimit(w, r, 50)
+	if !ok {
+		return
+	}
+	reports, err := s.store.ListReports(r.URL.Query().Get("type"), limit)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]reportJSON, 0, len(reports))
+	for _, rep := range reports {
+		out = append(out, toReportJSON(rep, nil, false))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleGetReport(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	rep, err := s.store.GetReport(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("report %d not found", id))
+		return
+	} else if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	items, err := s.store.GetReportItems(id)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toReportJSON(rep, items, true))
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "query parameter q is required")
+		return
+	}
+	limit, ok := queryLimit(w, r, 50)
+	if !ok {
+		return
+	}
+	results, err := s.store.Search(q, r.URL.Query().Get("sig"), limit)
+	if err != nil {
+		writeServerError(w, err)
+		return
+	}
+	out := make([]searchResultJSON, 0, len(results))
+	for _, res := range results {
+		out = append(out, searchResultJSON{
+			SourceType: res.SourceType,
+			ID:         res.ID,
+			SIGID:      res.SIGID,
+			Date:       res.Date,
+			Snippet:    snippet(res.Text, q, 160),
+		})
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// lookupSIG loads the SIG named by the {id} path segment, writing a 404 if
+// it does not exist.
+func (s *Server) lookupSIG(w http.ResponseWriter, r *http.Request) (*store.SIG, bool) {
+	id := r.PathValue("id")
+	sig, err := s.store.GetSIG(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("SIG %q not found", id))
+		return nil, false
+	} else if err != nil {
+		writeServerError(w, err)
+		return nil, false
+	}
+	return sig, true
+}
+
+// queryWindow resolves the since/until/week/lookback query parameters the
+// same way the CLI flags are resolved, defaulting to the configured window.
+func (s *Server) queryWindow(w http.ResponseWriter, r *http.Request) (start, end time.Time, ok bool) {
+	c := *s.cfg
+	q := r.URL.Query()
+	if v := q.Get("lookback"); v != "" {
+		d, err := config.ParseLookback(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return time.Time{}, time.Time{}, false
+		}
+		c.Lookback = d
+		c.LookbackMonths, _ = config.ParseLookbackMonths(v)
+	}
+	if q.Has("since") || q.Has("until") || q.Has("week") {
+		c.Since, c.Until, c.Week = q.Get("since"), q.Get("until"), q.Get("week")
+	}
+	start, end, err := c.Window(time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// pathID parses the {id} path segment as a positive integer.
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid id %q", r.PathValue("id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// queryLimit parses the limit query parameter, defaulting to def.
+func queryLimit(w http.ResponseWriter, r *http.Request, def int) (int, bool) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit %q", v))
+		return 0, false
+	}
+	return n, true
+}
+
+// snippet returns up to width characters of text around the first
+// case-insensitive occurrence of q.
+func snippet(text, q string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	idx := strings.Index(strings.ToLower(text), strings.ToLower(q))
+	start := 0
+	if idx > 0 {
+		start = len([]rune(text[:idx])) - width/3
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+		start = max(0, end-width)
+	}
+	out := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		slog.Warn("server: encoding response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeServerError(w http.ResponseWriter, err error) {
+	slog.Error("server: request failed", "err", err)
+	writeError(w, http.StatusInternalServerError, "internal error")
+}
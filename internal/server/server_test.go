@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/config"
+	"github.com/gordyrad/otel-sig-tracker/internal/pipeline"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+const testToken = "secret-token"
+
+func newTestServer(t *testing.T) (*Server, *store.Store, *httptest.Server) {
+	t.Helper()
+	st, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { st.Close() })
+
+	cfg := config.DefaultConfig()
+	cfg.LLM.AnthropicKey = "test-key"
+	cfg.Timezone = "UTC"
+	srv := New(cfg, st, testToken)
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+	return srv, st, ts
+}
+
+func doRequest(t *testing.T, method, url, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func decode(t *testing.T, resp *http.Response, v any) {
+	t.Helper()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	_, _, ts := newTestServer(t)
+
+	for _, auth := range []string{"", "Bearer wrong", testToken} {
+		req, _ := http.NewRequest("GET", ts.URL+"/api/sigs", nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status = %d, want 401", auth, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatalf("GET /healthz: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/healthz status = %d, want 200 without a token", resp.StatusCode)
+	}
+}
+
+func TestServer_BrowseData(t *testing.T) {
+	_, st, ts := newTestServer(t)
+
+	if err := st.UpsertSIG(&store.SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG: %v", err)
+	}
+	if err := st.UpsertMeetingNote(&store.MeetingNote{
+		SIGID: "collector", DocID: "doc1", MeetingDate: time.Date(2026, 10, 7, 0, 0, 0, 0, time.UTC),
+		RawText: "Agreed to deprecate the logging exporter", ContentHash: "h1",
+	}); err != nil {
+		t.Fatalf("UpsertMeetingNote: %v", err)
+	}
+
+	var sigs []sigJSON
+	decode(t, doRequest(t, "GET", ts.URL+"/api/sigs", ""), &sigs)
+	if len(sigs) != 1 || sigs[0].ID != "collector" {
+		t.Fatalf("GET /api/sigs = %+v, want the collector SIG", sigs)
+	}
+
+	if resp := doRequest(t, "GET", ts.URL+"/api/sigs/nope", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown SIG status = %d, want 404", resp.StatusCode)
+	}
+
+	var notes []noteJSON
+	decode(t, doRequest(t, "GET", ts.URL+"/api/sigs/collector/notes?week=2026-W41", ""), &notes)
+	if len(notes) != 1 || notes[0].MeetingDate != "2026-10-07" {
+		t.Errorf("notes for 2026-W41 = %+v, want the 2026-10-07 note", notes)
+	}
+	decode(t, doRequest(t, "GET", ts.URL+"/api/sigs/collector/notes?week=2026-W42", ""), &notes)
+	if len(notes) != 0 {
+		t.Errorf("notes for 2026-W42 = %d, want 0", len(notes))
+	}
+	if resp := doRequest(t, "GET", ts.URL+"/api/sigs/collector/notes?since=yesterday", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid window status = %d, want 400", resp.StatusCode)
+	}
+
+	var results []searchResultJSON
+	decode(t, doRequest(t, "GET", ts.URL+"/api/search?q=LOGGING+exporter", ""), &results)
+	if len(results) != 1 || results[0].SourceType != "notes" || !strings.Contains(results[0].Snippet, "logging exporter") {
+		t.Errorf("search results = %+v, want the note", results)
+	}
+	if resp := doRequest(t, "GET", ts.URL+"/api/search", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("search without q status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestServer_Reports(t *testing.T) {
+	_, st, ts := newTestServer(t)
+
+	r := &store.Report{
+		ReportType:     "digest",
+		SIGSet:         "all",
+		DateRangeStart: time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC),
+		DateRangeEnd:   time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC),
+		FilePath:       "reports/2026-10-11-weekly-digest.md",
+		Payload:        `{"sig_count":1}`,
+	}
+	if err := st.InsertReport(r); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+	if err := st.InsertReportItems(r.ID, []*store.ReportItem{
+		{SIGID: "collector", Level: "high", Topic: "Logging exporter", Text: "**Logging exporter** removed"},
+	}); err != nil {
+		t.Fatalf("InsertReportItems: %v", err)
+	}
+
+	var list []reportJSON
+	decode(t, doRequest(t, "GET", ts.URL+"/api/reports?type=digest", ""), &list)
+	if len(list) != 1 || list[0].DateRangeEnd != "2026-10-11" || list[0].Report != nil {
+		t.Fatalf("report list = %+v, want one digest without payload", list)
+	}
+
+	var got reportJSON
+	decode(t, doRequest(t, "GET", fmt.Sprintf("%s/api/reports/%d", ts.URL, r.ID), ""), &got)
+	var payload map[string]int
+	if err := json.Unmarshal(got.Report, &payload); err != nil || payload["sig_count"] != 1 {
+		t.Errorf("report payload = %s, want the stored payload", got.Report)
+	}
+	if len(got.Items) != 1 || got.Items[0].Topic != "Logging exporter" {
+		t.Errorf("report items = %+v, want the stored item", got.Items)
+	}
+
+	if resp := doRequest(t, "GET", ts.URL+"/api/reports/999", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown report status = %d, want 404", resp.StatusCode)
+	}
+}
+
+// fakePipeline records runs in the store without fetching or calling an LLM.
+type fakePipeline struct {
+	st  *store.Store
+	cfg *config.Config
+	run *store.Run
+	err error
+
+	observers []pipeline.Observer
+}
+
+func (f *fakePipeline) BeginRun(command string) (*store.Run, error) {
+	f.run = &store.Run{Command: command, Config: "{}", SIGSet: "all", Status: store.RunRunning}
+	return f.run, f.st.CreateRun(f.run)
+}
+
+func (f *fakePipeline) EndRun(runErr error) {
+	f.run.Status = store.RunCompleted
+	if runErr != nil {
+		f.run.Status = store.RunFailed
+	}
+	_ = f.st.UpdateRunStatus(f.run.ID, f.run.Status, "")
+}
+
+func (f *fakePipeline) FetchOnly(ctx context.Context) error { return f.err }
+func (f *fakePipeline) Run(ctx context.Context) error       { return f.err }
+func (f *fakePipeline) Close() error                        { return nil }
+
+// AnalyzeOnly reports one SIG analyzed to the subscribed observers.
+func (f *fakePipeline) AnalyzeOnly(ctx context.Context) error {
+	for _, e := range []pipeline.Event{
+		{Type: pipeline.EventPhaseStarted, Phase: "analyze", Total: 1},
+		{Type: pipeline.EventLLMUsage, Phase: "analyze", SIGID: "collector", Tokens: 1200},
+		{Type: pipeline.EventSIGState, Phase: "analyze", SIGID: "collector", State: pipeline.StateDone},
+		{Type: pipeline.EventPhaseFinished, Phase: "analyze"},
+	} {
+		e.Time = time.Now()
+		for _, o := range f.observers {
+			o.OnEvent(e)
+		}
+	}
+	return f.err
+}
+
+func (f *fakePipeline) Subscribe(o pipeline.Observer) func() {
+	f.observers = append(f.observers, o)
+	return func() {}
+}
+
+func TestServer_Jobs(t *testing.T) {
+	srv, st, ts := newTestServer(t)
+
+	var gotCfg *config.Config
+	srv.newPipeline = func(c *config.Config) (Pipeline, error) {
+		gotCfg = c
+		return &fakePipeline{st: st, cfg: c}, nil
+	}
+	var observed []pipeline.Event
+	srv.AddObserver(pipeline.ObserverFunc(func(e pipeline.Event) { observed = append(observed, e) }))
+
+	for _, body := range []string{
+		`{"command":"delete"}`,
+		`{"command":"report","week":"2026-W99"}`,
+		`{"command":"report","bogus":true}`,
+	} {
+		if resp := doRequest(t, "POST", ts.URL+"/api/jobs", body); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("POST %s status = %d, want 400", body, resp.StatusCode)
+		}
+	}
+
+	resp := doRequest(t, "POST", ts.URL+"/api/jobs", `{"command":"report","sigs":["collector"],"week":"2026-W41","offline":true}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /api/jobs status = %d, want 202", resp.StatusCode)
+	}
+	var created job
+	decode(t, resp, &created)
+	if created.Status != jobQueued {
+		t.Errorf("new job status = %q, want %q", created.Status, jobQueued)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		srv.RunJobs(ctx)
+		close(done)
+	}()
+
+	var got job
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		decode(t, doRequest(t, "GET", fmt.Sprintf("%s/api/jobs/%d", ts.URL, created.ID), ""), &got)
+		if got.Status != jobQueued && got.Status != jobRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job did not finish, status %q", got.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if got.Status != store.RunCompleted || got.RunID == 0 {
+		t.Errorf("finished job = %+v, want completed with a run ID", got)
+	}
+	if pr := got.Progress; pr == nil || pr.Phase != "analyze" || pr.Done != 1 || pr.Tokens != 1200 ||
+		len(pr.SIGs) != 1 || pr.SIGs[0].State != pipeline.StateDone {
+		t.Errorf("job progress = %+v, want the analyze phase with collector done", pr)
+	}
+	if len(observed) != 4 {
+		t.Errorf("added observer saw %d events, want 4", len(observed))
+	}
+	if gotCfg == nil || gotCfg.Week != "2026-W41" || !gotCfg.Offline || len(gotCfg.SIGs) != 1 {
+		t.Errorf("job config = %+v, want the requested week, SIGs and offline", gotCfg)
+	}
+	if srv.cfg.Week != "" || srv.cfg.Offline {
+		t.Error("job settings must not leak into the server config")
+	}
+
+	var run runJSON
+	decode(t, doRequest(t, "GET", fmt.Sprintf("%s/api/runs/%d", ts.URL, got.RunID), ""), &run)
+	if run.Status != store.RunCompleted || run.Command != "report" {
+		t.Errorf("run = %+v, want a completed report run", run)
+	}
+
+	var jobs []job
+	decode(t, doRequest(t, "GET", ts.URL+"/api/jobs", ""), &jobs)
+	if len(jobs) != 1 {
+		t.Errorf("GET /api/jobs returned %d jobs, want 1", len(jobs))
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	long := strings.Repeat("a", 200) + " needle " + strings.Repeat("b", 200)
+	got := snippet(long, "NEEDLE", 60)
+	if !strings.Contains(got, "needle") {
+		t.Errorf("snippet %q should contain the match", got)
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet %q should be elided on both sides", got)
+	}
+	if got := snippet("short text", "text", 60); got != "short text" {
+		t.Errorf("snippet of short text = %q, want it unchanged", got)
+	}
+}
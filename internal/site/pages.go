@@ -0,0 +1,362 @@
+package site
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var pageTemplates = template.Must(template.New("").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).ParseFS(templateFS, "templates/*.html"))
+
+// siteCSS is the stylesheet shared by the index, SIG and tag pages. Digest
+// pages are self-contained and carry their own styles.
+var siteCSS, _ = templateFS.ReadFile("templates/style.css")
+
+// Levels in the order items are listed.
+var levels = []string{"HIGH", "MEDIUM", "LOW"}
+
+// pageBase holds the fields every page template uses. Root is the relative
+// path from the page to the site root, "" or "../".
+type pageBase struct {
+	Title     string
+	Root      string
+	Generated string
+}
+
+func rootPage(title string) pageBase {
+	return pageBase{Title: title, Generated: time.Now().UTC().Format("2006-01-02 15:04 UTC")}
+}
+
+func subPage(title string) pageBase {
+	p := rootPage(title)
+	p.Root = "../"
+	return p
+}
+
+type indexPage struct {
+	pageBase
+	Weeks []*week
+}
+
+type sigsPage struct {
+	pageBase
+	SIGs []*sigHistory
+}
+
+type sigPage struct {
+	pageBase
+	SIG *sigHistory
+}
+
+type tagsPage struct {
+	pageBase
+	Tags []*tag
+}
+
+type tagPage struct {
+	pageBase
+	Tag *tag
+}
+
+// week groups the digests whose end date falls in one ISO week.
+type week struct {
+	Name    string
+	Digests []*digestLink
+}
+
+// digestLink summarizes a digest on the index page.
+type digestLink struct {
+	Page              string
+	Range             string
+	SIGSet            string
+	Active            int
+	High, Medium, Low int
+}
+
+// item is a relevance item as listed on SIG and tag pages.
+type item struct {
+	Level  string
+	Status analysis.ItemStatus
+	Text   template.HTML
+}
+
+// sigHistory is a SIG's activity across all digests, newest first.
+type sigHistory struct {
+	ID      string
+	Name    string
+	Weeks   []*sigWeek
+	Items   int
+	Ongoing int
+}
+
+// sigWeek is a SIG's section of one digest.
+type sigWeek struct {
+	Week          string
+	Range         string
+	Page          string // digest page, with the SIG's anchor
+	Items         []item
+	Ongoing       []string
+	NotesLink     string
+	RecordingLink string
+}
+
+// tag is a topic and every item about it, newest first.
+type tag struct {
+	Slug    string
+	Name    string
+	Entries []*tagEntry
+}
+
+// tagEntry is an item about a topic in one digest.
+type tagEntry struct {
+	Week    string
+	Range   string
+	SIGID   string
+	SIGName string
+	Page    string // digest page, with the SIG's anchor
+	Item    item
+}
+
+func sigPagePath(id string) string   { return "sigs/" + id + ".html" }
+func tagPagePath(slug string) string { return "tags/" + slug + ".html" }
+
+func (h *sigHistory) page() string { return sigPagePath(h.ID) }
+func (t *tag) page() string        { return tagPagePath(t.Slug) }
+
+// render executes the named template into the page at rel.
+func (b *Builder) render(rel, name string, data any) error {
+	var buf bytes.Buffer
+	if err := pageTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		return fmt.Errorf("rendering %s: %w", rel, err)
+	}
+	return b.write(rel, buf.Bytes())
+}
+
+// activeSIGs returns the digest's SIG reports with relevance items or
+// ongoing topics, which are the ones with a section on its page.
+func (e *entry) activeSIGs() []*analysis.SIGReport {
+	var active []*analysis.SIGReport
+	for _, sr := range e.digest.SIGReports {
+		if rr := sr.RelevanceReport; rr != nil && len(rr.HighItems)+len(rr.MediumItems)+len(rr.LowItems)+len(rr.OngoingItems) > 0 {
+			active = append(active, sr)
+		}
+	}
+	return active
+}
+
+// sigIDs returns the IDs of the SIG pages the digest contributes to.
+func (e *entry) sigIDs() []string {
+	var ids []string
+	for _, sr := range e.activeSIGs() {
+		if id := sigID(sr); !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// tagSlugs returns the slugs of the tag pages the digest contributes to.
+func (e *entry) tagSlugs() []string {
+	var slugs []string
+	for _, sr := range e.activeSIGs() {
+		forEachItem(sr.RelevanceReport, func(_ string, text string) {
+			if slug := report.Slugify(analysis.ItemTopic(text)); slug != "" && !slices.Contains(slugs, slug) {
+				slugs = append(slugs, slug)
+			}
+		})
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// anchorPage returns the link to a SIG's section of the digest page, from
+// a page one directory below the site root.
+func (e *entry) anchorPage(sr *analysis.SIGReport) string {
+	return "../" + e.page + "#" + report.SIGAnchor(sr)
+}
+
+func (e *entry) dateRange() string {
+	if e.digest.DateRangeStart == e.digest.DateRangeEnd {
+		return e.digest.DateRangeEnd
+	}
+	return e.digest.DateRangeStart + " to " + e.digest.DateRangeEnd
+}
+
+// groupByWeek groups entries, which are sorted newest first, by week.
+func groupByWeek(entries []*entry) []*week {
+	var weeks []*week
+	for _, e := range entries {
+		if len(weeks) == 0 || weeks[len(weeks)-1].Name != e.week {
+			weeks = append(weeks, &week{Name: e.week})
+		}
+		link := &digestLink{Page: e.page, Range: e.dateRange(), SIGSet: e.rec.SIGSet}
+		if link.SIGSet == "" || link.SIGSet == "all" {
+			link.SIGSet = "All SIGs"
+		}
+		for _, sr := range e.activeSIGs() {
+			rr := sr.RelevanceReport
+			link.Active++
+			link.High += len(rr.HighItems)
+			link.Medium += len(rr.MediumItems)
+			link.Low += len(rr.LowItems)
+		}
+		w := weeks[len(weeks)-1]
+		w.Digests = append(w.Digests, link)
+	}
+	return weeks
+}
+
+// buildSIGHistories returns the history of every SIG with activity in any
+// digest, sorted by name.
+func buildSIGHistories(entries []*entry) []*sigHistory {
+	byID := map[string]*sigHistory{}
+	var sigs []*sigHistory
+	for _, e := range entries {
+		for _, sr := range e.activeSIGs() {
+			id := sigID(sr)
+			h := byID[id]
+			if h == nil {
+				// Entries are newest first, so this is the SIG's current name.
+				h = &sigHistory{ID: id, Name: sr.SIGName}
+				byID[id] = h
+				sigs = append(sigs, h)
+			}
+			rr := sr.RelevanceReport
+			sw := &sigWeek{
+				Week:          e.week,
+				Range:         e.dateRange(),
+				Page:          e.anchorPage(sr),
+				NotesLink:     sr.NotesLink,
+				RecordingLink: sr.RecordingLink,
+			}
+			forEachItem(rr, func(level, text string) {
+				sw.Items = append(sw.Items, newItem(level, text, rr))
+			})
+			for _, text := range rr.OngoingItems {
+				sw.Ongoing = append(sw.Ongoing, analysis.ItemTopic(text))
+			}
+			h.Weeks = append(h.Weeks, sw)
+			h.Items += len(sw.Items)
+			h.Ongoing += len(sw.Ongoing)
+		}
+	}
+	sort.Slice(sigs, func(i, j int) bool { return strings.ToLower(sigs[i].Name) < strings.ToLower(sigs[j].Name) })
+	return sigs
+}
+
+// buildTags returns a tag for every item topic in any digest, sorted by
+// name.
+func buildTags(entries []*entry) []*tag {
+	bySlug := map[string]*tag{}
+	var tags []*tag
+	for _, e := range entries {
+		for _, sr := range e.activeSIGs() {
+			rr := sr.RelevanceReport
+			forEachItem(rr, func(level, text string) {
+				topic := analysis.ItemTopic(text)
+				slug := report.Slugify(topic)
+				if slug == "" {
+					return
+				}
+				t := bySlug[slug]
+				if t == nil {
+					t = &tag{Slug: slug, Name: topic}
+					bySlug[slug] = t
+					tags = append(tags, t)
+				}
+				t.Entries = append(t.Entries, &tagEntry{
+					Week:    e.week,
+					Range:   e.dateRange(),
+					SIGID:   sigID(sr),
+					SIGName: sr.SIGName,
+					Page:    e.anchorPage(sr),
+					Item:    newItem(level, text, rr),
+				})
+			})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return strings.ToLower(tags[i].Name) < strings.ToLower(tags[j].Name) })
+	return tags
+}
+
+// searchDoc is one relevance item in the search index. Keys are short to
+// keep the index small.
+type searchDoc struct {
+	Topic string `json:"t"`
+	Text  string `json:"x"`
+	SIG   string `json:"s"`
+	Week  string `json:"w"`
+	Level string `json:"l"`
+	URL   string `json:"u"`
+}
+
+var (
+	boldPattern = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	linkPattern = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+)
+
+// searchIndexJS returns the search index as a script that assigns it to
+// window.SEARCH_INDEX, so the search page also works when opened from disk,
+// where browsers refuse to fetch JSON files.
+func searchIndexJS(entries []*entry) ([]byte, error) {
+	docs := []searchDoc{}
+	for _, e := range entries {
+		for _, sr := range e.activeSIGs() {
+			forEachItem(sr.RelevanceReport, func(level, text string) {
+				plain := linkPattern.ReplaceAllString(boldPattern.ReplaceAllString(text, "$1"), "$1")
+				docs = append(docs, searchDoc{
+					Topic: analysis.ItemTopic(text),
+					Text:  plain,
+					SIG:   sr.SIGName,
+					Week:  e.week,
+					Level: level,
+					URL:   e.page + "#" + report.SIGAnchor(sr),
+				})
+			})
+		}
+	}
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return nil, fmt.Errorf("encoding search index: %w", err)
+	}
+	return []byte("window.SEARCH_INDEX = " + string(data) + ";\n"), nil
+}
+
+// forEachItem calls fn for each relevance item in rr, by level.
+func forEachItem(rr *analysis.RelevanceReport, fn func(level, text string)) {
+	for i, items := range [][]string{rr.HighItems, rr.MediumItems, rr.LowItems} {
+		for _, text := range items {
+			fn(levels[i], text)
+		}
+	}
+}
+
+func newItem(level, text string, rr *analysis.RelevanceReport) item {
+	status := rr.ItemStatuses[text]
+	if status != analysis.ItemNew && status != analysis.ItemUpdated {
+		status = ""
+	}
+	return item{Level: level, Status: status, Text: report.ItemHTML(text)}
+}
+
+// sigID returns the name of the SIG's page, derived from its digest page
+// anchor.
+func sigID(sr *analysis.SIGReport) string {
+	return report.Slugify(strings.TrimPrefix(report.SIGAnchor(sr), "sig-"))
+}
@@ -0,0 +1,319 @@
+// Package site builds a static website from the stored digest archive: an
+// index by week, a history page per SIG, topic tag pages and a client-side
+// search index. Builds are incremental; a manifest in the output directory
+// records what each page was built from, so only pages whose inputs changed
+// are written again.
+package site
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+// manifestFile is the name of the build manifest in the output directory.
+const manifestFile = ".site-manifest.json"
+
+// layoutVersion is bumped whenever page templates or paths change, which
+// forces a full rebuild of existing sites.
+const layoutVersion = 1
+
+// Builder writes the static site for a store's digests to a directory.
+type Builder struct {
+	store  *store.Store
+	outDir string
+	logger *slog.Logger
+}
+
+// Result counts what a build did.
+type Result struct {
+	// DigestPages, SIGPages and TagPages count pages written.
+	DigestPages int
+	SIGPages    int
+	TagPages    int
+	// Unchanged counts digests whose pages were already up to date.
+	Unchanged int
+	// Removed counts pages deleted because their reports or topics are gone.
+	Removed int
+}
+
+// manifest records the inputs of a build.
+type manifest struct {
+	Version int                       `json:"version"`
+	Reports map[string]manifestReport `json:"reports"`
+}
+
+// manifestReport records one digest page and the SIG and tag pages it
+// contributes to.
+type manifestReport struct {
+	Hash string   `json:"hash"`
+	Page string   `json:"page"`
+	SIGs []string `json:"sigs"`
+	Tags []string `json:"tags"`
+}
+
+// New creates a Builder that reads digests from s and writes to outDir.
+func New(s *store.Store, outDir string) *Builder {
+	return &Builder{store: s, outDir: outDir, logger: slog.Default()}
+}
+
+// SetLogger replaces the builder's logger.
+func (b *Builder) SetLogger(l *slog.Logger) {
+	b.logger = l
+}
+
+// Build writes the site. Digest pages are written for new or changed
+// reports only, SIG and tag pages for the SIGs and topics those reports
+// touch, and the index pages whenever anything changed. With full, every
+// page is written again.
+func (b *Builder) Build(full bool) (*Result, error) {
+	recs, err := b.store.ListReports("digest", 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing digests: %w", err)
+	}
+	var entries []*entry
+	for _, rec := range recs {
+		if rec.Payload == "" {
+			b.logger.Warn("digest has no stored content, skipping", "report_id", rec.ID)
+			continue
+		}
+		digest, err := report.UnmarshalDigest([]byte(rec.Payload))
+		if err != nil {
+			return nil, fmt.Errorf("report %d: %w", rec.ID, err)
+		}
+		entries = append(entries, newEntry(rec, digest))
+	}
+	sortEntries(entries)
+
+	if err := os.MkdirAll(b.outDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	old, err := b.readManifest()
+	if err != nil {
+		return nil, err
+	}
+	// The old manifest is still used to remove stale pages on a full rebuild.
+	rebuild := full || old.Version != layoutVersion
+
+	res := &Result{}
+	next := manifest{Version: layoutVersion, Reports: make(map[string]manifestReport, len(entries))}
+	dirtySIGs := map[string]bool{}
+	dirtyTags := map[string]bool{}
+	changed := rebuild
+
+	for _, e := range entries {
+		key := fmt.Sprint(e.rec.ID)
+		m := manifestReport{Hash: e.hash, Page: e.page, SIGs: e.sigIDs(), Tags: e.tagSlugs()}
+		next.Reports[key] = m
+		if prev, ok := old.Reports[key]; ok && !rebuild && prev.Hash == m.Hash && b.exists(m.Page) {
+			res.Unchanged++
+			continue
+		}
+		page, err := report.RenderArchiveDigestHTML(e.digest, "../index.html")
+		if err != nil {
+			return nil, fmt.Errorf("report %d: %w", e.rec.ID, err)
+		}
+		if err := b.write(e.page, []byte(page)); err != nil {
+			return nil, err
+		}
+		b.logger.Debug("wrote digest page", "report_id", e.rec.ID, "page", e.page)
+		res.DigestPages++
+		changed = true
+		markDirty(dirtySIGs, m.SIGs)
+		markDirty(dirtyTags, m.Tags)
+		if prev, ok := old.Reports[key]; ok {
+			// The report's items may have moved between SIGs or topics.
+			markDirty(dirtySIGs, prev.SIGs)
+			markDirty(dirtyTags, prev.Tags)
+		}
+	}
+	for key, prev := range old.Reports {
+		if _, ok := next.Reports[key]; ok {
+			continue
+		}
+		if err := b.remove(prev.Page, res); err != nil {
+			return nil, err
+		}
+		changed = true
+		markDirty(dirtySIGs, prev.SIGs)
+		markDirty(dirtyTags, prev.Tags)
+	}
+
+	sigs := buildSIGHistories(entries)
+	for _, h := range sigs {
+		if !dirtySIGs[h.ID] && b.exists(h.page()) {
+			continue
+		}
+		if err := b.render(h.page(), "sig.html", sigPage{pageBase: subPage(h.Name), SIG: h}); err != nil {
+			return nil, err
+		}
+		res.SIGPages++
+		changed = true
+	}
+	tags := buildTags(entries)
+	for _, t := range tags {
+		if !dirtyTags[t.Slug] && b.exists(t.page()) {
+			continue
+		}
+		if err := b.render(t.page(), "tag.html", tagPage{pageBase: subPage(t.Name), Tag: t}); err != nil {
+			return nil, err
+		}
+		res.TagPages++
+		changed = true
+	}
+	// Pages of SIGs and topics that no longer appear in any digest.
+	for id := range dirtySIGs {
+		if !slices.ContainsFunc(sigs, func(h *sigHistory) bool { return h.ID == id }) {
+			if err := b.remove(sigPagePath(id), res); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for slug := range dirtyTags {
+		if !slices.ContainsFunc(tags, func(t *tag) bool { return t.Slug == slug }) {
+			if err := b.remove(tagPagePath(slug), res); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if changed || !b.exists("index.html") {
+		if err := b.writeIndexes(entries, sigs, tags); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.writeManifest(next); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// writeIndexes writes the pages that list every digest, SIG or topic, the
+// search page and index, and the stylesheet.
+func (b *Builder) writeIndexes(entries []*entry, sigs []*sigHistory, tags []*tag) error {
+	if err := b.write("style.css", siteCSS); err != nil {
+		return err
+	}
+	if err := b.render("index.html", "index.html", indexPage{pageBase: rootPage("OTel SIG Digest Archive"), Weeks: groupByWeek(entries)}); err != nil {
+		return err
+	}
+	if err := b.render("sigs/index.html", "sigs.html", sigsPage{pageBase: subPage("SIGs"), SIGs: sigs}); err != nil {
+		return err
+	}
+	if err := b.render("tags/index.html", "tags.html", tagsPage{pageBase: subPage("Topics"), Tags: tags}); err != nil {
+		return err
+	}
+	if err := b.render("search.html", "search.html", rootPage("Search")); err != nil {
+		return err
+	}
+	index, err := searchIndexJS(entries)
+	if err != nil {
+		return err
+	}
+	return b.write("search-index.js", index)
+}
+
+func (b *Builder) readManifest() (manifest, error) {
+	var m manifest
+	data, err := os.ReadFile(filepath.Join(b.outDir, manifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return m, nil
+	} else if err != nil {
+		return m, fmt.Errorf("reading site manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		// A damaged manifest only costs a full rebuild.
+		b.logger.Warn("ignoring unreadable site manifest", "err", err)
+		return manifest{}, nil
+	}
+	return m, nil
+}
+
+func (b *Builder) writeManifest(m manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding site manifest: %w", err)
+	}
+	return b.write(manifestFile, data)
+}
+
+// write writes data to the path relative to the output directory.
+func (b *Builder) write(rel string, data []byte) error {
+	path := filepath.Join(b.outDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating directory for %s: %w", rel, err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", rel, err)
+	}
+	return nil
+}
+
+// remove deletes the page at rel, if it exists.
+func (b *Builder) remove(rel string, res *Result) error {
+	err := os.Remove(filepath.Join(b.outDir, filepath.FromSlash(rel)))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("removing %s: %w", rel, err)
+	}
+	b.logger.Debug("removed stale page", "page", rel)
+	res.Removed++
+	return nil
+}
+
+func (b *Builder) exists(rel string) bool {
+	_, err := os.Stat(filepath.Join(b.outDir, filepath.FromSlash(rel)))
+	return err == nil
+}
+
+func markDirty(set map[string]bool, keys []string) {
+	for _, k := range keys {
+		set[k] = true
+	}
+}
+
+// entry is a stored digest with the derived data the site needs.
+type entry struct {
+	rec    *store.Report
+	digest *analysis.DigestReport
+	week   string // ISO week of the digest's end date, e.g. "2026-W08"
+	page   string // path of the digest page, relative to the output directory
+	hash   string
+}
+
+func newEntry(rec *store.Report, digest *analysis.DigestReport) *entry {
+	e := &entry{rec: rec, digest: digest, hash: rec.ContentHash}
+	if e.hash == "" {
+		e.hash = fmt.Sprintf("%x", sha256.Sum256([]byte(rec.Payload)))
+	}
+	e.week = report.DigestWeek(digest.DateRangeEnd, rec.DateRangeEnd)
+	end := digest.DateRangeEnd
+	if end == "" {
+		end = rec.DateRangeEnd.Format("2006-01-02")
+	}
+	e.page = fmt.Sprintf("digests/%s-%d.html", end, rec.ID)
+	return e
+}
+
+// sortEntries orders entries newest first, breaking ties by report ID.
+func sortEntries(entries []*entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.digest.DateRangeEnd != b.digest.DateRangeEnd {
+			return a.digest.DateRangeEnd > b.digest.DateRangeEnd
+		}
+		return a.rec.ID > b.rec.ID
+	})
+}
@@ -0,0 +1,237 @@
+package site
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gordyrad/otel-sig-tracker/internal/analysis"
+	"github.com/gordyrad/otel-sig-tracker/internal/report"
+	"github.com/gordyrad/otel-sig-tracker/internal/store"
+)
+
+func newTestStore(t *testing.T) *store.Store {
+	t.Helper()
+	s, err := store.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("store.New: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func insertDigest(t *testing.T, s *store.Store, digest *analysis.DigestReport) *store.Report {
+	t.Helper()
+	payload, err := report.MarshalDigest(digest)
+	if err != nil {
+		t.Fatalf("MarshalDigest: %v", err)
+	}
+	start, _ := time.Parse("2006-01-02", digest.DateRangeStart)
+	end, _ := time.Parse("2006-01-02", digest.DateRangeEnd)
+	rec := &store.Report{ReportType: "digest", SIGSet: "all", DateRangeStart: start, DateRangeEnd: end, Payload: string(payload)}
+	if err := s.InsertReport(rec); err != nil {
+		t.Fatalf("InsertReport: %v", err)
+	}
+	return rec
+}
+
+func digestFor(start, end string, collectorItem string) *analysis.DigestReport {
+	return &analysis.DigestReport{
+		DateRangeStart: start,
+		DateRangeEnd:   end,
+		SIGReports: []*analysis.SIGReport{
+			{
+				SIGID:   "collector",
+				SIGName: "Collector",
+				RelevanceReport: &analysis.RelevanceReport{
+					HighItems:    []string{collectorItem},
+					ItemStatuses: map[string]analysis.ItemStatus{collectorItem: analysis.ItemNew},
+				},
+			},
+			{
+				SIGID:   "specification",
+				SIGName: "Specification",
+				RelevanceReport: &analysis.RelevanceReport{
+					MediumItems: []string{"**Profiling Signal OTEP** — new profiling signal."},
+				},
+			},
+			{SIGID: "go", SIGName: "Go SDK", RelevanceReport: &analysis.RelevanceReport{}},
+		},
+	}
+}
+
+func readFile(t *testing.T, dir, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil {
+		t.Fatalf("reading %s: %v", rel, err)
+	}
+	return string(data)
+}
+
+func TestBuild(t *testing.T) {
+	s := newTestStore(t)
+	first := insertDigest(t, s, digestFor("2026-02-04", "2026-02-11", "**OTLP/HTTP Partial Success** — affects OTLP ingest."))
+	second := insertDigest(t, s, digestFor("2026-02-11", "2026-02-18", "**OTLP/HTTP Partial Success** — now merged."))
+	out := t.TempDir()
+
+	res, err := New(s, out).Build(false)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if res.DigestPages != 2 || res.SIGPages != 2 || res.TagPages != 2 || res.Unchanged != 0 {
+		t.Errorf("result = %+v, want 2 digest, 2 SIG and 2 tag pages", res)
+	}
+
+	index := readFile(t, out, "index.html")
+	for _, want := range []string{
+		`<h2 id="2026-W08">2026-W08</h2>`,
+		`<h2 id="2026-W07">2026-W07</h2>`,
+		`href="digests/2026-02-18-` + itoa(second.ID) + `.html"`,
+		`href="digests/2026-02-11-` + itoa(first.ID) + `.html"`,
+	} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html missing %q", want)
+		}
+	}
+	if strings.Index(index, "2026-W08") > strings.Index(index, "2026-W07") {
+		t.Error("index.html should list the newest week first")
+	}
+
+	digestPage := readFile(t, out, "digests/2026-02-18-"+itoa(second.ID)+".html")
+	if !strings.Contains(digestPage, `href="../index.html"`) {
+		t.Error("digest page should link back to the index")
+	}
+
+	sig := readFile(t, out, "sigs/collector.html")
+	for _, want := range []string{"now merged", "affects OTLP ingest", `<span class="badge badge-status">NEW</span>`, `href="../digests/2026-02-18-` + itoa(second.ID) + `.html#sig-collector"`} {
+		if !strings.Contains(sig, want) {
+			t.Errorf("sigs/collector.html missing %q", want)
+		}
+	}
+	if strings.Index(sig, "now merged") > strings.Index(sig, "affects OTLP ingest") {
+		t.Error("SIG history should list the newest digest first")
+	}
+	if _, err := os.Stat(filepath.Join(out, "sigs", "go.html")); err == nil {
+		t.Error("quiet SIG should not get a page")
+	}
+
+	tag := readFile(t, out, "tags/otlp-http-partial-success.html")
+	if !strings.Contains(tag, "now merged") || !strings.Contains(tag, `href="../sigs/collector.html"`) {
+		t.Errorf("tag page missing items or SIG link:\n%s", tag)
+	}
+	if tags := readFile(t, out, "tags/index.html"); !strings.Contains(tags, `href="profiling-signal-otep.html"`) {
+		t.Error("tags/index.html missing profiling tag")
+	}
+
+	js := readFile(t, out, "search-index.js")
+	if !strings.HasPrefix(js, "window.SEARCH_INDEX = ") {
+		t.Fatalf("search-index.js = %q", js)
+	}
+	var docs []searchDoc
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(js, "window.SEARCH_INDEX = "), ";\n")), &docs); err != nil {
+		t.Fatalf("decoding search index: %v", err)
+	}
+	if len(docs) != 4 {
+		t.Fatalf("search index has %d docs, want 4", len(docs))
+	}
+	if d := docs[0]; d.Topic != "OTLP/HTTP Partial Success" || d.Text != "OTLP/HTTP Partial Success — now merged." || d.Week != "2026-W08" || d.Level != "HIGH" || d.URL != "digests/2026-02-18-"+itoa(second.ID)+".html#sig-collector" {
+		t.Errorf("docs[0] = %+v", d)
+	}
+	for _, rel := range []string{"search.html", "style.css", "sigs/index.html", manifestFile} {
+		if _, err := os.Stat(filepath.Join(out, rel)); err != nil {
+			t.Errorf("missing %s: %v", rel, err)
+		}
+	}
+}
+
+func TestBuild_Incremental(t *testing.T) {
+	s := newTestStore(t)
+	insertDigest(t, s, digestFor("2026-02-04", "2026-02-11", "**OTLP/HTTP Partial Success** — affects OTLP ingest."))
+	out := t.TempDir()
+	b := New(s, out)
+	if _, err := b.Build(false); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	res, err := b.Build(false)
+	if err != nil {
+		t.Fatalf("second Build: %v", err)
+	}
+	if *res != (Result{Unchanged: 1}) {
+		t.Errorf("rebuild without new reports = %+v, want only 1 unchanged", *res)
+	}
+
+	// A new digest touching only the Collector rebuilds its page, the
+	// Collector page and its new topic, but not the Specification page.
+	insertDigest(t, s, &analysis.DigestReport{
+		DateRangeStart: "2026-02-11",
+		DateRangeEnd:   "2026-02-18",
+		SIGReports: []*analysis.SIGReport{{
+			SIGID:           "collector",
+			SIGName:         "Collector",
+			RelevanceReport: &analysis.RelevanceReport{LowItems: []string{"**Batch Processor Deprecation** — moving to exporters."}},
+		}},
+	})
+	res, err = b.Build(false)
+	if err != nil {
+		t.Fatalf("third Build: %v", err)
+	}
+	if want := (Result{DigestPages: 1, SIGPages: 1, TagPages: 1, Unchanged: 1}); *res != want {
+		t.Errorf("incremental build = %+v, want %+v", *res, want)
+	}
+	if !strings.Contains(readFile(t, out, "index.html"), "2026-W08") {
+		t.Error("index.html not updated with the new digest")
+	}
+
+	res, err = b.Build(true)
+	if err != nil {
+		t.Fatalf("full Build: %v", err)
+	}
+	if want := (Result{DigestPages: 2, SIGPages: 2, TagPages: 3}); *res != want {
+		t.Errorf("full build = %+v, want %+v", *res, want)
+	}
+}
+
+func TestBuild_RemovesStalePages(t *testing.T) {
+	s := newTestStore(t)
+	out := t.TempDir()
+	b := New(s, out)
+
+	// A manifest from a build that included a report since deleted.
+	stale := manifest{Version: layoutVersion, Reports: map[string]manifestReport{
+		"99": {Hash: "x", Page: "digests/2026-01-07-99.html", SIGs: []string{"java"}, Tags: []string{"old-topic"}},
+	}}
+	if err := b.writeManifest(stale); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{stale.Reports["99"].Page, "sigs/java.html", "tags/old-topic.html"} {
+		if err := b.write(rel, []byte("stale")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := b.Build(false)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if res.Removed != 3 {
+		t.Errorf("Removed = %d, want 3", res.Removed)
+	}
+	for _, rel := range []string{stale.Reports["99"].Page, "sigs/java.html", "tags/old-topic.html"} {
+		if b.exists(rel) {
+			t.Errorf("%s was not removed", rel)
+		}
+	}
+	if !strings.Contains(readFile(t, out, "index.html"), "No digests have been stored yet.") {
+		t.Error("index.html should say the archive is empty")
+	}
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -5,14 +5,16 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 )
 
 const (
@@ -23,6 +25,7 @@ const (
 type GoogleDocsFetcher struct {
 	store      *store.Store
 	httpClient *http.Client
+	logger     *slog.Logger
 }
 
 // NewGoogleDocsFetcher creates a new GoogleDocsFetcher.
@@ -30,11 +33,18 @@ func NewGoogleDocsFetcher(s *store.Store) *GoogleDocsFetcher {
 	return &GoogleDocsFetcher{
 		store: s,
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: telemetry.Transport(nil, "notes"),
 		},
+		logger: slog.Default(),
 	}
 }
 
+// SetLogger replaces the fetcher's logger.
+func (f *GoogleDocsFetcher) SetLogger(l *slog.Logger) {
+	f.logger = l
+}
+
 // parsedMeeting holds a single parsed meeting extracted from a Google Doc.
 type parsedMeeting struct {
 	date    time.Time
@@ -89,8 +99,9 @@ func (f *GoogleDocsFetcher) FetchMeetingNotes(ctx context.Context, sig *store.SI
 			ContentHash: hash,
 		}
 		if err := f.store.UpsertMeetingNote(note); err != nil {
-			log.Printf("warning: failed to store meeting note for %s on %s: %v",
-				sig.ID, m.date.Format("2006-01-02"), err)
+			f.logger.Warn("failed to store meeting note",
+				logging.KeySIG, sig.ID, logging.KeySource, "notes",
+				"meeting_date", m.date.Format("2006-01-02"), "err", err)
 			continue
 		}
 		stored++
@@ -102,8 +113,9 @@ func (f *GoogleDocsFetcher) FetchMeetingNotes(ctx context.Context, sig *store.SI
 	}
 	f.logFetch(sig.ID, url, status, "", time.Since(fetchStart))
 
-	log.Printf("googledocs: %s — found %d meetings in range, stored %d",
-		sig.ID, len(meetings), stored)
+	f.logger.Info("fetched meeting notes",
+		logging.KeySIG, sig.ID, logging.KeySource, "notes",
+		"meetings", len(meetings), "stored", stored)
 	return nil
 }
 
@@ -244,16 +256,19 @@ func sha256Hash(s string) string {
 	return fmt.Sprintf("%x", h)
 }
 
-// startOfDay returns the start of the day (00:00:00) for the given time.
+// startOfDay returns the start of t's calendar day (00:00:00). The day is
+// taken in t's own location, but the result is in UTC because meeting dates
+// parsed from docs and sheets carry no zone and are compared as UTC.
 func startOfDay(t time.Time) time.Time {
 	y, m, d := t.Date()
-	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
-// endOfDay returns the end of the day (23:59:59) for the given time.
+// endOfDay returns the end of t's calendar day (23:59:59), in UTC like
+// startOfDay.
 func endOfDay(t time.Time) time.Time {
 	y, m, d := t.Date()
-	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+	return time.Date(y, m, d, 23, 59, 59, 0, time.UTC)
 }
 
 // logFetch records a fetch operation in the store.
@@ -5,13 +5,15 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
 	"github.com/gordyrad/otel-sig-tracker/internal/registry"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 )
 
 const (
@@ -33,17 +35,25 @@ type Recording struct {
 // GoogleSheetsFetcher fetches the recording list from the public Google Sheet.
 type GoogleSheetsFetcher struct {
 	httpClient *http.Client
+	logger     *slog.Logger
 }
 
 // NewGoogleSheetsFetcher creates a new GoogleSheetsFetcher.
 func NewGoogleSheetsFetcher() *GoogleSheetsFetcher {
 	return &GoogleSheetsFetcher{
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: telemetry.Transport(nil, "recordings"),
 		},
+		logger: slog.Default(),
 	}
 }
 
+// SetLogger replaces the fetcher's logger.
+func (f *GoogleSheetsFetcher) SetLogger(l *slog.Logger) {
+	f.logger = l
+}
+
 // FetchRecordings downloads the recording spreadsheet as CSV, parses it, and
 // returns recordings filtered by the given date range and SIG IDs.
 // If sigIDs is empty, all SIGs are included.
@@ -141,7 +151,8 @@ func (f *GoogleSheetsFetcher) parseCSV(r io.Reader, start, end time.Time, sigIDs
 		// Parse start time. Format: "YYYY-MM-DD H:MM:SS"
 		recTime, err := parseRecordingTime(startStr)
 		if err != nil {
-			log.Printf("googlesheets: skipping row with unparseable time %q: %v", startStr, err)
+			f.logger.Debug("skipping recording row with unparseable time",
+				logging.KeySource, "recordings", "time", startStr, "err", err)
 			continue
 		}
 
@@ -173,8 +184,8 @@ func (f *GoogleSheetsFetcher) parseCSV(r io.Reader, start, end time.Time, sigIDs
 		})
 	}
 
-	log.Printf("googlesheets: parsed %d recordings in date range from %d total rows",
-		len(recordings), len(records)-1)
+	f.logger.Info("parsed recordings sheet",
+		logging.KeySource, "recordings", "in_range", len(recordings), "rows", len(records)-1)
 
 	return recordings, nil
 }
@@ -3,16 +3,19 @@ package sources
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 	"golang.org/x/time/rate"
 )
 
@@ -22,6 +25,11 @@ const (
 	slackPageSize = 200
 )
 
+// ErrSlackAuth is wrapped by errors caused by rejected Slack credentials,
+// such as an expired xoxc- token or d cookie. Running slack-login again
+// fixes them.
+var ErrSlackAuth = errors.New("slack credentials rejected")
+
 // SlackFetcher fetches messages from Slack channels using xoxc- token + d cookie.
 type SlackFetcher struct {
 	store       *store.Store
@@ -29,6 +37,7 @@ type SlackFetcher struct {
 	cookie      string
 	rateLimiter *rate.Limiter
 	httpClient  *http.Client
+	logger      *slog.Logger
 }
 
 // NewSlackFetcher creates a new SlackFetcher with the given credentials.
@@ -40,11 +49,18 @@ func NewSlackFetcher(s *store.Store, token, cookie string) *SlackFetcher {
 		cookie:      cookie,
 		rateLimiter: rate.NewLimiter(rate.Every(1200*time.Millisecond), 1), // ~50 req/min
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: telemetry.Transport(nil, "slack"),
 		},
+		logger: slog.Default(),
 	}
 }
 
+// SetLogger replaces the fetcher's logger.
+func (f *SlackFetcher) SetLogger(l *slog.Logger) {
+	f.logger = l
+}
+
 // slackResponse is the generic Slack API response envelope.
 type slackResponse struct {
 	OK               bool           `json:"ok"`
@@ -77,6 +93,7 @@ func (f *SlackFetcher) FetchMessages(ctx context.Context, sig *store.SIG, start,
 
 	fetchStart := time.Now()
 	channelID := sig.SlackChannelID
+	log := f.logger.With(logging.KeySIG, sig.ID, logging.KeySource, "slack")
 
 	// Convert time range to Slack timestamps (Unix epoch with microseconds).
 	oldest := fmt.Sprintf("%d.000000", start.Unix())
@@ -100,7 +117,7 @@ func (f *SlackFetcher) FetchMessages(ctx context.Context, sig *store.SIG, start,
 		}
 
 		allMessages = append(allMessages, msgs...)
-		log.Printf("slack: %s page %d — got %d messages", sig.ID, page, len(msgs))
+		log.Debug("fetched history page", "page", page, "messages", len(msgs))
 
 		if nextCursor == "" {
 			break
@@ -116,15 +133,14 @@ func (f *SlackFetcher) FetchMessages(ctx context.Context, sig *store.SIG, start,
 		}
 	}
 
-	log.Printf("slack: %s — %d messages, %d threads to fetch",
-		sig.ID, len(allMessages), threadsToFetch)
+	log.Debug("fetched channel history", "messages", len(allMessages), "threads", threadsToFetch)
 
 	// Store top-level messages and fetch threads.
 	stored := 0
 	for _, msg := range allMessages {
 		// Store the message.
 		if err := f.storeMessage(sig, channelID, &msg); err != nil {
-			log.Printf("slack: warning: failed to store message %s: %v", msg.TS, err)
+			log.Warn("failed to store message", "ts", msg.TS, "err", err)
 			continue
 		}
 		stored++
@@ -132,14 +148,14 @@ func (f *SlackFetcher) FetchMessages(ctx context.Context, sig *store.SIG, start,
 		// Fetch thread replies if this is a parent message with replies.
 		if msg.ReplyCount > 0 && msg.ThreadTS == "" {
 			if err := f.fetchAndStoreThread(ctx, sig, channelID, msg.TS); err != nil {
-				log.Printf("slack: warning: failed to fetch thread %s: %v", msg.TS, err)
+				log.Warn("failed to fetch thread", "ts", msg.TS, "err", err)
 				// Continue processing other messages.
 			}
 		}
 	}
 
 	f.logSlackFetch(sig.ID, channelID, "success", "", time.Since(fetchStart))
-	log.Printf("slack: %s — stored %d messages", sig.ID, stored)
+	log.Info("fetched slack messages", "stored", stored)
 
 	return nil
 }
@@ -162,7 +178,7 @@ func (f *SlackFetcher) fetchHistoryPage(ctx context.Context, channelID, oldest,
 	}
 
 	if !resp.OK {
-		return nil, "", fmt.Errorf("Slack API error: %s", resp.Error)
+		return nil, "", slackAPIError(resp.Error)
 	}
 
 	nextCursor := ""
@@ -191,7 +207,7 @@ func (f *SlackFetcher) fetchAndStoreThread(ctx context.Context, sig *store.SIG,
 	}
 
 	if !resp.OK {
-		return fmt.Errorf("Slack API error: %s", resp.Error)
+		return slackAPIError(resp.Error)
 	}
 
 	stored := 0
@@ -203,7 +219,8 @@ func (f *SlackFetcher) fetchAndStoreThread(ctx context.Context, sig *store.SIG,
 
 		msg.ThreadTS = threadTS
 		if err := f.storeMessage(sig, channelID, &msg); err != nil {
-			log.Printf("slack: warning: failed to store thread reply %s: %v", msg.TS, err)
+			f.logger.Warn("failed to store thread reply",
+				logging.KeySIG, sig.ID, logging.KeySource, "slack", "ts", msg.TS, "err", err)
 			continue
 		}
 		stored++
@@ -275,6 +292,16 @@ func (f *SlackFetcher) slackAPICall(ctx context.Context, method string, params u
 	return nil
 }
 
+// slackAPIError returns the error for a Slack API error code, wrapping
+// ErrSlackAuth for codes that mean the credentials are no longer valid.
+func slackAPIError(code string) error {
+	switch code {
+	case "invalid_auth", "not_authed", "token_expired", "token_revoked", "account_inactive":
+		return fmt.Errorf("Slack API error: %s: %w", code, ErrSlackAuth)
+	}
+	return fmt.Errorf("Slack API error: %s", code)
+}
+
 // parseSlackTS converts a Slack timestamp (e.g., "1706123456.789012") to time.Time.
 func parseSlackTS(ts string) (time.Time, error) {
 	parts := strings.SplitN(ts, ".", 2)
@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -39,8 +39,8 @@ type SlackCredentials struct {
 // waits for the user to authenticate interactively, extracts the xoxc- token
 // and d cookie, validates them, and saves credentials to the given file.
 func SlackLogin(ctx context.Context, credsFile string) error {
-	log.Println("slack-login: launching browser for interactive authentication...")
-	log.Println("slack-login: please log in to cloud-native.slack.com in the browser window")
+	slog.Info("slack-login: launching browser for interactive authentication")
+	slog.Info("slack-login: please log in to cloud-native.slack.com in the browser window")
 
 	// Use visible (non-headless) browser for interactive login.
 	pool := browser.NewPool(false)
@@ -57,7 +57,7 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 		return fmt.Errorf("navigating to Slack: %w", err)
 	}
 
-	log.Println("slack-login: waiting for authentication to complete...")
+	slog.Info("slack-login: waiting for authentication to complete")
 
 	// extractToken is the JS snippet that looks for an xoxc- token in all
 	// known locations within Slack's web client.
@@ -138,7 +138,7 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 					})()
 				`, &clicked).Do(ctx)
 				if clicked {
-					log.Println("slack-login: dismissed 'open in app' prompt, loading web client...")
+					slog.Debug("slack-login: dismissed 'open in app' prompt, loading web client")
 					handledRedirect = true
 					continue
 				}
@@ -148,7 +148,7 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 				var currentURL string
 				_ = chromedp.Evaluate(`window.location.href`, &currentURL).Do(ctx)
 				if strings.Contains(currentURL, "/ssb/") || strings.Contains(currentURL, "app_redirect") {
-					log.Println("slack-login: detected app redirect, navigating to web client...")
+					slog.Debug("slack-login: detected app redirect, navigating to web client")
 					_ = chromedp.Navigate("https://app.slack.com/client").Do(ctx)
 					handledRedirect = true
 				}
@@ -163,7 +163,7 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 		return fmt.Errorf("failed to extract xoxc- token (got: %q)", token)
 	}
 
-	log.Println("slack-login: extracted xoxc- token")
+	slog.Info("slack-login: extracted xoxc- token")
 
 	// Extract the d cookie from the browser.
 	var cookies string
@@ -193,7 +193,7 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 			}),
 		)
 		if err != nil {
-			log.Printf("slack-login: warning: CDP cookie extraction failed: %v", err)
+			slog.Warn("slack-login: CDP cookie extraction failed", "err", err)
 		}
 	}
 
@@ -201,7 +201,7 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 		return fmt.Errorf("failed to extract d cookie from browser")
 	}
 
-	log.Println("slack-login: extracted d cookie")
+	slog.Info("slack-login: extracted d cookie")
 
 	// Build credentials.
 	creds := &SlackCredentials{
@@ -220,8 +220,8 @@ func SlackLogin(ctx context.Context, credsFile string) error {
 		return fmt.Errorf("saving credentials: %w", err)
 	}
 
-	log.Printf("slack-login: credentials saved to %s", credsFile)
-	log.Printf("slack-login: team_id=%s user_id=%s", creds.TeamID, creds.UserID)
+	slog.Info("slack-login: credentials saved", "path", credsFile)
+	slog.Info("slack-login: authenticated", "team_id", creds.TeamID, "user_id", creds.UserID)
 
 	return nil
 }
@@ -298,7 +298,7 @@ func ValidateSlackCredentials(creds *SlackCredentials) error {
 	creds.TeamName = result.Team
 	creds.UserName = result.User
 
-	log.Printf("slack-login: authenticated as %s on team %s", result.User, result.Team)
+	slog.Info("slack-login: credentials valid", "user", result.User, "team", result.Team)
 
 	return nil
 }
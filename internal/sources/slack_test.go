@@ -3,7 +3,9 @@ package sources
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -168,6 +170,7 @@ func TestSlackFetcher_FetchMessages(t *testing.T) {
 			base:      http.DefaultTransport,
 			targetURL: srv.URL,
 		}},
+		logger: slog.Default(),
 	}
 
 	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
@@ -233,6 +236,7 @@ func TestSlackFetcher_APIErrorResponse(t *testing.T) {
 			base:      http.DefaultTransport,
 			targetURL: srv.URL,
 		}},
+		logger: slog.Default(),
 	}
 
 	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
@@ -301,6 +305,7 @@ func TestSlackFetcher_Pagination(t *testing.T) {
 			base:      http.DefaultTransport,
 			targetURL: srv.URL,
 		}},
+		logger: slog.Default(),
 	}
 
 	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
@@ -361,6 +366,7 @@ func TestSlackFetcher_HTTPError(t *testing.T) {
 			base:      http.DefaultTransport,
 			targetURL: srv.URL,
 		}},
+		logger: slog.Default(),
 	}
 
 	start := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
@@ -402,3 +408,21 @@ func (t *slackRewriteTransport) RoundTrip(req *http.Request) (*http.Response, er
 	req.URL = parsed
 	return t.base.RoundTrip(req)
 }
+
+func TestSlackAPIError_Auth(t *testing.T) {
+	for code, auth := range map[string]bool{
+		"invalid_auth":      true,
+		"token_expired":     true,
+		"not_authed":        true,
+		"channel_not_found": false,
+		"ratelimited":       false,
+	} {
+		err := slackAPIError(code)
+		if got := errors.Is(err, ErrSlackAuth); got != auth {
+			t.Errorf("slackAPIError(%q) is ErrSlackAuth = %v, want %v", code, got, auth)
+		}
+		if !strings.Contains(err.Error(), code) {
+			t.Errorf("slackAPIError(%q) = %q, want the code in the message", code, err)
+		}
+	}
+}
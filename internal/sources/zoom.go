@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strings"
@@ -12,7 +12,9 @@ import (
 
 	"github.com/chromedp/chromedp"
 	"github.com/gordyrad/otel-sig-tracker/internal/browser"
+	"github.com/gordyrad/otel-sig-tracker/internal/logging"
 	"github.com/gordyrad/otel-sig-tracker/internal/store"
+	"github.com/gordyrad/otel-sig-tracker/internal/telemetry"
 )
 
 const (
@@ -31,10 +33,11 @@ const (
 
 // ZoomFetcher extracts transcripts from Zoom recording share pages.
 type ZoomFetcher struct {
-	store       *store.Store
-	pool        *browser.Pool
-	httpClient  *http.Client
+	store        *store.Store
+	pool         *browser.Pool
+	httpClient   *http.Client
 	delayBetween time.Duration
+	logger       *slog.Logger
 }
 
 // NewZoomFetcher creates a new ZoomFetcher.
@@ -43,17 +46,30 @@ func NewZoomFetcher(s *store.Store) *ZoomFetcher {
 		store: s,
 		pool:  browser.NewPool(true), // headless
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: telemetry.Transport(nil, "video"),
 		},
 		delayBetween: 2 * time.Second, // rate limiting between requests
+		logger:       slog.Default(),
 	}
 }
 
+// SetLogger replaces the fetcher's logger.
+func (f *ZoomFetcher) SetLogger(l *slog.Logger) {
+	f.logger = l
+}
+
 // SetDelay sets the delay between consecutive Zoom page requests for rate limiting.
 func (f *ZoomFetcher) SetDelay(d time.Duration) {
 	f.delayBetween = d
 }
 
+// SetPool replaces the browser pool used to load Zoom pages, for example with
+// one whose shared browser is already running.
+func (f *ZoomFetcher) SetPool(pool *browser.Pool) {
+	f.pool = pool
+}
+
 // FetchTranscript loads the Zoom share page, extracts the VTT transcript URL
 // from the Vue store state, downloads and parses the VTT, and stores the
 // transcript in SQLite.
@@ -62,10 +78,11 @@ func (f *ZoomFetcher) FetchTranscript(ctx context.Context, recording *Recording)
 		return fmt.Errorf("recording has no Zoom URL")
 	}
 
+	log := f.logger.With(logging.KeySIG, recording.SIGID, logging.KeySource, "video", "url", recording.ZoomURL)
+
 	// Skip very short recordings.
 	if recording.DurationMinutes > 0 && recording.DurationMinutes < minRecordingDuration {
-		log.Printf("zoom: skipping short recording (%d min) for %s",
-			recording.DurationMinutes, recording.SIGID)
+		log.Info("skipping short recording", "minutes", recording.DurationMinutes)
 		return nil
 	}
 
@@ -84,8 +101,7 @@ func (f *ZoomFetcher) FetchTranscript(ctx context.Context, recording *Recording)
 	}
 
 	if !hasTranscript || transcriptURL == "" {
-		log.Printf("zoom: no transcript available for %s (%s)",
-			recording.SIGID, recording.ZoomURL)
+		log.Info("no transcript available")
 		f.logFetch(recording, "skipped", "no transcript available", time.Since(fetchStart))
 		return nil
 	}
@@ -106,7 +122,7 @@ func (f *ZoomFetcher) FetchTranscript(ctx context.Context, recording *Recording)
 	// Parse VTT to plain text with speaker names.
 	transcript := parseVTT(vttContent)
 	if transcript == "" {
-		log.Printf("zoom: empty transcript after parsing VTT for %s", recording.SIGID)
+		log.Warn("empty transcript after parsing VTT")
 		f.logFetch(recording, "skipped", "empty transcript after VTT parsing", time.Since(fetchStart))
 		return nil
 	}
@@ -129,8 +145,7 @@ func (f *ZoomFetcher) FetchTranscript(ctx context.Context, recording *Recording)
 	}
 
 	f.logFetch(recording, "success", "", time.Since(fetchStart))
-	log.Printf("zoom: stored transcript for %s (%d chars)",
-		recording.SIGID, len(transcript))
+	log.Info("stored transcript", "chars", len(transcript))
 
 	// Rate limiting delay.
 	if f.delayBetween > 0 {
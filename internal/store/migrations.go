@@ -91,6 +91,85 @@ var migrations = []string{
 	`CREATE TABLE IF NOT EXISTS schema_version (
 		version INTEGER PRIMARY KEY
 	)`,
+
+	`ALTER TABLE reports ADD COLUMN sig_set TEXT NOT NULL DEFAULT ''`,
+
+	`ALTER TABLE reports ADD COLUMN payload TEXT`,
+
+	`CREATE TABLE IF NOT EXISTS report_items (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		report_id INTEGER NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+		sig_id TEXT NOT NULL,
+		level TEXT NOT NULL,
+		topic TEXT NOT NULL,
+		text TEXT NOT NULL,
+		status TEXT
+	)`,
+
+	`CREATE INDEX IF NOT EXISTS idx_report_items_report ON report_items(report_id)`,
+
+	`CREATE TABLE IF NOT EXISTS runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		command TEXT NOT NULL,
+		config TEXT NOT NULL,
+		sig_set TEXT NOT NULL DEFAULT '',
+		date_range_start DATETIME NOT NULL,
+		date_range_end DATETIME NOT NULL,
+		status TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		started_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		finished_at DATETIME
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS run_steps (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		run_id INTEGER NOT NULL REFERENCES runs(id) ON DELETE CASCADE,
+		sig_id TEXT NOT NULL,
+		stage TEXT NOT NULL,
+		status TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		result TEXT,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(run_id, sig_id, stage)
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS locks (
+		name TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		expires_at DATETIME NOT NULL
+	)`,
+
+	`CREATE TABLE IF NOT EXISTS email_sends (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		report_id INTEGER NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+		recipient TEXT NOT NULL,
+		sig_ids TEXT NOT NULL DEFAULT '',
+		message_id TEXT NOT NULL DEFAULT '',
+		sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(report_id, recipient)
+	)`,
+
+	`ALTER TABLE analysis_cache ADD COLUMN provider TEXT NOT NULL DEFAULT ''`,
+
+	`ALTER TABLE analysis_cache ADD COLUMN prompt_version TEXT NOT NULL DEFAULT ''`,
+
+	// Recording and message dates used to be written by the driver as
+	// time.Time.String(), "2006-01-02 15:04:05.999999999 -0700 MST", in the
+	// fetcher's zone; they are now UTC timeLayout so range queries compare
+	// as strings. Rewrite the old values: the offset token after the
+	// seconds becomes a "-07:00" suffix, which datetime() converts to UTC.
+	// Values it cannot parse are left as they are.
+	`UPDATE video_transcripts SET recording_date = COALESCE(datetime(
+		substr(recording_date, 1, 19) ||
+		substr(substr(recording_date, 20), instr(substr(recording_date, 20), ' ') + 1, 3) || ':' ||
+		substr(substr(recording_date, 20), instr(substr(recording_date, 20), ' ') + 4, 2)), recording_date)
+	WHERE length(recording_date) > 19 AND instr(substr(recording_date, 20), ' ') > 0`,
+
+	`UPDATE slack_messages SET message_date = COALESCE(datetime(
+		substr(message_date, 1, 19) ||
+		substr(substr(message_date, 20), instr(substr(message_date, 20), ' ') + 1, 3) || ':' ||
+		substr(substr(message_date, 20), instr(substr(message_date, 20), ' ') + 4, 2)), message_date)
+	WHERE length(message_date) > 19 AND instr(substr(message_date, 20), ' ') > 0`,
 }
 
 func (s *Store) migrate() error {
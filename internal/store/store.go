@@ -3,6 +3,8 @@ package store
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	_ "modernc.org/sqlite"
@@ -67,10 +69,18 @@ type AnalysisCache struct {
 	DateRangeStart time.Time
 	DateRangeEnd   time.Time
 	PromptHash     string
-	Result         string
-	Model          string
-	TokensUsed     int
-	CreatedAt      time.Time
+	// PromptVersion names the versions of the prompt templates PromptHash
+	// was rendered from. It is empty for results cached before it was
+	// recorded.
+	PromptVersion string
+	Result        string
+	// Provider and Model are the LLM that produced Result, which may be a
+	// fallback rather than the configured one. Provider is empty for
+	// results cached before it was recorded.
+	Provider   string
+	Model      string
+	TokensUsed int
+	CreatedAt  time.Time
 }
 
 // Report represents a generated report record.
@@ -78,13 +88,81 @@ type Report struct {
 	ID             int64
 	ReportType     string
 	SIGID          string
+	SIGSet         string // normalized SIG filter the report was generated for
 	DateRangeStart time.Time
 	DateRangeEnd   time.Time
 	FilePath       string
 	ContentHash    string
+	Payload        string // JSON-encoded report, used for diffing and republishing
 	CreatedAt      time.Time
 }
 
+// ReportItem is a single relevance item recorded for a generated report.
+type ReportItem struct {
+	ID       int64
+	ReportID int64
+	SIGID    string
+	Level    string // "high", "medium", "low"
+	Topic    string
+	Text     string
+	Status   string // "NEW", "UPDATED", "ONGOING" or empty
+}
+
+// Run statuses.
+const (
+	RunRunning     = "running"
+	RunCompleted   = "completed"
+	RunPartial     = "partial"
+	RunFailed      = "failed"
+	RunInterrupted = "interrupted"
+)
+
+// Run step statuses.
+const (
+	StepRunning = "running"
+	StepDone    = "done"
+	StepFailed  = "failed"
+)
+
+// Run records one invocation of the pipeline so it can be inspected and
+// resumed later.
+type Run struct {
+	ID             int64
+	Command        string // "report", "fetch"
+	Config         string // JSON snapshot of the settings the run used
+	SIGSet         string
+	DateRangeStart time.Time
+	DateRangeEnd   time.Time
+	Status         string
+	Error          string
+	StartedAt      time.Time
+	FinishedAt     time.Time // zero while the run has not finished
+}
+
+// RunStep records the outcome of one stage ("fetch", "analyze") for one SIG
+// within a run.
+type RunStep struct {
+	ID        int64
+	RunID     int64
+	SIGID     string
+	Stage     string
+	Status    string
+	Error     string
+	Result    string // JSON-encoded stage output, if any
+	UpdatedAt time.Time
+}
+
+// EmailSend records a digest emailed to one recipient, so re-runs do not
+// send it, or another digest of the same week, twice.
+type EmailSend struct {
+	ID        int64
+	ReportID  int64
+	Recipient string
+	SIGIDs    string // comma-separated SIGs the email covered
+	MessageID string
+	SentAt    time.Time
+}
+
 // FetchLog represents a fetch operation log entry.
 type FetchLog struct {
 	ID           int64
@@ -140,6 +218,13 @@ func (s *Store) DB() *sql.DB {
 	return s.db
 }
 
+// SchemaVersion returns the number of migrations applied to the database.
+func (s *Store) SchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
+	return version, err
+}
+
 // UpsertSIG inserts or updates a SIG entry.
 func (s *Store) UpsertSIG(sig *SIG) error {
 	_, err := s.db.Exec(`
@@ -216,6 +301,15 @@ func (s *Store) UpsertMeetingNote(note *MeetingNote) error {
 	return err
 }
 
+// timeLayout is how timestamps are written to the database. Values are
+// stored in UTC so that range queries compare correctly as strings.
+const timeLayout = "2006-01-02 15:04:05"
+
+// formatTime formats t for storage and range queries.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
 // GetMeetingNotes retrieves meeting notes for a SIG within a date range.
 func (s *Store) GetMeetingNotes(sigID string, start, end time.Time) ([]*MeetingNote, error) {
 	rows, err := s.db.Query(`
@@ -250,7 +344,7 @@ func (s *Store) UpsertVideoTranscript(vt *VideoTranscript) error {
 			transcript_source=excluded.transcript_source,
 			content_hash=excluded.content_hash,
 			fetched_at=CURRENT_TIMESTAMP
-	`, vt.SIGID, vt.ZoomURL, vt.RecordingDate, vt.DurationMinutes, vt.Transcript, vt.TranscriptSource, vt.ContentHash)
+	`, vt.SIGID, vt.ZoomURL, formatTime(vt.RecordingDate), vt.DurationMinutes, vt.Transcript, vt.TranscriptSource, vt.ContentHash)
 	return err
 }
 
@@ -261,7 +355,7 @@ func (s *Store) GetVideoTranscripts(sigID string, start, end time.Time) ([]*Vide
 		FROM video_transcripts
 		WHERE sig_id = ? AND recording_date >= ? AND recording_date <= ?
 		ORDER BY recording_date DESC
-	`, sigID, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	`, sigID, formatTime(start), formatTime(end))
 	if err != nil {
 		return nil, err
 	}
@@ -288,7 +382,7 @@ func (s *Store) UpsertSlackMessage(msg *SlackMessage) error {
 			text=excluded.text,
 			user_name=excluded.user_name,
 			fetched_at=CURRENT_TIMESTAMP
-	`, msg.SIGID, msg.ChannelID, msg.MessageTS, msg.ThreadTS, msg.UserID, msg.UserName, msg.Text, msg.MessageDate)
+	`, msg.SIGID, msg.ChannelID, msg.MessageTS, msg.ThreadTS, msg.UserID, msg.UserName, msg.Text, formatTime(msg.MessageDate))
 	return err
 }
 
@@ -299,7 +393,7 @@ func (s *Store) GetSlackMessages(sigID string, start, end time.Time) ([]*SlackMe
 		FROM slack_messages
 		WHERE sig_id = ? AND message_date >= ? AND message_date <= ?
 		ORDER BY message_date DESC
-	`, sigID, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	`, sigID, formatTime(start), formatTime(end))
 	if err != nil {
 		return nil, err
 	}
@@ -317,14 +411,114 @@ func (s *Store) GetSlackMessages(sigID string, start, end time.Time) ([]*SlackMe
 	return msgs, rows.Err()
 }
 
+// sourceTables maps each source type to the table holding its data, the
+// column dating a row, and how that column is formatted.
+var sourceTables = map[string]struct {
+	table, dateColumn string
+	format            func(time.Time) string
+}{
+	"notes": {"meeting_notes", "meeting_date", func(t time.Time) string { return t.Format("2006-01-02") }},
+	"video": {"video_transcripts", "recording_date", formatTime},
+	"slack": {"slack_messages", "message_date", formatTime},
+}
+
+// DeleteSourceData deletes the meeting notes, video transcripts or Slack
+// messages (sourceType "notes", "video" or "slack") dated before before,
+// only of sigIDs if given, and returns how many were deleted. The SIGs the
+// rows belong to are kept.
+func (s *Store) DeleteSourceData(sourceType string, sigIDs []string, before time.Time) (int64, error) {
+	st, ok := sourceTables[sourceType]
+	if !ok {
+		return 0, fmt.Errorf("unknown source type %q", sourceType)
+	}
+	query := "DELETE FROM " + st.table + " WHERE " + st.dateColumn + " < ?"
+	args := []any{st.format(before)}
+	if len(sigIDs) > 0 {
+		query += " AND sig_id IN (?" + repeatParam(len(sigIDs)-1) + ")"
+		for _, id := range sigIDs {
+			args = append(args, id)
+		}
+	}
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// SearchResult is a stored meeting note, transcript or Slack message whose
+// text matches a search.
+type SearchResult struct {
+	SourceType string // "notes", "video", "slack"
+	ID         int64
+	SIGID      string
+	Date       time.Time
+	Text       string
+}
+
+// Search finds meeting notes, transcripts and Slack messages containing query
+// (case-insensitive), optionally restricted to one SIG. Results are ordered
+// newest first; a limit <= 0 returns all matches.
+func (s *Store) Search(query, sigID string, limit int) ([]*SearchResult, error) {
+	pattern := "%" + escapeLike(query) + "%"
+	queries := []struct {
+		sourceType string
+		sql        string
+	}{
+		{"notes", `SELECT id, sig_id, meeting_date, raw_text FROM meeting_notes
+			WHERE raw_text LIKE ? ESCAPE '\' AND (? = '' OR sig_id = ?) ORDER BY meeting_date DESC LIMIT ?`},
+		{"video", `SELECT id, sig_id, recording_date, transcript FROM video_transcripts
+			WHERE transcript LIKE ? ESCAPE '\' AND (? = '' OR sig_id = ?) ORDER BY recording_date DESC LIMIT ?`},
+		{"slack", `SELECT id, sig_id, message_date, text FROM slack_messages
+			WHERE text LIKE ? ESCAPE '\' AND (? = '' OR sig_id = ?) ORDER BY message_date DESC LIMIT ?`},
+	}
+
+	sqlLimit := limit
+	if sqlLimit <= 0 {
+		sqlLimit = -1
+	}
+
+	var results []*SearchResult
+	for _, q := range queries {
+		rows, err := s.db.Query(q.sql, pattern, sigID, sigID, sqlLimit)
+		if err != nil {
+			return nil, fmt.Errorf("searching %s: %w", q.sourceType, err)
+		}
+		for rows.Next() {
+			r := &SearchResult{SourceType: q.sourceType}
+			if err := rows.Scan(&r.ID, &r.SIGID, &r.Date, &r.Text); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			results = append(results, r)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Date.After(results[j].Date) })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 // GetAnalysisCache retrieves a cached analysis result.
 func (s *Store) GetAnalysisCache(cacheKey string) (*AnalysisCache, error) {
 	ac := &AnalysisCache{}
 	err := s.db.QueryRow(`
-		SELECT id, cache_key, sig_id, source_type, date_range_start, date_range_end, prompt_hash, result, model, tokens_used, created_at
+		SELECT id, cache_key, sig_id, source_type, date_range_start, date_range_end, prompt_hash, prompt_version, result, provider, model, tokens_used, created_at
 		FROM analysis_cache WHERE cache_key = ?`, cacheKey).Scan(
 		&ac.ID, &ac.CacheKey, &ac.SIGID, &ac.SourceType, &ac.DateRangeStart, &ac.DateRangeEnd,
-		&ac.PromptHash, &ac.Result, &ac.Model, &ac.TokensUsed, &ac.CreatedAt)
+		&ac.PromptHash, &ac.PromptVersion, &ac.Result, &ac.Provider, &ac.Model, &ac.TokensUsed, &ac.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -334,27 +528,507 @@ func (s *Store) GetAnalysisCache(cacheKey string) (*AnalysisCache, error) {
 // PutAnalysisCache stores an analysis result in the cache.
 func (s *Store) PutAnalysisCache(ac *AnalysisCache) error {
 	_, err := s.db.Exec(`
-		INSERT INTO analysis_cache (cache_key, sig_id, source_type, date_range_start, date_range_end, prompt_hash, result, model, tokens_used, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		INSERT INTO analysis_cache (cache_key, sig_id, source_type, date_range_start, date_range_end, prompt_hash, prompt_version, result, provider, model, tokens_used, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
 		ON CONFLICT(cache_key) DO UPDATE SET
+			prompt_version=excluded.prompt_version,
 			result=excluded.result,
+			provider=excluded.provider,
 			model=excluded.model,
 			tokens_used=excluded.tokens_used,
 			created_at=CURRENT_TIMESTAMP
 	`, ac.CacheKey, ac.SIGID, ac.SourceType, ac.DateRangeStart.Format("2006-01-02"),
-		ac.DateRangeEnd.Format("2006-01-02"), ac.PromptHash, ac.Result, ac.Model, ac.TokensUsed)
+		ac.DateRangeEnd.Format("2006-01-02"), ac.PromptHash, ac.PromptVersion, ac.Result, ac.Provider, ac.Model, ac.TokensUsed)
 	return err
 }
 
-// InsertReport inserts a report record.
+// AnalysisCacheFilter selects analysis cache entries. Empty fields match
+// every entry.
+type AnalysisCacheFilter struct {
+	SIGIDs []string
+	// Stages are source types; "relevance" also matches the
+	// "relevance:<profile>" entries of relevance profiles.
+	Stages []string
+	Models []string
+	// Since and Until select entries whose date window overlaps
+	// Since..Until.
+	Since time.Time
+	Until time.Time
+	// CreatedBefore selects entries cached before it.
+	CreatedBefore time.Time
+	// CurrentPromptVersions, when set, selects entries made with any other
+	// prompt version. Entries without a recorded version never match, as
+	// their prompts are unknown.
+	CurrentPromptVersions []string
+}
+
+// where returns the SQL condition selecting the entries of f and its
+// arguments.
+func (f *AnalysisCacheFilter) where() (string, []any) {
+	conds := []string{"1=1"}
+	var args []any
+	if len(f.SIGIDs) > 0 {
+		conds = append(conds, "sig_id IN (?"+repeatParam(len(f.SIGIDs)-1)+")")
+		for _, id := range f.SIGIDs {
+			args = append(args, id)
+		}
+	}
+	if len(f.Stages) > 0 {
+		var stageConds []string
+		for _, stage := range f.Stages {
+			stageConds = append(stageConds, `(source_type = ? OR source_type LIKE ? ESCAPE '\')`)
+			args = append(args, stage, escapeLike(stage)+":%")
+		}
+		conds = append(conds, "("+strings.Join(stageConds, " OR ")+")")
+	}
+	if len(f.Models) > 0 {
+		conds = append(conds, "model IN (?"+repeatParam(len(f.Models)-1)+")")
+		for _, m := range f.Models {
+			args = append(args, m)
+		}
+	}
+	if !f.Since.IsZero() {
+		conds = append(conds, "date_range_end >= ?")
+		args = append(args, f.Since.Format("2006-01-02"))
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, "date_range_start <= ?")
+		args = append(args, f.Until.Format("2006-01-02"))
+	}
+	if !f.CreatedBefore.IsZero() {
+		conds = append(conds, "created_at < ?")
+		args = append(args, formatTime(f.CreatedBefore))
+	}
+	if len(f.CurrentPromptVersions) > 0 {
+		conds = append(conds, "prompt_version != '' AND prompt_version NOT IN (?"+repeatParam(len(f.CurrentPromptVersions)-1)+")")
+		for _, v := range f.CurrentPromptVersions {
+			args = append(args, v)
+		}
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// AnalysisCacheStat summarizes the analysis cache entries of one SIG,
+// stage and model.
+type AnalysisCacheStat struct {
+	SIGID      string
+	SourceType string
+	Model      string
+	Entries    int
+	// Bytes is the size of the cached results.
+	Bytes      int64
+	TokensUsed int64
+	Oldest     time.Time
+	Newest     time.Time
+}
+
+// AnalysisCacheStats summarizes the analysis cache entries selected by f
+// per SIG, source type and model, ordered by those.
+func (s *Store) AnalysisCacheStats(f AnalysisCacheFilter) ([]*AnalysisCacheStat, error) {
+	where, args := f.where()
+	rows, err := s.db.Query(`
+		SELECT sig_id, source_type, model, COUNT(*), COALESCE(SUM(LENGTH(CAST(result AS BLOB))), 0),
+			COALESCE(SUM(tokens_used), 0), MIN(created_at), MAX(created_at)
+		FROM analysis_cache
+		WHERE `+where+`
+		GROUP BY sig_id, source_type, model
+		ORDER BY sig_id, source_type, model`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*AnalysisCacheStat
+	for rows.Next() {
+		st := &AnalysisCacheStat{}
+		var oldest, newest string
+		if err := rows.Scan(&st.SIGID, &st.SourceType, &st.Model, &st.Entries, &st.Bytes,
+			&st.TokensUsed, &oldest, &newest); err != nil {
+			return nil, err
+		}
+		st.Oldest, _ = time.Parse(timeLayout, oldest)
+		st.Newest, _ = time.Parse(timeLayout, newest)
+		stats = append(stats, st)
+	}
+	return stats, rows.Err()
+}
+
+// DeleteAnalysisCache deletes the analysis cache entries selected by f and
+// returns how many were deleted.
+func (s *Store) DeleteAnalysisCache(f AnalysisCacheFilter) (int64, error) {
+	where, args := f.where()
+	res, err := s.db.Exec(`DELETE FROM analysis_cache WHERE `+where, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// InsertReport inserts a report record and sets r.ID to the new row ID.
 func (s *Store) InsertReport(r *Report) error {
+	res, err := s.db.Exec(`
+		INSERT INTO reports (report_type, sig_id, sig_set, date_range_start, date_range_end, file_path, content_hash, payload, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, r.ReportType, r.SIGID, r.SIGSet, r.DateRangeStart.Format("2006-01-02"), r.DateRangeEnd.Format("2006-01-02"),
+		r.FilePath, r.ContentHash, r.Payload)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	r.ID = id
+	return nil
+}
+
+// reportColumns is the column list shared by report queries.
+const reportColumns = `id, report_type, COALESCE(sig_id, ''), sig_set, date_range_start, date_range_end,
+	file_path, content_hash, COALESCE(payload, ''), created_at`
+
+// scanReport scans a row selected with reportColumns.
+func scanReport(row interface{ Scan(...interface{}) error }) (*Report, error) {
+	r := &Report{}
+	if err := row.Scan(&r.ID, &r.ReportType, &r.SIGID, &r.SIGSet, &r.DateRangeStart, &r.DateRangeEnd,
+		&r.FilePath, &r.ContentHash, &r.Payload, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetReport retrieves a single report record by ID.
+func (s *Store) GetReport(id int64) (*Report, error) {
+	return scanReport(s.db.QueryRow(`SELECT `+reportColumns+` FROM reports WHERE id = ?`, id))
+}
+
+// LatestReport returns the most recently generated report of the given type
+// for the given SIG set. Returns sql.ErrNoRows if there is none.
+func (s *Store) LatestReport(reportType, sigSet string) (*Report, error) {
+	return scanReport(s.db.QueryRow(`
+		SELECT `+reportColumns+`
+		FROM reports
+		WHERE report_type = ? AND sig_set = ?
+		ORDER BY date_range_end DESC, id DESC
+		LIMIT 1
+	`, reportType, sigSet))
+}
+
+// ListReports returns report records of the given type, newest first. An
+// empty type lists all reports; a limit <= 0 returns all of them.
+func (s *Store) ListReports(reportType string, limit int) ([]*Report, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(`
+		SELECT `+reportColumns+`
+		FROM reports
+		WHERE ? = '' OR report_type = ?
+		ORDER BY date_range_end DESC, id DESC
+		LIMIT ?
+	`, reportType, reportType, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*Report
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, rows.Err()
+}
+
+// FindReport returns the report of the given type and SIG set that covers
+// exactly start..end. Returns sql.ErrNoRows if there is none.
+func (s *Store) FindReport(reportType, sigSet string, start, end time.Time) (*Report, error) {
+	return scanReport(s.db.QueryRow(`
+		SELECT `+reportColumns+`
+		FROM reports
+		WHERE report_type = ? AND sig_set = ? AND date_range_start = ? AND date_range_end = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`, reportType, sigSet, start.Format("2006-01-02"), end.Format("2006-01-02")))
+}
+
+// InsertReportItems stores the relevance items of a report in one transaction.
+func (s *Store) InsertReportItems(reportID int64, items []*ReportItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO report_items (report_id, sig_id, level, topic, text, status)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, item := range items {
+		res, err := stmt.Exec(reportID, item.SIGID, item.Level, item.Topic, item.Text, item.Status)
+		if err != nil {
+			return err
+		}
+		item.ReportID = reportID
+		item.ID, _ = res.LastInsertId()
+	}
+	return tx.Commit()
+}
+
+// GetReportItems retrieves all relevance items recorded for a report.
+func (s *Store) GetReportItems(reportID int64) ([]*ReportItem, error) {
+	rows, err := s.db.Query(`
+		SELECT id, report_id, sig_id, level, topic, text, COALESCE(status, '')
+		FROM report_items
+		WHERE report_id = ?
+		ORDER BY id
+	`, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*ReportItem
+	for rows.Next() {
+		item := &ReportItem{}
+		if err := rows.Scan(&item.ID, &item.ReportID, &item.SIGID, &item.Level, &item.Topic, &item.Text, &item.Status); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// ReportedTopics returns the topics of the items of level recorded for
+// sigID in reports of reportType whose window overlaps start..end.
+func (s *Store) ReportedTopics(reportType, sigID, level string, start, end time.Time) ([]string, error) {
+	rows, err := s.db.Query(`
+		SELECT DISTINCT i.topic
+		FROM report_items i
+		JOIN reports r ON r.id = i.report_id
+		WHERE r.report_type = ? AND i.sig_id = ? AND i.level = ?
+			AND r.date_range_start <= ? AND r.date_range_end >= ?
+	`, reportType, sigID, level, end.Format("2006-01-02"), start.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var topics []string
+	for rows.Next() {
+		var topic string
+		if err := rows.Scan(&topic); err != nil {
+			return nil, err
+		}
+		topics = append(topics, topic)
+	}
+	return topics, rows.Err()
+}
+
+// CreateRun inserts a new run and sets r.ID.
+func (s *Store) CreateRun(r *Run) error {
+	res, err := s.db.Exec(`
+		INSERT INTO runs (command, config, sig_set, date_range_start, date_range_end, status, error, started_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, r.Command, r.Config, r.SIGSet, formatTime(r.DateRangeStart), formatTime(r.DateRangeEnd), r.Status, r.Error)
+	if err != nil {
+		return err
+	}
+	r.ID, err = res.LastInsertId()
+	return err
+}
+
+// UpdateRunStatus sets the status and error of a run. Any status other than
+// RunRunning also stamps the finish time.
+func (s *Store) UpdateRunStatus(id int64, status, errMsg string) error {
+	var err error
+	if status == RunRunning {
+		_, err = s.db.Exec(`UPDATE runs SET status = ?, error = ?, finished_at = NULL WHERE id = ?`, status, errMsg, id)
+	} else {
+		_, err = s.db.Exec(`UPDATE runs SET status = ?, error = ?, finished_at = CURRENT_TIMESTAMP WHERE id = ?`, status, errMsg, id)
+	}
+	return err
+}
+
+const runColumns = `id, command, config, sig_set, date_range_start, date_range_end, status, error, started_at, finished_at`
+
+func scanRun(row interface{ Scan(...interface{}) error }) (*Run, error) {
+	r := &Run{}
+	var finished sql.NullTime
+	if err := row.Scan(&r.ID, &r.Command, &r.Config, &r.SIGSet, &r.DateRangeStart, &r.DateRangeEnd,
+		&r.Status, &r.Error, &r.StartedAt, &finished); err != nil {
+		return nil, err
+	}
+	if finished.Valid {
+		r.FinishedAt = finished.Time
+	}
+	return r, nil
+}
+
+// GetRun retrieves a run by ID. Returns sql.ErrNoRows if it does not exist.
+func (s *Store) GetRun(id int64) (*Run, error) {
+	return scanRun(s.db.QueryRow(`SELECT `+runColumns+` FROM runs WHERE id = ?`, id))
+}
+
+// ListRuns returns the most recent runs, newest first. A limit <= 0 returns
+// all runs.
+func (s *Store) ListRuns(limit int) ([]*Run, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	rows, err := s.db.Query(`SELECT `+runColumns+` FROM runs ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []*Run
+	for rows.Next() {
+		r, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+// SetRunStep inserts or replaces the step for (run, SIG, stage).
+func (s *Store) SetRunStep(step *RunStep) error {
 	_, err := s.db.Exec(`
-		INSERT INTO reports (report_type, sig_id, date_range_start, date_range_end, file_path, content_hash, created_at)
+		INSERT INTO run_steps (run_id, sig_id, stage, status, error, result, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
-	`, r.ReportType, r.SIGID, r.DateRangeStart.Format("2006-01-02"), r.DateRangeEnd.Format("2006-01-02"), r.FilePath, r.ContentHash)
+		ON CONFLICT(run_id, sig_id, stage) DO UPDATE SET
+			status=excluded.status,
+			error=excluded.error,
+			result=excluded.result,
+			updated_at=CURRENT_TIMESTAMP
+	`, step.RunID, step.SIGID, step.Stage, step.Status, step.Error, step.Result)
 	return err
 }
 
+// GetRunSteps returns all steps of a run ordered by SIG and stage.
+func (s *Store) GetRunSteps(runID int64) ([]*RunStep, error) {
+	rows, err := s.db.Query(`
+		SELECT id, run_id, sig_id, stage, status, error, COALESCE(result, ''), updated_at
+		FROM run_steps
+		WHERE run_id = ?
+		ORDER BY sig_id, stage
+	`, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var steps []*RunStep
+	for rows.Next() {
+		st := &RunStep{}
+		if err := rows.Scan(&st.ID, &st.RunID, &st.SIGID, &st.Stage, &st.Status,
+			&st.Error, &st.Result, &st.UpdatedAt); err != nil {
+			return nil, err
+		}
+		steps = append(steps, st)
+	}
+	return steps, rows.Err()
+}
+
+// RecordEmailSend records that a report was emailed to a recipient,
+// replacing any earlier record for the same pair.
+func (s *Store) RecordEmailSend(es *EmailSend) error {
+	_, err := s.db.Exec(`
+		INSERT INTO email_sends (report_id, recipient, sig_ids, message_id, sent_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(report_id, recipient) DO UPDATE SET
+			sig_ids=excluded.sig_ids,
+			message_id=excluded.message_id,
+			sent_at=excluded.sent_at
+	`, es.ReportID, es.Recipient, es.SIGIDs, es.MessageID)
+	return err
+}
+
+// EmailSent reports whether a report, or any other report of the same type
+// and SIG set covering the same window, was already emailed to recipient:
+// every run over a week records its own digest, and re-runs must not mail
+// the week again.
+func (s *Store) EmailSent(reportID int64, recipient string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM email_sends e
+		JOIN reports r ON r.id = e.report_id
+		JOIN reports cur ON cur.id = ?
+		WHERE e.recipient = ?
+			AND r.report_type = cur.report_type
+			AND r.sig_set = cur.sig_set
+			AND r.date_range_start = cur.date_range_start
+			AND r.date_range_end = cur.date_range_end
+	`, reportID, recipient).Scan(&n)
+	return n > 0, err
+}
+
+// ListEmailSends returns the recorded sends of a report, oldest first.
+func (s *Store) ListEmailSends(reportID int64) ([]*EmailSend, error) {
+	rows, err := s.db.Query(`
+		SELECT id, report_id, recipient, sig_ids, message_id, sent_at
+		FROM email_sends
+		WHERE report_id = ?
+		ORDER BY id
+	`, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sends []*EmailSend
+	for rows.Next() {
+		es := &EmailSend{}
+		if err := rows.Scan(&es.ID, &es.ReportID, &es.Recipient, &es.SIGIDs, &es.MessageID, &es.SentAt); err != nil {
+			return nil, err
+		}
+		sends = append(sends, es)
+	}
+	return sends, rows.Err()
+}
+
+// AcquireLock takes the named lock for owner until ttl from now. It succeeds
+// if the lock is free, expired, or already held by owner (which extends it),
+// and reports false if another owner holds it.
+func (s *Store) AcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	res, err := s.db.Exec(`
+		INSERT INTO locks (name, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			owner=excluded.owner,
+			expires_at=excluded.expires_at
+		WHERE locks.owner = excluded.owner OR locks.expires_at < ?
+	`, name, owner, formatTime(now.Add(ttl)), formatTime(now))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// ReleaseLock releases the named lock if owner holds it.
+func (s *Store) ReleaseLock(name, owner string) error {
+	_, err := s.db.Exec(`DELETE FROM locks WHERE name = ? AND owner = ?`, name, owner)
+	return err
+}
+
+// LockHolder returns the current owner of the named lock and when it
+// expires. Returns sql.ErrNoRows if the lock is not held.
+func (s *Store) LockHolder(name string) (owner string, expiresAt time.Time, err error) {
+	err = s.db.QueryRow(`SELECT owner, expires_at FROM locks WHERE name = ?`, name).Scan(&owner, &expiresAt)
+	return owner, expiresAt, err
+}
+
 // LogFetch inserts a fetch log entry.
 func (s *Store) LogFetch(fl *FetchLog) error {
 	_, err := s.db.Exec(`
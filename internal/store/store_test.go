@@ -1,6 +1,7 @@
 package store
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -29,7 +30,7 @@ func TestMigrations(t *testing.T) {
 	s := newTestStore(t)
 
 	// Verify all tables exist
-	tables := []string{"sigs", "meeting_notes", "video_transcripts", "slack_messages", "analysis_cache", "reports", "fetch_log", "schema_version"}
+	tables := []string{"sigs", "meeting_notes", "video_transcripts", "slack_messages", "analysis_cache", "reports", "fetch_log", "schema_version", "report_items", "runs", "run_steps", "locks"}
 	for _, table := range tables {
 		var name string
 		err := s.DB().QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&name)
@@ -207,6 +208,49 @@ func TestVideoTranscripts(t *testing.T) {
 	}
 }
 
+func TestVideoTranscripts_WindowTimeZone(t *testing.T) {
+	s := newTestStore(t)
+
+	if err := s.UpsertSIG(&SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG failed: %v", err)
+	}
+
+	// Recorded late on the last day of the window, New York time, which is
+	// already the next day in UTC.
+	ny := time.FixedZone("EDT", -4*60*60)
+	vt := &VideoTranscript{
+		SIGID:         "collector",
+		ZoomURL:       "https://zoom.us/rec/share/late",
+		RecordingDate: time.Date(2026, 10, 11, 22, 0, 0, 0, ny),
+		Transcript:    "Late meeting",
+	}
+	if err := s.UpsertVideoTranscript(vt); err != nil {
+		t.Fatalf("UpsertVideoTranscript failed: %v", err)
+	}
+
+	start := time.Date(2026, 10, 5, 0, 0, 0, 0, ny)
+	end := time.Date(2026, 10, 12, 0, 0, 0, 0, ny).Add(-time.Nanosecond)
+	transcripts, err := s.GetVideoTranscripts("collector", start, end)
+	if err != nil {
+		t.Fatalf("GetVideoTranscripts failed: %v", err)
+	}
+	if len(transcripts) != 1 {
+		t.Fatalf("GetVideoTranscripts returned %d, want 1", len(transcripts))
+	}
+	if !transcripts[0].RecordingDate.Equal(vt.RecordingDate) {
+		t.Errorf("RecordingDate = %v, want %v", transcripts[0].RecordingDate, vt.RecordingDate)
+	}
+
+	// The following week must not pick it up.
+	transcripts, err = s.GetVideoTranscripts("collector", end.Add(time.Nanosecond), end.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetVideoTranscripts failed: %v", err)
+	}
+	if len(transcripts) != 0 {
+		t.Errorf("GetVideoTranscripts for next week returned %d, want 0", len(transcripts))
+	}
+}
+
 func TestSlackMessages(t *testing.T) {
 	s := newTestStore(t)
 
@@ -253,6 +297,7 @@ func TestAnalysisCache(t *testing.T) {
 		DateRangeEnd:   time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC),
 		PromptHash:     "prompt-hash-abc",
 		Result:         "LLM analysis result text",
+		Provider:       "anthropic",
 		Model:          "claude-sonnet-4-20250514",
 		TokensUsed:     1500,
 	}
@@ -271,6 +316,22 @@ func TestAnalysisCache(t *testing.T) {
 	if got.Model != "claude-sonnet-4-20250514" {
 		t.Errorf("Model = %q, want %q", got.Model, "claude-sonnet-4-20250514")
 	}
+	if got.Provider != "anthropic" {
+		t.Errorf("Provider = %q, want %q", got.Provider, "anthropic")
+	}
+
+	// Overwriting records the provider that answered the second time.
+	ac.Provider, ac.Model = "openai", "gpt-4o"
+	if err := s.PutAnalysisCache(ac); err != nil {
+		t.Fatalf("PutAnalysisCache failed: %v", err)
+	}
+	got, err = s.GetAnalysisCache("test-key-123")
+	if err != nil {
+		t.Fatalf("GetAnalysisCache failed: %v", err)
+	}
+	if got.Provider != "openai" || got.Model != "gpt-4o" {
+		t.Errorf("provider/model = %s/%s, want openai/gpt-4o", got.Provider, got.Model)
+	}
 
 	// Test cache miss
 	_, err = s.GetAnalysisCache("nonexistent-key")
@@ -279,6 +340,127 @@ func TestAnalysisCache(t *testing.T) {
 	}
 }
 
+func TestAnalysisCacheStatsAndDelete(t *testing.T) {
+	s := newTestStore(t)
+
+	feb := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, ac := range []*AnalysisCache{
+		{CacheKey: "k1", SIGID: "collector", SourceType: "notes", DateRangeStart: feb, DateRangeEnd: feb.AddDate(0, 0, 6), PromptVersion: "notes@1:a", Result: "1234", Model: "haiku", TokensUsed: 10},
+		{CacheKey: "k2", SIGID: "collector", SourceType: "notes", DateRangeStart: mar, DateRangeEnd: mar.AddDate(0, 0, 6), PromptVersion: "notes@2:b", Result: "123456", Model: "haiku", TokensUsed: 20},
+		{CacheKey: "k3", SIGID: "collector", SourceType: "relevance:logs", DateRangeStart: mar, DateRangeEnd: mar.AddDate(0, 0, 6), Result: "12", Model: "sonnet", TokensUsed: 5},
+		{CacheKey: "k4", SIGID: "java", SourceType: "relevance", DateRangeStart: feb, DateRangeEnd: feb.AddDate(0, 0, 6), PromptVersion: "relevance@1:c", Result: "1", Model: "sonnet", TokensUsed: 1},
+	} {
+		if err := s.PutAnalysisCache(ac); err != nil {
+			t.Fatalf("PutAnalysisCache(%s): %v", ac.CacheKey, err)
+		}
+	}
+
+	stats, err := s.AnalysisCacheStats(AnalysisCacheFilter{})
+	if err != nil {
+		t.Fatalf("AnalysisCacheStats: %v", err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("got %d stats, want 3: %+v", len(stats), stats)
+	}
+	notes := stats[0]
+	if notes.SIGID != "collector" || notes.SourceType != "notes" || notes.Model != "haiku" ||
+		notes.Entries != 2 || notes.Bytes != 10 || notes.TokensUsed != 30 {
+		t.Errorf("notes stat = %+v, want 2 entries of 10 bytes and 30 tokens", notes)
+	}
+	if notes.Oldest.IsZero() || notes.Newest.Before(notes.Oldest) {
+		t.Errorf("notes stat cached %v..%v, want a valid range", notes.Oldest, notes.Newest)
+	}
+
+	// "relevance" covers profile entries; the window filter needs overlap.
+	stats, err = s.AnalysisCacheStats(AnalysisCacheFilter{Stages: []string{"relevance"}, Since: mar})
+	if err != nil {
+		t.Fatalf("AnalysisCacheStats: %v", err)
+	}
+	if len(stats) != 1 || stats[0].SourceType != "relevance:logs" {
+		t.Errorf("relevance stats since March = %+v, want the logs profile entry", stats)
+	}
+
+	// Entries without a recorded prompt version are never stale.
+	n, err := s.DeleteAnalysisCache(AnalysisCacheFilter{CurrentPromptVersions: []string{"notes@2:b"}})
+	if err != nil {
+		t.Fatalf("DeleteAnalysisCache: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("deleted %d stale entries, want 2", n)
+	}
+	for key, want := range map[string]bool{"k1": false, "k2": true, "k3": true, "k4": false} {
+		if _, err := s.GetAnalysisCache(key); (err == nil) != want {
+			t.Errorf("entry %s kept = %v, want %v", key, err == nil, want)
+		}
+	}
+
+	n, err = s.DeleteAnalysisCache(AnalysisCacheFilter{SIGIDs: []string{"collector"}, Models: []string{"sonnet"}})
+	if err != nil {
+		t.Fatalf("DeleteAnalysisCache: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("deleted %d sonnet entries of collector, want 1", n)
+	}
+	n, err = s.DeleteAnalysisCache(AnalysisCacheFilter{CreatedBefore: time.Now().Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("DeleteAnalysisCache: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("deleted %d entries cached before now, want 1", n)
+	}
+}
+
+func TestDeleteSourceData(t *testing.T) {
+	s := newTestStore(t)
+
+	jan := time.Date(2026, 1, 14, 17, 0, 0, 0, time.UTC)
+	mar := time.Date(2026, 3, 11, 17, 0, 0, 0, time.UTC)
+	for _, id := range []string{"collector", "java"} {
+		if err := s.UpsertSIG(&SIG{ID: id, Name: id, Category: "implementation"}); err != nil {
+			t.Fatalf("UpsertSIG: %v", err)
+		}
+		for _, day := range []time.Time{jan, mar} {
+			if err := s.UpsertMeetingNote(&MeetingNote{SIGID: id, DocID: "doc", MeetingDate: day, RawText: "notes"}); err != nil {
+				t.Fatalf("UpsertMeetingNote: %v", err)
+			}
+			if err := s.UpsertSlackMessage(&SlackMessage{SIGID: id, ChannelID: id, MessageTS: day.Format("0102"), Text: "hi", MessageDate: day}); err != nil {
+				t.Fatalf("UpsertSlackMessage: %v", err)
+			}
+		}
+	}
+
+	cutoff := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	n, err := s.DeleteSourceData("notes", []string{"collector"}, cutoff)
+	if err != nil {
+		t.Fatalf("DeleteSourceData: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("deleted %d collector notes, want 1", n)
+	}
+	if n, err = s.DeleteSourceData("slack", nil, cutoff); err != nil || n != 2 {
+		t.Errorf("DeleteSourceData(slack) = %d, %v; want 2 messages deleted", n, err)
+	}
+	if _, err := s.DeleteSourceData("email", nil, cutoff); err == nil {
+		t.Error("DeleteSourceData should reject an unknown source type")
+	}
+
+	notes, _ := s.GetMeetingNotes("java", jan, mar)
+	msgs, _ := s.GetSlackMessages("collector", jan, mar)
+	if len(notes) != 2 || len(msgs) != 1 {
+		t.Errorf("left %d java notes and %d collector messages, want 2 and 1", len(notes), len(msgs))
+	}
+	// The SIGs themselves are kept.
+	if sigs, _ := s.ListSIGs(nil); len(sigs) != 2 {
+		t.Errorf("left %d SIGs, want 2", len(sigs))
+	}
+
+	version, err := s.SchemaVersion()
+	if err != nil || version != len(migrations) {
+		t.Errorf("SchemaVersion = %d, %v; want %d", version, err, len(migrations))
+	}
+}
+
 func TestLogFetch(t *testing.T) {
 	s := newTestStore(t)
 
@@ -330,3 +512,353 @@ func TestInsertReport(t *testing.T) {
 		t.Errorf("reports count = %d, want 1", count)
 	}
 }
+
+func TestLatestReportAndItems(t *testing.T) {
+	s := newTestStore(t)
+
+	if _, err := s.LatestReport("digest", "all"); err == nil {
+		t.Fatal("LatestReport should return an error when no reports exist")
+	}
+
+	older := &Report{
+		ReportType:     "digest",
+		SIGSet:         "all",
+		DateRangeStart: time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC),
+		DateRangeEnd:   time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC),
+		FilePath:       "reports/2026-02-11-weekly-digest.md",
+		ContentHash:    "h1",
+	}
+	newer := &Report{
+		ReportType:     "digest",
+		SIGSet:         "all",
+		DateRangeStart: time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC),
+		DateRangeEnd:   time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		FilePath:       "reports/2026-02-18-weekly-digest.md",
+		ContentHash:    "h2",
+		Payload:        `{"sig_count":1}`,
+	}
+	other := &Report{
+		ReportType:     "digest",
+		SIGSet:         "collector",
+		DateRangeStart: time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC),
+		DateRangeEnd:   time.Date(2026, 2, 25, 0, 0, 0, 0, time.UTC),
+		FilePath:       "reports/2026-02-25-weekly-digest.md",
+		ContentHash:    "h3",
+	}
+	for _, r := range []*Report{newer, older, other} {
+		if err := s.InsertReport(r); err != nil {
+			t.Fatalf("InsertReport failed: %v", err)
+		}
+		if r.ID == 0 {
+			t.Fatal("InsertReport should set the report ID")
+		}
+	}
+
+	got, err := s.LatestReport("digest", "all")
+	if err != nil {
+		t.Fatalf("LatestReport failed: %v", err)
+	}
+	if got.ID != newer.ID {
+		t.Errorf("LatestReport ID = %d, want %d", got.ID, newer.ID)
+	}
+	if got.DateRangeEnd.Format("2006-01-02") != "2026-02-18" {
+		t.Errorf("DateRangeEnd = %v, want 2026-02-18", got.DateRangeEnd)
+	}
+	if got.Payload != `{"sig_count":1}` {
+		t.Errorf("Payload = %q", got.Payload)
+	}
+
+	items := []*ReportItem{
+		{SIGID: "collector", Level: "high", Topic: "OTLP", Text: "**OTLP** — partial success", Status: "NEW"},
+		{SIGID: "collector", Level: "low", Topic: "Docs", Text: "**Docs** — updates"},
+	}
+	if err := s.InsertReportItems(newer.ID, items); err != nil {
+		t.Fatalf("InsertReportItems failed: %v", err)
+	}
+
+	gotItems, err := s.GetReportItems(newer.ID)
+	if err != nil {
+		t.Fatalf("GetReportItems failed: %v", err)
+	}
+	if len(gotItems) != 2 {
+		t.Fatalf("GetReportItems returned %d items, want 2", len(gotItems))
+	}
+	if gotItems[0].Topic != "OTLP" || gotItems[0].Status != "NEW" {
+		t.Errorf("first item = %+v", gotItems[0])
+	}
+	if gotItems[1].Status != "" {
+		t.Errorf("second item status = %q, want empty", gotItems[1].Status)
+	}
+}
+
+func TestRunsAndSteps(t *testing.T) {
+	s := newTestStore(t)
+
+	ny := time.FixedZone("EDT", -4*60*60)
+	run := &Run{
+		Command:        "report",
+		Config:         `{"sigs":["collector"]}`,
+		SIGSet:         "collector",
+		DateRangeStart: time.Date(2026, 10, 5, 0, 0, 0, 0, ny),
+		DateRangeEnd:   time.Date(2026, 10, 11, 23, 59, 59, 0, ny),
+		Status:         RunRunning,
+	}
+	if err := s.CreateRun(run); err != nil {
+		t.Fatalf("CreateRun failed: %v", err)
+	}
+	if run.ID == 0 {
+		t.Fatal("CreateRun should set the run ID")
+	}
+
+	got, err := s.GetRun(run.ID)
+	if err != nil {
+		t.Fatalf("GetRun failed: %v", err)
+	}
+	if !got.DateRangeStart.Equal(run.DateRangeStart) || !got.DateRangeEnd.Equal(run.DateRangeEnd) {
+		t.Errorf("window = %v..%v, want %v..%v", got.DateRangeStart, got.DateRangeEnd, run.DateRangeStart, run.DateRangeEnd)
+	}
+	if got.Status != RunRunning || !got.FinishedAt.IsZero() {
+		t.Errorf("new run status = %q, finished = %v; want running, unfinished", got.Status, got.FinishedAt)
+	}
+
+	steps := []*RunStep{
+		{RunID: run.ID, SIGID: "collector", Stage: "fetch", Status: StepDone},
+		{RunID: run.ID, SIGID: "collector", Stage: "analyze", Status: StepFailed, Error: "rate limited"},
+	}
+	for _, st := range steps {
+		if err := s.SetRunStep(st); err != nil {
+			t.Fatalf("SetRunStep failed: %v", err)
+		}
+	}
+	// Retrying a step replaces it.
+	if err := s.SetRunStep(&RunStep{RunID: run.ID, SIGID: "collector", Stage: "analyze", Status: StepDone, Result: "{}"}); err != nil {
+		t.Fatalf("SetRunStep retry failed: %v", err)
+	}
+
+	gotSteps, err := s.GetRunSteps(run.ID)
+	if err != nil {
+		t.Fatalf("GetRunSteps failed: %v", err)
+	}
+	if len(gotSteps) != 2 {
+		t.Fatalf("GetRunSteps returned %d, want 2", len(gotSteps))
+	}
+	if gotSteps[0].Stage != "analyze" || gotSteps[0].Status != StepDone || gotSteps[0].Error != "" || gotSteps[0].Result != "{}" {
+		t.Errorf("analyze step = %+v, want done with result", gotSteps[0])
+	}
+
+	if err := s.UpdateRunStatus(run.ID, RunFailed, "boom"); err != nil {
+		t.Fatalf("UpdateRunStatus failed: %v", err)
+	}
+	runs, err := s.ListRuns(10)
+	if err != nil {
+		t.Fatalf("ListRuns failed: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != RunFailed || runs[0].Error != "boom" || runs[0].FinishedAt.IsZero() {
+		t.Errorf("ListRuns = %+v, want one failed, finished run", runs[0])
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s := newTestStore(t)
+
+	for _, id := range []string{"collector", "java-sdk"} {
+		if err := s.UpsertSIG(&SIG{ID: id, Name: id}); err != nil {
+			t.Fatalf("UpsertSIG failed: %v", err)
+		}
+	}
+	if err := s.UpsertMeetingNote(&MeetingNote{
+		SIGID: "collector", DocID: "doc1", MeetingDate: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC),
+		RawText: "Discussed OTLP partial success handling", ContentHash: "h1",
+	}); err != nil {
+		t.Fatalf("UpsertMeetingNote failed: %v", err)
+	}
+	if err := s.UpsertSlackMessage(&SlackMessage{
+		SIGID: "java-sdk", ChannelID: "C1", MessageTS: "1.0", Text: "otlp exporter 100% retries",
+		MessageDate: time.Date(2026, 2, 12, 9, 0, 0, 0, time.UTC),
+	}); err != nil {
+		t.Fatalf("UpsertSlackMessage failed: %v", err)
+	}
+
+	results, err := s.Search("otlp", "", 0)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search returned %d results, want 2", len(results))
+	}
+	if results[0].SourceType != "slack" || results[1].SourceType != "notes" {
+		t.Errorf("results not ordered newest first: %s, %s", results[0].SourceType, results[1].SourceType)
+	}
+
+	results, err = s.Search("otlp", "collector", 0)
+	if err != nil {
+		t.Fatalf("Search by SIG failed: %v", err)
+	}
+	if len(results) != 1 || results[0].SIGID != "collector" {
+		t.Errorf("Search by SIG = %+v, want only the collector note", results)
+	}
+
+	// Wildcards in the query are matched literally.
+	results, err = s.Search("100%", "", 0)
+	if err != nil {
+		t.Fatalf("Search with wildcard failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Search(%q) returned %d results, want 1", "100%", len(results))
+	}
+	results, err = s.Search("1_0", "", 0)
+	if err != nil {
+		t.Fatalf("Search with underscore failed: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Search(%q) returned %d results, want 0", "1_0", len(results))
+	}
+}
+
+func TestLocks(t *testing.T) {
+	s := newTestStore(t)
+
+	ok, err := s.AcquireLock("pipeline", "a", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("first AcquireLock = %v, %v; want true", ok, err)
+	}
+	if ok, _ := s.AcquireLock("pipeline", "b", time.Minute); ok {
+		t.Error("AcquireLock by another owner should fail while the lock is held")
+	}
+	if ok, _ := s.AcquireLock("pipeline", "a", time.Minute); !ok {
+		t.Error("AcquireLock by the holder should extend the lock")
+	}
+	if owner, _, err := s.LockHolder("pipeline"); err != nil || owner != "a" {
+		t.Errorf("LockHolder = %q, %v; want a", owner, err)
+	}
+
+	// Releasing someone else's lock is a no-op.
+	if err := s.ReleaseLock("pipeline", "b"); err != nil {
+		t.Fatalf("ReleaseLock failed: %v", err)
+	}
+	if ok, _ := s.AcquireLock("pipeline", "b", time.Minute); ok {
+		t.Error("lock should still be held by a")
+	}
+
+	if err := s.ReleaseLock("pipeline", "a"); err != nil {
+		t.Fatalf("ReleaseLock failed: %v", err)
+	}
+	if ok, _ := s.AcquireLock("pipeline", "b", -time.Second); !ok {
+		t.Error("AcquireLock should succeed after release")
+	}
+	// b's lock has already expired, so a can take it over.
+	if ok, _ := s.AcquireLock("pipeline", "a", time.Minute); !ok {
+		t.Error("AcquireLock should take over an expired lock")
+	}
+}
+
+func TestEmailSends(t *testing.T) {
+	s := newTestStore(t)
+	r := &Report{ReportType: "digest", DateRangeStart: time.Now().AddDate(0, 0, -7), DateRangeEnd: time.Now()}
+	if err := s.InsertReport(r); err != nil {
+		t.Fatalf("InsertReport failed: %v", err)
+	}
+
+	if sent, err := s.EmailSent(r.ID, "a@example.com"); err != nil || sent {
+		t.Fatalf("EmailSent before sending = %v, %v; want false", sent, err)
+	}
+	for _, es := range []*EmailSend{
+		{ReportID: r.ID, Recipient: "a@example.com", SIGIDs: "collector", MessageID: "<1@x>"},
+		{ReportID: r.ID, Recipient: "b@example.com", SIGIDs: "java-sdk"},
+		{ReportID: r.ID, Recipient: "a@example.com", SIGIDs: "collector,java-sdk", MessageID: "<2@x>"},
+	} {
+		if err := s.RecordEmailSend(es); err != nil {
+			t.Fatalf("RecordEmailSend failed: %v", err)
+		}
+	}
+	if sent, _ := s.EmailSent(r.ID, "a@example.com"); !sent {
+		t.Error("EmailSent = false after recording a send")
+	}
+	if sent, _ := s.EmailSent(r.ID+100, "a@example.com"); sent {
+		t.Error("EmailSent should be false for an unknown report")
+	}
+
+	// A re-run's digest of the same week counts as sent; other weeks and SIG
+	// sets do not.
+	for _, tt := range []struct {
+		name   string
+		report *Report
+		want   bool
+	}{
+		{"same week", &Report{ReportType: "digest", DateRangeStart: r.DateRangeStart, DateRangeEnd: r.DateRangeEnd}, true},
+		{"other week", &Report{ReportType: "digest", DateRangeStart: r.DateRangeEnd, DateRangeEnd: r.DateRangeEnd.AddDate(0, 0, 7)}, false},
+		{"other SIGs", &Report{ReportType: "digest", SIGSet: "collector", DateRangeStart: r.DateRangeStart, DateRangeEnd: r.DateRangeEnd}, false},
+	} {
+		if err := s.InsertReport(tt.report); err != nil {
+			t.Fatalf("InsertReport failed: %v", err)
+		}
+		if sent, err := s.EmailSent(tt.report.ID, "a@example.com"); err != nil || sent != tt.want {
+			t.Errorf("%s: EmailSent = %v, %v; want %v", tt.name, sent, err, tt.want)
+		}
+	}
+
+	sends, err := s.ListEmailSends(r.ID)
+	if err != nil {
+		t.Fatalf("ListEmailSends failed: %v", err)
+	}
+	if len(sends) != 2 {
+		t.Fatalf("got %d sends, want 2 (re-sends replace the record)", len(sends))
+	}
+	if sends[0].Recipient != "a@example.com" || sends[0].SIGIDs != "collector,java-sdk" || sends[0].MessageID != "<2@x>" {
+		t.Errorf("first send = %+v, want the latest send to a@example.com", sends[0])
+	}
+}
+
+func TestMigrations_RewritesLocalDates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+	s, err := New(path)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := s.UpsertSIG(&SIG{ID: "collector", Name: "Collector", Category: "implementation"}); err != nil {
+		t.Fatalf("UpsertSIG failed: %v", err)
+	}
+	// Dates as the driver wrote them before they were stored in UTC, and
+	// the schema as it was then.
+	ny := time.FixedZone("EDT", -4*60*60)
+	local := time.Date(2026, 10, 11, 22, 0, 0, 500, ny)
+	if _, err := s.db.Exec(`INSERT INTO video_transcripts (sig_id, zoom_url, recording_date, duration_minutes, transcript, transcript_source, content_hash) VALUES ('collector', 'https://zoom.us/rec/late', ?, 60, 'Late meeting', '', '')`, local.String()); err != nil {
+		t.Fatalf("inserting transcript: %v", err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO slack_messages (sig_id, channel_id, message_ts, thread_ts, user_id, user_name, text, message_date) VALUES ('collector', 'C1', '1.1', '', '', '', 'Hi', ?)`, local.String()); err != nil {
+		t.Fatalf("inserting message: %v", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM schema_version WHERE version > ?`, len(migrations)-2); err != nil {
+		t.Fatalf("resetting schema version: %v", err)
+	}
+	s.Close()
+
+	s, err = New(path)
+	if err != nil {
+		t.Fatalf("reopening store: %v", err)
+	}
+	defer s.Close()
+
+	for _, q := range []string{
+		`SELECT CAST(recording_date AS TEXT) FROM video_transcripts`,
+		`SELECT CAST(message_date AS TEXT) FROM slack_messages`,
+	} {
+		var got string
+		if err := s.db.QueryRow(q).Scan(&got); err != nil {
+			t.Fatalf("%s: %v", q, err)
+		}
+		if got != "2026-10-12 02:00:00" {
+			t.Errorf("%s = %q, want %q", q, got, "2026-10-12 02:00:00")
+		}
+	}
+
+	// The week ending on the Sunday, New York time, now finds them.
+	start := time.Date(2026, 10, 5, 0, 0, 0, 0, ny)
+	end := time.Date(2026, 10, 12, 0, 0, 0, 0, ny).Add(-time.Nanosecond)
+	if msgs, err := s.GetSlackMessages("collector", start, end); err != nil || len(msgs) != 1 {
+		t.Errorf("GetSlackMessages = %d, %v; want 1", len(msgs), err)
+	}
+	if transcripts, err := s.GetVideoTranscripts("collector", start, end); err != nil || len(transcripts) != 1 {
+		t.Errorf("GetVideoTranscripts = %d, %v; want 1", len(transcripts), err)
+	}
+}
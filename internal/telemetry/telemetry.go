@@ -0,0 +1,150 @@
+// Package telemetry instruments the tool itself with OpenTelemetry traces
+// and metrics, exported over OTLP/HTTP.
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName scopes the tool's tracer and meter.
+const instrumentationName = "github.com/gordyrad/otel-sig-tracker"
+
+// ServiceName is the service.name resource attribute.
+const ServiceName = "otel-sig-scraper"
+
+// Attribute keys shared by spans and metrics.
+const (
+	AttrSIGID      = attribute.Key("sig.id")
+	AttrSourceType = attribute.Key("source.type")
+	AttrStage      = attribute.Key("pipeline.stage")
+	AttrProvider   = attribute.Key("llm.provider")
+	AttrModel      = attribute.Key("llm.model")
+	AttrTokens     = attribute.Key("llm.tokens")
+	AttrCacheHit   = attribute.Key("cache.hit")
+)
+
+// Setup installs global tracer and meter providers that export to endpoint,
+// an OTLP/HTTP base URL such as "http://localhost:4318". If endpoint is
+// empty, OTEL_EXPORTER_OTLP_ENDPOINT is used; if that is unset too,
+// telemetry stays disabled and the no-op providers remain in place.
+//
+// The returned function flushes and stops the providers.
+func Setup(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	if endpoint == "" && os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var traceOpts []otlptracehttp.Option
+	var metricOpts []otlpmetrichttp.Option
+	if endpoint != "" {
+		traceOpts = append(traceOpts, otlptracehttp.WithEndpointURL(endpoint+"/v1/traces"))
+		metricOpts = append(metricOpts, otlpmetrichttp.WithEndpointURL(endpoint+"/v1/metrics"))
+	}
+	traceExporter, err := otlptracehttp.New(ctx, traceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx, metricOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP metric exporter: %w", err)
+	}
+
+	return Install(sdktrace.WithBatcher(traceExporter), sdkmetric.NewPeriodicReader(metricExporter)), nil
+}
+
+// Install sets global providers using the given span processor and metric
+// reader and returns a function that shuts them down. Tests use it with an
+// in-memory exporter and a manual reader.
+func Install(spans sdktrace.TracerProviderOption, reader sdkmetric.Reader) func(context.Context) error {
+	res, _ := resource.Merge(resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(ServiceName)))
+	tp := sdktrace.NewTracerProvider(spans, sdktrace.WithResource(res))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(res))
+	otel.SetTracerProvider(tp)
+	otel.SetMeterProvider(mp)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tp.Shutdown(ctx), mp.Shutdown(ctx))
+	}
+}
+
+// Start starts a span from the tool's tracer.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// instruments are created on first use from the global meter provider, which
+// forwards to the provider installed later by Setup.
+var (
+	instrumentsOnce sync.Once
+	fetchDuration   metric.Float64Histogram
+	fetchErrors     metric.Int64Counter
+	llmDuration     metric.Float64Histogram
+	llmTokens       metric.Int64Counter
+	llmErrors       metric.Int64Counter
+)
+
+func instruments() {
+	instrumentsOnce.Do(func() {
+		m := otel.Meter(instrumentationName)
+		fetchDuration, _ = m.Float64Histogram("otelsig.fetch.duration",
+			metric.WithUnit("s"), metric.WithDescription("Duration of fetching one source for a SIG"))
+		fetchErrors, _ = m.Int64Counter("otelsig.fetch.errors",
+			metric.WithDescription("Failed source fetches"))
+		llmDuration, _ = m.Float64Histogram("otelsig.llm.duration",
+			metric.WithUnit("s"), metric.WithDescription("Duration of LLM completion calls"))
+		llmTokens, _ = m.Int64Counter("otelsig.llm.tokens",
+			metric.WithUnit("{token}"), metric.WithDescription("Tokens used by LLM completion calls"))
+		llmErrors, _ = m.Int64Counter("otelsig.llm.errors",
+			metric.WithDescription("Failed LLM completion calls"))
+	})
+}
+
+// RecordFetch records the duration and outcome of fetching one source.
+func RecordFetch(ctx context.Context, sourceType, sigID string, d time.Duration, err error) {
+	instruments()
+	attrs := metric.WithAttributes(AttrSourceType.String(sourceType), AttrSIGID.String(sigID))
+	fetchDuration.Record(ctx, d.Seconds(), attrs)
+	if err != nil {
+		fetchErrors.Add(ctx, 1, attrs)
+	}
+}
+
+// RecordLLM records the duration, token usage and outcome of an LLM call.
+func RecordLLM(ctx context.Context, provider, model string, tokens int, d time.Duration, err error) {
+	instruments()
+	attrs := metric.WithAttributes(AttrProvider.String(provider), AttrModel.String(model))
+	llmDuration.Record(ctx, d.Seconds(), attrs)
+	if tokens > 0 {
+		llmTokens.Add(ctx, int64(tokens), attrs)
+	}
+	if err != nil {
+		llmErrors.Add(ctx, 1, attrs)
+	}
+}
@@ -0,0 +1,144 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumentation(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	shutdown := Install(sdktrace.WithSyncer(spans), reader)
+	defer shutdown(context.Background())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Transport(nil, "notes")}
+	ctx, parent := Start(context.Background(), "pipeline.fetch")
+	for _, path := range []string{"/doc?token=secret", "/missing"} {
+		req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+	}
+	End(parent, errors.New("boom"))
+
+	RecordFetch(ctx, "notes", "collector", 2*time.Second, nil)
+	RecordFetch(ctx, "notes", "collector", time.Second, errors.New("timeout"))
+	RecordLLM(ctx, "anthropic", "claude", 1500, time.Second, nil)
+
+	got := spans.GetSpans()
+	if len(got) != 3 {
+		t.Fatalf("recorded %d spans, want 3", len(got))
+	}
+	ok, notFound, root := got[0], got[1], got[2]
+	if ok.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Error("HTTP span should be a child of the caller's span")
+	}
+	if !hasAttr(ok.Attributes, AttrSourceType.String("notes")) || !hasAttr(ok.Attributes, attribute.String("url.path", "/doc")) {
+		t.Errorf("HTTP span attributes = %v", ok.Attributes)
+	}
+	for _, kv := range ok.Attributes {
+		if kv.Value.Emit() == "token=secret" {
+			t.Error("HTTP span must not record the query string")
+		}
+	}
+	if notFound.Status.Code != codes.Error || !hasAttr(notFound.Attributes, attribute.Int("http.response.status_code", 404)) {
+		t.Errorf("404 span status = %v, attributes = %v", notFound.Status, notFound.Attributes)
+	}
+	if root.Status.Code != codes.Error || len(root.Events) == 0 {
+		t.Error("End should record the error on the span")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	sums := make(map[string]int64)
+	counts := make(map[string]uint64)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					sums[m.Name] += dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					counts[m.Name] += dp.Count
+				}
+			}
+		}
+	}
+	if counts["otelsig.fetch.duration"] != 2 || sums["otelsig.fetch.errors"] != 1 {
+		t.Errorf("fetch metrics: durations %d, errors %d; want 2 and 1", counts["otelsig.fetch.duration"], sums["otelsig.fetch.errors"])
+	}
+	if sums["otelsig.llm.tokens"] != 1500 || counts["otelsig.llm.duration"] != 1 {
+		t.Errorf("llm metrics: tokens %d, calls %d; want 1500 and 1", sums["otelsig.llm.tokens"], counts["otelsig.llm.duration"])
+	}
+}
+
+func TestSetup_DisabledWithoutEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	shutdown, err := Setup(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestSetup_ExportsToCollector(t *testing.T) {
+	paths := make(chan string, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths <- r.URL.Path
+	}))
+	defer collector.Close()
+
+	shutdown, err := Setup(context.Background(), collector.URL)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	_, span := Start(context.Background(), "pipeline.run")
+	span.End()
+	RecordLLM(context.Background(), "openai", "gpt", 10, time.Second, nil)
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	got := make(map[string]bool)
+	for len(paths) > 0 {
+		got[<-paths] = true
+	}
+	if !got["/v1/traces"] || !got["/v1/metrics"] {
+		t.Errorf("collector received %v, want traces and metrics", got)
+	}
+}
+
+func hasAttr(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}